@@ -7,12 +7,14 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
@@ -20,7 +22,10 @@ import (
 
 	"github.com/tosin2013/openshift-coordination-engine/internal/coordination"
 	"github.com/tosin2013/openshift-coordination-engine/internal/detector"
+	"github.com/tosin2013/openshift-coordination-engine/internal/events"
+	"github.com/tosin2013/openshift-coordination-engine/internal/incidents"
 	"github.com/tosin2013/openshift-coordination-engine/internal/integrations"
+	"github.com/tosin2013/openshift-coordination-engine/internal/notifications"
 	"github.com/tosin2013/openshift-coordination-engine/internal/rbac"
 	"github.com/tosin2013/openshift-coordination-engine/internal/remediation"
 	v1 "github.com/tosin2013/openshift-coordination-engine/pkg/api/v1"
@@ -101,6 +106,7 @@ func main() {
 
 	// Initialize deployment detector
 	deploymentDetector := detector.NewDetector(k8sClients.Clientset, log)
+	deploymentDetector.SetDynamicClient(k8sClients.DynamicClient)
 	log.Info("Deployment detector initialized")
 
 	// Initialize multi-layer coordination components (Phase 3)
@@ -113,18 +119,39 @@ func main() {
 	healthChecker := coordination.NewHealthChecker(k8sClients.Clientset, k8sClients.DynamicClient, log)
 	log.Info("Health checker initialized")
 
+	// Memory increases for OOM-killed workloads; the ceiling was validated with the config
+	memoryCeiling, _ := resource.ParseQuantity(cfg.OOMMemoryCeiling)
+	memoryResizer := remediation.NewMemoryResizer(k8sClients.Clientset, cfg.OOMMemoryStepPercent, memoryCeiling, log)
+
 	// Initialize remediation components
 	manualRemediator := remediation.NewManualRemediator(k8sClients.Clientset, log)
+	manualRemediator.SetMemoryResizer(memoryResizer)
+	manualRemediator.SetDynamicClient(k8sClients.DynamicClient)
 	log.Info("Manual remediator initialized")
 
 	// Initialize Helm remediator
-	helmRemediator := remediation.NewHelmRemediator(log)
+	helmRemediator := remediation.NewHelmRemediator(k8sClients.Clientset, k8sClients.DynamicClient, log)
+	helmRemediator.SetMemoryResizer(memoryResizer)
 	log.Info("Helm remediator initialized")
 
 	// Initialize Operator remediator
 	operatorRemediator := remediation.NewOperatorRemediator(k8sClients.Clientset, k8sClients.DynamicClient, log)
 	log.Info("Operator remediator initialized")
 
+	// Remediation policy mapping issues to actions, reloaded when its source changes
+	policyEngine := remediation.NewPolicyEngine(k8sClients.Clientset, log)
+	watchCtx, stopWatches := context.WithCancel(context.Background())
+	defer stopWatches()
+	if policyLoader := remediationPolicyLoader(cfg, k8sClients.Clientset); policyLoader != nil {
+		if _, err := policyEngine.Load(context.Background(), policyLoader); err != nil {
+			log.WithError(err).Fatal("Failed to load remediation policy")
+		}
+		go policyEngine.Watch(watchCtx, policyLoader, cfg.PolicyReloadInterval)
+	} else {
+		log.Info("No remediation policy configured, using the built-in policy")
+	}
+	manualRemediator.SetPolicy(policyEngine)
+
 	// Initialize strategy selector for multi-remediator routing
 	strategySelector := remediation.NewStrategySelector(log)
 	strategySelector.SetFallbackRemediator(manualRemediator)
@@ -141,15 +168,153 @@ func main() {
 		argocdToken := os.Getenv("ARGOCD_TOKEN")
 		argocdClient := integrations.NewArgoCDClient(cfg.ArgocdAPIURL, argocdToken, log)
 		argocdRemediator := remediation.NewArgoCDRemediator(argocdClient, log)
+		argocdRemediator.SetPolicy(policyEngine)
+		argocdRemediator.SetMemoryResizer(memoryResizer)
 		strategySelector.RegisterRemediator(argocdRemediator)
 		log.WithField("argocd_url", cfg.ArgocdAPIURL).Info("ArgoCD remediator initialized")
 	} else {
 		log.Warn("ARGOCD_API_URL not set, ArgoCD remediation disabled")
 	}
 
+	// Remediator priorities, the confidence threshold and policy-allowed failover
+	strategySelector.SetPolicy(policyEngine)
+	for name, priority := range cfg.RemediatorPriorityMap() {
+		strategySelector.SetPriority(name, priority)
+	}
+	strategySelector.SetMinConfidence(cfg.MinConfidence)
+
+	// Retries of remediations failing with retryable errors, overridable by the policy and per request
+	strategySelector.SetRetryPolicy(cfg.RetryPolicy())
+
+	// External HTTP remediation plugins, offered each issue before the built-in remediators
+	var pluginRegistry *remediation.PluginRegistry
+	if cfg.PluginConfigFile != "" {
+		plugins, err := remediation.LoadPlugins(cfg.PluginConfigFile)
+		if err != nil {
+			log.WithError(err).Fatal("Failed to load remediation plugin configuration")
+		}
+		pluginRegistry = remediation.NewPluginRegistry(log)
+		for _, plugin := range plugins {
+			if err := pluginRegistry.Register(remediation.NewWebhookRemediator(plugin, k8sClients.Clientset, log)); err != nil {
+				log.WithError(err).Fatal("Failed to register remediation plugin")
+			}
+		}
+		strategySelector.SetPluginRegistry(pluginRegistry)
+		go pluginRegistry.Watch(watchCtx, cfg.PluginHealthInterval)
+		log.WithField("plugins", len(plugins)).Info("Remediation plugins loaded")
+	}
+
+	// Record remediation actions as Kubernetes Events on the remediated resources
+	eventRecorder := remediation.NewKubeEventRecorder(k8sClients.Clientset, k8sClients.DynamicClient, log)
+	strategySelector.SetEventRecorder(eventRecorder)
+
+	// Refuse remediation outside the allowed namespaces and of opted-out resources
+	guardrails := remediation.NewGuardrails(cfg.NamespaceAllowList, cfg.NamespaceDenyList, k8sClients.Clientset, log)
+	guardrails.SetDynamicClient(k8sClients.DynamicClient)
+	strategySelector.SetGuardrails(guardrails)
+	log.WithFields(logrus.Fields{
+		"allow": cfg.NamespaceAllowList,
+		"deny":  cfg.NamespaceDenyList,
+	}).Info("Remediation guardrails initialized")
+
+	// Per-resource locks shared by both orchestrators
+	lockManager := remediation.NewLockManager(log)
+	if cfg.LockLeaseAnnotations {
+		lockManager.EnableLeaseAnnotations(k8sClients.Clientset, engineIdentity(), remediation.DefaultLeaseDuration)
+		lockManager.SetDynamicClient(k8sClients.DynamicClient)
+	}
+
+	// Bounded, severity-ordered execution queue shared by both orchestrators
+	workQueue := remediation.NewWorkQueue(remediation.WorkQueueConfig{
+		Workers:         cfg.QueueWorkers,
+		MaxConcurrent:   cfg.QueueMaxConcurrent,
+		MaxPerNamespace: cfg.QueueMaxPerNamespace,
+	}, log)
+
+	// Approval gate for high-risk remediations, shared by both orchestrators
+	approvalPolicy := &remediation.ApprovalPolicy{
+		Severities:  cfg.ApprovalSeverities,
+		Namespaces:  cfg.ApprovalNamespaces,
+		Remediators: cfg.ApprovalRemediators,
+		Layers:      cfg.ApprovalLayers,
+		Expiry:      cfg.ApprovalExpiry,
+	}
+	if approvalPolicy.Enabled() {
+		log.WithFields(logrus.Fields{
+			"severities":  cfg.ApprovalSeverities,
+			"namespaces":  cfg.ApprovalNamespaces,
+			"remediators": cfg.ApprovalRemediators,
+			"layers":      cfg.ApprovalLayers,
+			"expiry":      cfg.ApprovalExpiry,
+		}).Info("Remediation approval gate enabled")
+	}
+
+	// Incident lifecycle tracking shared by both orchestrators
+	incidentTracker := incidents.NewTracker(incidents.NewMemoryStore(), log)
+
+	// Workflow progress events, streamed to API clients
+	eventBus := events.NewBus(log)
+
+	// Outbound webhook notifications for finished workflows
+	var notifier *notifications.Notifier
+	if cfg.WebhookConfigFile != "" {
+		webhooks, err := notifications.LoadWebhooks(cfg.WebhookConfigFile)
+		if err != nil {
+			log.WithError(err).Fatal("Failed to load webhook configuration")
+		}
+		notifier = notifications.NewNotifier(webhooks, notifications.NotifierConfig{
+			MaxRetries:   cfg.WebhookMaxRetries,
+			RetryBackoff: cfg.WebhookRetryBackoff,
+			Timeout:      cfg.WebhookTimeout,
+		}, log)
+		notifier.Start(eventBus)
+	}
+
+	// Maintenance windows and the global freeze, shared by both orchestrators
+	var maintenanceWindows []remediation.MaintenanceWindow
+	if cfg.MaintenanceConfigFile != "" {
+		maintenanceWindows, err = remediation.LoadMaintenanceWindows(cfg.MaintenanceConfigFile)
+		if err != nil {
+			log.WithError(err).Fatal("Failed to load maintenance window configuration")
+		}
+		log.WithField("windows", len(maintenanceWindows)).Info("Maintenance windows loaded")
+	}
+	maintenancePolicy := remediation.NewMaintenancePolicy(maintenanceWindows, k8sClients.Clientset, log)
+
 	// Initialize remediation orchestrator with detector and strategy selector
 	orchestrator := remediation.NewOrchestrator(deploymentDetector, strategySelector, log)
-	log.WithField("remediators", strategySelector.GetRegisteredRemediators()).Info("Remediation orchestrator initialized")
+	orchestrator.SetLockManager(lockManager)
+	orchestrator.SetWorkQueue(workQueue)
+	orchestrator.SetApprovalPolicy(approvalPolicy)
+	orchestrator.SetIncidentTracker(incidentTracker)
+	orchestrator.SetEventBus(eventBus)
+	orchestrator.SetEventRecorder(eventRecorder)
+	orchestrator.SetMaintenancePolicy(maintenancePolicy)
+	if cfg.BreakerThreshold > 0 {
+		orchestrator.SetCircuitBreaker(remediation.NewCircuitBreaker(cfg.BreakerThreshold, cfg.BreakerWindow, cfg.BreakerCooldown, log))
+	}
+	if cfg.VerificationTimeout > 0 {
+		verifier := remediation.NewVerifier(k8sClients.Clientset, log)
+		verifier.SetTimeout(cfg.VerificationTimeout)
+		verifier.SetDynamicClient(k8sClients.DynamicClient)
+		orchestrator.SetVerifier(verifier)
+	}
+	orchestrator.SetDedupWindow(cfg.RemediationDedupWindow)
+	if cfg.WorkflowStore == remediation.WorkflowStoreConfigMap {
+		orchestrator.SetWorkflowStore(remediation.NewConfigMapWorkflowStore(k8sClients.Clientset, cfg.Namespace, log))
+	}
+	orchestrator.SetOwner(engineIdentity(), remediation.PodOwnerCheck(k8sClients.Clientset, cfg.Namespace))
+	interrupted, err := orchestrator.ReconcileInterrupted(context.Background())
+	if err != nil {
+		log.WithError(err).Warn("Failed to reconcile interrupted workflows")
+	}
+	log.WithFields(logrus.Fields{
+		"remediators":    strategySelector.GetRegisteredRemediators(),
+		"workflow_store": cfg.WorkflowStore,
+		"verification":   cfg.VerificationTimeout,
+		"interrupted":    interrupted,
+	}).Info("Remediation orchestrator initialized")
+	workQueue.Start()
 
 	// Initialize multi-layer orchestrator with remediation integration (Phase 4)
 	multiLayerOrchestrator := coordination.NewMultiLayerOrchestrator(
@@ -159,6 +324,10 @@ func main() {
 		k8sClients.Clientset,
 		log,
 	)
+	multiLayerOrchestrator.SetLockManager(lockManager)
+	multiLayerOrchestrator.SetEventBus(eventBus)
+	multiLayerOrchestrator.SetGuardrails(guardrails)
+	multiLayerOrchestrator.SetPolicy(policyEngine)
 	log.Info("Multi-layer orchestrator initialized with remediation integration")
 
 	// Setup HTTP router with middleware
@@ -173,8 +342,18 @@ func main() {
 	// TODO: Add MCO health monitoring to health handler in future enhancement
 	_ = mcoClient // MCO client available for infrastructure layer operations
 	remediationHandler := v1.NewRemediationHandler(orchestrator, log)
+	remediationHandler.SetEventBus(eventBus)
 	detectionHandler := v1.NewDetectionHandler(deploymentDetector, log)
 	coordinationHandler := v1.NewCoordinationHandler(layerDetector, multiLayerPlanner, multiLayerOrchestrator, log)
+	coordinationHandler.SetWorkQueue(workQueue)
+	coordinationHandler.SetApprovalPolicy(approvalPolicy)
+	coordinationHandler.SetIncidentTracker(incidentTracker)
+	coordinationHandler.SetEventBus(eventBus)
+	coordinationHandler.SetMaintenancePolicy(maintenancePolicy)
+	incidentHandler := v1.NewIncidentHandler(incidentTracker, log)
+	notificationHandler := v1.NewNotificationHandler(notifier, log)
+	maintenanceHandler := v1.NewMaintenanceHandler(maintenancePolicy, log)
+	pluginHandler := v1.NewPluginHandler(pluginRegistry, log)
 	log.Info("Coordination handler initialized")
 
 	// API v1 routes
@@ -185,8 +364,24 @@ func main() {
 
 	// Remediation endpoints
 	apiV1.HandleFunc("/remediation/trigger", remediationHandler.TriggerRemediation).Methods("POST")
+	apiV1.HandleFunc("/remediation/circuit-breakers", remediationHandler.ListCircuitBreakers).Methods("GET")
 	apiV1.HandleFunc("/workflows/{id}", remediationHandler.GetWorkflow).Methods("GET")
-	apiV1.HandleFunc("/incidents", remediationHandler.ListIncidents).Methods("GET")
+	apiV1.HandleFunc("/workflows/{id}", remediationHandler.CancelWorkflow).Methods("DELETE")
+	apiV1.HandleFunc("/workflows/{id}/events", remediationHandler.StreamWorkflowEvents).Methods("GET")
+	apiV1.HandleFunc("/workflows/{id}/approve", remediationHandler.ApproveWorkflow).Methods("POST")
+	apiV1.HandleFunc("/workflows/{id}/reject", remediationHandler.RejectWorkflow).Methods("POST")
+
+	// Incident endpoints
+	incidentHandler.RegisterRoutes(router)
+
+	// Notification endpoints
+	notificationHandler.RegisterRoutes(router)
+
+	// Maintenance window and global freeze endpoints
+	maintenanceHandler.RegisterRoutes(router)
+
+	// Remediation plugin registry endpoints
+	pluginHandler.RegisterRoutes(router)
 
 	// Detection endpoints
 	detectionHandler.RegisterRoutes(router)
@@ -240,6 +435,7 @@ func main() {
 	<-quit
 
 	log.Info("Shutting down servers...")
+	stopWatches()
 
 	// Graceful shutdown
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -253,9 +449,57 @@ func main() {
 		log.WithError(err).Error("Metrics server shutdown error")
 	}
 
+	// Let running workflows finish within the shutdown timeout; queued ones are
+	// reconciled as interrupted on the next start
+	queueStopped := make(chan struct{})
+	go func() {
+		workQueue.Stop()
+		close(queueStopped)
+	}()
+	select {
+	case <-queueStopped:
+	case <-ctx.Done():
+		log.Warn("Timed out waiting for running workflows to finish")
+	}
+
+	// Deliver notifications for the workflows that just finished
+	if notifier != nil {
+		notifier.Stop(ctx)
+	}
+	eventRecorder.Shutdown()
+
 	log.Info("Servers stopped")
 }
 
+// remediationPolicyLoader returns the configured remediation policy source, or nil
+// if the built-in policy applies
+func remediationPolicyLoader(cfg *config.Config, clientset kubernetes.Interface) remediation.PolicyLoader {
+	switch {
+	case cfg.PolicyConfigFile != "":
+		return remediation.FilePolicyLoader(cfg.PolicyConfigFile)
+	case cfg.PolicyConfigMap != "":
+		namespace, name := cfg.Namespace, cfg.PolicyConfigMap
+		if i := strings.Index(name, "/"); i >= 0 {
+			namespace, name = name[:i], name[i+1:]
+		}
+		return remediation.ConfigMapPolicyLoader(clientset, namespace, name, "policy.yaml")
+	default:
+		return nil
+	}
+}
+
+// engineIdentity identifies this engine replica in resource lock leases and as the
+// owner of the workflows it executes
+func engineIdentity() string {
+	if podName := os.Getenv("POD_NAME"); podName != "" {
+		return podName
+	}
+	if hostname, err := os.Hostname(); err == nil {
+		return hostname
+	}
+	return "coordination-engine"
+}
+
 // initKubernetesClient creates both standard and dynamic Kubernetes clients
 // It tries in-cluster config first, then falls back to KUBECONFIG from configuration
 func initKubernetesClient(cfg *config.Config, log *logrus.Logger) (*KubernetesClients, error) {
@@ -3,12 +3,14 @@ package coordination
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/sirupsen/logrus"
 	"k8s.io/client-go/kubernetes"
 
 	"github.com/tosin2013/openshift-coordination-engine/internal/detector"
+	"github.com/tosin2013/openshift-coordination-engine/internal/events"
 	"github.com/tosin2013/openshift-coordination-engine/internal/remediation"
 	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
 )
@@ -19,6 +21,10 @@ type MultiLayerOrchestrator struct {
 	detector         *detector.Detector
 	strategySelector remediation.Remediator
 	clientset        kubernetes.Interface
+	locks            *remediation.LockManager
+	guardrails       *remediation.Guardrails
+	policy           *remediation.PolicyEngine
+	events           *events.Bus
 	log              *logrus.Logger
 }
 
@@ -39,13 +45,35 @@ func NewMultiLayerOrchestrator(
 	}
 }
 
+// SetLockManager enables per-resource locking shared with the remediation orchestrator
+func (mlo *MultiLayerOrchestrator) SetLockManager(locks *remediation.LockManager) {
+	mlo.locks = locks
+}
+
+// SetGuardrails refuses application steps on resources excluded from remediation
+func (mlo *MultiLayerOrchestrator) SetGuardrails(guardrails *remediation.Guardrails) {
+	mlo.guardrails = guardrails
+}
+
+// SetPolicy sets the remediation policy that maps step actions to issue types
+func (mlo *MultiLayerOrchestrator) SetPolicy(policy *remediation.PolicyEngine) {
+	mlo.policy = policy
+}
+
+// SetEventBus publishes step, checkpoint and rollback events of executed plans to bus
+func (mlo *MultiLayerOrchestrator) SetEventBus(bus *events.Bus) {
+	mlo.events = bus
+}
+
 // ExecutionResult contains the result of plan execution
 type ExecutionResult struct {
-	Status        string    `json:"status"` // success, failed, rolled_back
+	Status        string    `json:"status"` // success, failed, cancelled, rolled_back
 	Reason        string    `json:"reason,omitempty"`
 	ExecutedSteps int       `json:"executed_steps"`
 	FailedStep    *int      `json:"failed_step,omitempty"`
 	CompletedAt   time.Time `json:"completed_at"`
+
+	ExecutedOrders []int `json:"executed_orders,omitempty"` // orders of the steps that succeeded, in execution order
 }
 
 // ExecutePlan executes a remediation plan with health checkpoints
@@ -65,6 +93,11 @@ func (mlo *MultiLayerOrchestrator) ExecutePlan(ctx context.Context, plan *models
 	executedSteps := []models.RemediationStep{}
 
 	for i, step := range plan.Steps {
+		// Stop between steps when the workflow has been cancelled
+		if err := ctx.Err(); err != nil {
+			return mlo.cancelledResult(plan, startTime, executedSteps, err), fmt.Errorf("plan execution cancelled: %w", err)
+		}
+
 		// Execute step
 		mlo.log.WithFields(logrus.Fields{
 			"step":        step.Order,
@@ -73,8 +106,16 @@ func (mlo *MultiLayerOrchestrator) ExecutePlan(ctx context.Context, plan *models
 			"target":      step.Target,
 		}).Info("Executing remediation step")
 
-		if err := mlo.executeStep(ctx, &step); err != nil {
+		mlo.publish(plan.ID, events.TypeStepStarted, "running", step.Description, stepDetails(&step))
+		if err := mlo.executeStep(ctx, plan.ID, &step); err != nil {
+			// A step stopped by cancellation has not failed; the caller decides on rollback
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				mlo.publish(plan.ID, events.TypeStepCompleted, "cancelled", err.Error(), stepDetails(&step))
+				return mlo.cancelledResult(plan, startTime, executedSteps, ctxErr), fmt.Errorf("plan execution cancelled: %w", ctxErr)
+			}
+
 			mlo.log.WithError(err).WithField("step", step.Order).Error("Step execution failed")
+			mlo.publish(plan.ID, events.TypeStepCompleted, "failed", err.Error(), stepDetails(&step))
 
 			// For non-required steps, log warning but continue
 			if !step.Required {
@@ -85,7 +126,7 @@ func (mlo *MultiLayerOrchestrator) ExecutePlan(ctx context.Context, plan *models
 			// Rollback executed steps
 			plan.MarkFailed()
 			rollbackStart := time.Now()
-			if err := mlo.rollbackSteps(ctx, executedSteps); err != nil {
+			if err := mlo.rollbackSteps(ctx, plan.ID, "step_failed", executedSteps); err != nil {
 				mlo.log.WithError(err).Error("Rollback failed")
 				plan.MarkRolledBack()
 			}
@@ -98,16 +139,18 @@ func (mlo *MultiLayerOrchestrator) ExecutePlan(ctx context.Context, plan *models
 
 			failedStep := i
 			return &ExecutionResult{
-				Status:        "failed",
-				Reason:        err.Error(),
-				ExecutedSteps: len(executedSteps),
-				FailedStep:    &failedStep,
-				CompletedAt:   time.Now(),
+				Status:         "failed",
+				Reason:         err.Error(),
+				ExecutedSteps:  len(executedSteps),
+				FailedStep:     &failedStep,
+				CompletedAt:    time.Now(),
+				ExecutedOrders: stepOrders(executedSteps),
 			}, err
 		}
 
 		executedSteps = append(executedSteps, step)
 		plan.AdvanceStep()
+		mlo.publish(plan.ID, events.TypeStepCompleted, "completed", step.Description, stepDetails(&step))
 
 		// Wait for step to settle
 		if step.WaitTime > 0 {
@@ -115,16 +158,7 @@ func (mlo *MultiLayerOrchestrator) ExecutePlan(ctx context.Context, plan *models
 			select {
 			case <-time.After(step.WaitTime):
 			case <-ctx.Done():
-				// Record plan execution cancellation
-				duration := time.Since(startTime).Seconds()
-				RecordPlanExecutionEnd("cancelled", len(plan.Layers), duration)
-
-				return &ExecutionResult{
-					Status:        "failed",
-					Reason:        "context cancelled",
-					ExecutedSteps: len(executedSteps),
-					CompletedAt:   time.Now(),
-				}, fmt.Errorf("plan execution cancelled: %w", ctx.Err())
+				return mlo.cancelledResult(plan, startTime, executedSteps, ctx.Err()), fmt.Errorf("plan execution cancelled: %w", ctx.Err())
 			}
 		}
 
@@ -137,9 +171,13 @@ func (mlo *MultiLayerOrchestrator) ExecutePlan(ctx context.Context, plan *models
 
 			checkpointStart := time.Now()
 			if err := mlo.verifyCheckpoint(ctx, checkpoint); err != nil {
+				if ctxErr := ctx.Err(); ctxErr != nil {
+					return mlo.cancelledResult(plan, startTime, executedSteps, ctxErr), fmt.Errorf("plan execution cancelled: %w", ctxErr)
+				}
 				checkpointDuration := time.Since(checkpointStart).Seconds()
 				RecordHealthCheckpoint(checkpoint.Layer, checkpointDuration, false)
 				mlo.log.WithError(err).Error("Health checkpoint failed")
+				mlo.publish(plan.ID, events.TypeHealthCheckpoint, "failed", err.Error(), checkpointDetails(checkpoint))
 
 				// For non-required checkpoints, log warning but continue
 				if !checkpoint.Required {
@@ -150,7 +188,7 @@ func (mlo *MultiLayerOrchestrator) ExecutePlan(ctx context.Context, plan *models
 				// Rollback executed steps
 				plan.MarkFailed()
 				rollbackStart := time.Now()
-				if err := mlo.rollbackSteps(ctx, executedSteps); err != nil {
+				if err := mlo.rollbackSteps(ctx, plan.ID, "checkpoint_failed", executedSteps); err != nil {
 					mlo.log.WithError(err).Error("Rollback failed")
 					plan.MarkRolledBack()
 				}
@@ -163,17 +201,19 @@ func (mlo *MultiLayerOrchestrator) ExecutePlan(ctx context.Context, plan *models
 
 				failedStep := i
 				return &ExecutionResult{
-					Status:        "failed",
-					Reason:        fmt.Sprintf("checkpoint failed: %v", err),
-					ExecutedSteps: len(executedSteps),
-					FailedStep:    &failedStep,
-					CompletedAt:   time.Now(),
+					Status:         "failed",
+					Reason:         fmt.Sprintf("checkpoint failed: %v", err),
+					ExecutedSteps:  len(executedSteps),
+					FailedStep:     &failedStep,
+					CompletedAt:    time.Now(),
+					ExecutedOrders: stepOrders(executedSteps),
 				}, err
 			}
 
 			// Record successful checkpoint
 			checkpointDuration := time.Since(checkpointStart).Seconds()
 			RecordHealthCheckpoint(checkpoint.Layer, checkpointDuration, true)
+			mlo.publish(plan.ID, events.TypeHealthCheckpoint, "passed", "", checkpointDetails(checkpoint))
 		}
 	}
 
@@ -185,14 +225,68 @@ func (mlo *MultiLayerOrchestrator) ExecutePlan(ctx context.Context, plan *models
 	RecordPlanExecutionEnd("success", len(plan.Layers), duration)
 
 	return &ExecutionResult{
-		Status:        "success",
-		ExecutedSteps: len(executedSteps),
-		CompletedAt:   time.Now(),
+		Status:         "success",
+		ExecutedSteps:  len(executedSteps),
+		CompletedAt:    time.Now(),
+		ExecutedOrders: stepOrders(executedSteps),
 	}, nil
 }
 
+// cancelledResult marks the plan cancelled and records the cancellation
+func (mlo *MultiLayerOrchestrator) cancelledResult(plan *models.RemediationPlan, startTime time.Time, executedSteps []models.RemediationStep, cause error) *ExecutionResult {
+	mlo.log.WithFields(logrus.Fields{
+		"plan_id":        plan.ID,
+		"executed_steps": len(executedSteps),
+	}).Warn("Multi-layer remediation plan cancelled")
+
+	plan.MarkCancelled()
+	duration := time.Since(startTime).Seconds()
+	RecordPlanExecutionEnd("cancelled", len(plan.Layers), duration)
+
+	return &ExecutionResult{
+		Status:         "cancelled",
+		Reason:         cause.Error(),
+		ExecutedSteps:  len(executedSteps),
+		CompletedAt:    time.Now(),
+		ExecutedOrders: stepOrders(executedSteps),
+	}
+}
+
+// RollbackExecutedSteps rolls back the steps that succeeded before a plan stopped,
+// as recorded in its execution result. It is used when an operator cancels a plan
+// and asks for its changes to be reverted.
+func (mlo *MultiLayerOrchestrator) RollbackExecutedSteps(ctx context.Context, plan *models.RemediationPlan, result *ExecutionResult) error {
+	steps := make([]models.RemediationStep, 0, len(result.ExecutedOrders))
+	for _, order := range result.ExecutedOrders {
+		for i := range plan.Steps {
+			if plan.Steps[i].Order == order {
+				steps = append(steps, plan.Steps[i])
+				break
+			}
+		}
+	}
+
+	rollbackStart := time.Now()
+	if err := mlo.rollbackSteps(ctx, plan.ID, "cancelled", steps); err != nil {
+		return fmt.Errorf("rollback failed: %w", err)
+	}
+	RecordRollback("cancelled", len(steps), time.Since(rollbackStart).Seconds())
+
+	plan.MarkRolledBack()
+	return nil
+}
+
+// stepOrders returns the orders of steps
+func stepOrders(steps []models.RemediationStep) []int {
+	orders := make([]int, 0, len(steps))
+	for i := range steps {
+		orders = append(orders, steps[i].Order)
+	}
+	return orders
+}
+
 // executeStep performs a single remediation action
-func (mlo *MultiLayerOrchestrator) executeStep(ctx context.Context, step *models.RemediationStep) error {
+func (mlo *MultiLayerOrchestrator) executeStep(ctx context.Context, planID string, step *models.RemediationStep) error {
 	mlo.log.WithFields(logrus.Fields{
 		"action": step.ActionType,
 		"target": step.Target,
@@ -205,7 +299,7 @@ func (mlo *MultiLayerOrchestrator) executeStep(ctx context.Context, step *models
 	case models.LayerPlatform:
 		return mlo.executePlatformStep(ctx, step)
 	case models.LayerApplication:
-		return mlo.executeApplicationStep(ctx, step)
+		return mlo.executeApplicationStep(ctx, planID, step)
 	default:
 		return fmt.Errorf("unknown layer: %s", step.Layer)
 	}
@@ -263,7 +357,7 @@ func (mlo *MultiLayerOrchestrator) executePlatformStep(ctx context.Context, step
 }
 
 // executeApplicationStep executes application layer remediation using remediators
-func (mlo *MultiLayerOrchestrator) executeApplicationStep(ctx context.Context, step *models.RemediationStep) error {
+func (mlo *MultiLayerOrchestrator) executeApplicationStep(ctx context.Context, planID string, step *models.RemediationStep) error {
 	mlo.log.WithFields(logrus.Fields{
 		"action": step.ActionType,
 		"target": step.Target,
@@ -275,22 +369,12 @@ func (mlo *MultiLayerOrchestrator) executeApplicationStep(ctx context.Context, s
 		return fmt.Errorf("invalid target format: %w", err)
 	}
 
-	// Determine resource kind from metadata
-	resourceKind := step.Metadata["deployment"]
-	if resourceKind == "" {
-		resourceKind = step.Metadata["statefulset"]
-	}
-	if resourceKind == "" {
-		resourceKind = step.Metadata["pod"]
-	}
-	if resourceKind == "" {
-		resourceKind = "Deployment" // Default assumption
-	}
+	resourceKind := stepResourceKind(step)
 
 	// Create issue for remediation
 	issue := &models.Issue{
 		ID:           fmt.Sprintf("step-%d", step.Order),
-		Type:         mapActionTypeToIssueType(step.ActionType),
+		Type:         mlo.policy.StepIssueType(step.ActionType),
 		Description:  step.Description,
 		Namespace:    namespace,
 		ResourceName: resourceName,
@@ -319,12 +403,68 @@ func (mlo *MultiLayerOrchestrator) executeApplicationStep(ctx context.Context, s
 		"deployment_method": deploymentInfo.Method,
 	}).Info("Executing application remediation")
 
-	if err := mlo.strategySelector.Remediate(ctx, deploymentInfo, issue); err != nil {
+	// Refuse before anything is mutated; the selector checks the remediator's planned actions again
+	if err := mlo.guardrails.Check(ctx, namespace, stepResourceKind(step), resourceName, []string{step.ActionType}); err != nil {
+		mlo.log.WithError(err).WithFields(logrus.Fields{
+			"plan_id": planID,
+			"step":    step.Order,
+		}).Warn("Application step refused by guardrails")
+		return fmt.Errorf("application step refused: %w", err)
+	}
+
+	// Serialize mutations of the target with single-resource workflows
+	if mlo.locks != nil {
+		ref := remediation.ResourceRef{Namespace: namespace, Kind: stepResourceKind(step), Name: resourceName}
+		lease, err := mlo.locks.Acquire(ctx, ref, fmt.Sprintf("%s/step-%d", planID, step.Order))
+		if err != nil {
+			return fmt.Errorf("failed to acquire lock on %s: %w", ref, err)
+		}
+		defer lease.Release()
+
+		if lease.Contended && step.Metadata != nil {
+			step.Metadata["lock_waited"] = lease.Waited.Round(time.Millisecond).String()
+		}
+	}
+
+	result, err := mlo.strategySelector.Remediate(ctx, deploymentInfo, issue)
+	if result != nil && step.Metadata != nil {
+		step.Metadata["remediation_status"] = string(result.Status)
+		if result.ErrorClass != "" {
+			step.Metadata["error_class"] = string(result.ErrorClass)
+		}
+	}
+	if err != nil {
 		return fmt.Errorf("application remediation failed: %w", err)
 	}
 	return nil
 }
 
+// stepActionKinds maps application step actions to the kind of resource they act on
+var stepActionKinds = map[string]string{
+	"restart_pod":              "Pod",
+	"restart_deployment":       "Deployment",
+	"restart_statefulset":      "StatefulSet",
+	"restart_daemonset":        "DaemonSet",
+	"restart_deploymentconfig": "DeploymentConfig",
+	"restart_rollout":          "Rollout",
+}
+
+// stepResourceKind returns the Kubernetes kind targeted by an application step,
+// from its action or else from its metadata
+func stepResourceKind(step *models.RemediationStep) string {
+	if kind, ok := stepActionKinds[step.ActionType]; ok {
+		return kind
+	}
+	switch {
+	case step.Metadata["statefulset"] != "":
+		return "StatefulSet"
+	case step.Metadata["pod"] != "":
+		return "Pod"
+	default:
+		return "Deployment"
+	}
+}
+
 // parseTarget parses "namespace/name" format
 func parseTarget(target string) (namespace, name string, err error) {
 	parts := splitTarget(target)
@@ -354,20 +494,6 @@ func splitTarget(target string) []string {
 	return result
 }
 
-// mapActionTypeToIssueType maps remediation step action types to issue types
-func mapActionTypeToIssueType(actionType string) string {
-	switch actionType {
-	case "restart_pod":
-		return "pod_crash_loop"
-	case "restart_deployment":
-		return "deployment_not_ready"
-	case "restart_statefulset":
-		return "statefulset_not_ready"
-	default:
-		return "generic_issue"
-	}
-}
-
 // verifyCheckpoint checks health conditions for a layer
 func (mlo *MultiLayerOrchestrator) verifyCheckpoint(ctx context.Context, checkpoint *models.HealthCheckpoint) error {
 	timeoutCtx, cancel := context.WithTimeout(ctx, checkpoint.Timeout)
@@ -388,8 +514,10 @@ func (mlo *MultiLayerOrchestrator) verifyCheckpoint(ctx context.Context, checkpo
 // rollbackSteps executes rollback in reverse order
 //
 //nolint:unparam // error return kept for future error aggregation
-func (mlo *MultiLayerOrchestrator) rollbackSteps(ctx context.Context, steps []models.RemediationStep) error {
+func (mlo *MultiLayerOrchestrator) rollbackSteps(ctx context.Context, planID, reason string, steps []models.RemediationStep) error {
 	mlo.log.WithField("steps", len(steps)).Warn("Starting coordinated rollback")
+	details := map[string]string{"reason": reason, "steps": strconv.Itoa(len(steps))}
+	mlo.publish(planID, events.TypeRollback, "started", "", details)
 
 	for i := len(steps) - 1; i >= 0; i-- {
 		step := steps[i]
@@ -410,9 +538,40 @@ func (mlo *MultiLayerOrchestrator) rollbackSteps(ctx context.Context, steps []mo
 	}
 
 	mlo.log.Info("Coordinated rollback completed")
+	mlo.publish(planID, events.TypeRollback, "completed", "", details)
 	return nil
 }
 
+// publish sends a plan progress event to the event bus
+func (mlo *MultiLayerOrchestrator) publish(planID string, eventType events.Type, status, message string, details map[string]string) {
+	mlo.events.Publish(events.Event{
+		Type:    eventType,
+		Source:  events.SourceCoordination,
+		PlanID:  planID,
+		Status:  status,
+		Message: message,
+		Details: details,
+	})
+}
+
+// stepDetails describes a plan step in event details
+func stepDetails(step *models.RemediationStep) map[string]string {
+	return map[string]string{
+		"order":       strconv.Itoa(step.Order),
+		"layer":       string(step.Layer),
+		"action_type": step.ActionType,
+		"target":      step.Target,
+	}
+}
+
+// checkpointDetails describes a health checkpoint in event details
+func checkpointDetails(checkpoint *models.HealthCheckpoint) map[string]string {
+	return map[string]string{
+		"layer":      string(checkpoint.Layer),
+		"after_step": strconv.Itoa(checkpoint.AfterStep),
+	}
+}
+
 // executeRollback performs rollback for a single step
 func (mlo *MultiLayerOrchestrator) executeRollback(ctx context.Context, step *models.RemediationStep) error {
 	mlo.log.WithFields(logrus.Fields{
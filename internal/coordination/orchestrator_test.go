@@ -0,0 +1,234 @@
+package coordination
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/detector"
+	"github.com/tosin2013/openshift-coordination-engine/internal/events"
+	"github.com/tosin2013/openshift-coordination-engine/internal/remediation"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// stepRemediator fails remediation of "broken", blocks on "slow" until cancelled
+// and succeeds on anything else
+type stepRemediator struct {
+	started chan string
+}
+
+func newStepRemediator() *stepRemediator {
+	return &stepRemediator{started: make(chan string, 10)}
+}
+
+func (s *stepRemediator) Remediate(ctx context.Context, _ *models.DeploymentInfo, issue *models.Issue) (*models.RemediationResult, error) {
+	s.started <- issue.ResourceName
+	switch issue.ResourceName {
+	case "broken":
+		return nil, errors.New("restart failed")
+	case "slow":
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	return models.NewRemediationResult("step"), nil
+}
+
+func (s *stepRemediator) PlanActions(_ context.Context, _ *models.DeploymentInfo, _ *models.Issue) ([]remediation.PlannedAction, error) {
+	return nil, nil
+}
+
+func (s *stepRemediator) CanRemediate(_ *models.DeploymentInfo) bool { return true }
+
+func (s *stepRemediator) Name() string { return "step" }
+
+func newTestMultiLayerOrchestrator(remediator remediation.Remediator) *MultiLayerOrchestrator {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	clientset := k8sfake.NewSimpleClientset()
+	return NewMultiLayerOrchestrator(NewHealthChecker(clientset, nil, log), detector.NewDetector(clientset, log), remediator, clientset, log)
+}
+
+// newTestPlan returns a plan restarting a deployment per name, with steps of names
+// prefixed "optional-" not required
+func newTestPlan(namespace string, names ...string) *models.RemediationPlan {
+	plan := models.NewRemediationPlan("issue-1", []models.Layer{models.LayerApplication})
+	for _, name := range names {
+		required := true
+		if trimmed, ok := strings.CutPrefix(name, "optional-"); ok {
+			name, required = trimmed, false
+		}
+		plan.AddStep(&models.RemediationStep{
+			Layer:      models.LayerApplication,
+			ActionType: "restart_deployment",
+			Target:     namespace + "/" + name,
+			Required:   required,
+			Metadata:   map[string]string{"deployment": name},
+		})
+	}
+	return plan
+}
+
+func TestExecutePlan_CancelDuringStep(t *testing.T) {
+	remediator := newStepRemediator()
+	mlo := newTestMultiLayerOrchestrator(remediator)
+	bus := events.NewBus(mlo.log)
+	mlo.SetEventBus(bus)
+	sub := bus.Subscribe(func(event events.Event) bool { return event.Type == events.TypeRollback })
+	defer sub.Close()
+
+	plan := newTestPlan("default", "payment", "slow", "cart")
+	ctx, cancel := context.WithCancel(context.Background())
+	type outcome struct {
+		result *ExecutionResult
+		err    error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		result, err := mlo.ExecutePlan(ctx, plan)
+		done <- outcome{result, err}
+	}()
+
+	assert.Equal(t, "payment", <-remediator.started)
+	assert.Equal(t, "slow", <-remediator.started)
+	cancel()
+
+	out := <-done
+	require.ErrorIs(t, out.err, context.Canceled)
+	assert.Equal(t, "cancelled", out.result.Status)
+	assert.Nil(t, out.result.FailedStep)
+	assert.Equal(t, []int{1}, out.result.ExecutedOrders)
+	assert.Equal(t, "cancelled", plan.Status)
+	assert.Empty(t, remediator.started, "no step runs after cancellation")
+
+	// The caller decides whether to roll back
+	select {
+	case event := <-sub.C:
+		t.Fatalf("unexpected rollback event %+v", event)
+	default:
+	}
+	require.NoError(t, mlo.RollbackExecutedSteps(context.Background(), plan, out.result))
+	assert.Equal(t, "rolled_back", plan.Status)
+	event := <-sub.C
+	assert.Equal(t, "cancelled", event.Details["reason"])
+	assert.Equal(t, "1", event.Details["steps"])
+}
+
+func TestExecutePlan_RecordsExecutedSteps(t *testing.T) {
+	tests := []struct {
+		name          string
+		steps         []string
+		wantStatus    string
+		wantExecuted  []int
+		wantFailed    *int
+		wantPlanState string
+	}{
+		{
+			name:          "all steps succeed",
+			steps:         []string{"payment", "cart"},
+			wantStatus:    "success",
+			wantExecuted:  []int{1, 2},
+			wantPlanState: "completed",
+		},
+		{
+			name:          "failed optional step is skipped",
+			steps:         []string{"payment", "optional-broken", "cart"},
+			wantStatus:    "success",
+			wantExecuted:  []int{1, 3},
+			wantPlanState: "completed",
+		},
+		{
+			name:          "failed required step stops the plan",
+			steps:         []string{"payment", "optional-broken", "broken", "cart"},
+			wantStatus:    "failed",
+			wantExecuted:  []int{1},
+			wantFailed:    intPtr(2),
+			wantPlanState: "failed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mlo := newTestMultiLayerOrchestrator(newStepRemediator())
+			plan := newTestPlan("default", tt.steps...)
+
+			result, err := mlo.ExecutePlan(context.Background(), plan)
+			assert.Equal(t, tt.wantStatus == "failed", err != nil)
+			assert.Equal(t, tt.wantStatus, result.Status)
+			assert.Equal(t, tt.wantExecuted, result.ExecutedOrders)
+			assert.Equal(t, len(tt.wantExecuted), result.ExecutedSteps)
+			assert.Equal(t, tt.wantFailed, result.FailedStep)
+			assert.Equal(t, tt.wantPlanState, plan.Status)
+		})
+	}
+}
+
+func TestRollbackExecutedSteps_OnlyExecuted(t *testing.T) {
+	mlo := newTestMultiLayerOrchestrator(newStepRemediator())
+	bus := events.NewBus(mlo.log)
+	mlo.SetEventBus(bus)
+	sub := bus.Subscribe(func(event events.Event) bool { return event.Type == events.TypeRollback })
+	defer sub.Close()
+
+	// The optional step that failed is not rolled back; the later step that succeeded is
+	plan := newTestPlan("default", "optional-broken", "cart")
+	result, err := mlo.ExecutePlan(context.Background(), plan)
+	require.NoError(t, err)
+	require.Equal(t, []int{2}, result.ExecutedOrders)
+
+	require.NoError(t, mlo.RollbackExecutedSteps(context.Background(), plan, result))
+	event := <-sub.C
+	assert.Equal(t, "1", event.Details["steps"])
+	assert.Equal(t, "rolled_back", plan.Status)
+}
+
+func TestExecuteApplicationStep_Guardrails(t *testing.T) {
+	remediator := newStepRemediator()
+	mlo := newTestMultiLayerOrchestrator(remediator)
+	mlo.SetGuardrails(remediation.NewGuardrails(nil, []string{"prod-*"}, mlo.clientset, mlo.log))
+
+	plan := newTestPlan("prod-payments", "payment")
+	result, err := mlo.ExecutePlan(context.Background(), plan)
+	require.ErrorIs(t, err, remediation.ErrRemediationRefused)
+	assert.Equal(t, "failed", result.Status)
+	assert.Empty(t, remediator.started, "a refused step is never remediated")
+}
+
+func TestExecuteApplicationStep_WaitsForLock(t *testing.T) {
+	remediator := newStepRemediator()
+	mlo := newTestMultiLayerOrchestrator(remediator)
+	locks := remediation.NewLockManager(mlo.log)
+	mlo.SetLockManager(locks)
+
+	ref := remediation.ResourceRef{Namespace: "default", Kind: "Deployment", Name: "payment"}
+	lease, err := locks.Acquire(context.Background(), ref, "wf-other")
+	require.NoError(t, err)
+
+	plan := newTestPlan("default", "payment")
+	done := make(chan *ExecutionResult, 1)
+	go func() {
+		result, _ := mlo.ExecutePlan(context.Background(), plan)
+		done <- result
+	}()
+
+	select {
+	case name := <-remediator.started:
+		t.Fatalf("%s remediated while another workflow held its lock", name)
+	case <-time.After(100 * time.Millisecond):
+	}
+	lease.Release()
+
+	result := <-done
+	assert.Equal(t, "success", result.Status)
+	assert.Equal(t, "payment", <-remediator.started)
+	assert.NotEmpty(t, plan.Steps[0].Metadata["lock_waited"])
+}
+
+func intPtr(i int) *int {
+	return &i
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/sirupsen/logrus"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 
 	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
@@ -38,9 +39,10 @@ const (
 
 // DeploymentDetector detects the deployment method of Kubernetes resources
 type DeploymentDetector struct {
-	clientset kubernetes.Interface
-	log       *logrus.Logger
-	cache     *deploymentCache
+	clientset     kubernetes.Interface
+	dynamicClient dynamic.Interface // reads DeploymentConfigs and Argo Rollouts; optional
+	log           *logrus.Logger
+	cache         *deploymentCache
 }
 
 // deploymentCache caches deployment detection results to reduce API calls
@@ -25,7 +25,12 @@ func (d *Detector) DetectByKind(ctx context.Context, namespace, name, kind strin
 		return d.DetectStatefulSetMethod(ctx, namespace, name)
 	case "DaemonSet":
 		return d.DetectDaemonSetMethod(ctx, namespace, name)
-	case "Deployment", "Pod", "":
+	case "Pod", "ReplicaSet", "Job", "ReplicationController":
+		// Detect the top-level controller that owns the resource
+		return d.DetectThroughOwners(ctx, namespace, name, kind)
+	case "CronJob", "DeploymentConfig", "Rollout":
+		return d.detectResourceMethod(ctx, namespace, OwnerRef{Kind: kind, Name: name})
+	case "Deployment", "":
 		// Default to deployment detection
 		return d.DetectDeploymentMethod(ctx, namespace, name)
 	default:
@@ -0,0 +1,217 @@
+package detector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// DeploymentInfo details recorded when detection resolved an owner chain
+const (
+	DetailOwnerChain     = "owner_chain"     // e.g. Pod/payment-7d9f-abc12 > ReplicaSet/payment-7d9f > Deployment/payment
+	DetailControllerKind = "controller_kind" // kind of the top-level controller
+	DetailControllerName = "controller_name" // name of the top-level controller
+)
+
+// maxOwnerDepth bounds the walk in case of an ownership cycle
+const maxOwnerDepth = 8
+
+// OwnerRef is one resource in an owner chain
+type OwnerRef struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+}
+
+// String returns Kind/Name
+func (r OwnerRef) String() string {
+	return r.Kind + "/" + r.Name
+}
+
+// OwnerChain lists a resource followed by its controllers, ending with the
+// top-level controller
+type OwnerChain []OwnerRef
+
+// Top returns the top-level controller, or the resource itself if it has none
+func (c OwnerChain) Top() OwnerRef {
+	return c[len(c)-1]
+}
+
+// String formats the chain as Pod/a > ReplicaSet/b > Deployment/c
+func (c OwnerChain) String() string {
+	parts := make([]string, len(c))
+	for i, ref := range c {
+		parts[i] = ref.String()
+	}
+	return strings.Join(parts, " > ")
+}
+
+// ResolveOwnerChain follows controller ownerReferences from a resource to its
+// top-level controller:
+//   - Pod → ReplicaSet → Deployment (or Argo Rollout)
+//   - Pod → StatefulSet
+//   - Pod → DaemonSet
+//   - Pod → Job → CronJob
+//   - Pod → ReplicationController → DeploymentConfig
+//
+// Only pods, ReplicaSets, Jobs and ReplicationControllers are looked up; any other
+// owner ends the chain. An owner that no longer exists also ends it. An owner the
+// engine may not read leaves the chain at the resource itself.
+func ResolveOwnerChain(ctx context.Context, clientset kubernetes.Interface, namespace, kind, name string) (OwnerChain, error) {
+	chain := OwnerChain{{Kind: kind, Name: name}}
+	for len(chain) < maxOwnerDepth {
+		current := chain.Top()
+		meta, err := getOwnedResource(ctx, clientset, namespace, current)
+		switch {
+		case err != nil && len(chain) > 1 && apierrors.IsNotFound(err):
+			return chain, nil
+		case err != nil && len(chain) > 1 && apierrors.IsForbidden(err):
+			return chain[:1], nil
+		case err != nil:
+			return nil, fmt.Errorf("failed to get %s %s/%s: %w", strings.ToLower(current.Kind), namespace, current.Name, err)
+		case meta == nil:
+			return chain, nil
+		}
+
+		owner := metav1.GetControllerOf(meta)
+		if owner == nil {
+			return chain, nil
+		}
+		chain = append(chain, OwnerRef{Kind: owner.Kind, Name: owner.Name})
+	}
+	return chain, nil
+}
+
+// getOwnedResource returns the metadata of a resource kind that is usually owned
+// by a controller, or nil for kinds that end the chain
+func getOwnedResource(ctx context.Context, clientset kubernetes.Interface, namespace string, ref OwnerRef) (metav1.Object, error) {
+	switch ref.Kind {
+	case "Pod":
+		return clientset.CoreV1().Pods(namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+	case "ReplicaSet":
+		return clientset.AppsV1().ReplicaSets(namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+	case "Job":
+		return clientset.BatchV1().Jobs(namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+	case "ReplicationController":
+		return clientset.CoreV1().ReplicationControllers(namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+	default:
+		return nil, nil
+	}
+}
+
+// Controllers outside the core API, read through the dynamic client
+var (
+	DeploymentConfigGVR = schema.GroupVersionResource{Group: "apps.openshift.io", Version: "v1", Resource: "deploymentconfigs"}
+	RolloutGVR          = schema.GroupVersionResource{Group: "argoproj.io", Version: "v1alpha1", Resource: "rollouts"}
+)
+
+// SetDynamicClient enables detection of DeploymentConfigs and Argo Rollouts
+func (d *DeploymentDetector) SetDynamicClient(client dynamic.Interface) {
+	d.dynamicClient = client
+}
+
+// DetectThroughOwners resolves the owner chain of a pod or other owned resource and
+// detects the deployment method of its top-level controller. The chain is recorded
+// in the returned info's details. A controller the engine may not read is skipped
+// and the method detected from the resource itself.
+func (d *DeploymentDetector) DetectThroughOwners(ctx context.Context, namespace, name, kind string) (*models.DeploymentInfo, error) {
+	chain, err := ResolveOwnerChain(ctx, d.clientset, namespace, kind, name)
+	if err != nil {
+		RecordDetectionError("owner_resolution", kind)
+		return nil, err
+	}
+
+	top := chain.Top()
+	info, err := d.detectControllerMethod(ctx, namespace, top)
+	if apierrors.IsForbidden(err) && len(chain) > 1 {
+		d.log.WithFields(map[string]interface{}{
+			"namespace":  namespace,
+			"controller": top.String(),
+		}).WithError(err).Warn("Not allowed to read controller, detecting deployment method from the resource itself")
+		chain = chain[:1]
+		top = chain.Top()
+		info, err = d.detectControllerMethod(ctx, namespace, top)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	d.log.WithFields(map[string]interface{}{
+		"namespace":   namespace,
+		"resource":    name,
+		"owner_chain": chain.String(),
+		"method":      info.Method,
+	}).Debug("Detected deployment method through owner chain")
+
+	// The detected info may be cached and shared, so record the chain on a copy
+	resolved := *info
+	resolved.Details = make(map[string]string, len(info.Details)+3)
+	for key, value := range info.Details {
+		resolved.Details[key] = value
+	}
+	resolved.SetDetail(DetailOwnerChain, chain.String())
+	resolved.SetDetail(DetailControllerKind, top.Kind)
+	resolved.SetDetail(DetailControllerName, top.Name)
+	return &resolved, nil
+}
+
+// detectControllerMethod detects the deployment method of the top of an owner chain
+func (d *DeploymentDetector) detectControllerMethod(ctx context.Context, namespace string, top OwnerRef) (*models.DeploymentInfo, error) {
+	switch top.Kind {
+	case "Deployment":
+		return d.DetectDeploymentMethod(ctx, namespace, top.Name)
+	case "StatefulSet":
+		return d.DetectStatefulSetMethod(ctx, namespace, top.Name)
+	case "DaemonSet":
+		return d.DetectDaemonSetMethod(ctx, namespace, top.Name)
+	default:
+		return d.detectResourceMethod(ctx, namespace, top)
+	}
+}
+
+// detectResourceMethod detects the deployment method of a controller without a
+// dedicated detection method, such as a CronJob or DeploymentConfig
+func (d *DeploymentDetector) detectResourceMethod(ctx context.Context, namespace string, ref OwnerRef) (*models.DeploymentInfo, error) {
+	cacheKey := fmt.Sprintf("%s/%s/%s", strings.ToLower(ref.Kind), namespace, ref.Name)
+	if info := d.cache.get(cacheKey); info != nil {
+		return info, nil
+	}
+
+	var meta metav1.Object
+	var err error
+	switch ref.Kind {
+	case "CronJob":
+		meta, err = d.clientset.BatchV1().CronJobs(namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+	case "DeploymentConfig", "Rollout":
+		if d.dynamicClient == nil {
+			return nil, fmt.Errorf("cannot detect %s %s/%s without a dynamic client", ref.Kind, namespace, ref.Name)
+		}
+		gvr := DeploymentConfigGVR
+		if ref.Kind == "Rollout" {
+			gvr = RolloutGVR
+		}
+		meta, err = d.dynamicClient.Resource(gvr).Namespace(namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+	default:
+		// The resource has no controller, e.g. a bare pod or Job
+		meta, err = getOwnedResource(ctx, d.clientset, namespace, ref)
+		if err == nil && meta == nil {
+			err = fmt.Errorf("unsupported resource kind %s", ref.Kind)
+		}
+	}
+	if err != nil {
+		RecordDetectionError("api_error", ref.Kind)
+		return nil, fmt.Errorf("failed to get %s %s/%s: %w", strings.ToLower(ref.Kind), namespace, ref.Name, err)
+	}
+
+	info := d.detectFromMetadata(meta.GetAnnotations(), meta.GetLabels(), namespace, ref.Name, ref.Kind)
+	d.cache.set(cacheKey, info)
+	RecordDetection(string(info.Method), info.Source, ref.Kind, info.Confidence, false)
+	return info, nil
+}
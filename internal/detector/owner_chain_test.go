@@ -0,0 +1,155 @@
+package detector
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// ownedBy returns object metadata with a controller owner reference
+func ownedBy(name, ownerKind, ownerName string) metav1.ObjectMeta {
+	isController := true
+	return metav1.ObjectMeta{
+		Name:            name,
+		Namespace:       "default",
+		OwnerReferences: []metav1.OwnerReference{{Kind: ownerKind, Name: ownerName, Controller: &isController}},
+	}
+}
+
+func TestResolveOwnerChain(t *testing.T) {
+	clientset := fake.NewSimpleClientset(
+		&corev1.Pod{ObjectMeta: ownedBy("payment-7d9f-abc12", "ReplicaSet", "payment-7d9f")},
+		&appsv1.ReplicaSet{ObjectMeta: ownedBy("payment-7d9f", "Deployment", "payment")},
+		&corev1.Pod{ObjectMeta: ownedBy("db-0", "StatefulSet", "db")},
+		&corev1.Pod{ObjectMeta: ownedBy("report-28391-xk2p9", "Job", "report-28391")},
+		&batchv1.Job{ObjectMeta: ownedBy("report-28391", "CronJob", "report")},
+		&corev1.Pod{ObjectMeta: ownedBy("legacy-3-x7k2p", "ReplicationController", "legacy-3")},
+		&corev1.ReplicationController{ObjectMeta: ownedBy("legacy-3", "DeploymentConfig", "legacy")},
+		&corev1.Pod{ObjectMeta: ownedBy("orphan-abc12", "ReplicaSet", "orphan")},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "debug", Namespace: "default"}},
+	)
+
+	tests := []struct {
+		pod  string
+		want string
+	}{
+		{"payment-7d9f-abc12", "Pod/payment-7d9f-abc12 > ReplicaSet/payment-7d9f > Deployment/payment"},
+		{"db-0", "Pod/db-0 > StatefulSet/db"},
+		{"report-28391-xk2p9", "Pod/report-28391-xk2p9 > Job/report-28391 > CronJob/report"},
+		{"legacy-3-x7k2p", "Pod/legacy-3-x7k2p > ReplicationController/legacy-3 > DeploymentConfig/legacy"},
+		{"orphan-abc12", "Pod/orphan-abc12 > ReplicaSet/orphan"}, // the ReplicaSet is gone
+		{"debug", "Pod/debug"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pod, func(t *testing.T) {
+			chain, err := ResolveOwnerChain(context.Background(), clientset, "default", "Pod", tt.pod)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, chain.String())
+		})
+	}
+
+	_, err := ResolveOwnerChain(context.Background(), clientset, "default", "Pod", "missing")
+	assert.Error(t, err)
+}
+
+func TestDetectByKind_Pod(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+		Name: "payment", Namespace: "default", Annotations: map[string]string{ArgoCDTrackingAnnotation: "payment:apps/Deployment:default/payment"},
+	}}
+	clientset := fake.NewSimpleClientset(
+		deployment,
+		&appsv1.ReplicaSet{ObjectMeta: ownedBy("payment-7d9f", "Deployment", "payment")},
+		&corev1.Pod{ObjectMeta: ownedBy("payment-7d9f-abc12", "ReplicaSet", "payment-7d9f")},
+		&corev1.Pod{ObjectMeta: ownedBy("legacy-3-x7k2p", "ReplicationController", "legacy-3")},
+		&corev1.ReplicationController{ObjectMeta: ownedBy("legacy-3", "DeploymentConfig", "legacy")},
+	)
+	detector := NewDetector(clientset, log)
+
+	info, err := detector.DetectByKind(context.Background(), "default", "payment-7d9f-abc12", "Pod")
+	require.NoError(t, err)
+	assert.Equal(t, models.DeploymentMethodArgoCD, info.Method)
+	assert.Equal(t, "Deployment", info.ResourceKind)
+	assert.Equal(t, "payment", info.ResourceName)
+	assert.Equal(t, "Pod/payment-7d9f-abc12 > ReplicaSet/payment-7d9f > Deployment/payment", info.GetDetail(DetailOwnerChain))
+	assert.Equal(t, "Deployment", info.GetDetail(DetailControllerKind))
+	assert.Equal(t, "payment", info.GetDetail(DetailControllerName))
+
+	// The chain is not recorded on the cached deployment result
+	cached, err := detector.DetectDeploymentMethod(context.Background(), "default", "payment")
+	require.NoError(t, err)
+	assert.Empty(t, cached.GetDetail(DetailOwnerChain))
+
+	// DeploymentConfigs need the dynamic client
+	_, err = detector.DetectByKind(context.Background(), "default", "legacy-3-x7k2p", "Pod")
+	assert.Error(t, err)
+
+	dc := &unstructured.Unstructured{}
+	dc.SetAPIVersion("apps.openshift.io/v1")
+	dc.SetKind("DeploymentConfig")
+	dc.SetNamespace("default")
+	dc.SetName("legacy")
+	dc.SetLabels(map[string]string{ManagedByLabel: "Helm"})
+	dc.SetAnnotations(map[string]string{HelmReleaseNameAnnotation: "legacy"})
+	detector.SetDynamicClient(dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), dc))
+
+	info, err = detector.DetectByKind(context.Background(), "default", "legacy-3-x7k2p", "Pod")
+	require.NoError(t, err)
+	assert.Equal(t, models.DeploymentMethodHelm, info.Method)
+	assert.Equal(t, "DeploymentConfig", info.GetDetail(DetailControllerKind))
+	assert.Equal(t, "legacy", info.GetDetail(DetailControllerName))
+}
+
+func TestDetectByKind_ForbiddenController(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	forbid := func(resource string) k8stesting.ReactionFunc {
+		return func(action k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, apierrors.NewForbidden(schema.GroupResource{Resource: resource}, "", errors.New("no RBAC rule"))
+		}
+	}
+
+	pod := &corev1.Pod{ObjectMeta: ownedBy("legacy-3-x7k2p", "ReplicationController", "legacy-3")}
+	pod.Labels = map[string]string{ManagedByLabel: "Helm"}
+	pod.Annotations = map[string]string{HelmReleaseNameAnnotation: "legacy"}
+
+	// The ReplicationController may not be read
+	clientset := fake.NewSimpleClientset(pod, &corev1.ReplicationController{ObjectMeta: ownedBy("legacy-3", "DeploymentConfig", "legacy")})
+	clientset.PrependReactor("get", "replicationcontrollers", forbid("replicationcontrollers"))
+	chain, err := ResolveOwnerChain(context.Background(), clientset, "default", "Pod", "legacy-3-x7k2p")
+	require.NoError(t, err)
+	assert.Equal(t, "Pod/legacy-3-x7k2p", chain.String())
+
+	// The DeploymentConfig may not be read
+	clientset = fake.NewSimpleClientset(pod, &corev1.ReplicationController{ObjectMeta: ownedBy("legacy-3", "DeploymentConfig", "legacy")})
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+	dynamicClient.PrependReactor("get", "deploymentconfigs", forbid("deploymentconfigs"))
+	detector := NewDetector(clientset, log)
+	detector.SetDynamicClient(dynamicClient)
+
+	info, err := detector.DetectByKind(context.Background(), "default", "legacy-3-x7k2p", "Pod")
+	require.NoError(t, err)
+	assert.Equal(t, models.DeploymentMethodHelm, info.Method)
+	assert.Equal(t, "Pod", info.GetDetail(DetailControllerKind))
+	assert.Equal(t, "legacy-3-x7k2p", info.GetDetail(DetailControllerName))
+	assert.Equal(t, "Pod/legacy-3-x7k2p", info.GetDetail(DetailOwnerChain))
+}
@@ -0,0 +1,127 @@
+// Package events distributes workflow progress events to subscribers such as API streams.
+package events
+
+import (
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Type identifies the kind of progress event
+type Type string
+
+// Event types
+const (
+	TypeStatusChanged    Type = "status_changed"
+	TypeStepAdded        Type = "step_added"
+	TypeStepStarted      Type = "step_started"
+	TypeStepCompleted    Type = "step_completed"
+	TypeHealthCheckpoint Type = "health_checkpoint"
+	TypeRollback         Type = "rollback"
+)
+
+// Event sources
+const (
+	SourceRemediation  = "remediation"
+	SourceCoordination = "coordination"
+)
+
+// DefaultBufferSize is the number of events buffered per subscriber before events are dropped
+const DefaultBufferSize = 64
+
+// Event is a progress update of a remediation or coordination workflow
+type Event struct {
+	Type       Type              `json:"type"`
+	Source     string            `json:"source"`
+	WorkflowID string            `json:"workflow_id,omitempty"`
+	PlanID     string            `json:"plan_id,omitempty"` // set by the multi-layer orchestrator
+	IncidentID string            `json:"incident_id,omitempty"`
+	Namespace  string            `json:"namespace,omitempty"`
+	Severity   string            `json:"severity,omitempty"`
+	Status     string            `json:"status,omitempty"`
+	Message    string            `json:"message,omitempty"`
+	Details    map[string]string `json:"details,omitempty"`
+	Final      bool              `json:"final,omitempty"` // the workflow reached a terminal status
+	Timestamp  time.Time         `json:"timestamp"`
+}
+
+// Filter selects the events delivered to a subscription
+type Filter func(Event) bool
+
+// Bus fans out published events to subscribers. Publishing never blocks: a
+// subscriber that falls behind loses events rather than stalling workflows.
+type Bus struct {
+	mu          sync.RWMutex
+	subscribers map[*Subscription]struct{}
+	log         *logrus.Logger
+}
+
+// Subscription receives events matching its filter on C until it is closed
+type Subscription struct {
+	C      <-chan Event
+	ch     chan Event
+	filter Filter
+	bus    *Bus
+	once   sync.Once
+}
+
+// NewBus creates an event bus
+func NewBus(log *logrus.Logger) *Bus {
+	return &Bus{
+		subscribers: make(map[*Subscription]struct{}),
+		log:         log,
+	}
+}
+
+// Subscribe registers a subscriber. A nil filter receives every event.
+func (b *Bus) Subscribe(filter Filter) *Subscription {
+	ch := make(chan Event, DefaultBufferSize)
+	sub := &Subscription{C: ch, ch: ch, filter: filter, bus: b}
+
+	b.mu.Lock()
+	b.subscribers[sub] = struct{}{}
+	b.mu.Unlock()
+
+	SubscribersActive.Inc()
+	return sub
+}
+
+// Publish delivers an event to matching subscribers. It is a no-op on a nil bus.
+func (b *Bus) Publish(event Event) {
+	if b == nil {
+		return
+	}
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+	EventsPublished.WithLabelValues(event.Source, string(event.Type)).Inc()
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for sub := range b.subscribers {
+		if sub.filter != nil && !sub.filter(event) {
+			continue
+		}
+		select {
+		case sub.ch <- event:
+		default:
+			EventsDropped.Inc()
+			b.log.WithFields(logrus.Fields{
+				"type":        event.Type,
+				"workflow_id": event.WorkflowID,
+			}).Debug("Dropped event for slow subscriber")
+		}
+	}
+}
+
+// Close unregisters the subscription and closes its channel
+func (s *Subscription) Close() {
+	s.once.Do(func() {
+		s.bus.mu.Lock()
+		delete(s.bus.subscribers, s)
+		s.bus.mu.Unlock()
+		close(s.ch)
+		SubscribersActive.Dec()
+	})
+}
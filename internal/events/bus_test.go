@@ -0,0 +1,57 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestBus() *Bus {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	return NewBus(log)
+}
+
+func TestBus_FiltersEvents(t *testing.T) {
+	bus := newTestBus()
+	sub := bus.Subscribe(func(event Event) bool { return event.WorkflowID == "wf-1" })
+	defer sub.Close()
+
+	bus.Publish(Event{Type: TypeStatusChanged, WorkflowID: "wf-2", Status: "running"})
+	bus.Publish(Event{Type: TypeStatusChanged, WorkflowID: "wf-1", Status: "running"})
+
+	event := <-sub.C
+	assert.Equal(t, "wf-1", event.WorkflowID)
+	assert.False(t, event.Timestamp.IsZero())
+	assert.Empty(t, sub.C)
+}
+
+func TestBus_DropsEventsForSlowSubscribers(t *testing.T) {
+	bus := newTestBus()
+	sub := bus.Subscribe(nil)
+
+	// Publishing never blocks, even once the buffer is full
+	for i := 0; i < DefaultBufferSize+10; i++ {
+		bus.Publish(Event{Type: TypeStepAdded, WorkflowID: "wf-1"})
+	}
+	assert.Len(t, sub.C, DefaultBufferSize)
+
+	sub.Close()
+	sub.Close()
+	bus.Publish(Event{Type: TypeStepAdded, WorkflowID: "wf-1"})
+
+	drained := 0
+	for range sub.C {
+		drained++
+	}
+	require.Equal(t, DefaultBufferSize, drained)
+}
+
+func TestBus_NilBusPublish(t *testing.T) {
+	var bus *Bus
+	assert.NotPanics(t, func() {
+		bus.Publish(Event{Type: TypeRollback})
+	})
+}
@@ -0,0 +1,33 @@
+package events
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// EventsPublished counts workflow progress events by source and type
+	EventsPublished = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "coordination_engine_events_published_total",
+			Help: "Total number of workflow progress events published",
+		},
+		[]string{"source", "type"},
+	)
+
+	// EventsDropped counts events not delivered because a subscriber buffer was full
+	EventsDropped = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "coordination_engine_events_dropped_total",
+			Help: "Total number of events dropped for slow subscribers",
+		},
+	)
+
+	// SubscribersActive tracks the number of open event subscriptions
+	SubscribersActive = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "coordination_engine_event_subscribers",
+			Help: "Current number of event subscribers",
+		},
+	)
+)
@@ -0,0 +1,169 @@
+// Package incidents tracks incidents and the remediation attempts linked to them.
+package incidents
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// ErrIncidentNotFound is returned when an incident does not exist in the store
+var ErrIncidentNotFound = errors.New("incident not found")
+
+// ErrInvalidCursor is returned when a pagination cursor cannot be decoded
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Pagination limits
+const (
+	DefaultListLimit = 50
+	MaxListLimit     = 500
+)
+
+// StatusActive is a list filter matching incidents that are not closed
+const StatusActive = "active"
+
+// ListOptions filters and paginates incident listings
+type ListOptions struct {
+	Namespace string // incident or affected resource namespace
+	Status    string // an IncidentStatus, or StatusActive
+	Limit     int
+	Cursor    string // opaque cursor returned by a previous List call
+}
+
+// Store persists incidents
+type Store interface {
+	Save(ctx context.Context, incident *models.Incident) error
+	Get(ctx context.Context, id string) (*models.Incident, error)
+	// List returns incidents newest first and a cursor for the next page, empty on the last page
+	List(ctx context.Context, opts ListOptions) ([]*models.Incident, string, error)
+}
+
+// MemoryStore keeps incidents in process memory
+type MemoryStore struct {
+	mu        sync.RWMutex
+	incidents map[string]*models.Incident
+}
+
+// NewMemoryStore creates an empty in-memory incident store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{incidents: make(map[string]*models.Incident)}
+}
+
+// Save stores a copy of the incident
+func (s *MemoryStore) Save(_ context.Context, incident *models.Incident) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.incidents[incident.ID] = incident.Clone()
+	return nil
+}
+
+// Get returns a copy of the incident
+func (s *MemoryStore) Get(_ context.Context, id string) (*models.Incident, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	incident, ok := s.incidents[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrIncidentNotFound, id)
+	}
+	return incident.Clone(), nil
+}
+
+// List returns a filtered page of incidents, newest first
+func (s *MemoryStore) List(_ context.Context, opts ListOptions) ([]*models.Incident, string, error) {
+	after, err := decodeCursor(opts.Cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = DefaultListLimit
+	}
+	if limit > MaxListLimit {
+		limit = MaxListLimit
+	}
+
+	s.mu.RLock()
+	matched := make([]*models.Incident, 0, len(s.incidents))
+	for _, incident := range s.incidents {
+		if matches(incident, opts) {
+			matched = append(matched, incident.Clone())
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(matched, func(i, j int) bool {
+		return newerThan(matched[i], matched[j])
+	})
+
+	// Skip past the cursor position
+	start := 0
+	if after != nil {
+		start = sort.Search(len(matched), func(i int) bool {
+			return newerThan(after, matched[i])
+		})
+	}
+
+	end := start + limit
+	if end >= len(matched) {
+		return matched[start:], "", nil
+	}
+	return matched[start:end], encodeCursor(matched[end-1]), nil
+}
+
+// matches applies list filters to an incident
+func matches(incident *models.Incident, opts ListOptions) bool {
+	if opts.Namespace != "" && !incident.AffectsNamespace(opts.Namespace) {
+		return false
+	}
+	switch opts.Status {
+	case "":
+		return true
+	case StatusActive:
+		return !incident.IsClosed()
+	default:
+		return string(incident.Status) == opts.Status
+	}
+}
+
+// newerThan orders incidents by detection time descending, then by ID
+func newerThan(a, b *models.Incident) bool {
+	if !a.DetectedAt.Equal(b.DetectedAt) {
+		return a.DetectedAt.After(b.DetectedAt)
+	}
+	return a.ID < b.ID
+}
+
+// encodeCursor builds an opaque cursor pointing after incident
+func encodeCursor(incident *models.Incident) string {
+	raw := strconv.FormatInt(incident.DetectedAt.UnixNano(), 10) + "|" + incident.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeCursor returns the sort position encoded in a cursor, or nil for the first page
+func decodeCursor(cursor string) (*models.Incident, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	nanos, id, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return nil, ErrInvalidCursor
+	}
+	n, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &models.Incident{ID: id, DetectedAt: time.Unix(0, n)}, nil
+}
@@ -0,0 +1,194 @@
+package incidents
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// Tracker maintains incidents and their lifecycle as remediation workflows start and finish
+type Tracker struct {
+	store  Store
+	mu     sync.Mutex
+	active map[string]map[string]bool // incident ID -> workflows still running
+	log    *logrus.Logger
+}
+
+// NewTracker creates an incident tracker backed by store
+func NewTracker(store Store, log *logrus.Logger) *Tracker {
+	return &Tracker{
+		store:  store,
+		active: make(map[string]map[string]bool),
+		log:    log,
+	}
+}
+
+// RemediationStarted links a remediation workflow to the incident, opening it if needed
+func (t *Tracker) RemediationStarted(ctx context.Context, incidentID, source string, issue *models.Issue, workflowID string) {
+	t.update(ctx, incidentID, workflowID, func(incident *models.Incident) {
+		if incident.Source == "" {
+			incident.Source = source
+		}
+		if incident.Severity == "" {
+			incident.Severity = issue.Severity
+		}
+		if incident.Namespace == "" {
+			incident.Namespace = issue.Namespace
+		}
+		if incident.IssueType == "" {
+			incident.IssueType = issue.Type
+		}
+		if incident.Description == "" {
+			incident.Description = issue.Description
+		}
+		if !issue.DetectedAt.IsZero() && (incident.DetectedAt.IsZero() || issue.DetectedAt.Before(incident.DetectedAt)) {
+			incident.DetectedAt = issue.DetectedAt
+		}
+		incident.AddResource(models.Resource{
+			Kind:      issue.ResourceType,
+			Name:      issue.ResourceName,
+			Namespace: issue.Namespace,
+			Issue:     issue.Type,
+		})
+		incident.LinkWorkflow(workflowID)
+	})
+}
+
+// CoordinationStarted links a multi-layer coordination workflow to the incident
+func (t *Tracker) CoordinationStarted(ctx context.Context, incidentID, severity, description string, resources []models.Resource, workflowID string) {
+	t.update(ctx, incidentID, workflowID, func(incident *models.Incident) {
+		if incident.Source == "" {
+			incident.Source = "coordination"
+		}
+		if incident.Severity == "" {
+			incident.Severity = severity
+		}
+		if incident.Description == "" {
+			incident.Description = description
+		}
+		for _, resource := range resources {
+			if incident.Namespace == "" {
+				incident.Namespace = resource.Namespace
+			}
+			incident.AddResource(resource)
+		}
+		incident.LinkCoordinationWorkflow(workflowID)
+	})
+}
+
+// WorkflowFinished updates the incident from the final status of a linked workflow.
+// A failed attempt leaves the incident remediating while other attempts still run.
+func (t *Tracker) WorkflowFinished(ctx context.Context, incidentID, workflowID, workflowStatus string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	running := t.active[incidentID]
+	delete(running, workflowID)
+	if len(running) == 0 {
+		delete(t.active, incidentID)
+	}
+
+	incident, err := t.store.Get(ctx, incidentID)
+	if err != nil {
+		if !errors.Is(err, ErrIncidentNotFound) {
+			t.log.WithError(err).WithField("incident_id", incidentID).Error("Failed to load incident")
+		}
+		return
+	}
+
+	next := outcomeStatus(workflowStatus)
+	if next != models.IncidentStatusResolved && len(running) > 0 {
+		next = models.IncidentStatusRemediating
+	}
+	t.transition(incident, next)
+	t.save(ctx, incident)
+}
+
+// Get returns an incident by ID
+func (t *Tracker) Get(ctx context.Context, id string) (*models.Incident, error) {
+	return t.store.Get(ctx, id)
+}
+
+// List returns a filtered page of incidents and the cursor for the next page
+func (t *Tracker) List(ctx context.Context, opts ListOptions) ([]*models.Incident, string, error) {
+	return t.store.List(ctx, opts)
+}
+
+// update loads or opens an incident, applies fn and marks it remediating
+func (t *Tracker) update(ctx context.Context, incidentID, workflowID string, fn func(*models.Incident)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	incident, err := t.store.Get(ctx, incidentID)
+	if err != nil {
+		if !errors.Is(err, ErrIncidentNotFound) {
+			t.log.WithError(err).WithField("incident_id", incidentID).Error("Failed to load incident")
+			return
+		}
+		now := time.Now()
+		incident = &models.Incident{
+			ID:         incidentID,
+			Status:     models.IncidentStatusOpen,
+			DetectedAt: now,
+			UpdatedAt:  now,
+		}
+		t.log.WithField("incident_id", incidentID).Info("Incident opened")
+	}
+
+	fn(incident)
+
+	if t.active[incidentID] == nil {
+		t.active[incidentID] = make(map[string]bool)
+	}
+	t.active[incidentID][workflowID] = true
+
+	t.transition(incident, models.IncidentStatusRemediating)
+	t.save(ctx, incident)
+}
+
+// transition applies a lifecycle change, logging transitions the lifecycle forbids
+func (t *Tracker) transition(incident *models.Incident, status models.IncidentStatus) {
+	previous := incident.Status
+	if err := incident.TransitionTo(status); err != nil {
+		t.log.WithError(err).WithField("incident_id", incident.ID).Warn("Ignoring incident status change")
+		return
+	}
+	if previous != status {
+		t.log.WithFields(logrus.Fields{
+			"incident_id": incident.ID,
+			"from":        previous,
+			"to":          status,
+		}).Info("Incident status changed")
+	}
+}
+
+// save persists an incident, logging failures
+func (t *Tracker) save(ctx context.Context, incident *models.Incident) {
+	if err := t.store.Save(ctx, incident); err != nil {
+		t.log.WithError(err).WithField("incident_id", incident.ID).Error("Failed to save incident")
+	}
+}
+
+// outcomeStatus maps a final workflow status to the incident status it implies
+func outcomeStatus(workflowStatus string) models.IncidentStatus {
+	switch workflowStatus {
+	case string(models.WorkflowStatusCompleted):
+		return models.IncidentStatusResolved
+	case string(models.WorkflowStatusFailed), string(models.WorkflowStatusFailedVerification):
+		return models.IncidentStatusFailed
+	case string(models.WorkflowStatusRejected), string(models.WorkflowStatusCircuitOpen),
+		string(models.WorkflowStatusRecommended), string(models.WorkflowStatusRefused):
+		// A human declined the automated fix, the circuit breaker refused to retry
+		// it, a maintenance window downgraded it to recommendations or guardrails
+		// excluded the resource, so someone has to handle it
+		return models.IncidentStatusEscalated
+	default:
+		// Cancelled, interrupted or rolled back: nothing is remediating any more
+		return models.IncidentStatusOpen
+	}
+}
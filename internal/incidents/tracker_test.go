@@ -0,0 +1,125 @@
+package incidents
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+func newTestTracker() *Tracker {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	return NewTracker(NewMemoryStore(), log)
+}
+
+func newTestIssue(namespace string) *models.Issue {
+	return &models.Issue{
+		ID:           "issue-1",
+		Type:         "CrashLoopBackOff",
+		Severity:     "high",
+		Namespace:    namespace,
+		ResourceType: "pod",
+		ResourceName: "app-1",
+		DetectedAt:   time.Now(),
+	}
+}
+
+func TestTracker_Lifecycle(t *testing.T) {
+	ctx := context.Background()
+	tracker := newTestTracker()
+
+	tracker.RemediationStarted(ctx, "inc-1", "alertmanager", newTestIssue("default"), "wf-1")
+	tracker.RemediationStarted(ctx, "inc-1", "api", newTestIssue("default"), "wf-2")
+
+	incident, err := tracker.Get(ctx, "inc-1")
+	require.NoError(t, err)
+	assert.Equal(t, models.IncidentStatusRemediating, incident.Status)
+	assert.Equal(t, "alertmanager", incident.Source)
+	assert.Equal(t, "high", incident.Severity)
+	assert.Equal(t, []string{"wf-1", "wf-2"}, incident.WorkflowIDs)
+	assert.Len(t, incident.AffectedResources, 1)
+
+	// One failed attempt does not close the incident while another still runs
+	tracker.WorkflowFinished(ctx, "inc-1", "wf-1", string(models.WorkflowStatusFailed))
+	incident, err = tracker.Get(ctx, "inc-1")
+	require.NoError(t, err)
+	assert.Equal(t, models.IncidentStatusRemediating, incident.Status)
+	assert.Nil(t, incident.ResolvedAt)
+
+	tracker.WorkflowFinished(ctx, "inc-1", "wf-2", string(models.WorkflowStatusCompleted))
+	incident, err = tracker.Get(ctx, "inc-1")
+	require.NoError(t, err)
+	assert.Equal(t, models.IncidentStatusResolved, incident.Status)
+	assert.NotNil(t, incident.ResolvedAt)
+
+	// Coordination workflows are linked to the same incident
+	tracker.CoordinationStarted(ctx, "inc-1", "critical", "node pressure",
+		[]models.Resource{{Kind: "node", Name: "worker-1"}}, "coord-1")
+	tracker.WorkflowFinished(ctx, "inc-1", "coord-1", string(models.WorkflowStatusRejected))
+	incident, err = tracker.Get(ctx, "inc-1")
+	require.NoError(t, err)
+	assert.Equal(t, models.IncidentStatusEscalated, incident.Status)
+	assert.Equal(t, []string{"coord-1"}, incident.CoordinationWorkflowIDs)
+	assert.Nil(t, incident.ResolvedAt)
+
+	_, err = tracker.Get(ctx, "inc-missing")
+	assert.True(t, errors.Is(err, ErrIncidentNotFound))
+}
+
+func TestMemoryStore_ListFiltersAndPagination(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	base := time.Now()
+
+	for i := 0; i < 5; i++ {
+		namespace := "default"
+		status := models.IncidentStatusRemediating
+		if i%2 == 1 {
+			namespace = "payments"
+			status = models.IncidentStatusResolved
+		}
+		require.NoError(t, store.Save(ctx, &models.Incident{
+			ID:         fmt.Sprintf("inc-%d", i),
+			Namespace:  namespace,
+			Status:     status,
+			DetectedAt: base.Add(time.Duration(i) * time.Minute),
+		}))
+	}
+
+	page, next, err := store.List(ctx, ListOptions{Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, page, 2)
+	assert.Equal(t, "inc-4", page[0].ID)
+	assert.Equal(t, "inc-3", page[1].ID)
+	require.NotEmpty(t, next)
+
+	page, next, err = store.List(ctx, ListOptions{Limit: 2, Cursor: next})
+	require.NoError(t, err)
+	assert.Equal(t, "inc-2", page[0].ID)
+	assert.Equal(t, "inc-1", page[1].ID)
+
+	page, next, err = store.List(ctx, ListOptions{Limit: 2, Cursor: next})
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Equal(t, "inc-0", page[0].ID)
+	assert.Empty(t, next)
+
+	page, _, err = store.List(ctx, ListOptions{Namespace: "payments"})
+	require.NoError(t, err)
+	assert.Len(t, page, 2)
+
+	page, _, err = store.List(ctx, ListOptions{Status: StatusActive})
+	require.NoError(t, err)
+	assert.Len(t, page, 3)
+
+	_, _, err = store.List(ctx, ListOptions{Cursor: "not-a-cursor!"})
+	assert.True(t, errors.Is(err, ErrInvalidCursor))
+}
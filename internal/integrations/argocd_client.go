@@ -33,6 +33,23 @@ func NewArgoCDClient(baseURL, token string, log *logrus.Logger) *ArgoCDClient {
 	}
 }
 
+// APIError is an error response of the ArgoCD API
+type APIError struct {
+	Message    string // what failed, e.g. "ArgoCD sync failed"
+	StatusCode int
+	Body       string
+}
+
+// Error returns the message, status and response body
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s (status %d): %s", e.Message, e.StatusCode, e.Body)
+}
+
+// HTTPStatusCode returns the status of the response
+func (e *APIError) HTTPStatusCode() int {
+	return e.StatusCode
+}
+
 // Application represents an ArgoCD application
 type Application struct {
 	Metadata ApplicationMetadata `json:"metadata"`
@@ -44,6 +61,7 @@ type Application struct {
 type ApplicationMetadata struct {
 	Name      string `json:"name"`
 	Namespace string `json:"namespace"`
+	UID       string `json:"uid,omitempty"`
 }
 
 // ApplicationSpec contains application specification
@@ -132,7 +150,7 @@ func (c *ArgoCDClient) GetApplication(ctx context.Context, appName string) (*App
 		if readErr != nil {
 			return nil, fmt.Errorf("ArgoCD API error (status %d), failed to read body: %w", resp.StatusCode, readErr)
 		}
-		return nil, fmt.Errorf("ArgoCD API error (status %d): %s", resp.StatusCode, string(body))
+		return nil, &APIError{Message: "ArgoCD API error", StatusCode: resp.StatusCode, Body: string(body)}
 	}
 
 	var app Application
@@ -188,7 +206,7 @@ func (c *ArgoCDClient) SyncApplication(ctx context.Context, appName string, sync
 		if readErr != nil {
 			return fmt.Errorf("ArgoCD sync failed (status %d), failed to read body: %w", resp.StatusCode, readErr)
 		}
-		return fmt.Errorf("ArgoCD sync failed (status %d): %s", resp.StatusCode, string(body))
+		return &APIError{Message: "ArgoCD sync failed", StatusCode: resp.StatusCode, Body: string(body)}
 	}
 
 	c.log.WithField("app_name", appName).Info("ArgoCD sync triggered successfully")
@@ -268,7 +286,7 @@ func (c *ArgoCDClient) FindApplicationByResource(ctx context.Context, namespace,
 		if readErr != nil {
 			return nil, fmt.Errorf("ArgoCD API error (status %d), failed to read body: %w", resp.StatusCode, readErr)
 		}
-		return nil, fmt.Errorf("ArgoCD API error (status %d): %s", resp.StatusCode, string(body))
+		return nil, &APIError{Message: "ArgoCD API error", StatusCode: resp.StatusCode, Body: string(body)}
 	}
 
 	var appList struct {
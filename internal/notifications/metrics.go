@@ -0,0 +1,31 @@
+package notifications
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// WebhookDeliveries counts webhook delivery outcomes by webhook and event
+	WebhookDeliveries = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "coordination_engine_webhook_deliveries_total",
+			Help: "Total number of webhook notification deliveries",
+		},
+		[]string{"webhook", "event", "outcome"}, // outcome: delivered, dead_lettered
+	)
+
+	// WebhookAttempts counts individual webhook HTTP attempts, including retries
+	WebhookAttempts = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "coordination_engine_webhook_attempts_total",
+			Help: "Total number of webhook delivery attempts including retries",
+		},
+		[]string{"webhook"},
+	)
+)
+
+// RecordDelivery records the final outcome of a webhook delivery
+func RecordDelivery(webhook, event, outcome string) {
+	WebhookDeliveries.WithLabelValues(webhook, event, outcome).Inc()
+}
@@ -0,0 +1,276 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/events"
+)
+
+// Delivery headers
+const (
+	HeaderSignature = "X-Coordination-Signature-256" // sha256=<hex HMAC of the body>
+	HeaderEvent     = "X-Coordination-Event"
+	HeaderDelivery  = "X-Coordination-Delivery"
+)
+
+// Delivery defaults
+const (
+	DefaultMaxRetries     = 3
+	DefaultRetryBackoff   = 2 * time.Second
+	DefaultTimeout        = 10 * time.Second
+	DeadLetterCapacity    = 100
+	maxRetryBackoffFactor = 32
+)
+
+// NotifierConfig controls webhook delivery
+type NotifierConfig struct {
+	MaxRetries   int           // retries after the first attempt
+	RetryBackoff time.Duration // delay before the first retry, doubled for each further retry
+	Timeout      time.Duration // per-attempt HTTP timeout
+}
+
+// DeadLetter is a notification that could not be delivered
+type DeadLetter struct {
+	Webhook      string        `json:"webhook"`
+	Notification *Notification `json:"notification"`
+	Attempts     int           `json:"attempts"`
+	LastError    string        `json:"last_error"`
+	FailedAt     time.Time     `json:"failed_at"`
+}
+
+// Notifier sends webhook notifications for workflows reaching a final status
+type Notifier struct {
+	webhooks []WebhookConfig
+	config   NotifierConfig
+	client   *http.Client
+	sub      *events.Subscription
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+
+	mu          sync.Mutex
+	deadLetters []DeadLetter
+
+	log *logrus.Logger
+}
+
+// NewNotifier creates a notifier for the given webhooks. Zero config values use the defaults.
+func NewNotifier(webhooks []WebhookConfig, config NotifierConfig, log *logrus.Logger) *Notifier {
+	if config.MaxRetries < 0 {
+		config.MaxRetries = 0
+	}
+	if config.RetryBackoff <= 0 {
+		config.RetryBackoff = DefaultRetryBackoff
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultTimeout
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Notifier{
+		webhooks: webhooks,
+		config:   config,
+		client:   &http.Client{Timeout: config.Timeout},
+		ctx:      ctx,
+		cancel:   cancel,
+		log:      log,
+	}
+}
+
+// Start subscribes to final workflow status events on bus
+func (n *Notifier) Start(bus *events.Bus) {
+	n.sub = bus.Subscribe(func(event events.Event) bool {
+		return eventType(event) != ""
+	})
+
+	n.wg.Add(1)
+	go func() {
+		defer n.wg.Done()
+		for event := range n.sub.C {
+			n.Notify(event)
+		}
+	}()
+
+	n.log.WithField("webhooks", len(n.webhooks)).Info("Webhook notifier started")
+}
+
+// Stop stops receiving events and waits for in-flight deliveries until ctx is done.
+// Deliveries still retrying after that are dead-lettered.
+func (n *Notifier) Stop(ctx context.Context) {
+	if n.sub != nil {
+		n.sub.Close()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		n.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		n.cancel()
+		<-done
+	}
+	n.cancel()
+}
+
+// Notify delivers a final status event to every matching webhook in the background
+func (n *Notifier) Notify(event events.Event) {
+	notificationType := eventType(event)
+	if notificationType == "" {
+		return
+	}
+
+	notification := newNotification(notificationType, event)
+	for i := range n.webhooks {
+		webhook := &n.webhooks[i]
+		if !webhook.Matches(notificationType, event) {
+			continue
+		}
+		n.wg.Add(1)
+		go func() {
+			defer n.wg.Done()
+			n.deliver(webhook, notification)
+		}()
+	}
+}
+
+// DeadLetters returns the most recent undeliverable notifications, oldest first
+func (n *Notifier) DeadLetters() []DeadLetter {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return append([]DeadLetter(nil), n.deadLetters...)
+}
+
+// deliver sends a notification to one webhook, retrying with exponential backoff
+func (n *Notifier) deliver(webhook *WebhookConfig, notification *Notification) {
+	body, err := render(webhook.Template, notification)
+	if err != nil {
+		n.deadLetter(webhook, notification, 0, fmt.Errorf("failed to render payload: %w", err))
+		return
+	}
+
+	backoff := n.config.RetryBackoff
+	var lastErr error
+	attempts := 0
+	for attempt := 0; attempt <= n.config.MaxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(backoff):
+			case <-n.ctx.Done():
+				n.deadLetter(webhook, notification, attempts, fmt.Errorf("notifier stopped: %w", lastErr))
+				return
+			}
+			if backoff < n.config.RetryBackoff*maxRetryBackoffFactor {
+				backoff *= 2
+			}
+		}
+
+		attempts++
+		retryable, err := n.send(webhook, notification, body)
+		if err == nil {
+			RecordDelivery(webhook.Name, notification.Event, "delivered")
+			n.log.WithFields(logrus.Fields{
+				"webhook":     webhook.Name,
+				"event":       notification.Event,
+				"workflow_id": notification.Workflow.ID,
+				"attempts":    attempts,
+			}).Debug("Webhook notification delivered")
+			return
+		}
+
+		lastErr = err
+		n.log.WithError(err).WithFields(logrus.Fields{
+			"webhook":  webhook.Name,
+			"event":    notification.Event,
+			"attempt":  attempts,
+			"retrying": retryable && attempt < n.config.MaxRetries,
+		}).Warn("Webhook delivery failed")
+		if !retryable {
+			break
+		}
+	}
+
+	n.deadLetter(webhook, notification, attempts, lastErr)
+}
+
+// send performs one delivery attempt. It reports whether a failure is worth retrying.
+func (n *Notifier) send(webhook *WebhookConfig, notification *Notification, body []byte) (retryable bool, err error) {
+	WebhookAttempts.WithLabelValues(webhook.Name).Inc()
+
+	req, err := http.NewRequestWithContext(n.ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, notification.Event)
+	req.Header.Set(HeaderDelivery, notification.ID)
+	for name, value := range webhook.Headers {
+		req.Header.Set(name, value)
+	}
+	if webhook.Secret != "" {
+		req.Header.Set(HeaderSignature, Sign(webhook.Secret, body))
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return true, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return false, nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return true, fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	default:
+		return false, fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+}
+
+// deadLetter records an undeliverable notification, keeping the most recent ones
+func (n *Notifier) deadLetter(webhook *WebhookConfig, notification *Notification, attempts int, err error) {
+	RecordDelivery(webhook.Name, notification.Event, "dead_lettered")
+	n.log.WithError(err).WithFields(logrus.Fields{
+		"webhook":         webhook.Name,
+		"event":           notification.Event,
+		"workflow_id":     notification.Workflow.ID,
+		"incident_id":     notification.Incident.ID,
+		"notification_id": notification.ID,
+		"attempts":        attempts,
+	}).Error("Webhook notification dead-lettered")
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.deadLetters = append(n.deadLetters, DeadLetter{
+		Webhook:      webhook.Name,
+		Notification: notification,
+		Attempts:     attempts,
+		LastError:    err.Error(),
+		FailedAt:     time.Now(),
+	})
+	if len(n.deadLetters) > DeadLetterCapacity {
+		n.deadLetters = n.deadLetters[len(n.deadLetters)-DeadLetterCapacity:]
+	}
+}
+
+// Sign returns the signature header value for body: sha256= followed by the hex HMAC-SHA256
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
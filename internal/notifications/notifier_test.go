@@ -0,0 +1,180 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/events"
+)
+
+func newTestNotifier(webhooks []WebhookConfig) *Notifier {
+	log := logrus.New()
+	log.SetLevel(logrus.FatalLevel)
+	return NewNotifier(webhooks, NotifierConfig{MaxRetries: 2, RetryBackoff: time.Millisecond}, log)
+}
+
+func failedEvent() events.Event {
+	return events.Event{
+		Type:       events.TypeStatusChanged,
+		Source:     events.SourceRemediation,
+		WorkflowID: "wf-1",
+		IncidentID: "inc-1",
+		Namespace:  "prod-payments",
+		Severity:   "critical",
+		Status:     "failed",
+		Message:    "helm rollback failed",
+		Details:    map[string]string{"remediator": "helm", "resource_name": "payment"},
+		Final:      true,
+		Timestamp:  time.Now(),
+	}
+}
+
+func TestWebhookConfig_Matches(t *testing.T) {
+	webhook := WebhookConfig{
+		Events:     []string{EventWorkflowFailed},
+		Namespaces: []string{"prod-*"},
+		Severities: []string{"Critical"},
+	}
+
+	assert.True(t, webhook.Matches(EventWorkflowFailed, failedEvent()))
+	assert.False(t, webhook.Matches(EventWorkflowCompleted, failedEvent()))
+
+	other := failedEvent()
+	other.Namespace = "dev"
+	assert.False(t, webhook.Matches(EventWorkflowFailed, other))
+
+	other = failedEvent()
+	other.Severity = "low"
+	assert.False(t, webhook.Matches(EventWorkflowFailed, other))
+}
+
+func TestNotifier_SignsAndRetries(t *testing.T) {
+	var calls atomic.Int32
+	var received Notification
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		assert.Equal(t, Sign("s3cret", body), r.Header.Get(HeaderSignature))
+		assert.Equal(t, EventWorkflowFailed, r.Header.Get(HeaderEvent))
+		assert.NoError(t, json.Unmarshal(body, &received))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	n := newTestNotifier([]WebhookConfig{{Name: "ops", URL: server.URL, Secret: "s3cret"}})
+	n.Notify(failedEvent())
+	n.Stop(context.Background())
+
+	assert.Equal(t, int32(2), calls.Load())
+	assert.Equal(t, "wf-1", received.Workflow.ID)
+	assert.Equal(t, "helm", received.Workflow.Remediator)
+	assert.Equal(t, "inc-1", received.Incident.ID)
+	assert.Equal(t, "helm rollback failed", received.Error)
+	assert.Empty(t, n.DeadLetters())
+}
+
+func TestNotifier_DeadLetters(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	rejecting := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer rejecting.Close()
+
+	n := newTestNotifier([]WebhookConfig{
+		{Name: "flaky", URL: server.URL},
+		{Name: "rejecting", URL: rejecting.URL, Template: TemplateSlack},
+	})
+	n.Notify(failedEvent())
+	n.Stop(context.Background())
+
+	// Three attempts for the server error, one for the client error
+	assert.Equal(t, int32(3), calls.Load())
+	deadLetters := n.DeadLetters()
+	require.Len(t, deadLetters, 2)
+	attempts := map[string]int{}
+	for _, dl := range deadLetters {
+		attempts[dl.Webhook] = dl.Attempts
+		assert.Equal(t, "wf-1", dl.Notification.Workflow.ID)
+	}
+	assert.Equal(t, map[string]int{"flaky": 3, "rejecting": 1}, attempts)
+}
+
+func TestNotifier_IgnoresNonFinalEvents(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+	}))
+	defer server.Close()
+
+	n := newTestNotifier([]WebhookConfig{{Name: "ops", URL: server.URL}})
+	running := failedEvent()
+	running.Status = "in_progress"
+	running.Final = false
+	n.Notify(running)
+	n.Stop(context.Background())
+
+	assert.Zero(t, calls.Load())
+}
+
+func TestSlackTemplate(t *testing.T) {
+	body, err := render(TemplateSlack, newNotification(EventWorkflowFailed, failedEvent()))
+	require.NoError(t, err)
+
+	var message map[string]interface{}
+	require.NoError(t, json.Unmarshal(body, &message))
+	assert.Contains(t, message["text"], "wf-1 failed in prod-payments")
+	attachments := message["attachments"].([]interface{})
+	assert.Equal(t, "danger", attachments[0].(map[string]interface{})["color"])
+}
+
+func TestLoadWebhooks(t *testing.T) {
+	t.Setenv("TEST_WEBHOOK_SECRET", "from-env")
+	dir := t.TempDir()
+
+	valid := filepath.Join(dir, "webhooks.yaml")
+	require.NoError(t, os.WriteFile(valid, []byte(`
+webhooks:
+  - name: slack-sre
+    url: https://hooks.example.com/services/T000
+    template: slack
+    secret_env: TEST_WEBHOOK_SECRET
+    events: [workflow.failed, workflow.rolled_back]
+    severities: [critical]
+`), 0o600))
+
+	webhooks, err := LoadWebhooks(valid)
+	require.NoError(t, err)
+	require.Len(t, webhooks, 1)
+	assert.Equal(t, "from-env", webhooks[0].Secret)
+	assert.Equal(t, TemplateSlack, webhooks[0].Template)
+
+	invalid := filepath.Join(dir, "invalid.yaml")
+	require.NoError(t, os.WriteFile(invalid, []byte(`
+webhooks:
+  - name: bad
+    url: ftp://example.com
+`), 0o600))
+	_, err = LoadWebhooks(invalid)
+	assert.Error(t, err)
+}
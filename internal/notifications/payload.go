@@ -0,0 +1,136 @@
+package notifications
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/events"
+)
+
+// Notification is the generic JSON payload sent to webhooks
+type Notification struct {
+	ID        string          `json:"id"`
+	Event     string          `json:"event"`  // e.g. workflow.failed
+	Source    string          `json:"source"` // remediation or coordination
+	Timestamp time.Time       `json:"timestamp"`
+	Workflow  WorkflowSummary `json:"workflow"`
+	Incident  IncidentSummary `json:"incident"`
+	Error     string          `json:"error,omitempty"`
+}
+
+// WorkflowSummary describes the workflow a notification is about
+type WorkflowSummary struct {
+	ID         string            `json:"id"`
+	Status     string            `json:"status"`
+	Namespace  string            `json:"namespace,omitempty"`
+	Remediator string            `json:"remediator,omitempty"`
+	Details    map[string]string `json:"details,omitempty"`
+}
+
+// IncidentSummary describes the incident the workflow remediates
+type IncidentSummary struct {
+	ID       string `json:"id"`
+	Severity string `json:"severity,omitempty"`
+}
+
+// eventType returns the notification type of a final status event, or "" if the
+// event does not warrant a notification
+func eventType(event events.Event) string {
+	if event.Type != events.TypeStatusChanged || !event.Final {
+		return ""
+	}
+	return "workflow." + event.Status
+}
+
+// newNotification builds the payload for a final status event
+func newNotification(notificationType string, event events.Event) *Notification {
+	details := make(map[string]string, len(event.Details))
+	for k, v := range event.Details {
+		if k != "remediator" && v != "" {
+			details[k] = v
+		}
+	}
+
+	return &Notification{
+		ID:        uuid.New().String(),
+		Event:     notificationType,
+		Source:    event.Source,
+		Timestamp: event.Timestamp,
+		Workflow: WorkflowSummary{
+			ID:         event.WorkflowID,
+			Status:     event.Status,
+			Namespace:  event.Namespace,
+			Remediator: event.Details["remediator"],
+			Details:    details,
+		},
+		Incident: IncidentSummary{
+			ID:       event.IncidentID,
+			Severity: event.Severity,
+		},
+		Error: event.Message,
+	}
+}
+
+// render encodes a notification with the webhook's payload template
+func render(template string, notification *Notification) ([]byte, error) {
+	switch template {
+	case TemplateSlack:
+		return json.Marshal(slackMessage(notification))
+	default:
+		return json.Marshal(notification)
+	}
+}
+
+// slackMessage formats a notification as a Slack incoming webhook message
+func slackMessage(n *Notification) map[string]interface{} {
+	color := "warning"
+	switch n.Event {
+	case EventWorkflowCompleted:
+		color = "good"
+	case EventWorkflowFailed, EventWorkflowFailedVerification, EventWorkflowCircuitOpen,
+		EventWorkflowRefused, EventWorkflowRolledBack, EventWorkflowInterrupted:
+		color = "danger"
+	}
+
+	text := fmt.Sprintf("%s workflow %s %s", n.Source, n.Workflow.ID, n.Workflow.Status)
+	if n.Workflow.Namespace != "" {
+		text += " in " + n.Workflow.Namespace
+	}
+
+	fields := []map[string]interface{}{
+		{"title": "Incident", "value": n.Incident.ID, "short": true},
+		{"title": "Severity", "value": valueOrNone(n.Incident.Severity), "short": true},
+		{"title": "Remediator", "value": valueOrNone(n.Workflow.Remediator), "short": true},
+	}
+	if resource := n.Workflow.Details["resource_name"]; resource != "" {
+		fields = append(fields, map[string]interface{}{
+			"title": "Resource",
+			"value": n.Workflow.Details["resource_kind"] + "/" + resource,
+			"short": true,
+		})
+	}
+	if n.Error != "" {
+		fields = append(fields, map[string]interface{}{"title": "Error", "value": n.Error, "short": false})
+	}
+
+	return map[string]interface{}{
+		"text": text,
+		"attachments": []map[string]interface{}{{
+			"color":    color,
+			"fallback": text,
+			"fields":   fields,
+			"ts":       n.Timestamp.Unix(),
+		}},
+	}
+}
+
+// valueOrNone returns a placeholder for empty Slack field values
+func valueOrNone(value string) string {
+	if value == "" {
+		return "none"
+	}
+	return value
+}
@@ -0,0 +1,132 @@
+// Package notifications delivers workflow lifecycle notifications to outbound webhooks.
+package notifications
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/events"
+)
+
+// Payload templates
+const (
+	TemplateGeneric = "generic" // the Notification JSON document
+	TemplateSlack   = "slack"   // Slack-compatible incoming webhook message
+)
+
+// Notification event types, one per final workflow status
+const (
+	EventWorkflowCompleted   = "workflow.completed"
+	EventWorkflowFailed      = "workflow.failed"
+	EventWorkflowRolledBack  = "workflow.rolled_back"
+	EventWorkflowCancelled   = "workflow.cancelled"
+	EventWorkflowRejected    = "workflow.rejected"
+	EventWorkflowInterrupted = "workflow.interrupted"
+
+	EventWorkflowFailedVerification = "workflow.failed_verification"
+	EventWorkflowCircuitOpen        = "workflow.circuit_open"
+	EventWorkflowRecommended        = "workflow.recommended"
+	EventWorkflowRefused            = "workflow.refused"
+)
+
+// WebhookConfig describes one outbound webhook and the notifications it receives
+type WebhookConfig struct {
+	Name     string `json:"name"`
+	URL      string `json:"url"`
+	Template string `json:"template,omitempty"` // generic (default) or slack
+	Secret   string `json:"secret,omitempty"`   // HMAC-SHA256 signing key
+	// SecretEnv names an environment variable holding the signing key, so the key can
+	// come from a Secret while the webhook list lives in a ConfigMap
+	SecretEnv string            `json:"secret_env,omitempty"`
+	Headers   map[string]string `json:"headers,omitempty"`
+
+	// Filters; an empty filter matches everything
+	Events     []string `json:"events,omitempty"`     // e.g. workflow.failed
+	Namespaces []string `json:"namespaces,omitempty"` // exact names or path.Match globs
+	Severities []string `json:"severities,omitempty"`
+}
+
+// webhookFile is the document read by LoadWebhooks
+type webhookFile struct {
+	Webhooks []WebhookConfig `json:"webhooks"`
+}
+
+// LoadWebhooks reads webhook definitions from a YAML or JSON file
+func LoadWebhooks(filename string) ([]WebhookConfig, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read webhook config: %w", err)
+	}
+
+	var file webhookFile
+	if err := yaml.UnmarshalStrict(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse webhook config %s: %w", filename, err)
+	}
+
+	for i := range file.Webhooks {
+		webhook := &file.Webhooks[i]
+		if webhook.SecretEnv != "" && webhook.Secret == "" {
+			webhook.Secret = os.Getenv(webhook.SecretEnv)
+		}
+		if err := webhook.Validate(); err != nil {
+			return nil, fmt.Errorf("webhook %d: %w", i, err)
+		}
+	}
+	return file.Webhooks, nil
+}
+
+// Validate checks the webhook definition
+func (w *WebhookConfig) Validate() error {
+	if w.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	parsed, err := url.Parse(w.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("%s: url must be an absolute http or https URL", w.Name)
+	}
+	switch w.Template {
+	case "", TemplateGeneric, TemplateSlack:
+	default:
+		return fmt.Errorf("%s: invalid template %q (must be generic or slack)", w.Name, w.Template)
+	}
+	for _, pattern := range w.Namespaces {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("%s: invalid namespace pattern %q", w.Name, pattern)
+		}
+	}
+	return nil
+}
+
+// Matches returns true if the webhook wants notifications of this type for the event
+func (w *WebhookConfig) Matches(eventType string, event events.Event) bool {
+	if len(w.Events) > 0 && !containsFold(w.Events, eventType) {
+		return false
+	}
+	if len(w.Severities) > 0 && !containsFold(w.Severities, event.Severity) {
+		return false
+	}
+	if len(w.Namespaces) > 0 {
+		for _, pattern := range w.Namespaces {
+			if matched, _ := path.Match(pattern, event.Namespace); matched {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// containsFold returns true if values contains value, ignoring case
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -59,7 +59,13 @@ func RequiredPermissions(namespace string) []Permission {
 		{APIGroup: "", Resource: "services", Verb: "get", Namespace: namespace},
 		{APIGroup: "", Resource: "services", Verb: "list", Namespace: namespace},
 		{APIGroup: "", Resource: "configmaps", Verb: "get", Namespace: namespace},
+		{APIGroup: "", Resource: "configmaps", Verb: "list", Namespace: namespace},
+		{APIGroup: "", Resource: "configmaps", Verb: "create", Namespace: namespace},
+		{APIGroup: "", Resource: "configmaps", Verb: "update", Namespace: namespace},
 		{APIGroup: "", Resource: "secrets", Verb: "get", Namespace: namespace},
+		{APIGroup: "", Resource: "secrets", Verb: "list", Namespace: namespace},
+		{APIGroup: "", Resource: "secrets", Verb: "create", Namespace: namespace},
+		{APIGroup: "", Resource: "secrets", Verb: "update", Namespace: namespace},
 		{APIGroup: "", Resource: "events", Verb: "create", Namespace: namespace},
 
 		// Core API resources - read-only
@@ -67,6 +73,10 @@ func RequiredPermissions(namespace string) []Permission {
 		{APIGroup: "", Resource: "namespaces", Verb: "list", Namespace: namespace},
 		{APIGroup: "", Resource: "nodes", Verb: "get", Namespace: namespace},
 		{APIGroup: "", Resource: "nodes", Verb: "list", Namespace: namespace},
+		{APIGroup: "", Resource: "limitranges", Verb: "get", Namespace: namespace},
+		{APIGroup: "", Resource: "limitranges", Verb: "list", Namespace: namespace},
+		{APIGroup: "", Resource: "resourcequotas", Verb: "get", Namespace: namespace},
+		{APIGroup: "", Resource: "resourcequotas", Verb: "list", Namespace: namespace},
 
 		// Apps API resources
 		{APIGroup: "apps", Resource: "deployments", Verb: "get", Namespace: namespace},
@@ -80,6 +90,18 @@ func RequiredPermissions(namespace string) []Permission {
 		{APIGroup: "apps", Resource: "statefulsets", Verb: "get", Namespace: namespace},
 		{APIGroup: "apps", Resource: "statefulsets", Verb: "list", Namespace: namespace},
 
+		{APIGroup: "apps", Resource: "controllerrevisions", Verb: "get", Namespace: namespace},
+		{APIGroup: "apps", Resource: "controllerrevisions", Verb: "list", Namespace: namespace},
+
+		// OpenShift DeploymentConfigs and their ReplicationControllers (owner chain resolution, restarts)
+		{APIGroup: "", Resource: "replicationcontrollers", Verb: "get", Namespace: namespace},
+		{APIGroup: "apps.openshift.io", Resource: "deploymentconfigs", Verb: "get", Namespace: namespace},
+		{APIGroup: "apps.openshift.io", Resource: "deploymentconfigs", Verb: "patch", Namespace: namespace},
+
+		// Argo Rollouts (restarts)
+		{APIGroup: "argoproj.io", Resource: "rollouts", Verb: "get", Namespace: namespace},
+		{APIGroup: "argoproj.io", Resource: "rollouts", Verb: "patch", Namespace: namespace},
+
 		// Batch API resources
 		{APIGroup: "batch", Resource: "jobs", Verb: "get", Namespace: namespace},
 		{APIGroup: "batch", Resource: "jobs", Verb: "list", Namespace: namespace},
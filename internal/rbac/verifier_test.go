@@ -46,6 +46,41 @@ func TestRequiredPermissions(t *testing.T) {
 	assert.True(t, hasMCO, "Should include MachineConfigPool permission")
 }
 
+// hasPermission reports whether perms include the verb on the resource
+func hasPermission(perms []Permission, apiGroup, resource, verb string) bool {
+	for _, perm := range perms {
+		if perm.APIGroup == apiGroup && perm.Resource == resource && perm.Verb == verb {
+			return true
+		}
+	}
+	return false
+}
+
+func TestRequiredPermissions_Remediation(t *testing.T) {
+	perms := RequiredPermissions("test-namespace")
+
+	tests := []struct {
+		apiGroup string
+		resource string
+		verb     string
+	}{
+		{"", "secrets", "list"},
+		{"", "secrets", "create"},
+		{"", "secrets", "update"},
+		{"", "limitranges", "list"},
+		{"", "resourcequotas", "list"},
+		{"", "replicationcontrollers", "get"},
+		{"apps", "controllerrevisions", "list"},
+		{"apps.openshift.io", "deploymentconfigs", "get"},
+		{"apps.openshift.io", "deploymentconfigs", "patch"},
+		{"argoproj.io", "rollouts", "get"},
+		{"argoproj.io", "rollouts", "patch"},
+	}
+	for _, tt := range tests {
+		assert.True(t, hasPermission(perms, tt.apiGroup, tt.resource, tt.verb), "Should include %s/%s:%s permission", tt.apiGroup, tt.resource, tt.verb)
+	}
+}
+
 func TestPermission_Structure(t *testing.T) {
 	perm := Permission{
 		APIGroup:  "apps",
@@ -0,0 +1,108 @@
+package remediation
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// DefaultApprovalExpiry is how long a workflow waits for approval before it is cancelled
+const DefaultApprovalExpiry = time.Hour
+
+// ErrWorkflowNotPendingApproval is returned when approving or rejecting a workflow
+// that is not waiting for approval
+var ErrWorkflowNotPendingApproval = errors.New("workflow is not pending approval")
+
+// ApprovalPolicy decides which remediations must wait for a human decision.
+// A workflow requires approval if any configured rule matches it.
+type ApprovalPolicy struct {
+	Severities  []string      // e.g. "critical"
+	Namespaces  []string      // exact names or glob patterns such as "prod-*"
+	Remediators []string      // e.g. "helm", "argocd"
+	Layers      []string      // multi-layer plans touching these layers, e.g. "infrastructure"
+	Expiry      time.Duration // unapproved workflows are cancelled after this long
+}
+
+// Enabled returns true if the policy has at least one rule
+func (p *ApprovalPolicy) Enabled() bool {
+	return p != nil &&
+		(len(p.Severities) > 0 || len(p.Namespaces) > 0 || len(p.Remediators) > 0 || len(p.Layers) > 0)
+}
+
+// ExpiryOrDefault returns the configured expiry, or DefaultApprovalExpiry if unset
+func (p *ApprovalPolicy) ExpiryOrDefault() time.Duration {
+	if p == nil || p.Expiry <= 0 {
+		return DefaultApprovalExpiry
+	}
+	return p.Expiry
+}
+
+// RequiresApproval checks a single-resource workflow against the severity, namespace
+// and remediator rules. It returns the matching rule as the reason.
+func (p *ApprovalPolicy) RequiresApproval(severity, namespace, remediator string) (bool, string) {
+	if !p.Enabled() {
+		return false, ""
+	}
+	if containsFold(p.Severities, severity) {
+		return true, fmt.Sprintf("severity %s requires approval", severity)
+	}
+	if p.matchesNamespace(namespace) {
+		return true, fmt.Sprintf("namespace %s requires approval", namespace)
+	}
+	if containsFold(p.Remediators, remediator) {
+		return true, fmt.Sprintf("remediator %s requires approval", remediator)
+	}
+	return false, ""
+}
+
+// RequiresPlanApproval checks a multi-layer plan against the severity, namespace and
+// layer rules
+func (p *ApprovalPolicy) RequiresPlanApproval(severity string, namespaces []string, layers []models.Layer) (bool, string) {
+	if !p.Enabled() {
+		return false, ""
+	}
+	if containsFold(p.Severities, severity) {
+		return true, fmt.Sprintf("severity %s requires approval", severity)
+	}
+	for _, ns := range namespaces {
+		if p.matchesNamespace(ns) {
+			return true, fmt.Sprintf("namespace %s requires approval", ns)
+		}
+	}
+	for _, layer := range layers {
+		if containsFold(p.Layers, string(layer)) {
+			return true, fmt.Sprintf("%s layer requires approval", layer)
+		}
+	}
+	return false, ""
+}
+
+// matchesNamespace reports whether namespace matches any namespace rule
+func (p *ApprovalPolicy) matchesNamespace(namespace string) bool {
+	if namespace == "" {
+		return false
+	}
+	for _, pattern := range p.Namespaces {
+		if ok, err := path.Match(pattern, namespace); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
+
+// containsFold reports whether values contains s, ignoring case
+func containsFold(values []string, s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, v := range values {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,147 @@
+package remediation
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+func TestApprovalPolicy_RequiresApproval(t *testing.T) {
+	policy := &ApprovalPolicy{
+		Severities:  []string{"critical"},
+		Namespaces:  []string{"prod-*", "payments"},
+		Remediators: []string{"helm"},
+	}
+
+	tests := []struct {
+		name       string
+		severity   string
+		namespace  string
+		remediator string
+		expected   bool
+	}{
+		{name: "Critical severity", severity: "Critical", namespace: "dev", remediator: "manual", expected: true},
+		{name: "Namespace glob", severity: "low", namespace: "prod-eu", remediator: "manual", expected: true},
+		{name: "Exact namespace", severity: "low", namespace: "payments", remediator: "manual", expected: true},
+		{name: "Remediator", severity: "low", namespace: "dev", remediator: "helm", expected: true},
+		{name: "No match", severity: "high", namespace: "dev", remediator: "manual", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			required, reason := policy.RequiresApproval(tt.severity, tt.namespace, tt.remediator)
+			assert.Equal(t, tt.expected, required)
+			if tt.expected {
+				assert.NotEmpty(t, reason)
+			}
+		})
+	}
+
+	var disabled *ApprovalPolicy
+	required, _ := disabled.RequiresApproval("critical", "prod-eu", "helm")
+	assert.False(t, required)
+	assert.Equal(t, DefaultApprovalExpiry, disabled.ExpiryOrDefault())
+}
+
+func TestApprovalPolicy_RequiresPlanApproval(t *testing.T) {
+	policy := &ApprovalPolicy{Layers: []string{"infrastructure"}}
+
+	required, reason := policy.RequiresPlanApproval("low", []string{"dev"},
+		[]models.Layer{models.LayerInfrastructure, models.LayerApplication})
+	assert.True(t, required)
+	assert.Contains(t, reason, "infrastructure")
+
+	required, _ = policy.RequiresPlanApproval("low", []string{"dev"}, []models.Layer{models.LayerApplication})
+	assert.False(t, required)
+}
+
+func TestOrchestrator_ApproveWorkflow(t *testing.T) {
+	remediator := newBlockingRemediator()
+	defer close(remediator.release)
+	o := newTestOrchestrator(remediator)
+	o.SetApprovalPolicy(&ApprovalPolicy{Severities: []string{"high"}})
+
+	wf, created, err := o.TriggerRemediation(context.Background(), "inc-1", newTestIssue("inc-1"))
+	require.NoError(t, err)
+	assert.True(t, created)
+	assert.Equal(t, models.WorkflowStatusPendingApproval, wf.Status)
+	require.NotNil(t, wf.Approval)
+	assert.True(t, wf.Approval.IsPending())
+
+	// A repeat trigger is folded into the held workflow
+	again, created, err := o.TriggerRemediation(context.Background(), "inc-1", newTestIssue("inc-1"))
+	require.NoError(t, err)
+	assert.False(t, created)
+	assert.Equal(t, wf.ID, again.ID)
+
+	approved, err := o.ApproveWorkflow(wf.ID, "alice", "looks safe")
+	require.NoError(t, err)
+	assert.Equal(t, models.ApprovalDecisionApproved, approved.Approval.Decision)
+	assert.Equal(t, "alice", approved.Approval.DecidedBy)
+	assert.NotNil(t, approved.Approval.DecidedAt)
+
+	<-remediator.started
+	running := waitForStatus(t, o, wf.ID, models.WorkflowStatusRunning)
+	assert.Equal(t, "alice", running.Approval.DecidedBy)
+
+	// A second decision is rejected
+	_, err = o.ApproveWorkflow(wf.ID, "bob", "")
+	assert.True(t, errors.Is(err, ErrWorkflowNotPendingApproval))
+}
+
+func TestOrchestrator_RejectWorkflow(t *testing.T) {
+	o := newTestOrchestrator(newBlockingRemediator())
+	o.SetApprovalPolicy(&ApprovalPolicy{Namespaces: []string{"default"}})
+
+	wf, _, err := o.TriggerRemediation(context.Background(), "inc-1", newTestIssue("inc-1"))
+	require.NoError(t, err)
+
+	rejected, err := o.RejectWorkflow(wf.ID, "bob", "not during business hours")
+	require.NoError(t, err)
+	assert.Equal(t, models.WorkflowStatusRejected, rejected.Status)
+	assert.Equal(t, models.ApprovalDecisionRejected, rejected.Approval.Decision)
+	assert.Equal(t, "not during business hours", rejected.Approval.Comment)
+
+	stored, err := o.GetWorkflow(wf.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.WorkflowStatusRejected, stored.Status)
+	assert.Nil(t, stored.StartedAt)
+
+	// The resource is free for a new workflow once the held one is closed
+	_, created, err := o.TriggerRemediation(context.Background(), "inc-2", newTestIssue("inc-2"))
+	require.NoError(t, err)
+	assert.True(t, created)
+
+	_, err = o.RejectWorkflow("wf-missing", "bob", "")
+	assert.True(t, errors.Is(err, ErrWorkflowNotFound))
+}
+
+func TestOrchestrator_ApprovalExpires(t *testing.T) {
+	o := newTestOrchestrator(newBlockingRemediator())
+	o.SetApprovalPolicy(&ApprovalPolicy{Severities: []string{"high"}, Expiry: 20 * time.Millisecond})
+
+	wf, _, err := o.TriggerRemediation(context.Background(), "inc-1", newTestIssue("inc-1"))
+	require.NoError(t, err)
+
+	expired := waitForStatus(t, o, wf.ID, models.WorkflowStatusCancelled)
+	assert.Equal(t, models.ApprovalDecisionExpired, expired.Approval.Decision)
+	assert.Contains(t, expired.ErrorMessage, "approval not given")
+}
+
+func TestOrchestrator_CancelPendingApproval(t *testing.T) {
+	o := newTestOrchestrator(newBlockingRemediator())
+	o.SetApprovalPolicy(&ApprovalPolicy{Severities: []string{"high"}})
+
+	wf, _, err := o.TriggerRemediation(context.Background(), "inc-1", newTestIssue("inc-1"))
+	require.NoError(t, err)
+
+	require.NoError(t, o.CancelWorkflow(wf.ID))
+	cancelled := waitForStatus(t, o, wf.ID, models.WorkflowStatusCancelled)
+	assert.True(t, cancelled.Approval.IsPending())
+}
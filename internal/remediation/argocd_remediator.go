@@ -4,9 +4,12 @@ package remediation
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
 
 	"github.com/tosin2013/openshift-coordination-engine/internal/integrations"
 	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
@@ -15,10 +18,20 @@ import (
 // ArgoCDRemediator handles ArgoCD-managed application remediation
 type ArgoCDRemediator struct {
 	argocdClient *integrations.ArgoCDClient
+	recorder     EventRecorder
+	policy       *PolicyEngine // nil uses the built-in policy
+	memory       *MemoryResizer
 	log          *logrus.Logger
 	syncTimeout  time.Duration
 }
 
+// argocdPolicyActions are the policy actions the ArgoCD remediator executes
+var argocdPolicyActions = policyActionSet{
+	"argocd_sync":          true,
+	"argocd_wait_for_sync": true,
+	"recommend_memory":     true,
+}
+
 // NewArgoCDRemediator creates a new ArgoCD remediator
 func NewArgoCDRemediator(argocdClient *integrations.ArgoCDClient, log *logrus.Logger) *ArgoCDRemediator {
 	return &ArgoCDRemediator{
@@ -29,7 +42,9 @@ func NewArgoCDRemediator(argocdClient *integrations.ArgoCDClient, log *logrus.Lo
 }
 
 // Remediate performs ArgoCD-based remediation by triggering sync
-func (ar *ArgoCDRemediator) Remediate(ctx context.Context, deploymentInfo *models.DeploymentInfo, issue *models.Issue) error {
+func (ar *ArgoCDRemediator) Remediate(ctx context.Context, deploymentInfo *models.DeploymentInfo, issue *models.Issue) (*models.RemediationResult, error) {
+	result := models.NewRemediationResult(ar.Name())
+
 	ar.log.WithFields(logrus.Fields{
 		"namespace":  issue.Namespace,
 		"resource":   issue.ResourceName,
@@ -37,29 +52,34 @@ func (ar *ArgoCDRemediator) Remediate(ctx context.Context, deploymentInfo *model
 		"method":     "argocd",
 	}).Info("Starting ArgoCD remediation")
 
+	decision, err := ar.policy.Resolve(ctx, deploymentInfo, issue, argocdPolicyActions)
+	if err != nil {
+		return result, err
+	}
+	result.AddEvidence("policy_rule", decision.Rule)
+
 	// Find ArgoCD application managing this resource
-	appName := deploymentInfo.GetDetail("argocd_app")
-	if appName == "" {
-		// Try to find application by resource
-		app, err := ar.argocdClient.FindApplicationByResource(ctx, issue.Namespace, issue.ResourceName, issue.ResourceType)
-		if err != nil {
-			return fmt.Errorf("failed to find ArgoCD application: %w", err)
-		}
-		appName = app.Metadata.Name
+	appName, err := ar.applicationName(ctx, deploymentInfo, issue)
+	if err != nil {
+		return result, err
 	}
+	result.AddEvidence("argocd_app", appName)
 
 	ar.log.WithField("app_name", appName).Info("Found ArgoCD application")
 
 	// Get current application status
 	app, err := ar.argocdClient.GetApplication(ctx, appName)
 	if err != nil {
-		return fmt.Errorf("failed to get application status: %w", err)
+		return result, fmt.Errorf("failed to get application status: %w", err)
 	}
+	result.AddEvidence("sync_status", app.Status.Sync.Status)
+	result.AddEvidence("health_status", app.Status.Health.Status)
 
 	ar.log.WithFields(logrus.Fields{
 		"app_name":      appName,
 		"sync_status":   app.Status.Sync.Status,
 		"health_status": app.Status.Health.Status,
+		"rule":          decision.Rule,
 	}).Info("Current application status")
 
 	// Check if application is already synced and healthy
@@ -67,34 +87,126 @@ func (ar *ArgoCDRemediator) Remediate(ctx context.Context, deploymentInfo *model
 		ar.log.Info("Application is already synced and healthy, triggering refresh sync")
 	}
 
-	// Trigger ArgoCD sync (respects GitOps workflow)
-	syncReq := &integrations.SyncRequest{
-		Prune:  false, // Don't prune by default - safer
-		DryRun: false,
+	for i := range decision.Actions {
+		action := &decision.Actions[i]
+		if err := ar.policy.Execute(ctx, issue, *action, func(ctx context.Context) error {
+			return ar.execute(ctx, action, appName, app, issue, result)
+		}); err != nil {
+			return result, err
+		}
 	}
 
-	// For CrashLoopBackOff or similar, trigger a full sync
-	if issue.Type == "CrashLoopBackOff" || issue.Type == "pod_crash_loop" {
-		ar.log.Info("Triggering full ArgoCD sync for crash loop issue")
-		if err := ar.argocdClient.SyncApplication(ctx, appName, syncReq); err != nil {
-			return fmt.Errorf("failed to trigger sync: %w", err)
+	ar.log.WithField("app_name", appName).Info("ArgoCD remediation completed successfully")
+	return result, nil
+}
+
+// execute carries out one policy action on the application and records it in the result
+func (ar *ArgoCDRemediator) execute(ctx context.Context, action *PolicyAction, appName string, app *integrations.Application,
+	issue *models.Issue, result *models.RemediationResult) error {
+	switch action.Action {
+	case "argocd_sync":
+		// Trigger ArgoCD sync (respects GitOps workflow); pruning is off unless the policy enables it
+		syncReq := &integrations.SyncRequest{
+			Prune:  action.Param("prune", "false") == "true",
+			DryRun: false,
 		}
-	} else {
-		// For other issues, try targeted sync
-		ar.log.Info("Triggering ArgoCD sync")
+		ar.log.WithFields(logrus.Fields{
+			"app_name": appName,
+			"prune":    syncReq.Prune,
+		}).Info("Triggering ArgoCD sync")
 		if err := ar.argocdClient.SyncApplication(ctx, appName, syncReq); err != nil {
 			return fmt.Errorf("failed to trigger sync: %w", err)
 		}
+		targetRevision := app.Spec.Source.TargetRevision
+		if targetRevision == "" {
+			targetRevision = "HEAD"
+		}
+		result.AddAction("argocd_sync", "Application "+appName,
+			strings.TrimSpace(fmt.Sprintf("%s %s", app.Status.Sync.Status, app.Status.Sync.Revision)), "syncing to "+targetRevision)
+
+		appRef := corev1.ObjectReference{
+			APIVersion: "argoproj.io/v1alpha1",
+			Kind:       "Application",
+			Namespace:  app.Metadata.Namespace,
+			Name:       appName,
+			UID:        types.UID(app.Metadata.UID),
+		}
+		recordOnTargets(ctx, ar.recorder, RemediationEvent{
+			Reason:     EventReasonSyncTriggered,
+			Action:     "argocd_sync",
+			Remediator: ar.Name(),
+			Message:    fmt.Sprintf("Triggered ArgoCD sync of application %s to remediate %s", appName, issue.Type),
+		}, appRef, workloadRef(issue.ResourceType, issue.Namespace, issue.ResourceName))
+		return nil
+	case "recommend_memory":
+		// The next sync would revert a change to the live object
+		result.Recommend(ar.memory.Recommend(ctx, issue, fmt.Sprintf("the manifests of ArgoCD application %s", appName), false)...)
+		return nil
+	case "argocd_wait_for_sync":
+		timeout := ar.waitTimeout(action)
+		ar.log.WithField("timeout", timeout).Info("Waiting for ArgoCD sync completion")
+		if err := ar.argocdClient.WaitForSync(ctx, appName, timeout); err != nil {
+			return fmt.Errorf("sync did not complete successfully: %w", err)
+		}
+		result.AddEvidence("sync_result", "Synced")
+		return nil
+	default:
+		return Permanent(fmt.Errorf("ArgoCD remediator cannot execute action %s", action.Action))
+	}
+}
+
+// waitTimeout returns how long to wait for a sync: the action's timeout, or the
+// remediator default
+func (ar *ArgoCDRemediator) waitTimeout(action *PolicyAction) time.Duration {
+	if action.timeout > 0 {
+		return action.timeout
 	}
+	return ar.syncTimeout
+}
 
-	// Wait for sync to complete
-	ar.log.WithField("timeout", ar.syncTimeout).Info("Waiting for ArgoCD sync completion")
-	if err := ar.argocdClient.WaitForSync(ctx, appName, ar.syncTimeout); err != nil {
-		return fmt.Errorf("sync did not complete successfully: %w", err)
+// applicationName returns the ArgoCD application managing the issue's resource
+func (ar *ArgoCDRemediator) applicationName(ctx context.Context, deploymentInfo *models.DeploymentInfo, issue *models.Issue) (string, error) {
+	if appName := deploymentInfo.GetDetail("argocd_app"); appName != "" {
+		return appName, nil
 	}
+	// Try to find application by resource
+	app, err := ar.argocdClient.FindApplicationByResource(ctx, issue.Namespace, issue.ResourceName, issue.ResourceType)
+	if err != nil {
+		return "", fmt.Errorf("failed to find ArgoCD application: %w", err)
+	}
+	return app.Metadata.Name, nil
+}
 
-	ar.log.WithField("app_name", appName).Info("ArgoCD remediation completed successfully")
-	return nil
+// PlanActions resolves the ArgoCD application and describes the sync Remediate would trigger
+func (ar *ArgoCDRemediator) PlanActions(ctx context.Context, deploymentInfo *models.DeploymentInfo, issue *models.Issue) ([]PlannedAction, error) {
+	decision, err := ar.policy.Resolve(ctx, deploymentInfo, issue, argocdPolicyActions)
+	if err != nil {
+		return nil, err
+	}
+	appName, err := ar.applicationName(ctx, deploymentInfo, issue)
+	if err != nil {
+		return nil, err
+	}
+
+	planned := make([]PlannedAction, 0, len(decision.Actions))
+	for i := range decision.Actions {
+		action := &decision.Actions[i]
+		plan := PlannedAction{Action: action.Action, Target: appName}
+		switch action.Action {
+		case "argocd_sync":
+			if action.Param("prune", "false") == "true" {
+				plan.Description = fmt.Sprintf("ArgoCD sync app %s with pruning", appName)
+			} else {
+				plan.Description = fmt.Sprintf("ArgoCD sync app %s without pruning", appName)
+			}
+		case "argocd_wait_for_sync":
+			plan.Description = fmt.Sprintf("wait up to %s for app %s to become synced and healthy", ar.waitTimeout(action), appName)
+		case "recommend_memory":
+			plan.Description = fmt.Sprintf("recommend raising the memory of %s in the manifests of app %s", issue.ResourceName, appName)
+		}
+		planned = append(planned, plan)
+	}
+	return planned, nil
 }
 
 // CanRemediate returns true if deployment is ArgoCD-managed
@@ -107,6 +219,21 @@ func (ar *ArgoCDRemediator) Name() string {
 	return "argocd"
 }
 
+// SetEventRecorder records the actions taken as Kubernetes Events
+func (ar *ArgoCDRemediator) SetEventRecorder(recorder EventRecorder) {
+	ar.recorder = recorder
+}
+
+// SetMemoryResizer sets how memory recommendations for OOM-killed workloads are sized
+func (ar *ArgoCDRemediator) SetMemoryResizer(memory *MemoryResizer) {
+	ar.memory = memory
+}
+
+// SetPolicy sets the remediation policy that maps issues to actions
+func (ar *ArgoCDRemediator) SetPolicy(policy *PolicyEngine) {
+	ar.policy = policy
+}
+
 // SetSyncTimeout allows customizing the sync timeout
 func (ar *ArgoCDRemediator) SetSyncTimeout(timeout time.Duration) {
 	ar.syncTimeout = timeout
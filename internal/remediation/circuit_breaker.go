@@ -0,0 +1,328 @@
+package remediation
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// Circuit breaker defaults
+const (
+	DefaultBreakerThreshold = 3
+	DefaultBreakerWindow    = time.Hour
+	DefaultBreakerCooldown  = 30 * time.Minute
+)
+
+// Circuit breaker states
+const (
+	BreakerStateClosed = "closed"
+	BreakerStateOpen   = "open"
+)
+
+// BreakerKey identifies the remediations a circuit breaker counts
+type BreakerKey struct {
+	Namespace string `json:"namespace"`
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	IssueType string `json:"issue_type"`
+}
+
+// String returns namespace/kind/name:issue_type
+func (k BreakerKey) String() string {
+	return fmt.Sprintf("%s/%s/%s:%s", k.Namespace, k.Kind, k.Name, k.IssueType)
+}
+
+// breakerKeyForIssue returns the breaker key of an issue
+func breakerKeyForIssue(issue *models.Issue) BreakerKey {
+	return BreakerKey{
+		Namespace: issue.Namespace,
+		Kind:      NormalizeKind(issue.ResourceType),
+		Name:      issue.ResourceName,
+		IssueType: issue.Type,
+	}
+}
+
+// BreakerStatus is the queryable state of one circuit breaker
+type BreakerStatus struct {
+	BreakerKey
+	State    string     `json:"state"`
+	Failures int        `json:"failures"` // failed or ineffective remediations in the window
+	Attempts int        `json:"attempts"` // remediations in the window
+	OpenedAt *time.Time `json:"opened_at,omitempty"`
+	ResetsAt *time.Time `json:"resets_at,omitempty"`
+}
+
+// breakerAttempt is one finished remediation counted by a breaker
+type breakerAttempt struct {
+	at     time.Time
+	failed bool
+}
+
+// breaker tracks the remediations of one key
+type breaker struct {
+	attempts []breakerAttempt
+	openedAt *time.Time
+	reset    *time.Timer
+}
+
+// CircuitBreaker stops remediating a resource whose issue keeps coming back. Each
+// key counts failed remediations and successful ones that did not hold, i.e. the
+// issue was triggered again within the window. Once Threshold is reached the
+// breaker opens and refuses triggers until Cooldown has passed.
+type CircuitBreaker struct {
+	Threshold int
+	Window    time.Duration
+	Cooldown  time.Duration
+
+	breakers map[BreakerKey]*breaker
+	now      func() time.Time
+	mu       sync.Mutex
+	log      *logrus.Logger
+}
+
+// NewCircuitBreaker creates a circuit breaker. A zero window or cooldown uses the default.
+func NewCircuitBreaker(threshold int, window, cooldown time.Duration, log *logrus.Logger) *CircuitBreaker {
+	if window <= 0 {
+		window = DefaultBreakerWindow
+	}
+	if cooldown <= 0 {
+		cooldown = DefaultBreakerCooldown
+	}
+	return &CircuitBreaker{
+		Threshold: threshold,
+		Window:    window,
+		Cooldown:  cooldown,
+		breakers:  make(map[BreakerKey]*breaker),
+		now:       time.Now,
+		log:       log,
+	}
+}
+
+// Enabled returns true if the breaker can open. A nil breaker is disabled.
+func (cb *CircuitBreaker) Enabled() bool {
+	return cb != nil && cb.Threshold > 0
+}
+
+// Allow reports whether a new remediation of key may start. A trigger arriving
+// while the last remediation in the window succeeded marks that remediation as
+// ineffective. When the breaker is open, the returned status explains why.
+func (cb *CircuitBreaker) Allow(key BreakerKey) (bool, *BreakerStatus) {
+	if !cb.Enabled() {
+		return true, nil
+	}
+
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	b := cb.breakers[key]
+	if b == nil {
+		return true, nil
+	}
+	if b.openedAt != nil {
+		RecordBreakerRejection(key.Namespace, key.IssueType)
+		return false, cb.status(key, b)
+	}
+
+	cb.prune(b)
+	if n := len(b.attempts); n > 0 && !b.attempts[n-1].failed {
+		// The issue came back after a successful remediation
+		b.attempts[n-1].failed = true
+	}
+	if cb.failures(b) < cb.Threshold {
+		return true, nil
+	}
+
+	cb.open(key, b)
+	RecordBreakerRejection(key.Namespace, key.IssueType)
+	return false, cb.status(key, b)
+}
+
+// Peek reports whether Allow would let a remediation of key start, without
+// counting the trigger or opening the breaker
+func (cb *CircuitBreaker) Peek(key BreakerKey) (bool, *BreakerStatus) {
+	if !cb.Enabled() {
+		return true, nil
+	}
+
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	b := cb.breakers[key]
+	if b == nil {
+		return true, nil
+	}
+	if b.openedAt != nil {
+		return false, cb.status(key, b)
+	}
+
+	status := cb.status(key, b)
+	cutoff := cb.now().Add(-cb.Window)
+	status.Failures, status.Attempts = 0, 0
+	for i, attempt := range b.attempts {
+		if attempt.at.Before(cutoff) {
+			continue
+		}
+		status.Attempts++
+		// Allow counts a trigger after a successful remediation against it
+		if attempt.failed || i == len(b.attempts)-1 {
+			status.Failures++
+		}
+	}
+	return status.Failures < cb.Threshold, status
+}
+
+// RecordOutcome counts a finished remediation of key. Only completed, failed and
+// failed verification outcomes are counted.
+func (cb *CircuitBreaker) RecordOutcome(key BreakerKey, status models.WorkflowStatus) {
+	if !cb.Enabled() {
+		return
+	}
+
+	var failed bool
+	switch status {
+	case models.WorkflowStatusCompleted:
+	case models.WorkflowStatusFailed, models.WorkflowStatusFailedVerification:
+		failed = true
+	default:
+		return
+	}
+
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	b := cb.breakers[key]
+	if b == nil {
+		b = &breaker{}
+		cb.breakers[key] = b
+	}
+	if b.openedAt != nil {
+		return
+	}
+	b.attempts = append(b.attempts, breakerAttempt{at: cb.now(), failed: failed})
+	cb.prune(b)
+	if failed && cb.failures(b) >= cb.Threshold {
+		cb.open(key, b)
+	}
+}
+
+// Status returns the state of every tracked breaker, open breakers first
+func (cb *CircuitBreaker) Status() []BreakerStatus {
+	if cb == nil {
+		return []BreakerStatus{}
+	}
+
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	statuses := make([]BreakerStatus, 0, len(cb.breakers))
+	for key, b := range cb.breakers {
+		cb.prune(b)
+		if b.openedAt == nil && len(b.attempts) == 0 {
+			delete(cb.breakers, key)
+			continue
+		}
+		statuses = append(statuses, *cb.status(key, b))
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		if statuses[i].State != statuses[j].State {
+			return statuses[i].State == BreakerStateOpen
+		}
+		return statuses[i].String() < statuses[j].String()
+	})
+	return statuses
+}
+
+// Reset closes the breaker of key and forgets its attempts
+func (cb *CircuitBreaker) Reset(key BreakerKey) bool {
+	if cb == nil {
+		return false
+	}
+
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	b, ok := cb.breakers[key]
+	if !ok {
+		return false
+	}
+	cb.close(key, b)
+	return true
+}
+
+// open trips the breaker of key and schedules its reset after the cooldown
+func (cb *CircuitBreaker) open(key BreakerKey, b *breaker) {
+	openedAt := cb.now()
+	b.openedAt = &openedAt
+	b.reset = time.AfterFunc(cb.Cooldown, func() {
+		cb.mu.Lock()
+		defer cb.mu.Unlock()
+		// A manual reset may already have replaced this breaker
+		if cb.breakers[key] == b {
+			cb.close(key, b)
+		}
+	})
+
+	UpdateBreakerState(key, true)
+	RecordBreakerTrip(key.Namespace, key.IssueType)
+	cb.log.WithFields(logrus.Fields{
+		"breaker":  key.String(),
+		"failures": cb.failures(b),
+		"cooldown": cb.Cooldown,
+	}).Warn("Remediation circuit breaker opened")
+}
+
+// close removes the breaker of key
+func (cb *CircuitBreaker) close(key BreakerKey, b *breaker) {
+	if b.reset != nil {
+		b.reset.Stop()
+	}
+	delete(cb.breakers, key)
+	if b.openedAt != nil {
+		UpdateBreakerState(key, false)
+		cb.log.WithField("breaker", key.String()).Info("Remediation circuit breaker reset")
+	}
+}
+
+// prune drops attempts older than the window
+func (cb *CircuitBreaker) prune(b *breaker) {
+	cutoff := cb.now().Add(-cb.Window)
+	i := 0
+	for i < len(b.attempts) && b.attempts[i].at.Before(cutoff) {
+		i++
+	}
+	b.attempts = b.attempts[i:]
+}
+
+// failures counts failed or ineffective attempts in the window
+func (cb *CircuitBreaker) failures(b *breaker) int {
+	failures := 0
+	for _, attempt := range b.attempts {
+		if attempt.failed {
+			failures++
+		}
+	}
+	return failures
+}
+
+// status returns the queryable state of a breaker
+func (cb *CircuitBreaker) status(key BreakerKey, b *breaker) *BreakerStatus {
+	status := &BreakerStatus{
+		BreakerKey: key,
+		State:      BreakerStateClosed,
+		Failures:   cb.failures(b),
+		Attempts:   len(b.attempts),
+	}
+	if b.openedAt != nil {
+		openedAt := *b.openedAt
+		resetsAt := openedAt.Add(cb.Cooldown)
+		status.State = BreakerStateOpen
+		status.OpenedAt = &openedAt
+		status.ResetsAt = &resetsAt
+	}
+	return status
+}
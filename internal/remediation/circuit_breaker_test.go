@@ -0,0 +1,149 @@
+package remediation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+func newTestBreaker(threshold int, cooldown time.Duration) *CircuitBreaker {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	return NewCircuitBreaker(threshold, time.Hour, cooldown, log)
+}
+
+func TestCircuitBreaker_OpensAfterFailures(t *testing.T) {
+	cb := newTestBreaker(2, time.Hour)
+	key := BreakerKey{Namespace: "default", Kind: "Deployment", Name: "payment", IssueType: "CrashLoopBackOff"}
+	other := BreakerKey{Namespace: "default", Kind: "Deployment", Name: "payment", IssueType: "OOMKilled"}
+
+	allowed, _ := cb.Allow(key)
+	assert.True(t, allowed)
+	cb.RecordOutcome(key, models.WorkflowStatusFailed)
+	cb.RecordOutcome(key, models.WorkflowStatusCancelled) // not counted
+
+	allowed, _ = cb.Allow(key)
+	assert.True(t, allowed)
+	cb.RecordOutcome(key, models.WorkflowStatusFailedVerification)
+
+	allowed, status := cb.Allow(key)
+	assert.False(t, allowed)
+	require.NotNil(t, status)
+	assert.Equal(t, BreakerStateOpen, status.State)
+	assert.Equal(t, 2, status.Failures)
+	require.NotNil(t, status.ResetsAt)
+
+	// Breakers are per issue type
+	allowed, _ = cb.Allow(other)
+	assert.True(t, allowed)
+
+	statuses := cb.Status()
+	require.Len(t, statuses, 1)
+	assert.Equal(t, key, statuses[0].BreakerKey)
+
+	assert.True(t, cb.Reset(key))
+	allowed, _ = cb.Allow(key)
+	assert.True(t, allowed)
+}
+
+func TestCircuitBreaker_CountsIneffectiveRemediations(t *testing.T) {
+	cb := newTestBreaker(2, time.Hour)
+	key := BreakerKey{Namespace: "default", Kind: "Deployment", Name: "payment", IssueType: "CrashLoopBackOff"}
+
+	// Each successful remediation is followed by the issue coming back
+	cb.RecordOutcome(key, models.WorkflowStatusCompleted)
+	allowed, _ := cb.Allow(key)
+	assert.True(t, allowed)
+	cb.RecordOutcome(key, models.WorkflowStatusCompleted)
+
+	allowed, status := cb.Allow(key)
+	assert.False(t, allowed)
+	assert.Equal(t, 2, status.Failures)
+	assert.Equal(t, 2, status.Attempts)
+}
+
+func TestCircuitBreaker_Peek(t *testing.T) {
+	cb := newTestBreaker(2, time.Hour)
+	key := BreakerKey{Namespace: "default", Kind: "Deployment", Name: "payment", IssueType: "CrashLoopBackOff"}
+
+	allowed, status := cb.Peek(key)
+	assert.True(t, allowed)
+	assert.Nil(t, status)
+
+	cb.RecordOutcome(key, models.WorkflowStatusFailed)
+	cb.RecordOutcome(key, models.WorkflowStatusCompleted)
+
+	// A trigger now would count the completed remediation as ineffective and open
+	// the breaker, but peeking changes nothing
+	for i := 0; i < 2; i++ {
+		allowed, status = cb.Peek(key)
+		assert.False(t, allowed)
+		assert.Equal(t, BreakerStateClosed, status.State)
+		assert.Equal(t, 2, status.Failures)
+	}
+	assert.Equal(t, BreakerStateClosed, cb.Status()[0].State)
+
+	allowed, _ = cb.Allow(key)
+	assert.False(t, allowed)
+	_, status = cb.Peek(key)
+	assert.Equal(t, BreakerStateOpen, status.State)
+}
+
+func TestCircuitBreaker_ResetsAfterCooldown(t *testing.T) {
+	cb := newTestBreaker(1, 50*time.Millisecond)
+	key := BreakerKey{Namespace: "default", Kind: "Pod", Name: "web-0", IssueType: "OOMKilled"}
+
+	cb.RecordOutcome(key, models.WorkflowStatusFailed)
+	allowed, _ := cb.Allow(key)
+	assert.False(t, allowed)
+
+	require.Eventually(t, func() bool {
+		allowed, _ := cb.Allow(key)
+		return allowed
+	}, time.Second, 10*time.Millisecond)
+	assert.Empty(t, cb.Status())
+}
+
+func TestCircuitBreaker_Disabled(t *testing.T) {
+	var cb *CircuitBreaker
+	allowed, _ := cb.Allow(BreakerKey{Name: "payment"})
+	assert.True(t, allowed)
+	cb.RecordOutcome(BreakerKey{Name: "payment"}, models.WorkflowStatusFailed)
+	assert.Empty(t, cb.Status())
+}
+
+func TestOrchestrator_CircuitOpenRefusesTrigger(t *testing.T) {
+	remediator := newBlockingRemediator()
+	close(remediator.release)
+	o := newTestOrchestrator(remediator)
+	cb := newTestBreaker(1, time.Hour)
+	o.SetCircuitBreaker(cb)
+
+	issue := newTestIssue("inc-1")
+	cb.RecordOutcome(breakerKeyForIssue(issue), models.WorkflowStatusFailed)
+
+	wf, created, err := o.TriggerRemediation(context.Background(), "inc-1", issue)
+	require.NoError(t, err)
+	assert.True(t, created)
+	assert.Equal(t, models.WorkflowStatusCircuitOpen, wf.Status)
+	assert.False(t, wf.IsActive())
+	assert.Contains(t, wf.ErrorMessage, "circuit breaker open")
+	assert.NotEmpty(t, wf.Recommendations)
+	assert.Equal(t, "escalated", wf.Steps[len(wf.Steps)-1].Status)
+
+	// Nothing was remediated and the refused workflow is stored
+	assert.Empty(t, remediator.started)
+	stored, err := o.GetWorkflow(wf.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.WorkflowStatusCircuitOpen, stored.Status)
+
+	breakers := o.CircuitBreakers()
+	require.Len(t, breakers, 1)
+	assert.Equal(t, BreakerStateOpen, breakers[0].State)
+}
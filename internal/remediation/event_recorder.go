@@ -0,0 +1,256 @@
+package remediation
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+// EventComponent is the source component of recorded Kubernetes Events
+const EventComponent = "coordination-engine"
+
+// Reasons of recorded Kubernetes Events
+const (
+	EventReasonRemediationStarted   = "RemediationStarted"
+	EventReasonRemediationSucceeded = "RemediationSucceeded"
+	EventReasonRemediationFailed    = "RemediationFailed"
+	EventReasonVerificationFailed   = "VerificationFailed"
+	EventReasonRemediationRefused   = "RemediationRefused"
+	EventReasonPodDeleted           = "PodDeleted"
+	EventReasonRestarted            = "Restarted"
+	EventReasonMemoryIncreased      = "MemoryIncreased"
+	EventReasonRolledBack           = "RolledBack"
+	EventReasonUpgraded             = "Upgraded"
+	EventReasonSyncTriggered        = "SyncTriggered"
+	EventReasonReconcileTriggered   = "ReconcileTriggered"
+)
+
+// Annotations attached to recorded Kubernetes Events
+const (
+	EventAnnotationWorkflowID = "remediation.aiops/workflow-id"
+	EventAnnotationRemediator = "remediation.aiops/remediator"
+	EventAnnotationAction     = "remediation.aiops/action"
+)
+
+// KindHelmRelease is a pseudo-kind for event targets naming a Helm release. The
+// Kubernetes recorder records such events on the release's latest storage Secret.
+const KindHelmRelease = "HelmRelease"
+
+// RemediationEvent describes an action taken on a Kubernetes object
+type RemediationEvent struct {
+	Target     corev1.ObjectReference // Kind, Namespace and Name are required
+	Type       string                 // corev1.EventTypeNormal or corev1.EventTypeWarning
+	Reason     string
+	Action     string // e.g. restart_deployment, helm_rollback
+	Remediator string
+	Message    string
+}
+
+// EventRecorder records remediation actions as Kubernetes Events
+type EventRecorder interface {
+	Record(ctx context.Context, event RemediationEvent)
+}
+
+// workflowIDKey is the context key of the workflow a remediation runs for
+type workflowIDKey struct{}
+
+// WithWorkflowID returns a context carrying the workflow ID recorded on events
+func WithWorkflowID(ctx context.Context, workflowID string) context.Context {
+	return context.WithValue(ctx, workflowIDKey{}, workflowID)
+}
+
+// WorkflowIDFromContext returns the workflow ID set by WithWorkflowID, or ""
+func WorkflowIDFromContext(ctx context.Context) string {
+	workflowID, _ := ctx.Value(workflowIDKey{}).(string)
+	return workflowID
+}
+
+// recordEvent records an event if recorder is set
+func recordEvent(ctx context.Context, recorder EventRecorder, event RemediationEvent) {
+	if recorder == nil {
+		return
+	}
+	if event.Type == "" {
+		event.Type = corev1.EventTypeNormal
+	}
+	recorder.Record(ctx, event)
+}
+
+// recordOnTargets records the same action on each target, e.g. a workload and the
+// ArgoCD Application or Helm release that owns it
+func recordOnTargets(ctx context.Context, recorder EventRecorder, event RemediationEvent, targets ...corev1.ObjectReference) {
+	for _, target := range targets {
+		event.Target = target
+		recordEvent(ctx, recorder, event)
+	}
+}
+
+// workloadRef returns an event target for a workload kind such as deployment or Pod
+func workloadRef(kind, namespace, name string) corev1.ObjectReference {
+	kind = NormalizeKind(kind)
+	var apiVersion string
+	switch kind {
+	case "Deployment", "StatefulSet", "DaemonSet", "ReplicaSet":
+		apiVersion = "apps/v1"
+	case "Pod":
+		apiVersion = "v1"
+	}
+	return corev1.ObjectReference{APIVersion: apiVersion, Kind: kind, Namespace: namespace, Name: name}
+}
+
+// KubeEventRecorder records remediation events through the Kubernetes Events API
+type KubeEventRecorder struct {
+	clientset     kubernetes.Interface
+	dynamicClient dynamic.Interface
+	broadcaster   record.EventBroadcaster
+	recorder      record.EventRecorder
+	log           *logrus.Logger
+}
+
+// NewKubeEventRecorder creates a recorder writing Events with clientset. The dynamic
+// client, which may be nil, resolves custom resources such as ArgoCD Applications.
+func NewKubeEventRecorder(clientset kubernetes.Interface, dynamicClient dynamic.Interface, log *logrus.Logger) *KubeEventRecorder {
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: clientset.CoreV1().Events("")})
+
+	return &KubeEventRecorder{
+		clientset:     clientset,
+		dynamicClient: dynamicClient,
+		broadcaster:   broadcaster,
+		recorder:      broadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: EventComponent}),
+		log:           log,
+	}
+}
+
+// Shutdown stops the event broadcaster after flushing queued events
+func (r *KubeEventRecorder) Shutdown() {
+	r.broadcaster.Shutdown()
+}
+
+// Record emits event on its target. Targets without a UID are looked up so the
+// event is listed by `oc describe`.
+func (r *KubeEventRecorder) Record(ctx context.Context, event RemediationEvent) {
+	target, err := r.resolve(ctx, event.Target)
+	if err != nil {
+		r.log.WithError(err).WithFields(logrus.Fields{
+			"kind":      event.Target.Kind,
+			"namespace": event.Target.Namespace,
+			"name":      event.Target.Name,
+			"reason":    event.Reason,
+		}).Debug("Skipping event for unresolvable target")
+		return
+	}
+
+	annotations := map[string]string{
+		EventAnnotationRemediator: event.Remediator,
+		EventAnnotationAction:     event.Action,
+	}
+	message := event.Message
+	if workflowID := WorkflowIDFromContext(ctx); workflowID != "" {
+		annotations[EventAnnotationWorkflowID] = workflowID
+		message = fmt.Sprintf("%s (workflow %s, remediator %s)", message, workflowID, event.Remediator)
+	}
+
+	r.recorder.AnnotatedEventf(target, annotations, event.Type, event.Reason, "%s", message)
+}
+
+// resolve fills in the UID and API version of an event target
+func (r *KubeEventRecorder) resolve(ctx context.Context, ref corev1.ObjectReference) (*corev1.ObjectReference, error) {
+	if ref.Kind == KindHelmRelease {
+		return r.helmReleaseSecret(ctx, ref.Namespace, ref.Name)
+	}
+	if ref.UID != "" {
+		return &ref, nil
+	}
+
+	var meta metav1.Object
+	var err error
+	switch ref.Kind {
+	case "Deployment":
+		meta, err = r.clientset.AppsV1().Deployments(ref.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+	case "StatefulSet":
+		meta, err = r.clientset.AppsV1().StatefulSets(ref.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+	case "DaemonSet":
+		meta, err = r.clientset.AppsV1().DaemonSets(ref.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+	case "ReplicaSet":
+		meta, err = r.clientset.AppsV1().ReplicaSets(ref.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+	case "Pod":
+		meta, err = r.clientset.CoreV1().Pods(ref.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+	case "Secret":
+		meta, err = r.clientset.CoreV1().Secrets(ref.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+	default:
+		meta, err = r.getCustomResource(ctx, ref)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ref.UID = meta.GetUID()
+	ref.ResourceVersion = ""
+	return &ref, nil
+}
+
+// getCustomResource looks up a custom resource with the dynamic client
+func (r *KubeEventRecorder) getCustomResource(ctx context.Context, ref corev1.ObjectReference) (metav1.Object, error) {
+	if r.dynamicClient == nil || ref.APIVersion == "" {
+		return nil, fmt.Errorf("cannot look up %s %s/%s", ref.Kind, ref.Namespace, ref.Name)
+	}
+	gv, err := schema.ParseGroupVersion(ref.APIVersion)
+	if err != nil {
+		return nil, fmt.Errorf("invalid apiVersion %q: %w", ref.APIVersion, err)
+	}
+	gvr := gv.WithResource(inferResourceName(ref.Kind))
+	return r.dynamicClient.Resource(gvr).Namespace(ref.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+}
+
+// helmReleaseSecret returns the storage Secret of the latest revision of a Helm release
+func (r *KubeEventRecorder) helmReleaseSecret(ctx context.Context, namespace, release string) (*corev1.ObjectReference, error) {
+	secrets, err := r.clientset.CoreV1().Secrets(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: "owner=helm,name=" + release,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list helm release secrets: %w", err)
+	}
+	if len(secrets.Items) == 0 {
+		return nil, fmt.Errorf("no helm release secret for %s/%s", namespace, release)
+	}
+
+	items := secrets.Items
+	sort.Slice(items, func(i, j int) bool {
+		return helmSecretVersion(&items[i]) > helmSecretVersion(&items[j])
+	})
+	latest := &items[0]
+	return &corev1.ObjectReference{
+		APIVersion: "v1",
+		Kind:       "Secret",
+		Namespace:  latest.Namespace,
+		Name:       latest.Name,
+		UID:        latest.UID,
+	}, nil
+}
+
+// helmSecretVersion returns the release revision stored in a Helm release Secret
+func helmSecretVersion(secret *corev1.Secret) int {
+	if version, err := strconv.Atoi(secret.Labels["version"]); err == nil {
+		return version
+	}
+	// Fall back to the sh.helm.release.v1.<release>.v<revision> name
+	if i := strings.LastIndex(secret.Name, ".v"); i >= 0 {
+		if version, err := strconv.Atoi(secret.Name[i+2:]); err == nil {
+			return version
+		}
+	}
+	return 0
+}
@@ -0,0 +1,134 @@
+package remediation
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// recordedEvent is an event captured by fakeEventRecorder
+type recordedEvent struct {
+	RemediationEvent
+	WorkflowID string
+}
+
+// fakeEventRecorder captures recorded events
+type fakeEventRecorder struct {
+	mu     sync.Mutex
+	events []recordedEvent
+}
+
+func (f *fakeEventRecorder) Record(ctx context.Context, event RemediationEvent) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.events = append(f.events, recordedEvent{RemediationEvent: event, WorkflowID: WorkflowIDFromContext(ctx)})
+}
+
+func (f *fakeEventRecorder) recorded() []recordedEvent {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]recordedEvent(nil), f.events...)
+}
+
+func TestManualRemediator_RecordsPodDeletedEvent(t *testing.T) {
+	clientset := fake.NewSimpleClientset(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-pod", Namespace: "default", UID: "pod-uid"},
+	})
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	recorder := &fakeEventRecorder{}
+
+	remediator := NewManualRemediator(clientset, log)
+	remediator.SetEventRecorder(recorder)
+
+	issue := &models.Issue{
+		ID:           "issue-1",
+		Type:         "CrashLoopBackOff",
+		Namespace:    "default",
+		ResourceType: "Pod",
+		ResourceName: "test-pod",
+	}
+	info := models.NewDeploymentInfo("default", "test-pod", "Pod", models.DeploymentMethodManual, 0.9)
+
+	_, err := remediator.Remediate(WithWorkflowID(context.Background(), "wf-1"), info, issue)
+	require.NoError(t, err)
+
+	events := recorder.recorded()
+	require.Len(t, events, 1)
+	assert.Equal(t, "wf-1", events[0].WorkflowID)
+	assert.Equal(t, EventReasonPodDeleted, events[0].Reason)
+	assert.Equal(t, corev1.EventTypeNormal, events[0].Type)
+	assert.Equal(t, "delete_pod", events[0].Action)
+	assert.Equal(t, "manual", events[0].Remediator)
+	assert.Equal(t, "Pod", events[0].Target.Kind)
+	assert.Equal(t, "pod-uid", string(events[0].Target.UID))
+}
+
+func TestKubeEventRecorder_Record(t *testing.T) {
+	clientset := fake.NewSimpleClientset(
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "test-pod", Namespace: "default", UID: "pod-uid"}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Name: "sh.helm.release.v1.app.v1", Namespace: "default", UID: "rev1-uid",
+			Labels: map[string]string{"owner": "helm", "name": "app", "version": "1"},
+		}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Name: "sh.helm.release.v1.app.v2", Namespace: "default", UID: "rev2-uid",
+			Labels: map[string]string{"owner": "helm", "name": "app", "version": "2"},
+		}},
+	)
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	recorder := NewKubeEventRecorder(clientset, nil, log)
+	t.Cleanup(recorder.Shutdown)
+	fakeRecorder := record.NewFakeRecorder(10)
+	recorder.recorder = fakeRecorder
+
+	ctx := WithWorkflowID(context.Background(), "wf-1")
+
+	t.Run("workload", func(t *testing.T) {
+		target, err := recorder.resolve(ctx, workloadRef("pod", "default", "test-pod"))
+		require.NoError(t, err)
+		assert.Equal(t, "pod-uid", string(target.UID))
+
+		recorder.Record(ctx, RemediationEvent{
+			Target:     workloadRef("Pod", "default", "test-pod"),
+			Type:       corev1.EventTypeNormal,
+			Reason:     EventReasonPodDeleted,
+			Action:     "delete_pod",
+			Remediator: "manual",
+			Message:    "Deleted pod",
+		})
+		event := <-fakeRecorder.Events
+		assert.Contains(t, event, "Normal PodDeleted Deleted pod (workflow wf-1, remediator manual)")
+		assert.Contains(t, event, EventAnnotationWorkflowID+":wf-1")
+		assert.Contains(t, event, EventAnnotationAction+":delete_pod")
+	})
+
+	t.Run("helm release resolves to latest secret", func(t *testing.T) {
+		target, err := recorder.resolve(ctx, corev1.ObjectReference{Kind: KindHelmRelease, Namespace: "default", Name: "app"})
+		require.NoError(t, err)
+		assert.Equal(t, "Secret", target.Kind)
+		assert.Equal(t, "sh.helm.release.v1.app.v2", target.Name)
+		assert.Equal(t, "rev2-uid", string(target.UID))
+	})
+
+	t.Run("missing target is skipped", func(t *testing.T) {
+		recorder.Record(ctx, RemediationEvent{
+			Target: workloadRef("Deployment", "default", "missing"),
+			Type:   corev1.EventTypeWarning,
+			Reason: EventReasonRemediationFailed,
+		})
+		assert.Empty(t, fakeRecorder.Events)
+	})
+}
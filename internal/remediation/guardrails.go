@@ -0,0 +1,257 @@
+package remediation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/detector"
+)
+
+// Guardrail annotations, honoured on workloads and their namespaces. An annotation
+// on the workload overrides the same annotation on its namespace.
+const (
+	AnnotationDisabled       = "remediation.aiops/disabled"        // "true" turns remediation off
+	AnnotationMode           = "remediation.aiops/mode"            // "recommend-only" limits remediation to recommendations
+	AnnotationAllowedActions = "remediation.aiops/allowed-actions" // comma-separated action categories, e.g. restart,rollback
+)
+
+// ModeRecommendOnly is the remediation mode that only produces recommendations
+const ModeRecommendOnly = "recommend-only"
+
+// Guardrail rules, reported in refusals and metrics
+const (
+	GuardrailNamespaceDenied     = "namespace_denied"
+	GuardrailNamespaceNotAllowed = "namespace_not_allowed"
+	GuardrailDisabled            = "disabled"
+	GuardrailRecommendOnly       = "recommend_only"
+	GuardrailActionNotAllowed    = "action_not_allowed"
+)
+
+var (
+	// ErrRemediationRefused is matched by every guardrail refusal
+	ErrRemediationRefused = errors.New("remediation refused by guardrails")
+
+	// ErrRecommendOnly is matched by refusals of recommend-only resources
+	ErrRecommendOnly = errors.New("resource is recommend-only")
+)
+
+// GuardrailError explains why guardrails refused a remediation
+type GuardrailError struct {
+	Rule   string
+	Reason string
+}
+
+// Error returns the refusal with its reason
+func (e *GuardrailError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrRemediationRefused, e.Reason)
+}
+
+// Is matches ErrRemediationRefused, and ErrRecommendOnly for recommend-only refusals
+func (e *GuardrailError) Is(target error) bool {
+	return target == ErrRemediationRefused || (target == ErrRecommendOnly && e.Rule == GuardrailRecommendOnly)
+}
+
+// ActionCategory returns the category that allows action in the allowed-actions
+// annotation, or "" for actions that change nothing
+func ActionCategory(action string) string {
+	switch {
+	case action == "helm_rollback", strings.HasPrefix(action, "rollback_"):
+		return "rollback"
+	case action == "delete_pod", strings.HasPrefix(action, "restart_"):
+		return "restart"
+	case action == "increase_memory":
+		return "resize"
+	case action == "helm_upgrade":
+		return "upgrade"
+	case action == "argocd_sync":
+		return "sync"
+	case action == "annotate_custom_resource", action == "trigger_operator_reconciliation":
+		return "reconcile"
+	case action == PluginAction:
+		return "plugin"
+	case action == "argocd_wait_for_sync", action == "manual_intervention", action == "recommend_memory",
+		strings.HasPrefix(action, "monitor_"):
+		return ""
+	default:
+		return action
+	}
+}
+
+// Guardrails decide whether a resource may be remediated at all, from namespace
+// allow and deny lists and from annotations on the resource and its namespace
+type Guardrails struct {
+	AllowNamespaces []string // when set, only matching namespaces are remediated; exact names or globs
+	DenyNamespaces  []string // never remediated, even when allowed
+
+	clientset kubernetes.Interface // reads annotations; nil only applies the namespace lists
+	dynamic   dynamic.Interface    // reads annotations of DeploymentConfigs and Argo Rollouts
+	log       *logrus.Logger
+}
+
+// NewGuardrails creates guardrails from namespace lists
+func NewGuardrails(allow, deny []string, clientset kubernetes.Interface, log *logrus.Logger) *Guardrails {
+	return &Guardrails{
+		AllowNamespaces: allow,
+		DenyNamespaces:  deny,
+		clientset:       clientset,
+		log:             log,
+	}
+}
+
+// SetDynamicClient enables reading the annotations of DeploymentConfigs and Argo
+// Rollouts. Without it, remediation of those workloads is refused.
+func (g *Guardrails) SetDynamicClient(client dynamic.Interface) {
+	g.dynamic = client
+}
+
+// Check returns nil if actions may be applied to the resource. A refusal is a
+// *GuardrailError explaining which rule refused it.
+func (g *Guardrails) Check(ctx context.Context, namespace, kind, name string, actions []string) error {
+	if g == nil {
+		return nil
+	}
+
+	if matchesNamespace(g.DenyNamespaces, namespace) {
+		return g.refuse(GuardrailNamespaceDenied, fmt.Sprintf("namespace %s is on the remediation deny list", namespace))
+	}
+	if len(g.AllowNamespaces) > 0 && !matchesNamespace(g.AllowNamespaces, namespace) {
+		return g.refuse(GuardrailNamespaceNotAllowed, fmt.Sprintf("namespace %s is not on the remediation allow list", namespace))
+	}
+	if g.clientset == nil {
+		return nil
+	}
+
+	annotations, err := g.annotations(ctx, namespace, kind, name)
+	if err != nil {
+		return fmt.Errorf("failed to read remediation annotations: %w", err)
+	}
+
+	if a, ok := annotations[AnnotationDisabled]; ok && strings.EqualFold(a.value, "true") {
+		return g.refuse(GuardrailDisabled, fmt.Sprintf("remediation disabled by %s=true on %s", AnnotationDisabled, a.source))
+	}
+	if a, ok := annotations[AnnotationMode]; ok && strings.EqualFold(a.value, ModeRecommendOnly) {
+		return g.refuse(GuardrailRecommendOnly, fmt.Sprintf("%s=%s on %s", AnnotationMode, a.value, a.source))
+	}
+	if a, ok := annotations[AnnotationAllowedActions]; ok {
+		allowed := strings.Split(a.value, ",")
+		for i := range allowed {
+			allowed[i] = strings.TrimSpace(allowed[i])
+		}
+		for _, action := range actions {
+			category := ActionCategory(action)
+			if category != "" && !containsFold(allowed, category) {
+				return g.refuse(GuardrailActionNotAllowed, fmt.Sprintf("action %s (%s) is not allowed by %s=%s on %s",
+					action, category, AnnotationAllowedActions, a.value, a.source))
+			}
+		}
+	}
+	return nil
+}
+
+// refuse records and returns a refusal
+func (g *Guardrails) refuse(rule, reason string) error {
+	RecordGuardrailRefusal(rule)
+	return &GuardrailError{Rule: rule, Reason: reason}
+}
+
+// guardrailAnnotation is an annotation value and the object it was set on
+type guardrailAnnotation struct {
+	value  string
+	source string
+}
+
+// annotations returns the guardrail annotations that apply to a resource: those of
+// its namespace, overridden by its controller's for a pod, overridden by its own.
+// Objects that no longer exist are skipped.
+func (g *Guardrails) annotations(ctx context.Context, namespace, kind, name string) (map[string]guardrailAnnotation, error) {
+	result := make(map[string]guardrailAnnotation)
+	merge := func(meta metav1.Object, source string) {
+		for _, key := range []string{AnnotationDisabled, AnnotationMode, AnnotationAllowedActions} {
+			if value, ok := meta.GetAnnotations()[key]; ok {
+				result[key] = guardrailAnnotation{value: value, source: source}
+			}
+		}
+	}
+
+	ns, err := g.clientset.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{})
+	switch {
+	case err == nil:
+		merge(ns, "namespace "+namespace)
+	case !apierrors.IsNotFound(err):
+		return nil, fmt.Errorf("failed to get namespace %s: %w", namespace, err)
+	}
+
+	kind = NormalizeKind(kind)
+	source := fmt.Sprintf("%s %s/%s", kind, namespace, name)
+	var meta metav1.Object
+	switch kind {
+	case "Deployment":
+		meta, err = g.clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+	case "StatefulSet":
+		meta, err = g.clientset.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+	case "DaemonSet":
+		meta, err = g.clientset.AppsV1().DaemonSets(namespace).Get(ctx, name, metav1.GetOptions{})
+	case "DeploymentConfig", "Rollout":
+		if g.dynamic == nil {
+			return nil, fmt.Errorf("cannot read %s without a dynamic client", source)
+		}
+		gvr := detector.DeploymentConfigGVR
+		if kind == "Rollout" {
+			gvr = detector.RolloutGVR
+		}
+		meta, err = g.dynamic.Resource(gvr).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	case "Pod":
+		var pod *corev1.Pod
+		pod, err = g.clientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			break
+		}
+		// Annotations are usually set on the pod's workload rather than the pod
+		if owner := metav1.GetControllerOf(pod); owner != nil {
+			chain, chainErr := detector.ResolveOwnerChain(ctx, g.clientset, namespace, owner.Kind, owner.Name)
+			if chainErr == nil {
+				controller := chain.Top()
+				workload, err := g.annotations(ctx, namespace, controller.Kind, controller.Name)
+				if err != nil {
+					return nil, err
+				}
+				for key, a := range workload {
+					result[key] = a
+				}
+			}
+		}
+		meta = pod
+	default:
+		return result, nil
+	}
+
+	switch {
+	case err == nil:
+		merge(meta, source)
+	case apierrors.IsNotFound(err):
+		g.log.WithField("resource", source).Debug("Resource not found while reading remediation annotations")
+	default:
+		return nil, fmt.Errorf("failed to get %s: %w", source, err)
+	}
+	return result, nil
+}
+
+// matchesNamespace returns true if namespace matches any exact name or glob
+func matchesNamespace(patterns []string, namespace string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, namespace); matched {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,179 @@
+package remediation
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+func newTestGuardrails(allow, deny []string, objects ...runtime.Object) *Guardrails {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	guardrails := NewGuardrails(allow, deny, fake.NewSimpleClientset(objects...), log)
+	guardrails.SetDynamicClient(dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(),
+		newGuardrailWorkload("apps.openshift.io/v1", "DeploymentConfig", "legacy-api", AnnotationDisabled, "true"),
+		newGuardrailWorkload("argoproj.io/v1alpha1", "Rollout", "canary", AnnotationAllowedActions, "sync"),
+	))
+	return guardrails
+}
+
+// newGuardrailWorkload returns a workload read through the dynamic client, annotated
+// with one guardrail annotation
+func newGuardrailWorkload(apiVersion, kind, name, annotation, value string) *unstructured.Unstructured {
+	workload := &unstructured.Unstructured{}
+	workload.SetAPIVersion(apiVersion)
+	workload.SetKind(kind)
+	workload.SetNamespace("prod")
+	workload.SetName(name)
+	workload.SetAnnotations(map[string]string{annotation: value})
+	return workload
+}
+
+func TestGuardrails_Check(t *testing.T) {
+	isController := true
+	objects := []runtime.Object{
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name: "legacy", Annotations: map[string]string{AnnotationDisabled: "true"},
+		}},
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+			Name: "billing", Namespace: "legacy", Annotations: map[string]string{AnnotationDisabled: "false"},
+		}},
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+			Name: "payment", Namespace: "prod", Annotations: map[string]string{AnnotationMode: ModeRecommendOnly},
+		}},
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+			Name: "checkout", Namespace: "prod", Annotations: map[string]string{AnnotationAllowedActions: "restart, sync"},
+		}},
+		&appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{
+			Name: "payment-5d4f", Namespace: "prod",
+			OwnerReferences: []metav1.OwnerReference{{Kind: "Deployment", Name: "payment", Controller: &isController}},
+		}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name: "payment-5d4f-x7k2p", Namespace: "prod",
+			OwnerReferences: []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "payment-5d4f", Controller: &isController}},
+		}},
+		&corev1.ReplicationController{ObjectMeta: metav1.ObjectMeta{
+			Name: "legacy-api-3", Namespace: "prod",
+			OwnerReferences: []metav1.OwnerReference{{Kind: "DeploymentConfig", Name: "legacy-api", Controller: &isController}},
+		}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name: "legacy-api-3-x7k2p", Namespace: "prod",
+			OwnerReferences: []metav1.OwnerReference{{Kind: "ReplicationController", Name: "legacy-api-3", Controller: &isController}},
+		}},
+		&appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{
+			Name: "canary-6c8b", Namespace: "prod",
+			OwnerReferences: []metav1.OwnerReference{{Kind: "Rollout", Name: "canary", Controller: &isController}},
+		}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name: "canary-6c8b-p2m4q", Namespace: "prod",
+			OwnerReferences: []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "canary-6c8b", Controller: &isController}},
+		}},
+	}
+
+	tests := []struct {
+		name      string
+		allow     []string
+		deny      []string
+		namespace string
+		kind      string
+		resource  string
+		actions   []string
+		wantRule  string // empty when allowed
+	}{
+		{name: "deny list", deny: []string{"kube-*"}, namespace: "kube-system", kind: "Deployment", resource: "dns", wantRule: GuardrailNamespaceDenied},
+		{name: "deny list wins over allow list", allow: []string{"*"}, deny: []string{"prod"}, namespace: "prod", kind: "Deployment", resource: "api", wantRule: GuardrailNamespaceDenied},
+		{name: "not on allow list", allow: []string{"team-*"}, namespace: "prod", kind: "Deployment", resource: "api", wantRule: GuardrailNamespaceNotAllowed},
+		{name: "on allow list", allow: []string{"team-*"}, namespace: "team-a", kind: "Deployment", resource: "api"},
+		{name: "namespace disabled", namespace: "legacy", kind: "Deployment", resource: "reports", wantRule: GuardrailDisabled},
+		{name: "workload overrides namespace", namespace: "legacy", kind: "deployment", resource: "billing"},
+		{name: "recommend-only", namespace: "prod", kind: "Deployment", resource: "payment", actions: []string{"restart_deployment"}, wantRule: GuardrailRecommendOnly},
+		{name: "pod inherits its deployment", namespace: "prod", kind: "Pod", resource: "payment-5d4f-x7k2p", wantRule: GuardrailRecommendOnly},
+		{name: "allowed action", namespace: "prod", kind: "Deployment", resource: "checkout", actions: []string{"delete_pod", "argocd_sync", "argocd_wait_for_sync"}},
+		{name: "action not allowed", namespace: "prod", kind: "Deployment", resource: "checkout", actions: []string{"helm_rollback"}, wantRule: GuardrailActionNotAllowed},
+		{name: "deploymentconfig disabled", namespace: "prod", kind: "DeploymentConfig", resource: "legacy-api", wantRule: GuardrailDisabled},
+		{name: "pod inherits its deploymentconfig", namespace: "prod", kind: "Pod", resource: "legacy-api-3-x7k2p", wantRule: GuardrailDisabled},
+		{name: "rollout action not allowed", namespace: "prod", kind: "Rollout", resource: "canary", actions: []string{"restart_rollout"}, wantRule: GuardrailActionNotAllowed},
+		{name: "pod inherits its rollout", namespace: "prod", kind: "Pod", resource: "canary-6c8b-p2m4q", actions: []string{"delete_pod"}, wantRule: GuardrailActionNotAllowed},
+		{name: "rollout allowed action", namespace: "prod", kind: "Rollout", resource: "canary", actions: []string{"argocd_sync"}},
+		{name: "missing resource", namespace: "prod", kind: "Deployment", resource: "gone", actions: []string{"helm_rollback"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			guardrails := newTestGuardrails(tt.allow, tt.deny, objects...)
+			err := guardrails.Check(context.Background(), tt.namespace, tt.kind, tt.resource, tt.actions)
+			if tt.wantRule == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var refusal *GuardrailError
+			require.True(t, errors.As(err, &refusal), "expected a refusal, got %v", err)
+			assert.Equal(t, tt.wantRule, refusal.Rule)
+			assert.True(t, errors.Is(err, ErrRemediationRefused))
+			assert.Equal(t, tt.wantRule == GuardrailRecommendOnly, errors.Is(err, ErrRecommendOnly))
+		})
+	}
+
+	var nilGuardrails *Guardrails
+	assert.NoError(t, nilGuardrails.Check(context.Background(), "prod", "Deployment", "payment", nil))
+}
+
+func TestOrchestrator_GuardrailRefusal(t *testing.T) {
+	newOrchestrator := func(annotations map[string]string) (*Orchestrator, *blockingRemediator) {
+		remediator := newBlockingRemediator()
+		close(remediator.release)
+		deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+			Name: "payment", Namespace: "default", Annotations: annotations,
+		}}
+
+		log := logrus.New()
+		log.SetLevel(logrus.ErrorLevel)
+		selector := NewStrategySelector(log)
+		selector.SetFallbackRemediator(remediator)
+		selector.SetGuardrails(newTestGuardrails(nil, nil, deployment))
+		return newTestOrchestrator(selector), remediator
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		o, remediator := newOrchestrator(map[string]string{AnnotationDisabled: "true"})
+		wf, _, err := o.TriggerRemediation(context.Background(), "inc-1", newTestIssue("inc-1"))
+		require.NoError(t, err)
+
+		refused := waitForStatus(t, o, wf.ID, models.WorkflowStatusRefused)
+		assert.Contains(t, refused.ErrorMessage, AnnotationDisabled)
+		assert.Equal(t, "refused", refused.Steps[len(refused.Steps)-1].Status)
+		assert.Empty(t, remediator.started)
+	})
+
+	t.Run("recommend-only", func(t *testing.T) {
+		o, remediator := newOrchestrator(map[string]string{AnnotationMode: ModeRecommendOnly})
+		wf, _, err := o.TriggerRemediation(context.Background(), "inc-1", newTestIssue("inc-1"))
+		require.NoError(t, err)
+
+		recommended := waitForStatus(t, o, wf.ID, models.WorkflowStatusRecommended)
+		require.NotEmpty(t, recommended.Recommendations)
+		assert.Contains(t, recommended.Recommendations[0], ModeRecommendOnly)
+		assert.Empty(t, remediator.started)
+	})
+
+	t.Run("allowed", func(t *testing.T) {
+		o, remediator := newOrchestrator(nil)
+		wf, _, err := o.TriggerRemediation(context.Background(), "inc-1", newTestIssue("inc-1"))
+		require.NoError(t, err)
+
+		waitForStatus(t, o, wf.ID, models.WorkflowStatusCompleted)
+		assert.Len(t, remediator.started, 1)
+	})
+}
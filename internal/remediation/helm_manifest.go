@@ -0,0 +1,206 @@
+package remediation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/jsonmergepatch"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes/scheme"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/detector"
+)
+
+// Applying release manifests
+const (
+	helmFieldManager             = "helm"
+	helmResourcePolicyAnnotation = "helm.sh/resource-policy" // "keep" leaves an object behind when a revision drops it
+)
+
+// helmManifestObject is an object of a release manifest and the resource it is
+type helmManifestObject struct {
+	object  *unstructured.Unstructured
+	mapping *meta.RESTMapping
+}
+
+// key identifies the object across revisions of a release
+func (o helmManifestObject) key() string {
+	gvk := o.object.GroupVersionKind()
+	return strings.Join([]string{gvk.Group, gvk.Kind, o.object.GetNamespace(), o.object.GetName()}, "/")
+}
+
+// applyManifest applies the objects of the release's manifest, labeled as owned by
+// the release, and deletes the objects of the previous manifest the release no
+// longer has unless they are annotated to be kept
+func (hr *HelmRemediator) applyManifest(ctx context.Context, release *HelmRelease, previous string) error {
+	if hr.dynamic == nil || hr.mapper == nil {
+		return Permanent(fmt.Errorf("helm remediator has no Kubernetes client to apply release manifests"))
+	}
+	objects, err := hr.parseManifest(release.Manifest, release.Namespace)
+	if err != nil {
+		return Permanent(fmt.Errorf("invalid manifest of revision %d: %w", release.Version, err))
+	}
+	previousObjects, err := hr.parseManifest(previous, release.Namespace)
+	if err != nil {
+		return Permanent(fmt.Errorf("invalid manifest of the current revision: %w", err))
+	}
+
+	originals := make(map[string]*unstructured.Unstructured, len(previousObjects))
+	for _, obj := range previousObjects {
+		originals[obj.key()] = obj.object
+	}
+	keep := make(map[string]bool, len(objects))
+	for _, obj := range objects {
+		keep[obj.key()] = true
+		setHelmOwnership(obj.object, release)
+		if err := hr.applyObject(ctx, obj, originals[obj.key()]); err != nil {
+			return fmt.Errorf("failed to apply %s %s: %w", obj.object.GetKind(), obj.object.GetName(), err)
+		}
+	}
+
+	for _, obj := range previousObjects {
+		if keep[obj.key()] || obj.object.GetAnnotations()[helmResourcePolicyAnnotation] == "keep" {
+			continue
+		}
+		propagation := metav1.DeletePropagationBackground
+		err := hr.resource(obj).Delete(ctx, obj.object.GetName(), metav1.DeleteOptions{PropagationPolicy: &propagation})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete %s %s: %w", obj.object.GetKind(), obj.object.GetName(), err)
+		}
+		hr.log.WithFields(logrus.Fields{
+			"release": release.Name,
+			"kind":    obj.object.GetKind(),
+			"name":    obj.object.GetName(),
+		}).Info("Deleted object the Helm release revision no longer has")
+	}
+	return nil
+}
+
+// applyObject creates the object, or patches the live object as helm does with a
+// three-way merge of original, the object in the previous manifest, the target and
+// the live object: fields the previous revision set that the target does not are
+// removed, while fields set by others, e.g. replicas scaled by an autoscaler, are kept
+func (hr *HelmRemediator) applyObject(ctx context.Context, obj helmManifestObject, original *unstructured.Unstructured) error {
+	client := hr.resource(obj)
+	live, err := client.Get(ctx, obj.object.GetName(), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = client.Create(ctx, obj.object, metav1.CreateOptions{FieldManager: helmFieldManager})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	patch, patchType, err := threeWayPatch(original, obj.object, live)
+	if err != nil {
+		return Permanent(fmt.Errorf("failed to compute patch: %w", err))
+	}
+	if string(patch) == "{}" {
+		return nil
+	}
+	_, err = client.Patch(ctx, obj.object.GetName(), patchType, patch, metav1.PatchOptions{FieldManager: helmFieldManager})
+	return err
+}
+
+// threeWayPatch returns the patch turning live into target, deleting the fields of
+// original target no longer has. Built-in kinds get a strategic merge patch, so
+// lists such as containers merge by key; other kinds get a JSON merge patch.
+func threeWayPatch(original, target, live *unstructured.Unstructured) ([]byte, types.PatchType, error) {
+	var originalJSON []byte
+	if original != nil {
+		var err error
+		if originalJSON, err = original.MarshalJSON(); err != nil {
+			return nil, "", err
+		}
+	}
+	targetJSON, err := target.MarshalJSON()
+	if err != nil {
+		return nil, "", err
+	}
+	liveJSON, err := live.MarshalJSON()
+	if err != nil {
+		return nil, "", err
+	}
+
+	typed, err := scheme.Scheme.New(target.GroupVersionKind())
+	if err != nil {
+		patch, err := jsonmergepatch.CreateThreeWayJSONMergePatch(originalJSON, targetJSON, liveJSON)
+		return patch, types.MergePatchType, err
+	}
+	patchMeta, err := strategicpatch.NewPatchMetaFromStruct(typed)
+	if err != nil {
+		return nil, "", err
+	}
+	patch, err := strategicpatch.CreateThreeWayMergePatch(originalJSON, targetJSON, liveJSON, patchMeta, true)
+	return patch, types.StrategicMergePatchType, err
+}
+
+// parseManifest splits a release manifest into its objects, placing namespaced
+// objects without a namespace in the release namespace
+func (hr *HelmRemediator) parseManifest(manifest, namespace string) ([]helmManifestObject, error) {
+	var objects []helmManifestObject
+	decoder := utilyaml.NewYAMLOrJSONDecoder(strings.NewReader(manifest), 4096)
+	for {
+		var content map[string]interface{}
+		if err := decoder.Decode(&content); errors.Is(err, io.EOF) {
+			return objects, nil
+		} else if err != nil {
+			return nil, err
+		}
+		if len(content) == 0 {
+			continue
+		}
+
+		object := &unstructured.Unstructured{Object: content}
+		gvk := object.GroupVersionKind()
+		if gvk.Kind == "" || object.GetName() == "" {
+			return nil, fmt.Errorf("object without kind or name in manifest")
+		}
+		mapping, err := hr.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil {
+			return nil, fmt.Errorf("unknown resource of %s %s: %w", gvk.Kind, object.GetName(), err)
+		}
+		if mapping.Scope.Name() == meta.RESTScopeNameNamespace && object.GetNamespace() == "" {
+			object.SetNamespace(namespace)
+		}
+		objects = append(objects, helmManifestObject{object: object, mapping: mapping})
+	}
+}
+
+// resource returns the client of the object's resource
+func (hr *HelmRemediator) resource(obj helmManifestObject) dynamic.ResourceInterface {
+	if obj.mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		return hr.dynamic.Resource(obj.mapping.Resource).Namespace(obj.object.GetNamespace())
+	}
+	return hr.dynamic.Resource(obj.mapping.Resource)
+}
+
+// setHelmOwnership labels and annotates an object as belonging to the release, as
+// helm does when it creates or updates the object
+func setHelmOwnership(object *unstructured.Unstructured, release *HelmRelease) {
+	labels := object.GetLabels()
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+	labels[detector.ManagedByLabel] = "Helm"
+	object.SetLabels(labels)
+
+	annotations := object.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[detector.HelmReleaseNameAnnotation] = release.Name
+	annotations[detector.HelmReleaseNamespaceAnnotation] = release.Namespace
+	object.SetAnnotations(annotations)
+}
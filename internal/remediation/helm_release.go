@@ -0,0 +1,291 @@
+package remediation
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Helm 3 keeps each revision of a release in a Secret of this type, named
+// sh.helm.release.v1.<release>.v<revision>
+const (
+	helmReleaseSecretType   = corev1.SecretType("helm.sh/release.v1")
+	helmReleaseSecretPrefix = "sh.helm.release.v1."
+	helmReleaseSecretKey    = "release"
+	helmReleaseMaxSize      = 64 << 20 // limit of a decompressed release document
+)
+
+// Helm release statuses
+const (
+	HelmStatusDeployed        = "deployed"
+	HelmStatusFailed          = "failed"
+	HelmStatusSuperseded      = "superseded"
+	HelmStatusPendingInstall  = "pending-install"
+	HelmStatusPendingUpgrade  = "pending-upgrade"
+	HelmStatusPendingRollback = "pending-rollback"
+)
+
+// gzipMagic starts a gzip stream; Helm compresses the release documents it stores
+var gzipMagic = []byte{0x1f, 0x8b, 0x08}
+
+// ErrHelmReleaseNotFound is returned when no revision of a Helm release is stored
+var ErrHelmReleaseNotFound = errors.New("helm release not found")
+
+// HelmRelease is one revision of a Helm release, decoded from its storage Secret
+type HelmRelease struct {
+	Name      string                 `json:"name"`
+	Namespace string                 `json:"namespace"`
+	Version   int                    `json:"version"`
+	Info      HelmReleaseInfo        `json:"info"`
+	Chart     HelmChart              `json:"chart"`
+	Config    map[string]interface{} `json:"config,omitempty"` // values supplied on install or upgrade
+	Manifest  string                 `json:"manifest,omitempty"`
+
+	// raw is the stored document, written back whole with new revisions so that
+	// fields not decoded here, such as hooks and chart templates, are kept
+	raw map[string]json.RawMessage
+}
+
+// HelmReleaseInfo describes the deployment of a release revision
+type HelmReleaseInfo struct {
+	FirstDeployed time.Time `json:"first_deployed,omitempty"`
+	LastDeployed  time.Time `json:"last_deployed,omitempty"`
+	Description   string    `json:"description,omitempty"`
+	Status        string    `json:"status,omitempty"`
+}
+
+// HelmChart is the chart a release revision was deployed from
+type HelmChart struct {
+	Metadata HelmChartMetadata      `json:"metadata"`
+	Values   map[string]interface{} `json:"values,omitempty"` // defaults of the chart
+}
+
+// HelmChartMetadata identifies a chart
+type HelmChartMetadata struct {
+	Name       string `json:"name"`
+	Version    string `json:"version"`
+	AppVersion string `json:"appVersion,omitempty"`
+}
+
+// String returns the chart as name-version, as helm list shows it
+func (c HelmChart) String() string {
+	return c.Metadata.Name + "-" + c.Metadata.Version
+}
+
+// decodeHelmRelease decodes a release document as Helm stores it: base64 encoded,
+// usually gzipped JSON
+func decodeHelmRelease(data []byte) (*HelmRelease, error) {
+	decoded, err := base64.StdEncoding.DecodeString(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid base64: %w", err)
+	}
+	if bytes.HasPrefix(decoded, gzipMagic) {
+		reader, err := gzip.NewReader(bytes.NewReader(decoded))
+		if err != nil {
+			return nil, fmt.Errorf("invalid gzip: %w", err)
+		}
+		defer func() { _ = reader.Close() }()
+		decoded, err = io.ReadAll(io.LimitReader(reader, helmReleaseMaxSize))
+		if err != nil {
+			return nil, fmt.Errorf("invalid gzip: %w", err)
+		}
+	}
+
+	var release HelmRelease
+	if err := json.Unmarshal(decoded, &release); err != nil {
+		return nil, fmt.Errorf("invalid release JSON: %w", err)
+	}
+	if err := json.Unmarshal(decoded, &release.raw); err != nil {
+		return nil, fmt.Errorf("invalid release JSON: %w", err)
+	}
+	return &release, nil
+}
+
+// encode encodes the release as Helm stores it. The stored document is written
+// back with the revision and deployment info of the release.
+func (r *HelmRelease) encode() ([]byte, error) {
+	doc := make(map[string]json.RawMessage, len(r.raw)+1)
+	for key, value := range r.raw {
+		doc[key] = value
+	}
+	info := make(map[string]json.RawMessage)
+	if stored, ok := r.raw["info"]; ok {
+		if err := json.Unmarshal(stored, &info); err != nil {
+			return nil, fmt.Errorf("invalid release info: %w", err)
+		}
+	}
+	fields := map[string]interface{}{
+		"first_deployed": r.Info.FirstDeployed,
+		"last_deployed":  r.Info.LastDeployed,
+		"description":    r.Info.Description,
+		"status":         r.Info.Status,
+	}
+	for key, value := range fields {
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		info[key] = encoded
+	}
+
+	var err error
+	if doc["info"], err = json.Marshal(info); err != nil {
+		return nil, err
+	}
+	if doc["version"], err = json.Marshal(r.Version); err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	var compressed bytes.Buffer
+	writer, err := gzip.NewWriterLevel(&compressed, gzip.BestCompression)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := writer.Write(data); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return []byte(base64.StdEncoding.EncodeToString(compressed.Bytes())), nil
+}
+
+// next returns a new revision of the release with the chart, values and manifest
+// of r, in the given status
+func (r *HelmRelease) next(version int, status, description string, now time.Time) *HelmRelease {
+	next := *r
+	next.Version = version
+	next.Info.Status = status
+	next.Info.Description = description
+	next.Info.LastDeployed = now
+	return &next
+}
+
+// helmReleaseStore reads and writes release revisions where Helm keeps them, so
+// that the helm CLI sees the changes made here
+type helmReleaseStore struct {
+	clientset kubernetes.Interface
+	now       func() time.Time
+}
+
+// newHelmReleaseStore creates a store of the release Secrets read through clientset
+func newHelmReleaseStore(clientset kubernetes.Interface) *helmReleaseStore {
+	return &helmReleaseStore{clientset: clientset, now: time.Now}
+}
+
+// history returns every stored revision of a release, oldest first
+func (s *helmReleaseStore) history(ctx context.Context, namespace, name string) ([]*HelmRelease, error) {
+	selector := labels.SelectorFromSet(labels.Set{"owner": "helm", "name": name})
+	secrets, err := s.clientset.CoreV1().Secrets(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list revisions of helm release %s/%s: %w", namespace, name, err)
+	}
+
+	releases := make([]*HelmRelease, 0, len(secrets.Items))
+	for i := range secrets.Items {
+		secret := &secrets.Items[i]
+		if secret.Type != helmReleaseSecretType {
+			continue
+		}
+		release, err := decodeHelmRelease(secret.Data[helmReleaseSecretKey])
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode helm release secret %s/%s: %w", namespace, secret.Name, err)
+		}
+		releases = append(releases, release)
+	}
+	if len(releases) == 0 {
+		return nil, fmt.Errorf("%w: %s/%s", ErrHelmReleaseNotFound, namespace, name)
+	}
+	sort.Slice(releases, func(i, j int) bool { return releases[i].Version < releases[j].Version })
+	return releases, nil
+}
+
+// create stores a new revision. A revision stored concurrently by another Helm
+// client fails with ErrHelmOperationInProgress.
+func (s *helmReleaseStore) create(ctx context.Context, release *HelmRelease) error {
+	secret, err := s.secret(release)
+	if err != nil {
+		return err
+	}
+	secret.Labels["createdAt"] = strconv.FormatInt(s.now().Unix(), 10)
+	_, err = s.clientset.CoreV1().Secrets(release.Namespace).Create(ctx, secret, metav1.CreateOptions{})
+	if apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("revision %d of helm release %s/%s already exists: %w",
+			release.Version, release.Namespace, release.Name, ErrHelmOperationInProgress)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to store revision %d of helm release %s/%s: %w", release.Version, release.Namespace, release.Name, err)
+	}
+	return nil
+}
+
+// update stores the changed status of an existing revision
+func (s *helmReleaseStore) update(ctx context.Context, release *HelmRelease) error {
+	secrets := s.clientset.CoreV1().Secrets(release.Namespace)
+	name := helmReleaseSecretName(release.Name, release.Version)
+	existing, err := secrets.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to read helm release secret %s/%s: %w", release.Namespace, name, err)
+	}
+
+	secret, err := s.secret(release)
+	if err != nil {
+		return err
+	}
+	for key, value := range existing.Labels {
+		if _, ok := secret.Labels[key]; !ok {
+			secret.Labels[key] = value
+		}
+	}
+	secret.Labels["modifiedAt"] = strconv.FormatInt(s.now().Unix(), 10)
+	secret.ResourceVersion = existing.ResourceVersion
+	if _, err := secrets.Update(ctx, secret, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to update helm release secret %s/%s: %w", release.Namespace, name, err)
+	}
+	return nil
+}
+
+// secret returns the Secret a revision is stored in, labeled as Helm labels it
+func (s *helmReleaseStore) secret(release *HelmRelease) (*corev1.Secret, error) {
+	data, err := release.encode()
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode helm release %s/%s: %w", release.Namespace, release.Name, err)
+	}
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      helmReleaseSecretName(release.Name, release.Version),
+			Namespace: release.Namespace,
+			Labels: map[string]string{
+				"name":    release.Name,
+				"owner":   "helm",
+				"status":  release.Info.Status,
+				"version": strconv.Itoa(release.Version),
+			},
+		},
+		Type: helmReleaseSecretType,
+		Data: map[string][]byte{helmReleaseSecretKey: data},
+	}, nil
+}
+
+// helmReleaseSecretName returns the name of the Secret storing a revision
+func helmReleaseSecretName(name string, version int) string {
+	return fmt.Sprintf("%s%s.v%d", helmReleaseSecretPrefix, name, version)
+}
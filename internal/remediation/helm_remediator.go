@@ -2,50 +2,66 @@ package remediation
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
-	"os/exec"
 	"time"
 
 	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/restmapper"
 
 	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
 )
 
-// HelmRemediator handles Helm-managed application remediation
+// ErrHelmOperationInProgress is returned when a release is locked by another Helm
+// operation, which usually finishes shortly
+var ErrHelmOperationInProgress = errors.New("another helm operation is in progress")
+
+// HelmRemediator handles Helm-managed application remediation. It reads releases
+// from the Secrets Helm stores them in and rolls them back or redeploys them
+// through the Kubernetes API, without the helm binary. Chart hooks are not run.
 type HelmRemediator struct {
+	releases    *helmReleaseStore
+	dynamic     dynamic.Interface // applies and deletes the objects of release manifests
+	mapper      meta.RESTMapper   // resolves the resources of manifest objects
+	recorder    EventRecorder
+	memory      *MemoryResizer
 	log         *logrus.Logger
 	helmTimeout time.Duration
 }
 
-// HelmStatus represents the Helm release status JSON response
-type HelmStatus struct {
-	Name      string `json:"name"`
-	Namespace string `json:"namespace"`
-	Info      struct {
-		Status        string    `json:"status"`
-		FirstDeployed time.Time `json:"first_deployed"`
-		LastDeployed  time.Time `json:"last_deployed"`
-		Description   string    `json:"description"`
-	} `json:"info"`
-	Version int `json:"version"`
-}
-
 // NewHelmRemediator creates a new Helm remediator
-func NewHelmRemediator(log *logrus.Logger) *HelmRemediator {
-	return &HelmRemediator{
+func NewHelmRemediator(clientset kubernetes.Interface, dynamicClient dynamic.Interface, log *logrus.Logger) *HelmRemediator {
+	hr := &HelmRemediator{
+		releases:    newHelmReleaseStore(clientset),
+		dynamic:     dynamicClient,
 		log:         log,
 		helmTimeout: 5 * time.Minute, // Default 5 minute timeout for Helm operations
 	}
+	if clientset != nil {
+		hr.mapper = restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(clientset.Discovery()))
+	}
+	return hr
+}
+
+// SetRESTMapper sets how the resources of manifest objects are resolved, instead
+// of discovering them from the API server
+func (hr *HelmRemediator) SetRESTMapper(mapper meta.RESTMapper) {
+	hr.mapper = mapper
 }
 
-// Remediate triggers Helm upgrade or rollback based on release status
-func (hr *HelmRemediator) Remediate(ctx context.Context, deploymentInfo *models.DeploymentInfo, issue *models.Issue) error {
+// Remediate rolls back a failed or stuck release, or redeploys a deployed one
+func (hr *HelmRemediator) Remediate(ctx context.Context, deploymentInfo *models.DeploymentInfo, issue *models.Issue) (*models.RemediationResult, error) {
+	result := models.NewRemediationResult(hr.Name())
+
 	// Extract Helm release information from deployment info
 	releaseName := deploymentInfo.GetDetail("release_name")
 	if releaseName == "" {
-		return fmt.Errorf("helm release name not found in deployment info")
+		return result, Permanent(fmt.Errorf("helm release name not found in deployment info"))
 	}
 
 	releaseNamespace := deploymentInfo.GetDetail("release_namespace")
@@ -62,54 +78,174 @@ func (hr *HelmRemediator) Remediate(ctx context.Context, deploymentInfo *models.
 		"method":     "helm",
 	}).Info("Starting Helm remediation")
 
-	// Check release status
-	releaseStatus, err := hr.getReleaseStatus(ctx, releaseName, releaseNamespace)
+	// A redeploy of the release would revert a change to the live object
+	if isOOMIssue(issue) {
+		result.Recommend(hr.memory.Recommend(ctx, issue, fmt.Sprintf("the values of Helm release %s/%s", releaseNamespace, releaseName), true)...)
+		return result, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, hr.helmTimeout)
+	defer cancel()
+
+	// Read the release history
+	history, err := hr.releases.history(ctx, releaseNamespace, releaseName)
+	if errors.Is(err, ErrHelmReleaseNotFound) {
+		return result, Permanent(err)
+	}
 	if err != nil {
-		return fmt.Errorf("failed to get release status: %w", err)
+		return result, fmt.Errorf("failed to get release status: %w", err)
 	}
+	current := history[len(history)-1]
+	target := releaseNamespace + "/" + releaseName
+	result.AddEvidence("release_status", current.Info.Status)
+	result.AddEvidence("release_revision", fmt.Sprint(current.Version))
+	result.AddEvidence("chart", current.Chart.String())
 
 	hr.log.WithFields(logrus.Fields{
 		"release": releaseName,
-		"status":  releaseStatus.Info.Status,
-		"version": releaseStatus.Version,
+		"status":  current.Info.Status,
+		"version": current.Version,
+		"chart":   current.Chart.String(),
 	}).Info("Current Helm release status")
 
 	// Determine remediation strategy based on status
-	status := releaseStatus.Info.Status
-
-	// If release is in failed state, rollback to previous version
-	if status == "failed" || status == "superseded" || status == "pending-upgrade" {
+	status := current.Info.Status
+	switch status {
+	case HelmStatusFailed, HelmStatusSuperseded, HelmStatusPendingUpgrade:
+		rollbackTo, err := helmRollbackTarget(history, issue)
+		if err != nil {
+			return result, err
+		}
 		hr.log.WithFields(logrus.Fields{
 			"release": releaseName,
 			"status":  status,
+			"to":      rollbackTo.Version,
 		}).Info("Rolling back Helm release")
 
-		if err := hr.rollbackRelease(ctx, releaseName, releaseNamespace); err != nil {
-			return fmt.Errorf("helm rollback failed: %w", err)
+		if _, err := hr.deploy(ctx, rollbackTo, HelmStatusPendingRollback, fmt.Sprintf("Rollback to %d", rollbackTo.Version)); err != nil {
+			return result, withAction("helm_rollback", fmt.Errorf("helm rollback failed: %w", err))
 		}
+		result.AddAction("helm_rollback", target, fmt.Sprintf("revision %d (%s)", current.Version, status),
+			fmt.Sprintf("revision %d", rollbackTo.Version))
+		reportRollback(ctx, models.Rollback{
+			Kind:         KindHelmRelease,
+			Name:         releaseName,
+			FromRevision: int64(current.Version),
+			ToRevision:   int64(rollbackTo.Version),
+		})
+
+		hr.recordReleaseEvent(ctx, issue, releaseName, releaseNamespace, RemediationEvent{
+			Reason:  EventReasonRolledBack,
+			Action:  "helm_rollback",
+			Message: fmt.Sprintf("Rolled back Helm release %s from %s revision %d to revision %d", releaseName, status, current.Version, rollbackTo.Version),
+		})
 
 		hr.log.WithField("release", releaseName).Info("Helm rollback completed successfully")
-		return nil
+		return result, nil
+	case HelmStatusPendingInstall, HelmStatusPendingRollback:
+		return result, withAction("helm_upgrade", fmt.Errorf("helm release %s is %s: %w", target, status, ErrHelmOperationInProgress))
 	}
 
-	// If release is deployed but having issues, trigger upgrade with --reuse-values
-	// This re-applies the configuration and can fix transient issues
+	// If release is deployed but having issues, redeploy it with the same chart and
+	// values. This re-applies the configuration and can fix transient issues.
 	hr.log.WithFields(logrus.Fields{
 		"release":    releaseName,
 		"issue_type": issue.Type,
 	}).Info("Triggering Helm upgrade to remediate issue")
 
-	if err := hr.upgradeRelease(ctx, releaseName, releaseNamespace, deploymentInfo); err != nil {
+	upgraded, err := hr.deploy(ctx, current, HelmStatusPendingUpgrade, "Upgrade complete")
+	if err != nil {
+		// A cancelled workflow stops the upgrade; a rollback would be stopped too
+		if ctx.Err() != nil {
+			return result, fmt.Errorf("helm upgrade cancelled: %w", ctx.Err())
+		}
+		// The release is unchanged while another operation holds it
+		if errors.Is(err, ErrHelmOperationInProgress) || upgraded == nil {
+			return result, withAction("helm_upgrade", fmt.Errorf("helm upgrade failed: %w", err))
+		}
+
 		// If upgrade fails, attempt rollback as safety measure
 		hr.log.WithError(err).Warn("Helm upgrade failed, attempting rollback")
-		if rollbackErr := hr.rollbackRelease(ctx, releaseName, releaseNamespace); rollbackErr != nil {
-			return fmt.Errorf("helm upgrade failed: %w, and rollback also failed: %w", err, rollbackErr)
+		if _, rollbackErr := hr.deploy(ctx, current, HelmStatusPendingRollback, fmt.Sprintf("Rollback to %d", current.Version)); rollbackErr != nil {
+			return result, withAction("helm_upgrade", fmt.Errorf("helm upgrade failed: %w, and rollback also failed: %w", err, rollbackErr))
 		}
-		return fmt.Errorf("helm upgrade failed (rolled back): %w", err)
+		result.AddAction("helm_rollback", target, "failed upgrade", fmt.Sprintf("revision %d", current.Version))
+		hr.recordReleaseEvent(ctx, issue, releaseName, releaseNamespace, RemediationEvent{
+			Type:    corev1.EventTypeWarning,
+			Reason:  EventReasonRolledBack,
+			Action:  "helm_rollback",
+			Message: fmt.Sprintf("Rolled back Helm release %s after a failed upgrade", releaseName),
+		})
+		return result, withAction("helm_upgrade", fmt.Errorf("helm upgrade failed (rolled back): %w", err))
 	}
+	result.AddAction("helm_upgrade", target, fmt.Sprintf("revision %d", current.Version),
+		fmt.Sprintf("revision %d", upgraded.Version))
+
+	hr.recordReleaseEvent(ctx, issue, releaseName, releaseNamespace, RemediationEvent{
+		Reason:  EventReasonUpgraded,
+		Action:  "helm_upgrade",
+		Message: fmt.Sprintf("Upgraded Helm release %s with reused values to remediate %s", releaseName, issue.Type),
+	})
 
 	hr.log.WithField("release", releaseName).Info("Helm remediation completed successfully")
-	return nil
+	return result, nil
+}
+
+// PlanActions reads the release status and describes the rollback or upgrade Remediate would run
+func (hr *HelmRemediator) PlanActions(ctx context.Context, deploymentInfo *models.DeploymentInfo, issue *models.Issue) ([]PlannedAction, error) {
+	releaseName := deploymentInfo.GetDetail("release_name")
+	if releaseName == "" {
+		return nil, fmt.Errorf("helm release name not found in deployment info")
+	}
+
+	releaseNamespace := deploymentInfo.GetDetail("release_namespace")
+	if releaseNamespace == "" {
+		releaseNamespace = deploymentInfo.Namespace
+	}
+	target := releaseNamespace + "/" + releaseName
+
+	if isOOMIssue(issue) {
+		return []PlannedAction{{
+			Action:      "recommend_memory",
+			Target:      target,
+			Description: fmt.Sprintf("recommend raising the memory of %s in the values of release %s", issue.ResourceName, releaseName),
+		}}, nil
+	}
+
+	history, err := hr.releases.history(ctx, releaseNamespace, releaseName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get release status: %w", err)
+	}
+	current := history[len(history)-1]
+
+	switch status := current.Info.Status; status {
+	case HelmStatusFailed, HelmStatusSuperseded, HelmStatusPendingUpgrade:
+		rollbackTo, err := helmRollbackTarget(history, issue)
+		if err != nil {
+			return nil, err
+		}
+		return []PlannedAction{{
+			Action: "helm_rollback",
+			Target: target,
+			Description: fmt.Sprintf("roll back release %s to revision %d (chart %s; current revision %d is %s)",
+				releaseName, rollbackTo.Version, rollbackTo.Chart, current.Version, status),
+		}}, nil
+	case HelmStatusPendingInstall, HelmStatusPendingRollback:
+		return nil, fmt.Errorf("helm release %s is %s: %w", target, status, ErrHelmOperationInProgress)
+	}
+
+	return []PlannedAction{
+		{
+			Action:      "helm_upgrade",
+			Target:      target,
+			Description: fmt.Sprintf("upgrade release %s to revision %d with chart %s and its current values", releaseName, current.Version+1, current.Chart),
+		},
+		{
+			Action:      "helm_rollback",
+			Target:      target,
+			Description: fmt.Sprintf("roll back release %s to revision %d if the upgrade fails", releaseName, current.Version),
+		},
+	}, nil
 }
 
 // CanRemediate returns true if deployment is Helm-managed
@@ -122,128 +258,100 @@ func (hr *HelmRemediator) Name() string {
 	return "helm"
 }
 
+// SetMemoryResizer sets how memory recommendations for OOM-killed workloads are sized
+func (hr *HelmRemediator) SetMemoryResizer(memory *MemoryResizer) {
+	hr.memory = memory
+}
+
+// SetEventRecorder records the actions taken as Kubernetes Events
+func (hr *HelmRemediator) SetEventRecorder(recorder EventRecorder) {
+	hr.recorder = recorder
+}
+
+// recordReleaseEvent records an action on the Helm release and the affected workload
+func (hr *HelmRemediator) recordReleaseEvent(ctx context.Context, issue *models.Issue, releaseName, releaseNamespace string, event RemediationEvent) {
+	event.Remediator = hr.Name()
+	release := corev1.ObjectReference{Kind: KindHelmRelease, Namespace: releaseNamespace, Name: releaseName}
+	recordOnTargets(ctx, hr.recorder, event, release, workloadRef(issue.ResourceType, issue.Namespace, issue.ResourceName))
+}
+
 // SetHelmTimeout allows customizing the Helm operation timeout
 func (hr *HelmRemediator) SetHelmTimeout(timeout time.Duration) {
 	hr.helmTimeout = timeout
 	hr.log.WithField("timeout", timeout).Debug("Helm timeout updated")
 }
 
-// getReleaseStatus queries Helm release status and returns parsed status
-func (hr *HelmRemediator) getReleaseStatus(ctx context.Context, releaseName, namespace string) (*HelmStatus, error) {
-	// Create context with timeout
-	timeoutCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
-	defer cancel()
-
-	cmd := exec.CommandContext(timeoutCtx, "helm", "status", releaseName,
-		"-n", namespace,
-		"-o", "json",
-	)
-
-	output, err := cmd.Output()
-	if err != nil {
-		// Check if release exists
-		var exitErr *exec.ExitError
-		if errors.As(err, &exitErr) {
-			hr.log.WithFields(logrus.Fields{
-				"release":  releaseName,
-				"stderr":   string(exitErr.Stderr),
-				"exitcode": exitErr.ExitCode(),
-			}).Error("Helm status command failed")
+// helmRollbackTarget returns the revision a rollback targets: the one requested
+// with the issue, else the last revision before the current one that was deployed
+func helmRollbackTarget(history []*HelmRelease, issue *models.Issue) (*HelmRelease, error) {
+	current := history[len(history)-1]
+	if issue.RollbackRevision > 0 {
+		for _, release := range history {
+			if int64(release.Version) == issue.RollbackRevision {
+				return release, nil
+			}
 		}
-		return nil, fmt.Errorf("helm status command failed: %w", err)
+		return nil, fmt.Errorf("helm release %s/%s has no revision %d: %w", current.Namespace, current.Name, issue.RollbackRevision, ErrNoRollbackRevision)
 	}
-
-	// Parse JSON output
-	var status HelmStatus
-	if err := json.Unmarshal(output, &status); err != nil {
-		hr.log.WithFields(logrus.Fields{
-			"release": releaseName,
-			"output":  string(output),
-		}).Error("Failed to parse Helm status JSON")
-		return nil, fmt.Errorf("failed to parse helm status output: %w", err)
+	for i := len(history) - 2; i >= 0; i-- {
+		if status := history[i].Info.Status; status == HelmStatusDeployed || status == HelmStatusSuperseded {
+			return history[i], nil
+		}
 	}
-
-	return &status, nil
+	return nil, fmt.Errorf("helm release %s/%s has no deployed revision before %d: %w", current.Namespace, current.Name, current.Version, ErrNoRollbackRevision)
 }
 
-// rollbackRelease rolls back Helm release to previous revision
-func (hr *HelmRemediator) rollbackRelease(ctx context.Context, releaseName, namespace string) error {
-	// Create context with timeout
-	timeoutCtx, cancel := context.WithTimeout(ctx, hr.helmTimeout)
-	defer cancel()
-
-	// #nosec G204 -- helm command with controlled inputs from deployment metadata
-	cmd := exec.CommandContext(timeoutCtx, "helm", "rollback", releaseName,
-		"-n", namespace,
-		"--wait",
-		"--timeout", hr.helmTimeout.String(),
-	)
-
-	output, err := cmd.CombinedOutput()
+// deploy records a new revision of the release with the chart, values and manifest
+// of source, applies its manifest over that of the latest revision, and supersedes
+// the revisions deployed before. The new revision is in the pending status while
+// its manifest is applied and failed if that fails, in which case it is returned
+// with the error. Once the manifest is applied no error is returned.
+func (hr *HelmRemediator) deploy(ctx context.Context, source *HelmRelease, pending, description string) (*HelmRelease, error) {
+	history, err := hr.releases.history(ctx, source.Namespace, source.Name)
 	if err != nil {
-		hr.log.WithFields(logrus.Fields{
-			"release": releaseName,
-			"output":  string(output),
-		}).Error("Helm rollback failed")
-		return fmt.Errorf("helm rollback failed: %w, output: %s", err, string(output))
+		return nil, err
+	}
+	latest := history[len(history)-1]
+	if latest.Version != source.Version && (latest.Info.Status == HelmStatusPendingInstall || latest.Info.Status == HelmStatusPendingRollback) {
+		return nil, fmt.Errorf("revision %d is %s: %w", latest.Version, latest.Info.Status, ErrHelmOperationInProgress)
 	}
 
-	hr.log.WithFields(logrus.Fields{
-		"release": releaseName,
-		"output":  string(output),
-	}).Info("Helm rollback completed")
-
-	return nil
-}
-
-// upgradeRelease triggers Helm upgrade with --reuse-values and --atomic
-func (hr *HelmRemediator) upgradeRelease(ctx context.Context, releaseName, namespace string, deploymentInfo *models.DeploymentInfo) error {
-	// Create context with timeout
-	timeoutCtx, cancel := context.WithTimeout(ctx, hr.helmTimeout)
-	defer cancel()
-
-	// Get chart name from deployment info
-	chart := deploymentInfo.GetDetail("chart")
-	if chart == "" {
-		// If chart not available, use release name as fallback
-		chart = releaseName
-		hr.log.WithField("release", releaseName).Warn("Chart name not found, using release name")
-	}
-
-	// Build helm upgrade command
-	// --reuse-values: Reuse the last release's values
-	// --atomic: If upgrade fails, rollback automatically
-	// --wait: Wait for resources to be ready
-	// #nosec G204 -- helm command with controlled inputs from deployment metadata
-	cmd := exec.CommandContext(timeoutCtx, "helm", "upgrade", releaseName, chart,
-		"-n", namespace,
-		"--reuse-values",
-		"--atomic",
-		"--wait",
-		"--timeout", hr.helmTimeout.String(),
-	)
-
-	hr.log.WithFields(logrus.Fields{
-		"release": releaseName,
-		"chart":   chart,
-		"command": cmd.String(),
-	}).Debug("Executing Helm upgrade")
-
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		hr.log.WithFields(logrus.Fields{
-			"release": releaseName,
-			"chart":   chart,
-			"output":  string(output),
-		}).Error("Helm upgrade failed")
-		return fmt.Errorf("helm upgrade failed: %w, output: %s", err, string(output))
+	release := source.next(latest.Version+1, pending, description, hr.releases.now())
+	release.Info.FirstDeployed = history[0].Info.FirstDeployed
+	if err := hr.releases.create(ctx, release); err != nil {
+		return nil, err
 	}
 
-	hr.log.WithFields(logrus.Fields{
-		"release": releaseName,
-		"chart":   chart,
-		"output":  string(output),
-	}).Info("Helm upgrade completed")
+	if err := hr.applyManifest(ctx, release, latest.Manifest); err != nil {
+		release.Info.Status = HelmStatusFailed
+		release.Info.Description = fmt.Sprintf("%s failed: %v", description, err)
+		if updateErr := hr.releases.update(context.WithoutCancel(ctx), release); updateErr != nil {
+			hr.log.WithError(updateErr).WithField("release", release.Name).Warn("Failed to mark Helm release revision failed")
+		}
+		return release, err
+	}
 
-	return nil
+	// The manifest is live: a failure to record that is logged rather than
+	// returned, as the caller would otherwise roll back a successful deploy
+	bookkeeping := context.WithoutCancel(ctx)
+	for _, previous := range history {
+		if previous != latest && previous.Info.Status != HelmStatusDeployed {
+			continue
+		}
+		previous.Info.Status = HelmStatusSuperseded
+		if err := hr.releases.update(bookkeeping, previous); err != nil {
+			hr.log.WithError(err).WithFields(logrus.Fields{
+				"release": previous.Name,
+				"version": previous.Version,
+			}).Warn("Failed to mark Helm release revision superseded")
+		}
+	}
+	release.Info.Status = HelmStatusDeployed
+	if err := hr.releases.update(bookkeeping, release); err != nil {
+		hr.log.WithError(err).WithFields(logrus.Fields{
+			"release": release.Name,
+			"version": release.Version,
+		}).Warn("Failed to mark Helm release revision deployed")
+	}
+	return release, nil
 }
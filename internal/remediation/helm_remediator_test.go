@@ -1,19 +1,39 @@
 package remediation
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	k8stesting "k8s.io/client-go/testing"
 
 	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
 )
 
 func TestNewHelmRemediator(t *testing.T) {
 	log := logrus.New()
-	remediator := NewHelmRemediator(log)
+	remediator := NewHelmRemediator(fake.NewSimpleClientset(), nil, log)
 
 	assert.NotNil(t, remediator)
 	assert.Equal(t, "helm", remediator.Name())
@@ -22,7 +42,7 @@ func TestNewHelmRemediator(t *testing.T) {
 
 func TestHelmRemediator_CanRemediate(t *testing.T) {
 	log := logrus.New()
-	remediator := NewHelmRemediator(log)
+	remediator := NewHelmRemediator(fake.NewSimpleClientset(), nil, log)
 
 	tests := []struct {
 		name     string
@@ -68,7 +88,7 @@ func TestHelmRemediator_CanRemediate(t *testing.T) {
 func TestHelmRemediator_SetHelmTimeout(t *testing.T) {
 	log := logrus.New()
 	log.SetLevel(logrus.ErrorLevel) // Reduce noise in tests
-	remediator := NewHelmRemediator(log)
+	remediator := NewHelmRemediator(fake.NewSimpleClientset(), nil, log)
 
 	// Default timeout
 	assert.Equal(t, 5*time.Minute, remediator.helmTimeout)
@@ -81,32 +101,15 @@ func TestHelmRemediator_SetHelmTimeout(t *testing.T) {
 
 func TestHelmRemediator_Name(t *testing.T) {
 	log := logrus.New()
-	remediator := NewHelmRemediator(log)
+	remediator := NewHelmRemediator(fake.NewSimpleClientset(), nil, log)
 
 	assert.Equal(t, "helm", remediator.Name())
 }
 
-// Note: Full remediation testing with Helm CLI execution requires integration tests
-// or more sophisticated mocking of exec.Command. These tests verify the structure
-// and interface compliance. Integration tests should:
-//
-// 1. Test getReleaseStatus with real/mocked Helm CLI
-// 2. Test rollbackRelease command construction and execution
-// 3. Test upgradeRelease command construction and execution
-// 4. Test Remediate workflow for different release statuses:
-//    - failed release -> rollback
-//    - deployed release -> upgrade
-//    - superseded release -> rollback
-//    - pending-upgrade release -> rollback
-// 5. Test error handling when Helm commands fail
-// 6. Test timeout handling
-// 7. Test missing release_name in deployment info
-// 8. Test chart name extraction and fallback logic
-
 func TestHelmRemediator_RemediateValidation(t *testing.T) {
 	log := logrus.New()
 	log.SetLevel(logrus.ErrorLevel)
-	remediator := NewHelmRemediator(log)
+	remediator := NewHelmRemediator(fake.NewSimpleClientset(), nil, log)
 
 	tests := []struct {
 		name           string
@@ -136,7 +139,7 @@ func TestHelmRemediator_RemediateValidation(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := remediator.Remediate(context.TODO(), tt.deploymentInfo, tt.issue)
+			_, err := remediator.Remediate(context.TODO(), tt.deploymentInfo, tt.issue)
 			if tt.expectError {
 				assert.Error(t, err)
 				if tt.errorContains != "" {
@@ -148,3 +151,359 @@ func TestHelmRemediator_RemediateValidation(t *testing.T) {
 		})
 	}
 }
+
+// helmTestManifest returns a manifest of the payment release running image, with
+// extra appended
+func helmTestManifest(image, extra string) string {
+	return `---
+# Source: payment/templates/deployment.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: payment
+spec:
+  template:
+    spec:
+      containers:
+      - name: app
+        image: ` + image + "\n" + extra
+}
+
+// helmExtraConfigMap is an object only some revisions of the payment release have
+const helmExtraConfigMap = `---
+# Source: payment/templates/configmap.yaml
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: payment-extra
+data:
+  feature: "on"
+`
+
+// encodeHelmTestRelease encodes a release document as helm stores it
+func encodeHelmTestRelease(t *testing.T, document map[string]interface{}) []byte {
+	t.Helper()
+	data, err := json.Marshal(document)
+	require.NoError(t, err)
+	var compressed bytes.Buffer
+	writer := gzip.NewWriter(&compressed)
+	_, err = writer.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+	return []byte(base64.StdEncoding.EncodeToString(compressed.Bytes()))
+}
+
+// newHelmReleaseSecret returns the Secret helm stores a revision of the payment
+// release in
+func newHelmReleaseSecret(t *testing.T, version int, status, manifest string) *corev1.Secret {
+	t.Helper()
+	release, err := decodeHelmRelease(encodeHelmTestRelease(t, map[string]interface{}{
+		"name":      "payment",
+		"namespace": "default",
+		"version":   version,
+		"info":      map[string]interface{}{"status": status, "description": "Install complete", "notes": "Thanks"},
+		"chart": map[string]interface{}{
+			"metadata":  map[string]interface{}{"name": "payment", "version": fmt.Sprintf("1.%d.0", version), "appVersion": "2.0"},
+			"templates": []interface{}{map[string]interface{}{"name": "templates/deployment.yaml"}},
+		},
+		"config":   map[string]interface{}{"replicas": 2},
+		"manifest": manifest,
+		"hooks":    []interface{}{map[string]interface{}{"name": "migrate", "events": []string{"pre-upgrade"}}},
+	}))
+	require.NoError(t, err)
+	secret, err := newHelmReleaseStore(nil).secret(release)
+	require.NoError(t, err)
+	return secret
+}
+
+// newHelmTestObject returns a live object of the payment release
+func newHelmTestObject(apiVersion, kind, name string, fields map[string]interface{}) *unstructured.Unstructured {
+	object := &unstructured.Unstructured{Object: fields}
+	object.SetAPIVersion(apiVersion)
+	object.SetKind(kind)
+	object.SetNamespace("default")
+	object.SetName(name)
+	return object
+}
+
+// newTestHelmRemediator returns a Helm remediator of the release secrets and live
+// objects, and the dynamic client the objects are kept in
+func newTestHelmRemediator(secrets []runtime.Object, objects ...runtime.Object) (*HelmRemediator, *fake.Clientset, *dynamicfake.FakeDynamicClient) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	clientset := fake.NewSimpleClientset(secrets...)
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), objects...)
+	// The fake tracker cannot merge a strategic merge patch into unstructured
+	// objects, so merge it using the typed object as the API server does
+	dynamicClient.PrependReactor("patch", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		patch := action.(k8stesting.PatchAction)
+		if patch.GetPatchType() != types.StrategicMergePatchType {
+			return false, nil, nil
+		}
+		stored, err := dynamicClient.Tracker().Get(patch.GetResource(), patch.GetNamespace(), patch.GetName())
+		if err != nil {
+			return true, nil, err
+		}
+		live := stored.(*unstructured.Unstructured)
+		typed, err := scheme.Scheme.New(live.GroupVersionKind())
+		if err != nil {
+			return true, nil, err
+		}
+		liveJSON, err := live.MarshalJSON()
+		if err != nil {
+			return true, nil, err
+		}
+		merged, err := strategicpatch.StrategicMergePatch(liveJSON, patch.GetPatch(), typed)
+		if err != nil {
+			return true, nil, err
+		}
+		object := &unstructured.Unstructured{}
+		if err := object.UnmarshalJSON(merged); err != nil {
+			return true, nil, err
+		}
+		return true, object, dynamicClient.Tracker().Update(patch.GetResource(), object, patch.GetNamespace())
+	})
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+
+	remediator := NewHelmRemediator(clientset, dynamicClient, log)
+	remediator.SetRESTMapper(mapper)
+	return remediator, clientset, dynamicClient
+}
+
+// newHelmDeploymentInfo returns the deployment info of the payment release's Deployment
+func newHelmDeploymentInfo() *models.DeploymentInfo {
+	info := models.NewDeploymentInfo("default", "payment", "Deployment", models.DeploymentMethodHelm, 0.9)
+	info.SetDetail("release_name", "payment")
+	return info
+}
+
+func TestDecodeHelmRelease(t *testing.T) {
+	secret := newHelmReleaseSecret(t, 3, HelmStatusDeployed, helmTestManifest("payment:v3", ""))
+	assert.Equal(t, "sh.helm.release.v1.payment.v3", secret.Name)
+	assert.Equal(t, map[string]string{"name": "payment", "owner": "helm", "status": "deployed", "version": "3"}, secret.Labels)
+
+	release, err := decodeHelmRelease(secret.Data["release"])
+	require.NoError(t, err)
+	assert.Equal(t, 3, release.Version)
+	assert.Equal(t, HelmStatusDeployed, release.Info.Status)
+	assert.Equal(t, "payment-1.3.0", release.Chart.String())
+	assert.Equal(t, "2.0", release.Chart.Metadata.AppVersion)
+	assert.Equal(t, map[string]interface{}{"replicas": float64(2)}, release.Config)
+	assert.Contains(t, release.Manifest, "image: payment:v3")
+
+	// A new revision keeps the fields it does not decode
+	next, err := release.next(4, HelmStatusPendingRollback, "Rollback to 3", time.Now()).encode()
+	require.NoError(t, err)
+	decoded, err := decodeHelmRelease(next)
+	require.NoError(t, err)
+	assert.Equal(t, 4, decoded.Version)
+	assert.Equal(t, HelmStatusPendingRollback, decoded.Info.Status)
+	assert.JSONEq(t, `[{"name":"migrate","events":["pre-upgrade"]}]`, string(decoded.raw["hooks"]))
+	assert.JSONEq(t, `"Thanks"`, string(decodedInfo(t, decoded)["notes"]))
+
+	// Documents that are not gzipped decode too
+	_, err = decodeHelmRelease([]byte(base64.StdEncoding.EncodeToString([]byte(`{"name":"payment","version":1}`))))
+	assert.NoError(t, err)
+	_, err = decodeHelmRelease([]byte("not base64!"))
+	assert.Error(t, err)
+}
+
+// decodedInfo returns the stored info fields of a release
+func decodedInfo(t *testing.T, release *HelmRelease) map[string]json.RawMessage {
+	t.Helper()
+	var info map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(release.raw["info"], &info))
+	return info
+}
+
+// releaseStatuses returns the status of each stored revision of the payment release
+func releaseStatuses(t *testing.T, clientset *fake.Clientset) map[int]string {
+	t.Helper()
+	history, err := newHelmReleaseStore(clientset).history(context.Background(), "default", "payment")
+	require.NoError(t, err)
+	statuses := make(map[int]string, len(history))
+	for _, release := range history {
+		statuses[release.Version] = release.Info.Status
+	}
+	return statuses
+}
+
+func TestHelmRemediator_RollsBackFailedRelease(t *testing.T) {
+	remediator, clientset, dynamicClient := newTestHelmRemediator(
+		[]runtime.Object{
+			newHelmReleaseSecret(t, 1, HelmStatusSuperseded, helmTestManifest("payment:v1", "")),
+			newHelmReleaseSecret(t, 2, HelmStatusFailed, helmTestManifest("payment:v2", helmExtraConfigMap)),
+		},
+		newHelmTestObject("apps/v1", "Deployment", "payment", map[string]interface{}{"spec": map[string]interface{}{
+			"template": map[string]interface{}{"spec": map[string]interface{}{"containers": []interface{}{
+				map[string]interface{}{"name": "app", "image": "payment:v2"},
+			}}},
+		}}),
+		newHelmTestObject("v1", "ConfigMap", "payment-extra", map[string]interface{}{"data": map[string]interface{}{"feature": "on"}}),
+	)
+
+	var rollback models.Rollback
+	ctx := withRollbackRecorder(context.Background(), func(r models.Rollback) { rollback = r })
+	result, err := remediator.Remediate(ctx, newHelmDeploymentInfo(), newTestIssue("issue-1"))
+	require.NoError(t, err)
+	require.Len(t, result.Actions, 1)
+	assert.Equal(t, models.PerformedAction{Action: "helm_rollback", Target: "default/payment", Before: "revision 2 (failed)", After: "revision 1"},
+		result.Actions[0])
+	assert.Equal(t, "payment-1.2.0", result.Evidence["chart"])
+	assert.Equal(t, models.Rollback{Kind: KindHelmRelease, Name: "payment", FromRevision: 2, ToRevision: 1}, rollback)
+
+	// The manifest of revision 1 is applied and owned by the release
+	deployments := dynamicClient.Resource(schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}).Namespace("default")
+	deployment, err := deployments.Get(context.Background(), "payment", metav1.GetOptions{})
+	require.NoError(t, err)
+	containers, _, _ := unstructured.NestedSlice(deployment.Object, "spec", "template", "spec", "containers")
+	assert.Equal(t, "payment:v1", containers[0].(map[string]interface{})["image"])
+	assert.Equal(t, "payment", deployment.GetAnnotations()["meta.helm.sh/release-name"])
+
+	// The object revision 1 does not have is deleted
+	configMaps := dynamicClient.Resource(schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}).Namespace("default")
+	_, err = configMaps.Get(context.Background(), "payment-extra", metav1.GetOptions{})
+	assert.True(t, apierrors.IsNotFound(err))
+
+	// Revision 3 is recorded as helm would
+	assert.Equal(t, map[int]string{1: HelmStatusSuperseded, 2: HelmStatusSuperseded, 3: HelmStatusDeployed}, releaseStatuses(t, clientset))
+	history, err := newHelmReleaseStore(clientset).history(context.Background(), "default", "payment")
+	require.NoError(t, err)
+	assert.Equal(t, "Rollback to 1", history[2].Info.Description)
+	assert.Equal(t, "payment-1.1.0", history[2].Chart.String())
+	assert.NotEmpty(t, history[2].raw["hooks"])
+}
+
+func TestHelmRemediator_RollbackRemovesNewerFields(t *testing.T) {
+	// Revision 2 added minReadySeconds, which the failed upgrade applied; the
+	// autoscaler has since scaled the Deployment
+	newer := `---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: payment
+spec:
+  minReadySeconds: 30
+  template:
+    spec:
+      containers:
+      - name: app
+        image: payment:v2
+`
+	remediator, _, dynamicClient := newTestHelmRemediator(
+		[]runtime.Object{
+			newHelmReleaseSecret(t, 1, HelmStatusSuperseded, helmTestManifest("payment:v1", "")),
+			newHelmReleaseSecret(t, 2, HelmStatusFailed, newer),
+		},
+		newHelmTestObject("apps/v1", "Deployment", "payment", map[string]interface{}{"spec": map[string]interface{}{
+			"replicas":        int64(5),
+			"minReadySeconds": int64(30),
+			"template": map[string]interface{}{"spec": map[string]interface{}{"containers": []interface{}{
+				map[string]interface{}{"name": "app", "image": "payment:v2", "terminationMessagePath": "/dev/termination-log"},
+			}}},
+		}}),
+	)
+
+	_, err := remediator.Remediate(context.Background(), newHelmDeploymentInfo(), newTestIssue("issue-1"))
+	require.NoError(t, err)
+
+	deployments := dynamicClient.Resource(schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}).Namespace("default")
+	deployment, err := deployments.Get(context.Background(), "payment", metav1.GetOptions{})
+	require.NoError(t, err)
+	_, found, _ := unstructured.NestedFieldNoCopy(deployment.Object, "spec", "minReadySeconds")
+	assert.False(t, found, "the field only revision 2 set is removed")
+	replicas, _, _ := unstructured.NestedInt64(deployment.Object, "spec", "replicas")
+	assert.Equal(t, int64(5), replicas, "fields the release never set are kept")
+	containers, _, _ := unstructured.NestedSlice(deployment.Object, "spec", "template", "spec", "containers")
+	require.Len(t, containers, 1)
+	assert.Equal(t, map[string]interface{}{"name": "app", "image": "payment:v1", "terminationMessagePath": "/dev/termination-log"}, containers[0])
+}
+
+func TestHelmRemediator_UpgradesDeployedRelease(t *testing.T) {
+	remediator, clientset, _ := newTestHelmRemediator(
+		[]runtime.Object{
+			newHelmReleaseSecret(t, 1, HelmStatusSuperseded, helmTestManifest("payment:v1", "")),
+			newHelmReleaseSecret(t, 2, HelmStatusDeployed, helmTestManifest("payment:v2", "")),
+		},
+		newHelmTestObject("apps/v1", "Deployment", "payment", map[string]interface{}{}),
+	)
+
+	actions, err := remediator.PlanActions(context.Background(), newHelmDeploymentInfo(), newTestIssue("issue-1"))
+	require.NoError(t, err)
+	assert.Equal(t, "upgrade release payment to revision 3 with chart payment-1.2.0 and its current values", actions[0].Description)
+
+	result, err := remediator.Remediate(context.Background(), newHelmDeploymentInfo(), newTestIssue("issue-1"))
+	require.NoError(t, err)
+	require.Len(t, result.Actions, 1)
+	assert.Equal(t, models.PerformedAction{Action: "helm_upgrade", Target: "default/payment", Before: "revision 2", After: "revision 3"},
+		result.Actions[0])
+	assert.Equal(t, map[int]string{1: HelmStatusSuperseded, 2: HelmStatusSuperseded, 3: HelmStatusDeployed}, releaseStatuses(t, clientset))
+}
+
+func TestHelmRemediator_UpgradeKeepsAppliedRevision(t *testing.T) {
+	remediator, clientset, _ := newTestHelmRemediator(
+		[]runtime.Object{newHelmReleaseSecret(t, 1, HelmStatusDeployed, helmTestManifest("payment:v1", ""))},
+		newHelmTestObject("apps/v1", "Deployment", "payment", map[string]interface{}{}),
+	)
+	clientset.PrependReactor("update", "secrets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.(k8stesting.UpdateAction).GetObject().(*corev1.Secret).Name != helmReleaseSecretName("payment", 1) {
+			return false, nil, nil
+		}
+		return true, nil, errors.New("etcd unavailable")
+	})
+
+	// Failing to supersede the old revision does not roll back the applied upgrade
+	result, err := remediator.Remediate(context.Background(), newHelmDeploymentInfo(), newTestIssue("issue-1"))
+	require.NoError(t, err)
+	require.Len(t, result.Actions, 1)
+	assert.Equal(t, "helm_upgrade", result.Actions[0].Action)
+	assert.Equal(t, map[int]string{1: HelmStatusDeployed, 2: HelmStatusDeployed}, releaseStatuses(t, clientset))
+}
+
+func TestHelmRemediator_ReleaseErrors(t *testing.T) {
+	tests := []struct {
+		name             string
+		secrets          []runtime.Object
+		rollbackRevision int64
+		wantErr          string
+		wantClass        models.ErrorClass
+	}{
+		{name: "release not found", wantErr: "helm release not found", wantClass: models.ErrorClassPermanent},
+		{
+			name:      "operation in progress",
+			secrets:   []runtime.Object{newHelmReleaseSecret(t, 1, HelmStatusPendingInstall, helmTestManifest("payment:v1", ""))},
+			wantErr:   "another helm operation is in progress",
+			wantClass: models.ErrorClassRetryable,
+		},
+		{
+			name:      "nothing to roll back to",
+			secrets:   []runtime.Object{newHelmReleaseSecret(t, 1, HelmStatusFailed, helmTestManifest("payment:v1", ""))},
+			wantErr:   "no deployed revision before 1",
+			wantClass: models.ErrorClassPermanent,
+		},
+		{
+			name: "requested revision missing",
+			secrets: []runtime.Object{
+				newHelmReleaseSecret(t, 1, HelmStatusSuperseded, helmTestManifest("payment:v1", "")),
+				newHelmReleaseSecret(t, 2, HelmStatusFailed, helmTestManifest("payment:v2", "")),
+			},
+			rollbackRevision: 7,
+			wantErr:          "has no revision 7",
+			wantClass:        models.ErrorClassPermanent,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			remediator, _, _ := newTestHelmRemediator(tt.secrets)
+			issue := newTestIssue("issue-1")
+			issue.RollbackRevision = tt.rollbackRevision
+
+			_, err := remediator.Remediate(context.Background(), newHelmDeploymentInfo(), issue)
+			assert.ErrorContains(t, err, tt.wantErr)
+			assert.Equal(t, tt.wantClass, ClassifyError(err))
+		})
+	}
+}
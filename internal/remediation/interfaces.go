@@ -8,8 +8,12 @@ import (
 
 // Remediator performs remediation for a specific deployment method
 type Remediator interface {
-	// Remediate executes remediation logic
-	Remediate(ctx context.Context, deploymentInfo *models.DeploymentInfo, issue *models.Issue) error
+	// Remediate executes remediation logic and reports what it did. A failed
+	// remediation still returns the actions it performed before failing.
+	Remediate(ctx context.Context, deploymentInfo *models.DeploymentInfo, issue *models.Issue) (*models.RemediationResult, error)
+
+	// PlanActions describes the actions Remediate would take, without mutating anything
+	PlanActions(ctx context.Context, deploymentInfo *models.DeploymentInfo, issue *models.Issue) ([]PlannedAction, error)
 
 	// CanRemediate returns true if this remediator can handle the deployment
 	CanRemediate(deploymentInfo *models.DeploymentInfo) bool
@@ -18,12 +22,9 @@ type Remediator interface {
 	Name() string
 }
 
-// RemediationResult contains the outcome of remediation
-//
-//nolint:revive // intentional naming for clarity in external package usage
-type RemediationResult struct {
-	Status   string `json:"status"` // "success", "failed", "recommendation"
-	Method   string `json:"method"` // Remediation method used
-	Message  string `json:"message,omitempty"`
-	Duration string `json:"duration,omitempty"`
+// PlannedAction describes a single mutation a remediator would perform
+type PlannedAction struct {
+	Action      string `json:"action"` // e.g. "helm_rollback", "argocd_sync", "delete_pod"
+	Target      string `json:"target"` // resource or release the action applies to
+	Description string `json:"description"`
 }
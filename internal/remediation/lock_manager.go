@@ -0,0 +1,410 @@
+package remediation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/detector"
+)
+
+// Lease annotations mirrored onto the target object while a remediation holds its lock
+const (
+	LockHolderAnnotation  = "remediation.aiops/lock-holder"
+	LockExpiresAnnotation = "remediation.aiops/lock-expires"
+)
+
+// DefaultLeaseDuration bounds how long a mirrored lease annotation is honoured.
+// Held leases are renewed well before they expire, so it only bounds how long the
+// lease of a crashed engine blocks others.
+const DefaultLeaseDuration = 15 * time.Minute
+
+// remoteLeasePollInterval is how often a lease held by another engine is re-checked
+const remoteLeasePollInterval = 2 * time.Second
+
+// ResourceRef identifies a Kubernetes resource targeted by remediation
+type ResourceRef struct {
+	Namespace string
+	Kind      string
+	Name      string
+}
+
+// Key returns the namespace/kind/name lock key
+func (r ResourceRef) Key() string {
+	return fmt.Sprintf("%s/%s/%s", r.Namespace, NormalizeKind(r.Kind), r.Name)
+}
+
+// String returns a human-readable representation
+func (r ResourceRef) String() string {
+	return fmt.Sprintf("%s %s/%s", NormalizeKind(r.Kind), r.Namespace, r.Name)
+}
+
+// ResourceLease is a held resource lock
+type ResourceLease struct {
+	Ref        ResourceRef
+	Owner      string
+	AcquiredAt time.Time
+	Waited     time.Duration
+	Contended  bool // true if another holder had to release first
+
+	manager    *LockManager
+	released   chan struct{}
+	once       sync.Once
+	annotating sync.Mutex // orders renewals before the annotation is cleared
+}
+
+// Release frees the lock and removes any mirrored lease annotation
+func (l *ResourceLease) Release() {
+	l.once.Do(func() {
+		l.manager.release(l)
+	})
+}
+
+// LockManager serializes mutations of the same resource across the remediation and
+// coordination orchestrators. Locks are held in process; optionally they are mirrored
+// as annotations on the target object so other engine replicas and tools can see them.
+type LockManager struct {
+	mu    sync.Mutex
+	locks map[string]*ResourceLease
+
+	clientset     kubernetes.Interface
+	dynamic       dynamic.Interface
+	identity      string
+	leaseDuration time.Duration
+	log           *logrus.Logger
+}
+
+// NewLockManager creates a new in-process resource lock manager
+func NewLockManager(log *logrus.Logger) *LockManager {
+	return &LockManager{
+		locks:         make(map[string]*ResourceLease),
+		leaseDuration: DefaultLeaseDuration,
+		log:           log,
+	}
+}
+
+// EnableLeaseAnnotations mirrors held locks as annotations on the target object.
+// identity distinguishes this engine replica from others sharing the cluster.
+func (lm *LockManager) EnableLeaseAnnotations(clientset kubernetes.Interface, identity string, leaseDuration time.Duration) {
+	lm.clientset = clientset
+	lm.identity = identity
+	if leaseDuration > 0 {
+		lm.leaseDuration = leaseDuration
+	}
+	lm.log.WithFields(logrus.Fields{
+		"identity":       identity,
+		"lease_duration": lm.leaseDuration,
+	}).Info("Resource lock lease annotations enabled")
+}
+
+// SetDynamicClient enables lease annotations on DeploymentConfigs and Argo Rollouts
+func (lm *LockManager) SetDynamicClient(client dynamic.Interface) {
+	lm.dynamic = client
+}
+
+// Holder returns the owner currently holding the lock for ref, or empty if free
+func (lm *LockManager) Holder(ref ResourceRef) string {
+	lm.mu.Lock()
+	defer lm.mu.Unlock()
+	if lease, ok := lm.locks[ref.Key()]; ok {
+		return lease.Owner
+	}
+	return ""
+}
+
+// TryAcquire takes the lock without waiting. When the lock is held, it returns nil
+// and the current holder.
+func (lm *LockManager) TryAcquire(ctx context.Context, ref ResourceRef, owner string) (lease *ResourceLease, holder string) {
+	if holder := lm.Holder(ref); holder != "" {
+		return nil, holder
+	}
+
+	// Another engine replica may hold the lease on the object itself
+	if remote := lm.remoteHolder(ctx, ref); remote != "" {
+		return nil, remote
+	}
+
+	lm.mu.Lock()
+	if existing, ok := lm.locks[ref.Key()]; ok {
+		lm.mu.Unlock()
+		return nil, existing.Owner
+	}
+	lease = &ResourceLease{
+		Ref:        ref,
+		Owner:      owner,
+		AcquiredAt: time.Now(),
+		manager:    lm,
+		released:   make(chan struct{}),
+	}
+	lm.locks[ref.Key()] = lease
+	lm.mu.Unlock()
+
+	if lm.clientset != nil {
+		lm.annotate(ctx, lease, lease.AcquiredAt)
+		go lm.renew(lease)
+	}
+	return lease, ""
+}
+
+// Acquire takes the lock, waiting until it is free or ctx is done
+func (lm *LockManager) Acquire(ctx context.Context, ref ResourceRef, owner string) (*ResourceLease, error) {
+	start := time.Now()
+	contended := false
+
+	for {
+		lease, holder := lm.TryAcquire(ctx, ref, owner)
+		if lease != nil {
+			lease.Waited = time.Since(start)
+			lease.Contended = contended
+			RecordLockAcquired(NormalizeKind(ref.Kind), contended, lease.Waited.Seconds())
+			return lease, nil
+		}
+
+		if !contended {
+			contended = true
+			lm.log.WithFields(logrus.Fields{
+				"resource": ref.Key(),
+				"owner":    owner,
+				"holder":   holder,
+			}).Info("Waiting for resource lock")
+		}
+
+		// Wait for the local holder to release, or poll a remote lease
+		lm.mu.Lock()
+		var released <-chan struct{}
+		if existing, ok := lm.locks[ref.Key()]; ok {
+			released = existing.released
+		}
+		lm.mu.Unlock()
+
+		var poll <-chan time.Time
+		if released == nil {
+			poll = time.After(remoteLeasePollInterval)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("waiting for lock on %s held by %s: %w", ref.Key(), holder, ctx.Err())
+		case <-released:
+		case <-poll:
+		}
+	}
+}
+
+// release frees a lease and wakes any waiters
+func (lm *LockManager) release(lease *ResourceLease) {
+	lm.mu.Lock()
+	if current, ok := lm.locks[lease.Ref.Key()]; ok && current == lease {
+		delete(lm.locks, lease.Ref.Key())
+	}
+	lm.mu.Unlock()
+	close(lease.released)
+
+	// Use a fresh context: the owner's context is often already cancelled on release
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	lease.annotating.Lock()
+	defer lease.annotating.Unlock()
+	lm.clearAnnotation(ctx, lease)
+}
+
+// renew extends the mirrored lease until it is released, so other engines keep
+// honouring it however long the remediation runs
+func (lm *LockManager) renew(lease *ResourceLease) {
+	ticker := time.NewTicker(lm.leaseDuration / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-lease.released:
+			return
+		case <-ticker.C:
+		}
+
+		lease.annotating.Lock()
+		select {
+		case <-lease.released:
+		default:
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			lm.annotate(ctx, lease, time.Now())
+			cancel()
+		}
+		lease.annotating.Unlock()
+	}
+}
+
+// remoteHolder returns the holder of an unexpired lease annotation set by another engine
+func (lm *LockManager) remoteHolder(ctx context.Context, ref ResourceRef) string {
+	if lm.clientset == nil {
+		return ""
+	}
+
+	annotations, err := lm.getAnnotations(ctx, ref)
+	if err != nil {
+		return ""
+	}
+
+	holder := annotations[LockHolderAnnotation]
+	if holder == "" || strings.HasPrefix(holder, lm.identity+"/") {
+		return ""
+	}
+	expires, err := time.Parse(time.RFC3339, annotations[LockExpiresAnnotation])
+	if err != nil || time.Now().After(expires) {
+		return ""
+	}
+	return holder
+}
+
+// annotate mirrors a lease onto the target object, expiring a lease duration after renewed
+func (lm *LockManager) annotate(ctx context.Context, lease *ResourceLease, renewed time.Time) {
+	if lm.clientset == nil {
+		return
+	}
+	patch := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{
+				LockHolderAnnotation:  lm.identity + "/" + lease.Owner,
+				LockExpiresAnnotation: renewed.Add(lm.leaseDuration).UTC().Format(time.RFC3339),
+			},
+		},
+	}
+	if err := lm.patchAnnotations(ctx, lease.Ref, patch); err != nil {
+		lm.log.WithError(err).WithField("resource", lease.Ref.Key()).Debug("Failed to mirror lock lease annotation")
+	}
+}
+
+// clearAnnotation removes a mirrored lease from the target object
+func (lm *LockManager) clearAnnotation(ctx context.Context, lease *ResourceLease) {
+	if lm.clientset == nil {
+		return
+	}
+	patch := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{
+				LockHolderAnnotation:  nil,
+				LockExpiresAnnotation: nil,
+			},
+		},
+	}
+	if err := lm.patchAnnotations(ctx, lease.Ref, patch); err != nil {
+		lm.log.WithError(err).WithField("resource", lease.Ref.Key()).Debug("Failed to clear lock lease annotation")
+	}
+}
+
+// getAnnotations reads the annotations of a supported target kind
+func (lm *LockManager) getAnnotations(ctx context.Context, ref ResourceRef) (map[string]string, error) {
+	switch NormalizeKind(ref.Kind) {
+	case "Deployment":
+		obj, err := lm.clientset.AppsV1().Deployments(ref.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return obj.Annotations, nil
+	case "StatefulSet":
+		obj, err := lm.clientset.AppsV1().StatefulSets(ref.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return obj.Annotations, nil
+	case "DaemonSet":
+		obj, err := lm.clientset.AppsV1().DaemonSets(ref.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return obj.Annotations, nil
+	case "Pod":
+		obj, err := lm.clientset.CoreV1().Pods(ref.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return obj.Annotations, nil
+	case "DeploymentConfig", "Rollout":
+		client, err := lm.customResource(ref)
+		if err != nil {
+			return nil, err
+		}
+		obj, err := client.Get(ctx, ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return obj.GetAnnotations(), nil
+	default:
+		return nil, fmt.Errorf("lease annotations not supported for kind %s", ref.Kind)
+	}
+}
+
+// patchAnnotations applies a merge patch to a supported target kind
+func (lm *LockManager) patchAnnotations(ctx context.Context, ref ResourceRef, patch map[string]interface{}) error {
+	data, err := json.Marshal(patch)
+	if err != nil {
+		return fmt.Errorf("failed to marshal lease patch: %w", err)
+	}
+
+	switch NormalizeKind(ref.Kind) {
+	case "Deployment":
+		_, err = lm.clientset.AppsV1().Deployments(ref.Namespace).Patch(ctx, ref.Name, types.MergePatchType, data, metav1.PatchOptions{})
+	case "StatefulSet":
+		_, err = lm.clientset.AppsV1().StatefulSets(ref.Namespace).Patch(ctx, ref.Name, types.MergePatchType, data, metav1.PatchOptions{})
+	case "DaemonSet":
+		_, err = lm.clientset.AppsV1().DaemonSets(ref.Namespace).Patch(ctx, ref.Name, types.MergePatchType, data, metav1.PatchOptions{})
+	case "Pod":
+		_, err = lm.clientset.CoreV1().Pods(ref.Namespace).Patch(ctx, ref.Name, types.MergePatchType, data, metav1.PatchOptions{})
+	case "DeploymentConfig", "Rollout":
+		client, clientErr := lm.customResource(ref)
+		if clientErr != nil {
+			return clientErr
+		}
+		_, err = client.Patch(ctx, ref.Name, types.MergePatchType, data, metav1.PatchOptions{})
+	default:
+		return fmt.Errorf("lease annotations not supported for kind %s", ref.Kind)
+	}
+	return err
+}
+
+// customResource returns the dynamic client of a DeploymentConfig or Rollout target
+func (lm *LockManager) customResource(ref ResourceRef) (dynamic.ResourceInterface, error) {
+	if lm.dynamic == nil {
+		return nil, fmt.Errorf("lease annotations on %s need a dynamic client", ref.Kind)
+	}
+	gvr := detector.DeploymentConfigGVR
+	if NormalizeKind(ref.Kind) == "Rollout" {
+		gvr = detector.RolloutGVR
+	}
+	return lm.dynamic.Resource(gvr).Namespace(ref.Namespace), nil
+}
+
+// NormalizeKind maps lowercase or plural resource types from issues to Kubernetes kinds
+func NormalizeKind(kind string) string {
+	switch strings.ToLower(kind) {
+	case "deployment", "deployments":
+		return "Deployment"
+	case "statefulset", "statefulsets":
+		return "StatefulSet"
+	case "daemonset", "daemonsets":
+		return "DaemonSet"
+	case "pod", "pods":
+		return "Pod"
+	case "replicaset", "replicasets":
+		return "ReplicaSet"
+	case "job", "jobs":
+		return "Job"
+	case "cronjob", "cronjobs":
+		return "CronJob"
+	case "replicationcontroller", "replicationcontrollers":
+		return "ReplicationController"
+	case "deploymentconfig", "deploymentconfigs":
+		return "DeploymentConfig"
+	case "rollout", "rollouts":
+		return "Rollout"
+	default:
+		return kind
+	}
+}
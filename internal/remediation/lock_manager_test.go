@@ -0,0 +1,195 @@
+package remediation
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/detector"
+)
+
+func newTestLockManager() *LockManager {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	return NewLockManager(log)
+}
+
+func TestLockManager_TryAcquire(t *testing.T) {
+	lm := newTestLockManager()
+	ctx := context.Background()
+	ref := ResourceRef{Namespace: "default", Kind: "deployment", Name: "payment"}
+
+	lease, holder := lm.TryAcquire(ctx, ref, "wf-1")
+	require.NotNil(t, lease)
+	assert.Empty(t, holder)
+
+	// Kind is normalized, so "Deployment" maps to the same lock
+	second, holder := lm.TryAcquire(ctx, ResourceRef{Namespace: "default", Kind: "Deployment", Name: "payment"}, "plan-1/step-1")
+	assert.Nil(t, second)
+	assert.Equal(t, "wf-1", holder)
+
+	lease.Release()
+	lease.Release() // idempotent
+
+	third, _ := lm.TryAcquire(ctx, ref, "plan-1/step-1")
+	require.NotNil(t, third)
+	third.Release()
+}
+
+func TestLockManager_AcquireWaitsForRelease(t *testing.T) {
+	lm := newTestLockManager()
+	ctx := context.Background()
+	ref := ResourceRef{Namespace: "default", Kind: "Deployment", Name: "payment"}
+
+	first, err := lm.Acquire(ctx, ref, "wf-1")
+	require.NoError(t, err)
+	assert.False(t, first.Contended)
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		first.Release()
+	}()
+
+	second, err := lm.Acquire(ctx, ref, "wf-2")
+	require.NoError(t, err)
+	assert.True(t, second.Contended)
+	assert.Equal(t, "wf-2", lm.Holder(ref))
+	second.Release()
+}
+
+func TestLockManager_AcquireHonoursContext(t *testing.T) {
+	lm := newTestLockManager()
+	ref := ResourceRef{Namespace: "default", Kind: "Deployment", Name: "payment"}
+
+	held, err := lm.Acquire(context.Background(), ref, "wf-1")
+	require.NoError(t, err)
+	defer held.Release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = lm.Acquire(ctx, ref, "wf-2")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "held by wf-1")
+}
+
+func TestLockManager_LeaseAnnotations(t *testing.T) {
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "payment", Namespace: "default"},
+	}
+	clientset := fake.NewSimpleClientset(deployment)
+	ctx := context.Background()
+	ref := ResourceRef{Namespace: "default", Kind: "Deployment", Name: "payment"}
+
+	lm := newTestLockManager()
+	lm.EnableLeaseAnnotations(clientset, "engine-a", time.Minute)
+
+	lease, _ := lm.TryAcquire(ctx, ref, "wf-1")
+	require.NotNil(t, lease)
+
+	got, err := clientset.AppsV1().Deployments("default").Get(ctx, "payment", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "engine-a/wf-1", got.Annotations[LockHolderAnnotation])
+	assert.NotEmpty(t, got.Annotations[LockExpiresAnnotation])
+
+	// A second replica sees the lease and does not take the lock
+	other := newTestLockManager()
+	other.EnableLeaseAnnotations(clientset, "engine-b", time.Minute)
+	remote, holder := other.TryAcquire(ctx, ref, "wf-9")
+	assert.Nil(t, remote)
+	assert.Equal(t, "engine-a/wf-1", holder)
+
+	lease.Release()
+
+	got, err = clientset.AppsV1().Deployments("default").Get(ctx, "payment", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.NotContains(t, got.Annotations, LockHolderAnnotation)
+
+	remote, _ = other.TryAcquire(ctx, ref, "wf-9")
+	require.NotNil(t, remote)
+	remote.Release()
+}
+
+func TestLockManager_RenewsLeaseAnnotation(t *testing.T) {
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "payment", Namespace: "default"},
+	}
+	clientset := fake.NewSimpleClientset(deployment)
+	var mu sync.Mutex
+	patches := 0
+	clientset.PrependReactor("patch", "deployments", func(k8stesting.Action) (bool, runtime.Object, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		patches++
+		return false, nil, nil
+	})
+	patchCount := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return patches
+	}
+
+	lm := newTestLockManager()
+	lm.EnableLeaseAnnotations(clientset, "engine-a", 30*time.Millisecond)
+	lease, _ := lm.TryAcquire(context.Background(), ResourceRef{Namespace: "default", Kind: "Deployment", Name: "payment"}, "wf-1")
+	require.NotNil(t, lease)
+
+	// The lease is renewed while held, well past its initial duration
+	assert.Eventually(t, func() bool { return patchCount() >= 4 }, time.Second, 5*time.Millisecond)
+
+	lease.Release()
+	released := patchCount()
+	got, err := clientset.AppsV1().Deployments("default").Get(context.Background(), "payment", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.NotContains(t, got.Annotations, LockHolderAnnotation)
+
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, released, patchCount(), "released leases are not renewed")
+}
+
+func TestLockManager_LeaseAnnotationsOnDeploymentConfig(t *testing.T) {
+	dc := &unstructured.Unstructured{}
+	dc.SetAPIVersion("apps.openshift.io/v1")
+	dc.SetKind("DeploymentConfig")
+	dc.SetNamespace("default")
+	dc.SetName("legacy")
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), dc)
+	clientset := fake.NewSimpleClientset()
+	ctx := context.Background()
+	ref := ResourceRef{Namespace: "default", Kind: "deploymentconfig", Name: "legacy"}
+
+	lm := newTestLockManager()
+	lm.EnableLeaseAnnotations(clientset, "engine-a", time.Minute)
+	lm.SetDynamicClient(dynamicClient)
+	lease, _ := lm.TryAcquire(ctx, ref, "wf-1")
+	require.NotNil(t, lease)
+
+	dcs := dynamicClient.Resource(detector.DeploymentConfigGVR).Namespace("default")
+	got, err := dcs.Get(ctx, "legacy", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "engine-a/wf-1", got.GetAnnotations()[LockHolderAnnotation])
+
+	// A second replica sees the lease and does not take the lock
+	other := newTestLockManager()
+	other.EnableLeaseAnnotations(clientset, "engine-b", time.Minute)
+	other.SetDynamicClient(dynamicClient)
+	remote, holder := other.TryAcquire(ctx, ref, "wf-9")
+	assert.Nil(t, remote)
+	assert.Equal(t, "engine-a/wf-1", holder)
+
+	lease.Release()
+	got, err = dcs.Get(ctx, "legacy", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.NotContains(t, got.GetAnnotations(), LockHolderAnnotation)
+}
@@ -0,0 +1,514 @@
+package remediation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"sync"
+	"time"
+	_ "time/tzdata" // window time zones must resolve without the host's zoneinfo
+
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/yaml"
+
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// Maintenance window types
+const (
+	WindowTypeBlackout    = "blackout"    // automated remediation is blocked while the window is open
+	WindowTypeMaintenance = "maintenance" // automated remediation only runs while the window is open
+)
+
+// Maintenance actions: what happens to a remediation blocked by a window or freeze
+const (
+	MaintenanceActionReject    = "reject"    // refuse the trigger
+	MaintenanceActionQueue     = "queue"     // defer the workflow until automation may run
+	MaintenanceActionRecommend = "recommend" // record the planned actions as recommendations only
+)
+
+// GlobalFreezeWindow names the runtime freeze in maintenance decisions
+const GlobalFreezeWindow = "global-freeze"
+
+// maintenanceRecheckInterval is how often a deferred workflow is re-evaluated when
+// the time automation resumes is unknown
+const maintenanceRecheckInterval = time.Minute
+
+// ErrMaintenanceBlocked is returned when a maintenance window or freeze rejects a remediation
+var ErrMaintenanceBlocked = errors.New("remediation blocked by maintenance policy")
+
+// MaintenanceWindow is a recurring period that blocks automated remediation
+// (blackout) or outside of which automated remediation is blocked (maintenance)
+type MaintenanceWindow struct {
+	Name     string `json:"name"`
+	Type     string `json:"type,omitempty"`     // blackout (default) or maintenance
+	Schedule string `json:"schedule"`           // cron expression for when the window opens
+	Duration string `json:"duration"`           // how long the window stays open, e.g. 2h
+	Timezone string `json:"timezone,omitempty"` // IANA time zone of the schedule, default UTC
+	Action   string `json:"action,omitempty"`   // reject, queue (default) or recommend
+
+	// Scope; an empty scope matches everything
+	Namespaces    []string `json:"namespaces,omitempty"`     // exact names or path.Match globs
+	LabelSelector string   `json:"label_selector,omitempty"` // matched against the remediated resource's labels
+	Layers        []string `json:"layers,omitempty"`         // infrastructure, platform or application
+
+	schedule *cronSchedule
+	duration time.Duration
+	location *time.Location
+	selector labels.Selector
+}
+
+// maintenanceFile is the document read by LoadMaintenanceWindows
+type maintenanceFile struct {
+	Windows []MaintenanceWindow `json:"windows"`
+}
+
+// LoadMaintenanceWindows reads maintenance window definitions from a YAML or JSON file
+func LoadMaintenanceWindows(filename string) ([]MaintenanceWindow, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read maintenance config: %w", err)
+	}
+
+	var file maintenanceFile
+	if err := yaml.UnmarshalStrict(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse maintenance config %s: %w", filename, err)
+	}
+
+	for i := range file.Windows {
+		if err := file.Windows[i].Validate(); err != nil {
+			return nil, fmt.Errorf("maintenance window %d: %w", i, err)
+		}
+	}
+	return file.Windows, nil
+}
+
+// Validate checks the window definition and prepares it for evaluation
+func (w *MaintenanceWindow) Validate() error {
+	if w.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	switch w.Type {
+	case "":
+		w.Type = WindowTypeBlackout
+	case WindowTypeBlackout, WindowTypeMaintenance:
+	default:
+		return fmt.Errorf("%s: invalid type %q (must be blackout or maintenance)", w.Name, w.Type)
+	}
+	switch w.Action {
+	case "":
+		w.Action = MaintenanceActionQueue
+	case MaintenanceActionReject, MaintenanceActionQueue, MaintenanceActionRecommend:
+	default:
+		return fmt.Errorf("%s: invalid action %q (must be reject, queue or recommend)", w.Name, w.Action)
+	}
+
+	schedule, err := parseCron(w.Schedule)
+	if err != nil {
+		return fmt.Errorf("%s: %w", w.Name, err)
+	}
+	w.schedule = schedule
+
+	duration, err := time.ParseDuration(w.Duration)
+	if err != nil || duration <= 0 {
+		return fmt.Errorf("%s: duration must be a positive duration such as 2h", w.Name)
+	}
+	w.duration = duration
+
+	w.location = time.UTC
+	if w.Timezone != "" {
+		location, err := time.LoadLocation(w.Timezone)
+		if err != nil {
+			return fmt.Errorf("%s: invalid timezone %q: %w", w.Name, w.Timezone, err)
+		}
+		w.location = location
+	}
+
+	for _, pattern := range w.Namespaces {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("%s: invalid namespace pattern %q", w.Name, pattern)
+		}
+	}
+	for _, layer := range w.Layers {
+		if err := models.Layer(layer).Validate(); err != nil {
+			return fmt.Errorf("%s: %w", w.Name, err)
+		}
+	}
+	if w.LabelSelector != "" {
+		selector, err := labels.Parse(w.LabelSelector)
+		if err != nil {
+			return fmt.Errorf("%s: invalid label selector: %w", w.Name, err)
+		}
+		w.selector = selector
+	}
+	return nil
+}
+
+// openAt reports whether the window is open at now and, if so, when it closes.
+// Occurrences that start before the previous one closes extend the window.
+func (w *MaintenanceWindow) openAt(now time.Time) (bool, time.Time) {
+	now = now.In(w.location)
+	start := w.schedule.next(now.Add(-w.duration))
+	if start.IsZero() || start.After(now) {
+		return false, time.Time{}
+	}
+
+	end := start.Add(w.duration)
+	for i := 0; i < 1000; i++ {
+		next := w.schedule.next(start)
+		if next.IsZero() || next.After(end) {
+			break
+		}
+		start, end = next, next.Add(w.duration)
+	}
+	return true, end
+}
+
+// nextOpen returns when the window next opens after now, or the zero time if never
+func (w *MaintenanceWindow) nextOpen(now time.Time) time.Time {
+	return w.schedule.next(now.In(w.location))
+}
+
+// matchesScope checks the namespace and layer scope. Label selectors are checked
+// separately because they need the resource's labels.
+func (w *MaintenanceWindow) matchesScope(target MaintenanceTarget) bool {
+	if len(w.Layers) > 0 && !containsFold(w.Layers, string(target.Layer)) {
+		return false
+	}
+	if len(w.Namespaces) == 0 {
+		return true
+	}
+	for _, pattern := range w.Namespaces {
+		if matched, _ := path.Match(pattern, target.Namespace); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// MaintenanceTarget is a resource a remediation would change
+type MaintenanceTarget struct {
+	Namespace string
+	Kind      string
+	Name      string
+	Layer     models.Layer
+}
+
+// MaintenanceDecision explains why a remediation may not run now
+type MaintenanceDecision struct {
+	Action string     `json:"action"`
+	Window string     `json:"window"` // window name, or global-freeze
+	Reason string     `json:"reason"`
+	Until  *time.Time `json:"until,omitempty"` // when automation may resume, if known
+}
+
+// RetryAfter returns how long to wait before re-evaluating a deferred remediation
+func (d *MaintenanceDecision) RetryAfter(now time.Time) time.Duration {
+	if d.Until == nil {
+		return maintenanceRecheckInterval
+	}
+	if wait := d.Until.Sub(now); wait > time.Second {
+		return wait
+	}
+	return time.Second
+}
+
+// Deferral returns the deferral recorded on a workflow held by this decision
+func (d *MaintenanceDecision) Deferral(now time.Time) *models.Deferral {
+	return &models.Deferral{
+		Window:     d.Window,
+		Reason:     d.Reason,
+		DeferredAt: now,
+		Until:      d.Until,
+	}
+}
+
+// FreezeStatus is the state of the runtime global freeze
+type FreezeStatus struct {
+	Active   bool       `json:"active"`
+	Action   string     `json:"action,omitempty"`
+	Reason   string     `json:"reason,omitempty"`
+	FrozenBy string     `json:"frozen_by,omitempty"`
+	FrozenAt *time.Time `json:"frozen_at,omitempty"`
+	Until    *time.Time `json:"until,omitempty"` // the freeze lifts itself at this time
+}
+
+// WindowStatus is a configured window and whether it is open
+type WindowStatus struct {
+	MaintenanceWindow
+	Open     bool       `json:"open"`
+	ClosesAt *time.Time `json:"closes_at,omitempty"`
+	NextOpen *time.Time `json:"next_open,omitempty"`
+}
+
+// MaintenancePolicy decides whether automated remediation may run now, based on
+// the configured windows and the runtime global freeze
+type MaintenancePolicy struct {
+	windows   []MaintenanceWindow
+	clientset kubernetes.Interface // looks up resource labels for label selectors
+
+	freeze   FreezeStatus
+	lift     *time.Timer
+	onResume []func()
+
+	now func() time.Time
+	mu  sync.Mutex
+	log *logrus.Logger
+}
+
+// NewMaintenancePolicy creates a policy from validated windows. clientset may be nil
+// when no window uses a label selector.
+func NewMaintenancePolicy(windows []MaintenanceWindow, clientset kubernetes.Interface, log *logrus.Logger) *MaintenancePolicy {
+	return &MaintenancePolicy{
+		windows:   windows,
+		clientset: clientset,
+		now:       time.Now,
+		log:       log,
+	}
+}
+
+// OnResume registers fn to run when the global freeze lifts, so deferred
+// remediations can be re-evaluated
+func (p *MaintenancePolicy) OnResume(fn func()) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.onResume = append(p.onResume, fn)
+}
+
+// Evaluate returns why automated remediation of targets may not run now, or nil
+// if it may. The global freeze wins over windows; otherwise the first target
+// blocked by a window decides.
+func (p *MaintenancePolicy) Evaluate(ctx context.Context, targets ...MaintenanceTarget) *MaintenanceDecision {
+	if p == nil {
+		return nil
+	}
+
+	if freeze := p.Freeze(); freeze.Active {
+		reason := "global remediation freeze is on"
+		if freeze.Reason != "" {
+			reason += ": " + freeze.Reason
+		}
+		return &MaintenanceDecision{
+			Action: freeze.Action,
+			Window: GlobalFreezeWindow,
+			Reason: reason,
+			Until:  freeze.Until,
+		}
+	}
+
+	now := p.now()
+	for _, target := range targets {
+		if decision := p.evaluateTarget(ctx, target, now); decision != nil {
+			return decision
+		}
+	}
+	return nil
+}
+
+// evaluateTarget checks one target against the configured windows. An open
+// blackout window blocks it, as does being in scope of maintenance windows none
+// of which is open.
+func (p *MaintenancePolicy) evaluateTarget(ctx context.Context, target MaintenanceTarget, now time.Time) *MaintenanceDecision {
+	var resourceLabels labels.Set
+	fetched := false
+	inScope := func(w *MaintenanceWindow) bool {
+		if !w.matchesScope(target) {
+			return false
+		}
+		if w.selector == nil {
+			return true
+		}
+		if !fetched {
+			resourceLabels = p.resourceLabels(ctx, target)
+			fetched = true
+		}
+		return w.selector.Matches(resourceLabels)
+	}
+
+	var closed *MaintenanceWindow
+	var opens time.Time
+	for i := range p.windows {
+		w := &p.windows[i]
+		if w.Type != WindowTypeBlackout || !inScope(w) {
+			continue
+		}
+		if open, closesAt := w.openAt(now); open {
+			return &MaintenanceDecision{
+				Action: w.Action,
+				Window: w.Name,
+				Reason: fmt.Sprintf("blackout window %s is open until %s", w.Name, closesAt.Format(time.RFC3339)),
+				Until:  &closesAt,
+			}
+		}
+	}
+
+	for i := range p.windows {
+		w := &p.windows[i]
+		if w.Type != WindowTypeMaintenance || !inScope(w) {
+			continue
+		}
+		if open, _ := w.openAt(now); open {
+			return nil
+		}
+		next := w.nextOpen(now)
+		if closed == nil {
+			closed = w
+		}
+		if !next.IsZero() && (opens.IsZero() || next.Before(opens)) {
+			opens = next
+		}
+	}
+	if closed == nil {
+		return nil
+	}
+
+	decision := &MaintenanceDecision{
+		Action: closed.Action,
+		Window: closed.Name,
+		Reason: fmt.Sprintf("outside maintenance window %s", closed.Name),
+	}
+	if !opens.IsZero() {
+		decision.Reason += "; next window opens at " + opens.Format(time.RFC3339)
+		decision.Until = &opens
+	}
+	return decision
+}
+
+// resourceLabels returns the labels of a workload or pod, or nil if it cannot be read
+func (p *MaintenancePolicy) resourceLabels(ctx context.Context, target MaintenanceTarget) labels.Set {
+	if p.clientset == nil || target.Name == "" {
+		return nil
+	}
+
+	var meta metav1.Object
+	var err error
+	switch NormalizeKind(target.Kind) {
+	case "Deployment":
+		meta, err = p.clientset.AppsV1().Deployments(target.Namespace).Get(ctx, target.Name, metav1.GetOptions{})
+	case "StatefulSet":
+		meta, err = p.clientset.AppsV1().StatefulSets(target.Namespace).Get(ctx, target.Name, metav1.GetOptions{})
+	case "DaemonSet":
+		meta, err = p.clientset.AppsV1().DaemonSets(target.Namespace).Get(ctx, target.Name, metav1.GetOptions{})
+	case "Pod":
+		meta, err = p.clientset.CoreV1().Pods(target.Namespace).Get(ctx, target.Name, metav1.GetOptions{})
+	default:
+		return nil
+	}
+	if err != nil {
+		p.log.WithError(err).WithFields(logrus.Fields{
+			"kind":      target.Kind,
+			"namespace": target.Namespace,
+			"name":      target.Name,
+		}).Debug("Failed to read labels for maintenance window selector")
+		return nil
+	}
+	return meta.GetLabels()
+}
+
+// Freeze returns the state of the global freeze
+func (p *MaintenancePolicy) Freeze() FreezeStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// A lapsed freeze may not have been lifted by its timer yet
+	if p.freeze.Active && p.freeze.Until != nil && !p.now().Before(*p.freeze.Until) {
+		return FreezeStatus{}
+	}
+	return p.freeze
+}
+
+// SetFreeze blocks all automated remediation with action until Unfreeze is called
+// or, when until is set, until that time
+func (p *MaintenancePolicy) SetFreeze(action, reason, frozenBy string, until *time.Time) (FreezeStatus, error) {
+	switch action {
+	case "":
+		action = MaintenanceActionReject
+	case MaintenanceActionReject, MaintenanceActionQueue, MaintenanceActionRecommend:
+	default:
+		return FreezeStatus{}, fmt.Errorf("invalid action %q (must be reject, queue or recommend)", action)
+	}
+
+	now := p.now()
+	if until != nil && !until.After(now) {
+		return FreezeStatus{}, fmt.Errorf("until must be in the future")
+	}
+
+	p.mu.Lock()
+	if p.lift != nil {
+		p.lift.Stop()
+		p.lift = nil
+	}
+	p.freeze = FreezeStatus{
+		Active:   true,
+		Action:   action,
+		Reason:   reason,
+		FrozenBy: frozenBy,
+		FrozenAt: &now,
+		Until:    until,
+	}
+	if until != nil {
+		p.lift = time.AfterFunc(until.Sub(now), func() { p.Unfreeze() })
+	}
+	status := p.freeze
+	p.mu.Unlock()
+
+	UpdateFreezeState(true)
+	p.log.WithFields(logrus.Fields{
+		"action":    action,
+		"reason":    reason,
+		"frozen_by": frozenBy,
+		"until":     until,
+	}).Warn("Global remediation freeze enabled")
+	return status, nil
+}
+
+// Unfreeze lifts the global freeze and re-evaluates deferred remediations. It
+// returns false if no freeze was on.
+func (p *MaintenancePolicy) Unfreeze() bool {
+	p.mu.Lock()
+	if !p.freeze.Active {
+		p.mu.Unlock()
+		return false
+	}
+	if p.lift != nil {
+		p.lift.Stop()
+		p.lift = nil
+	}
+	p.freeze = FreezeStatus{}
+	callbacks := append([]func(){}, p.onResume...)
+	p.mu.Unlock()
+
+	UpdateFreezeState(false)
+	p.log.Info("Global remediation freeze lifted")
+	for _, fn := range callbacks {
+		fn()
+	}
+	return true
+}
+
+// Windows returns the configured windows and whether each is open now
+func (p *MaintenancePolicy) Windows() []WindowStatus {
+	if p == nil {
+		return []WindowStatus{}
+	}
+
+	now := p.now()
+	statuses := make([]WindowStatus, 0, len(p.windows))
+	for _, w := range p.windows {
+		status := WindowStatus{MaintenanceWindow: w}
+		if open, closesAt := w.openAt(now); open {
+			status.Open = true
+			status.ClosesAt = &closesAt
+		}
+		if next := w.nextOpen(now); !next.IsZero() {
+			status.NextOpen = &next
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
@@ -0,0 +1,246 @@
+package remediation
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+func TestParseCron(t *testing.T) {
+	utc := func(s string) time.Time {
+		parsed, err := time.Parse(time.RFC3339, s)
+		require.NoError(t, err)
+		return parsed
+	}
+
+	tests := []struct {
+		schedule string
+		after    string
+		want     string
+	}{
+		{"0 22 * * *", "2026-03-02T10:00:00Z", "2026-03-02T22:00:00Z"},
+		{"0 22 * * *", "2026-03-02T22:00:00Z", "2026-03-03T22:00:00Z"},
+		{"*/15 9-17 * * mon-fri", "2026-03-06T17:50:00Z", "2026-03-09T09:00:00Z"}, // Friday evening to Monday
+		{"30 2 1 jan,jul *", "2026-03-02T00:00:00Z", "2026-07-01T02:30:00Z"},
+		{"0 0 * * 7", "2026-03-02T00:00:00Z", "2026-03-08T00:00:00Z"},  // 7 is Sunday
+		{"0 0 13 * 5", "2026-03-01T00:00:00Z", "2026-03-06T00:00:00Z"}, // day-of-month or day-of-week
+	}
+	for _, tt := range tests {
+		t.Run(tt.schedule, func(t *testing.T) {
+			schedule, err := parseCron(tt.schedule)
+			require.NoError(t, err)
+			assert.Equal(t, utc(tt.want), schedule.next(utc(tt.after)))
+		})
+	}
+
+	for _, invalid := range []string{"", "* * * *", "60 * * * *", "* * * * fri-mon", "*/0 * * * *", "* * 0 * *"} {
+		_, err := parseCron(invalid)
+		assert.Error(t, err, invalid)
+	}
+
+	// February 30th never happens
+	schedule, err := parseCron("0 0 30 feb *")
+	require.NoError(t, err)
+	assert.True(t, schedule.next(utc("2026-01-01T00:00:00Z")).IsZero())
+}
+
+func newTestMaintenancePolicy(t *testing.T, now time.Time, windows ...MaintenanceWindow) *MaintenancePolicy {
+	for i := range windows {
+		require.NoError(t, windows[i].Validate())
+	}
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+		Name: "payment", Namespace: "prod-payments", Labels: map[string]string{"tier": "critical"},
+	}}
+	policy := NewMaintenancePolicy(windows, fake.NewSimpleClientset(deployment), log)
+	policy.now = func() time.Time { return now }
+	return policy
+}
+
+func TestMaintenancePolicy_Evaluate(t *testing.T) {
+	// Tuesday 23:30 in New York
+	ny, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	now := time.Date(2026, 3, 3, 23, 30, 0, 0, ny)
+
+	prod := MaintenanceTarget{Namespace: "prod-payments", Kind: "Deployment", Name: "payment", Layer: models.LayerApplication}
+	dev := MaintenanceTarget{Namespace: "dev", Kind: "Deployment", Name: "payment", Layer: models.LayerApplication}
+
+	t.Run("open blackout window blocks until it closes", func(t *testing.T) {
+		policy := newTestMaintenancePolicy(t, now, MaintenanceWindow{
+			Name: "nightly-batch", Schedule: "0 23 * * *", Duration: "2h", Timezone: "America/New_York",
+			Namespaces: []string{"prod-*"},
+		})
+		decision := policy.Evaluate(context.Background(), prod)
+		require.NotNil(t, decision)
+		assert.Equal(t, MaintenanceActionQueue, decision.Action)
+		assert.Equal(t, "nightly-batch", decision.Window)
+		require.NotNil(t, decision.Until)
+		assert.True(t, decision.Until.Equal(time.Date(2026, 3, 4, 1, 0, 0, 0, ny)))
+
+		assert.Nil(t, policy.Evaluate(context.Background(), dev))
+	})
+
+	t.Run("closed blackout window does not block", func(t *testing.T) {
+		policy := newTestMaintenancePolicy(t, now, MaintenanceWindow{
+			Name: "morning", Schedule: "0 8 * * *", Duration: "1h", Timezone: "America/New_York",
+		})
+		assert.Nil(t, policy.Evaluate(context.Background(), prod))
+	})
+
+	t.Run("outside business hours maintenance window", func(t *testing.T) {
+		policy := newTestMaintenancePolicy(t, now, MaintenanceWindow{
+			Name: "business-hours", Type: WindowTypeMaintenance, Schedule: "0 9 * * mon-fri", Duration: "8h",
+			Timezone: "America/New_York", Action: MaintenanceActionRecommend, LabelSelector: "tier=critical",
+		})
+		decision := policy.Evaluate(context.Background(), prod)
+		require.NotNil(t, decision)
+		assert.Equal(t, MaintenanceActionRecommend, decision.Action)
+		require.NotNil(t, decision.Until)
+		assert.True(t, decision.Until.Equal(time.Date(2026, 3, 4, 9, 0, 0, 0, ny)))
+
+		// The selector does not match a resource without the label
+		assert.Nil(t, policy.Evaluate(context.Background(), dev))
+
+		// Within business hours the window is open
+		policy.now = func() time.Time { return time.Date(2026, 3, 4, 10, 0, 0, 0, ny) }
+		assert.Nil(t, policy.Evaluate(context.Background(), prod))
+	})
+
+	t.Run("layer scope", func(t *testing.T) {
+		policy := newTestMaintenancePolicy(t, now, MaintenanceWindow{
+			Name: "cluster-upgrade", Schedule: "0 * * * *", Duration: "1h", Action: MaintenanceActionReject,
+			Layers: []string{"infrastructure"},
+		})
+		assert.Nil(t, policy.Evaluate(context.Background(), prod))
+		node := MaintenanceTarget{Kind: "Node", Name: "worker-1", Layer: models.LayerInfrastructure}
+		decision := policy.Evaluate(context.Background(), prod, node)
+		require.NotNil(t, decision)
+		assert.Equal(t, MaintenanceActionReject, decision.Action)
+	})
+}
+
+func TestMaintenancePolicy_Freeze(t *testing.T) {
+	policy := newTestMaintenancePolicy(t, time.Now())
+	resumed := make(chan struct{}, 1)
+	policy.OnResume(func() { resumed <- struct{}{} })
+
+	_, err := policy.SetFreeze("pause", "", "oncall", nil)
+	assert.Error(t, err)
+
+	status, err := policy.SetFreeze("", "cluster upgrade", "oncall", nil)
+	require.NoError(t, err)
+	assert.True(t, status.Active)
+	assert.Equal(t, MaintenanceActionReject, status.Action)
+
+	decision := policy.Evaluate(context.Background(), MaintenanceTarget{Namespace: "dev"})
+	require.NotNil(t, decision)
+	assert.Equal(t, GlobalFreezeWindow, decision.Window)
+	assert.Contains(t, decision.Reason, "cluster upgrade")
+
+	assert.True(t, policy.Unfreeze())
+	assert.False(t, policy.Unfreeze())
+	assert.Len(t, resumed, 1)
+	assert.Nil(t, policy.Evaluate(context.Background(), MaintenanceTarget{Namespace: "dev"}))
+}
+
+func TestLoadMaintenanceWindows(t *testing.T) {
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "windows.yaml")
+	require.NoError(t, os.WriteFile(valid, []byte(`
+windows:
+  - name: change-freeze
+    schedule: "0 0 20 12 *"
+    duration: 336h
+    namespaces: ["prod-*"]
+    action: reject
+`), 0o600))
+	windows, err := LoadMaintenanceWindows(valid)
+	require.NoError(t, err)
+	require.Len(t, windows, 1)
+	assert.Equal(t, WindowTypeBlackout, windows[0].Type)
+	assert.Equal(t, MaintenanceActionReject, windows[0].Action)
+
+	invalid := filepath.Join(dir, "invalid.yaml")
+	require.NoError(t, os.WriteFile(invalid, []byte(`
+windows:
+  - name: bad
+    schedule: "0 0 * *"
+    duration: 1h
+`), 0o600))
+	_, err = LoadMaintenanceWindows(invalid)
+	assert.Error(t, err)
+}
+
+func TestOrchestrator_MaintenanceFreeze(t *testing.T) {
+	newOrchestrator := func(action string) (*Orchestrator, *blockingRemediator, *MaintenancePolicy) {
+		remediator := newBlockingRemediator()
+		close(remediator.release)
+		o := newTestOrchestrator(remediator)
+		policy := newTestMaintenancePolicy(t, time.Now())
+		o.SetMaintenancePolicy(policy)
+		_, err := policy.SetFreeze(action, "cluster upgrade", "oncall", nil)
+		require.NoError(t, err)
+		return o, remediator, policy
+	}
+
+	t.Run("reject", func(t *testing.T) {
+		o, remediator, _ := newOrchestrator(MaintenanceActionReject)
+		_, created, err := o.TriggerRemediation(context.Background(), "inc-1", newTestIssue("inc-1"))
+		assert.True(t, errors.Is(err, ErrMaintenanceBlocked))
+		assert.False(t, created)
+		assert.Empty(t, remediator.started)
+	})
+
+	t.Run("recommend", func(t *testing.T) {
+		o, remediator, _ := newOrchestrator(MaintenanceActionRecommend)
+		wf, created, err := o.TriggerRemediation(context.Background(), "inc-1", newTestIssue("inc-1"))
+		require.NoError(t, err)
+		assert.True(t, created)
+		assert.Equal(t, models.WorkflowStatusRecommended, wf.Status)
+		assert.False(t, wf.IsActive())
+		assert.Contains(t, wf.ErrorMessage, "cluster upgrade")
+		assert.NotEmpty(t, wf.Recommendations)
+		assert.Empty(t, remediator.started)
+	})
+
+	t.Run("queue until the freeze lifts", func(t *testing.T) {
+		o, remediator, policy := newOrchestrator(MaintenanceActionQueue)
+		wf, created, err := o.TriggerRemediation(context.Background(), "inc-1", newTestIssue("inc-1"))
+		require.NoError(t, err)
+		assert.True(t, created)
+		assert.Equal(t, models.WorkflowStatusDeferred, wf.Status)
+		assert.True(t, wf.IsActive())
+		require.NotNil(t, wf.Deferral)
+		assert.Equal(t, GlobalFreezeWindow, wf.Deferral.Window)
+		assert.Empty(t, remediator.started)
+
+		policy.Unfreeze()
+		waitForStatus(t, o, wf.ID, models.WorkflowStatusCompleted)
+	})
+
+	t.Run("cancel deferred workflow", func(t *testing.T) {
+		o, remediator, _ := newOrchestrator(MaintenanceActionQueue)
+		wf, _, err := o.TriggerRemediation(context.Background(), "inc-1", newTestIssue("inc-1"))
+		require.NoError(t, err)
+
+		require.NoError(t, o.CancelWorkflow(wf.ID))
+		cancelled, err := o.GetWorkflow(wf.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.WorkflowStatusCancelled, cancelled.Status)
+		assert.Empty(t, remediator.started)
+	})
+}
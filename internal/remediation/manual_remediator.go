@@ -3,10 +3,12 @@ package remediation
 import (
 	"context"
 	"fmt"
-	"time"
+	"strings"
 
 	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 
 	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
@@ -15,6 +17,10 @@ import (
 // ManualRemediator handles manually-deployed application remediation
 type ManualRemediator struct {
 	clientset kubernetes.Interface
+	recorder  EventRecorder
+	policy    *PolicyEngine // nil uses the built-in policy
+	memory    *MemoryResizer
+	dynamic   dynamic.Interface // restarts DeploymentConfigs and Argo Rollouts
 	log       *logrus.Logger
 }
 
@@ -22,12 +28,33 @@ type ManualRemediator struct {
 func NewManualRemediator(clientset kubernetes.Interface, log *logrus.Logger) *ManualRemediator {
 	return &ManualRemediator{
 		clientset: clientset,
+		memory:    NewMemoryResizer(clientset, 0, resource.Quantity{}, log),
 		log:       log,
 	}
 }
 
+// SetEventRecorder records the actions taken as Kubernetes Events
+func (mr *ManualRemediator) SetEventRecorder(recorder EventRecorder) {
+	mr.recorder = recorder
+}
+
+// SetPolicy sets the remediation policy that maps issues to actions
+func (mr *ManualRemediator) SetPolicy(policy *PolicyEngine) {
+	mr.policy = policy
+}
+
+// SetDynamicClient enables restarts of DeploymentConfigs and Argo Rollouts
+func (mr *ManualRemediator) SetDynamicClient(client dynamic.Interface) {
+	mr.dynamic = client
+}
+
+// SetMemoryResizer sets how OOM-killed workloads have their memory raised
+func (mr *ManualRemediator) SetMemoryResizer(memory *MemoryResizer) {
+	mr.memory = memory
+}
+
 // Remediate performs direct Kubernetes API remediation
-func (mr *ManualRemediator) Remediate(ctx context.Context, deploymentInfo *models.DeploymentInfo, issue *models.Issue) error {
+func (mr *ManualRemediator) Remediate(ctx context.Context, deploymentInfo *models.DeploymentInfo, issue *models.Issue) (*models.RemediationResult, error) {
 	mr.log.WithFields(logrus.Fields{
 		"namespace":     issue.Namespace,
 		"resource":      issue.ResourceName,
@@ -35,19 +62,78 @@ func (mr *ManualRemediator) Remediate(ctx context.Context, deploymentInfo *model
 		"issue_type":    issue.Type,
 	}).Info("Starting manual remediation")
 
-	// Route to appropriate remediation based on issue type
-	switch issue.Type {
-	case "CrashLoopBackOff", "crashloopbackoff":
-		return mr.remediateCrashLoop(ctx, issue)
-	case "ImagePullBackOff", "imagepullbackoff":
-		return mr.remediateImagePull(ctx, issue)
-	case "OOMKilled", "oomkilled":
-		return mr.remediateOOM(ctx, issue)
-	case "pod_crash_loop":
-		return mr.remediateCrashLoop(ctx, issue)
-	default:
-		return mr.remediateGeneric(ctx, issue)
+	result := models.NewRemediationResult(mr.Name())
+	if err := ctx.Err(); err != nil {
+		return result, fmt.Errorf("manual remediation cancelled: %w", err)
+	}
+
+	decision, err := mr.policy.Resolve(ctx, deploymentInfo, issue, manualPolicyActions)
+	if err != nil {
+		return result, err
 	}
+	mr.log.WithFields(logrus.Fields{
+		"issue_id": issue.ID,
+		"rule":     decision.Rule,
+	}).Info("Remediation policy rule matched")
+	result.AddEvidence("policy_rule", decision.Rule)
+
+	for i := range decision.Actions {
+		action := &decision.Actions[i]
+		if err := mr.policy.Execute(ctx, issue, *action, func(ctx context.Context) error {
+			return mr.execute(ctx, action, issue, result)
+		}); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// PlanActions describes the Kubernetes API calls Remediate would make for the issue
+func (mr *ManualRemediator) PlanActions(ctx context.Context, deploymentInfo *models.DeploymentInfo, issue *models.Issue) ([]PlannedAction, error) {
+	decision, err := mr.policy.Resolve(ctx, deploymentInfo, issue, manualPolicyActions)
+	if err != nil {
+		return nil, err
+	}
+
+	podTarget := fmt.Sprintf("Pod %s/%s", issue.Namespace, issue.ResourceName)
+
+	planned := make([]PlannedAction, 0, len(decision.Actions))
+	for i := range decision.Actions {
+		action := &decision.Actions[i]
+		plan := PlannedAction{Action: action.Action}
+		switch action.Action {
+		case "restart_deployment", "restart_statefulset", "restart_daemonset", "restart_deploymentconfig", "restart_rollout":
+			kind := restartKinds[action.Action]
+			plan.Target = fmt.Sprintf("%s %s/%s", kind, issue.Namespace, issue.ResourceName)
+			plan.Description = fmt.Sprintf("restart %s %s by updating its restarted-at template annotation", strings.ToLower(kind), issue.ResourceName)
+		case "rollback_deployment", "rollback_statefulset", "rollback_daemonset":
+			kind := strings.TrimPrefix(action.Action, "rollback_")
+			plan.Target = fmt.Sprintf("%s %s/%s", NormalizeKind(kind), issue.Namespace, issue.ResourceName)
+			revision := "its last available revision"
+			if toRevision, err := rollbackRevision(action, issue); err == nil && toRevision > 0 {
+				revision = fmt.Sprintf("revision %d", toRevision)
+			}
+			plan.Description = fmt.Sprintf("roll back %s %s to %s", kind, issue.ResourceName, revision)
+		case "delete_pod":
+			plan.Target = podTarget
+			plan.Description = fmt.Sprintf("delete pod %s so its controller recreates it", issue.ResourceName)
+		case "increase_memory":
+			target, changes, err := mr.memory.Plan(ctx, issue)
+			if err != nil {
+				plan.Target = issueResource(issue)
+				plan.Description = fmt.Sprintf("raise the memory of %s's controller: %v", issue.ResourceName, err)
+				break
+			}
+			plan.Target = fmt.Sprintf("%s %s/%s", target.Kind, target.Namespace, target.Name)
+			plan.Description = fmt.Sprintf("raise memory of %s %s (%s)", strings.ToLower(target.Kind), target.Name, describeMemoryChanges(changes))
+		case "manual_intervention":
+			plan.Target = issueResource(issue)
+			plan.Description = fmt.Sprintf("no automatic action for %s %s: %s", strings.ToLower(NormalizeKind(issue.ResourceType)),
+				issue.ResourceName, action.Param("message", "remediate manually"))
+		}
+		planned = append(planned, plan)
+	}
+	return planned, nil
 }
 
 // CanRemediate returns true for manual deployments or unknown methods
@@ -62,162 +148,95 @@ func (mr *ManualRemediator) Name() string {
 	return "manual"
 }
 
-// remediateCrashLoop handles CrashLoopBackOff by deleting pod
-func (mr *ManualRemediator) remediateCrashLoop(ctx context.Context, issue *models.Issue) error {
-	mr.log.WithFields(logrus.Fields{
-		"namespace": issue.Namespace,
-		"pod":       issue.ResourceName,
-	}).Info("Remediating CrashLoopBackOff: deleting pod")
-
-	// If resource type is deployment, try to rollback
-	if issue.ResourceType == "deployment" || issue.ResourceType == "Deployment" {
-		return mr.rollbackDeployment(ctx, issue)
-	}
-
-	// For pods, delete to trigger recreation
-	err := mr.clientset.CoreV1().Pods(issue.Namespace).Delete(ctx, issue.ResourceName, metav1.DeleteOptions{})
-	if err != nil {
-		return fmt.Errorf("failed to delete pod: %w", err)
-	}
-
-	mr.log.Info("Pod deleted, deployment will recreate it")
-	return nil
+// manualPolicyActions are the policy actions the manual remediator executes
+var manualPolicyActions = policyActionSet{
+	"restart_deployment":       true,
+	"restart_statefulset":      true,
+	"restart_daemonset":        true,
+	"restart_deploymentconfig": true,
+	"restart_rollout":          true,
+	"rollback_deployment":      true,
+	"rollback_statefulset":     true,
+	"rollback_daemonset":       true,
+	"delete_pod":               true,
+	"increase_memory":          true,
+	"manual_intervention":      true,
 }
 
-// remediateImagePull handles ImagePullBackOff
-func (mr *ManualRemediator) remediateImagePull(ctx context.Context, issue *models.Issue) error {
-	mr.log.WithFields(logrus.Fields{
-		"namespace": issue.Namespace,
-		"pod":       issue.ResourceName,
-	}).Warn("ImagePullBackOff detected: checking image and credentials")
-
-	// Get pod to check image
-	pod, err := mr.clientset.CoreV1().Pods(issue.Namespace).Get(ctx, issue.ResourceName, metav1.GetOptions{})
-	if err != nil {
-		return fmt.Errorf("failed to get pod: %w", err)
-	}
-
-	// Log image information
-	for i := range pod.Spec.Containers {
+// execute carries out one policy action and records it in the result
+func (mr *ManualRemediator) execute(ctx context.Context, action *PolicyAction, issue *models.Issue, result *models.RemediationResult) error {
+	switch action.Action {
+	case "restart_deployment", "restart_statefulset", "restart_daemonset", "restart_deploymentconfig", "restart_rollout":
+		return mr.restart(ctx, action.Action, issue, result)
+	case "rollback_deployment", "rollback_statefulset", "rollback_daemonset":
+		return mr.rollback(ctx, action, issue, result)
+	case "delete_pod":
+		phase, err := mr.deletePod(ctx, issue, fmt.Sprintf("Deleted pod to remediate %s so its controller recreates it", issue.Type))
+		if err != nil {
+			return err
+		}
+		result.AddAction("delete_pod", fmt.Sprintf("Pod %s/%s", issue.Namespace, issue.ResourceName), phase, "deleted")
 		mr.log.WithFields(logrus.Fields{
-			"container": pod.Spec.Containers[i].Name,
-			"image":     pod.Spec.Containers[i].Image,
-		}).Info("Container image details")
-	}
-
-	mr.log.Warn("ImagePullBackOff requires manual intervention: check image availability and credentials")
-	return fmt.Errorf("ImagePullBackOff requires manual intervention: verify image exists and pull secrets are configured")
-}
-
-// remediateOOM handles OOMKilled pods
-func (mr *ManualRemediator) remediateOOM(ctx context.Context, issue *models.Issue) error {
-	mr.log.WithFields(logrus.Fields{
-		"namespace": issue.Namespace,
-		"pod":       issue.ResourceName,
-	}).Warn("OOMKilled detected: considering memory limit increase")
-
-	// Get pod to check current limits
-	pod, err := mr.clientset.CoreV1().Pods(issue.Namespace).Get(ctx, issue.ResourceName, metav1.GetOptions{})
-	if err != nil {
-		return fmt.Errorf("failed to get pod: %w", err)
-	}
-
-	// Log current resource limits
-	for i := range pod.Spec.Containers {
+			"namespace": issue.Namespace,
+			"pod":       issue.ResourceName,
+		}).Info("Pod deleted, its controller will recreate it")
+		return nil
+	case "increase_memory":
+		target, changes, err := mr.memory.Apply(ctx, issue)
+		if err != nil {
+			return err
+		}
+		for _, change := range changes {
+			result.AddAction("increase_memory", fmt.Sprintf("%s %s/%s", target.Kind, target.Namespace, target.Name),
+				change.Before(), change.After())
+		}
+		recordEvent(ctx, mr.recorder, RemediationEvent{
+			Target:     target,
+			Reason:     EventReasonMemoryIncreased,
+			Action:     "increase_memory",
+			Remediator: mr.Name(),
+			Message:    fmt.Sprintf("Raised memory to remediate %s: %s", issue.Type, describeMemoryChanges(changes)),
+		})
+		return nil
+	case "manual_intervention":
+		message := action.Param("message", "remediate manually")
 		mr.log.WithFields(logrus.Fields{
-			"container":      pod.Spec.Containers[i].Name,
-			"memory_limit":   pod.Spec.Containers[i].Resources.Limits.Memory().String(),
-			"memory_request": pod.Spec.Containers[i].Resources.Requests.Memory().String(),
-		}).Info("Current container resource limits")
-	}
-
-	// Delete pod to restart (may OOM again)
-	err = mr.clientset.CoreV1().Pods(issue.Namespace).Delete(ctx, issue.ResourceName, metav1.DeleteOptions{})
-	if err != nil {
-		return fmt.Errorf("failed to delete pod: %w", err)
-	}
-
-	mr.log.Warn("Pod deleted, but OOM may recur without memory limit increase")
-	return nil
-}
-
-// remediateGeneric handles generic issues by restarting pod
-func (mr *ManualRemediator) remediateGeneric(ctx context.Context, issue *models.Issue) error {
-	mr.log.WithFields(logrus.Fields{
-		"namespace":  issue.Namespace,
-		"resource":   issue.ResourceName,
-		"issue_type": issue.Type,
-	}).Info("Generic remediation: restarting resource")
-
-	// Try deployment restart first if it's a deployment
-	if issue.ResourceType == "deployment" || issue.ResourceType == "Deployment" {
-		return mr.restartDeployment(ctx, issue)
-	}
-
-	// Otherwise delete the pod
-	err := mr.clientset.CoreV1().Pods(issue.Namespace).Delete(ctx, issue.ResourceName, metav1.DeleteOptions{})
-	if err != nil {
-		return fmt.Errorf("failed to delete pod: %w", err)
-	}
-
-	mr.log.Info("Pod deleted for restart")
-	return nil
-}
-
-// rollbackDeployment rolls back a deployment to previous revision
-func (mr *ManualRemediator) rollbackDeployment(ctx context.Context, issue *models.Issue) error {
-	mr.log.WithFields(logrus.Fields{
-		"namespace":  issue.Namespace,
-		"deployment": issue.ResourceName,
-	}).Info("Rolling back deployment to previous revision")
-
-	// Get deployment
-	deployment, err := mr.clientset.AppsV1().Deployments(issue.Namespace).Get(ctx, issue.ResourceName, metav1.GetOptions{})
-	if err != nil {
-		return fmt.Errorf("failed to get deployment: %w", err)
-	}
-
-	// Check if there are revisions to rollback to
-	if deployment.Status.ObservedGeneration <= 1 {
-		mr.log.Warn("No previous revision to rollback to, restarting pods instead")
-		return mr.restartDeployment(ctx, issue)
+			"namespace":  issue.Namespace,
+			"resource":   issue.ResourceName,
+			"issue_type": issue.Type,
+		}).Warn("Issue requires manual intervention: " + message)
+		result.Recommend(fmt.Sprintf("%s on %s requires manual intervention: %s", issue.Type, issueResource(issue), message))
+		return nil
+	default:
+		return Permanent(fmt.Errorf("manual remediator cannot execute action %s", action.Action))
 	}
-
-	// Trigger rollback by setting revision annotation
-	// Note: In Kubernetes, rollback is achieved by finding previous ReplicaSet
-	// and scaling it up while scaling down current one
-	// For simplicity, we'll restart the deployment
-	mr.log.Info("Triggering deployment restart")
-	return mr.restartDeployment(ctx, issue)
 }
 
-// restartDeployment restarts a deployment by updating its template
-func (mr *ManualRemediator) restartDeployment(ctx context.Context, issue *models.Issue) error {
-	mr.log.WithFields(logrus.Fields{
-		"namespace":  issue.Namespace,
-		"deployment": issue.ResourceName,
-	}).Info("Restarting deployment")
-
-	// Get deployment
-	deployment, err := mr.clientset.AppsV1().Deployments(issue.Namespace).Get(ctx, issue.ResourceName, metav1.GetOptions{})
-	if err != nil {
-		return fmt.Errorf("failed to get deployment: %w", err)
+// deletePod deletes a pod so its controller recreates it and records the deletion.
+// It returns the phase the pod was in, if it could be read.
+func (mr *ManualRemediator) deletePod(ctx context.Context, issue *models.Issue, message string) (string, error) {
+	// Look the pod up first: the event needs its UID, which is gone after deletion
+	var phase string
+	target := workloadRef("Pod", issue.Namespace, issue.ResourceName)
+	if pod, err := mr.clientset.CoreV1().Pods(issue.Namespace).Get(ctx, issue.ResourceName, metav1.GetOptions{}); err == nil {
+		target.UID = pod.UID
+		phase = string(pod.Status.Phase)
 	}
 
-	// Add/update restart annotation to trigger rollout
-	if deployment.Spec.Template.Annotations == nil {
-		deployment.Spec.Template.Annotations = make(map[string]string)
+	if err := mr.clientset.CoreV1().Pods(issue.Namespace).Delete(ctx, issue.ResourceName, metav1.DeleteOptions{}); err != nil {
+		return "", fmt.Errorf("failed to delete pod: %w", err)
 	}
-	deployment.Spec.Template.Annotations["remediation.aiops/restarted-at"] = time.Now().Format(time.RFC3339)
 
-	// Update deployment
-	_, err = mr.clientset.AppsV1().Deployments(issue.Namespace).Update(ctx, deployment, metav1.UpdateOptions{})
-	if err != nil {
-		return fmt.Errorf("failed to update deployment: %w", err)
+	if target.UID != "" {
+		recordEvent(ctx, mr.recorder, RemediationEvent{
+			Target:     target,
+			Reason:     EventReasonPodDeleted,
+			Action:     "delete_pod",
+			Remediator: mr.Name(),
+			Message:    message,
+		})
 	}
-
-	mr.log.Info("Deployment restart triggered")
-	return nil
+	return phase, nil
 }
 
 // Helper methods for additional remediation scenarios
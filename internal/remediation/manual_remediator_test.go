@@ -109,8 +109,11 @@ func TestManualRemediator_RemediateCrashLoop(t *testing.T) {
 	}
 
 	// Execute remediation
-	err = remediator.Remediate(context.Background(), deploymentInfo, issue)
+	result, err := remediator.Remediate(context.Background(), deploymentInfo, issue)
 	assert.NoError(t, err)
+	assert.Equal(t, models.RemediationStatusChanged, result.Status)
+	assert.Equal(t, []models.PerformedAction{{Action: "delete_pod", Target: "Pod default/test-pod", After: "deleted"}}, result.Actions)
+	assert.Equal(t, "crash-loop-pod", result.Evidence["policy_rule"])
 
 	// Verify pod was deleted
 	_, err = clientset.CoreV1().Pods("default").Get(context.Background(), "test-pod", metav1.GetOptions{})
@@ -154,10 +157,18 @@ func TestManualRemediator_RemediateImagePull(t *testing.T) {
 		DetectedAt:   time.Now(),
 	}
 
-	// Execute remediation - should return error for ImagePullBackOff
-	err = remediator.Remediate(context.Background(), deploymentInfo, issue)
-	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "manual intervention")
+	// Execute remediation - ImagePullBackOff only produces a recommendation
+	result, err := remediator.Remediate(context.Background(), deploymentInfo, issue)
+	assert.NoError(t, err)
+	assert.Equal(t, models.RemediationStatusRecommended, result.Status)
+	assert.Empty(t, result.Actions)
+	if assert.Len(t, result.Recommendations, 1) {
+		assert.Contains(t, result.Recommendations[0], "manual intervention")
+	}
+
+	// The pod is left alone
+	_, err = clientset.CoreV1().Pods("default").Get(context.Background(), "image-pull-pod", metav1.GetOptions{})
+	assert.NoError(t, err)
 }
 
 func TestManualRemediator_RemediateGeneric(t *testing.T) {
@@ -198,10 +209,55 @@ func TestManualRemediator_RemediateGeneric(t *testing.T) {
 	}
 
 	// Execute remediation
-	err = remediator.Remediate(context.Background(), deploymentInfo, issue)
+	_, err = remediator.Remediate(context.Background(), deploymentInfo, issue)
 	assert.NoError(t, err)
 
 	// Verify pod was deleted
 	_, err = clientset.CoreV1().Pods("default").Get(context.Background(), "generic-pod", metav1.GetOptions{})
 	assert.Error(t, err)
 }
+
+func TestManualRemediator_PlanActions(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	log := logrus.New()
+	remediator := NewManualRemediator(clientset, log)
+
+	tests := []struct {
+		name         string
+		issueType    string
+		resourceType string
+		action       string
+	}{
+		{name: "Crash loop on pod", issueType: "CrashLoopBackOff", resourceType: "pod", action: "delete_pod"},
+		{name: "Crash loop on deployment", issueType: "CrashLoopBackOff", resourceType: "Deployment", action: "restart_deployment"},
+		{name: "Crash loop on statefulset", issueType: "CrashLoopBackOff", resourceType: "statefulset", action: "restart_statefulset"},
+		{name: "Generic daemonset", issueType: "HighLatency", resourceType: "DaemonSet", action: "restart_daemonset"},
+		{name: "Image pull", issueType: "ImagePullBackOff", resourceType: "pod", action: "manual_intervention"},
+		{name: "OOM killed", issueType: "OOMKilled", resourceType: "pod", action: "increase_memory"},
+		{name: "Generic deployment", issueType: "HighLatency", resourceType: "deployment", action: "restart_deployment"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issue := &models.Issue{
+				ID:           "issue-1",
+				Type:         tt.issueType,
+				Namespace:    "default",
+				ResourceType: tt.resourceType,
+				ResourceName: "test-app",
+			}
+
+			actions, err := remediator.PlanActions(context.Background(), nil, issue)
+			assert.NoError(t, err)
+			if assert.Len(t, actions, 1) {
+				assert.Equal(t, tt.action, actions[0].Action)
+				assert.Contains(t, actions[0].Description, "test-app")
+			}
+		})
+	}
+
+	// Planning must not change the cluster
+	for _, action := range clientset.Actions() {
+		assert.Contains(t, []string{"get", "list"}, action.GetVerb())
+	}
+}
@@ -0,0 +1,543 @@
+package remediation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// AnnotationPreviousMemory records, on a resized workload, the container memory
+// before the last OOM remediation so the change can be reverted
+const AnnotationPreviousMemory = "remediation.aiops/previous-memory"
+
+// Default OOM memory sizing
+const (
+	DefaultMemoryStepPercent = 50
+	DefaultMemoryCeiling     = "4Gi"
+)
+
+const mebibyte = 1 << 20
+
+// MemoryChange is the memory of one container before and after a resize
+type MemoryChange struct {
+	Container  string `json:"container"`
+	OldLimit   string `json:"old_limit,omitempty"`
+	NewLimit   string `json:"new_limit"`
+	OldRequest string `json:"old_request,omitempty"`
+	NewRequest string `json:"new_request,omitempty"`
+}
+
+// String describes the change, e.g. app: limit 512Mi -> 768Mi, request 256Mi -> 384Mi
+func (c MemoryChange) String() string {
+	s := fmt.Sprintf("%s: limit %s -> %s", c.Container, valueOrNone(c.OldLimit), c.NewLimit)
+	if c.NewRequest != "" {
+		s += fmt.Sprintf(", request %s -> %s", valueOrNone(c.OldRequest), c.NewRequest)
+	}
+	return s
+}
+
+// Before returns the container's memory before the change
+func (c MemoryChange) Before() string {
+	return memoryValues(c.Container, c.OldLimit, c.OldRequest, c.NewRequest != "")
+}
+
+// After returns the container's memory after the change
+func (c MemoryChange) After() string {
+	return memoryValues(c.Container, c.NewLimit, c.NewRequest, c.NewRequest != "")
+}
+
+// memoryValues formats a container's memory limit and, if changed, its request
+func memoryValues(container, limit, request string, withRequest bool) string {
+	s := fmt.Sprintf("%s: limit %s", container, valueOrNone(limit))
+	if withRequest {
+		s += fmt.Sprintf(", request %s", valueOrNone(request))
+	}
+	return s
+}
+
+func valueOrNone(value string) string {
+	if value == "" {
+		return "none"
+	}
+	return value
+}
+
+// describeMemoryChanges joins the changes for messages
+func describeMemoryChanges(changes []MemoryChange) string {
+	parts := make([]string, len(changes))
+	for i, change := range changes {
+		parts[i] = change.String()
+	}
+	return strings.Join(parts, "; ")
+}
+
+// MemoryResizer raises the memory of OOM-killed workloads by a bounded step. New
+// limits stay within the ceiling, the namespace's LimitRange and its ResourceQuota.
+type MemoryResizer struct {
+	StepPercent int               // how much a limit is raised per remediation
+	Ceiling     resource.Quantity // no limit is raised above this
+
+	clientset kubernetes.Interface
+	log       *logrus.Logger
+}
+
+// NewMemoryResizer creates a memory resizer. A zero step or ceiling uses the default.
+func NewMemoryResizer(clientset kubernetes.Interface, stepPercent int, ceiling resource.Quantity, log *logrus.Logger) *MemoryResizer {
+	if stepPercent <= 0 {
+		stepPercent = DefaultMemoryStepPercent
+	}
+	if ceiling.IsZero() {
+		ceiling = resource.MustParse(DefaultMemoryCeiling)
+	}
+	return &MemoryResizer{
+		StepPercent: stepPercent,
+		Ceiling:     ceiling,
+		clientset:   clientset,
+		log:         log,
+	}
+}
+
+// memoryWorkload is the controller whose pod template is resized
+type memoryWorkload struct {
+	kind     string
+	object   metav1.Object
+	template *corev1.PodTemplateSpec
+	selector *metav1.LabelSelector
+	replicas int64
+	pod      *corev1.Pod // the OOM-killed pod, when the issue named one
+	update   func(ctx context.Context) (types.UID, error)
+}
+
+// memoryLimits are the namespace constraints on container memory, in bytes
+type memoryLimits struct {
+	maxLimit        int64   // ceiling and LimitRange max
+	defaultLimit    int64   // LimitRange default for containers without a limit
+	maxRatio        float64 // LimitRange max limit/request ratio, 0 when unset
+	limitHeadroom   int64   // ResourceQuota limits.memory left, -1 when unlimited
+	requestHeadroom int64   // ResourceQuota requests.memory left, -1 when unlimited
+}
+
+// Apply raises the memory of the issue's workload and returns the resized workload
+// and the changes. The previous values are recorded in the AnnotationPreviousMemory
+// annotation.
+func (r *MemoryResizer) Apply(ctx context.Context, issue *models.Issue) (corev1.ObjectReference, []MemoryChange, error) {
+	workload, changes, err := r.resize(ctx, issue)
+	if err != nil {
+		return corev1.ObjectReference{}, nil, err
+	}
+
+	for _, change := range changes {
+		for i := range workload.template.Spec.Containers {
+			container := &workload.template.Spec.Containers[i]
+			if container.Name != change.Container {
+				continue
+			}
+			if container.Resources.Limits == nil {
+				container.Resources.Limits = corev1.ResourceList{}
+			}
+			container.Resources.Limits[corev1.ResourceMemory] = resource.MustParse(change.NewLimit)
+			if change.NewRequest != "" {
+				if container.Resources.Requests == nil {
+					container.Resources.Requests = corev1.ResourceList{}
+				}
+				container.Resources.Requests[corev1.ResourceMemory] = resource.MustParse(change.NewRequest)
+			}
+		}
+	}
+
+	previous, err := json.Marshal(changes)
+	if err != nil {
+		return corev1.ObjectReference{}, nil, fmt.Errorf("failed to record previous memory: %w", err)
+	}
+	annotations := workload.object.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[AnnotationPreviousMemory] = string(previous)
+	workload.object.SetAnnotations(annotations)
+
+	uid, err := workload.update(ctx)
+	if err != nil {
+		return corev1.ObjectReference{}, nil, fmt.Errorf("failed to update %s: %w", strings.ToLower(workload.kind), err)
+	}
+	target := workloadRef(workload.kind, issue.Namespace, workload.object.GetName())
+	target.UID = uid
+
+	r.log.WithFields(logrus.Fields{
+		"namespace": issue.Namespace,
+		"kind":      workload.kind,
+		"name":      workload.object.GetName(),
+		"changes":   describeMemoryChanges(changes),
+	}).Info("Raised workload memory to remediate OOM kills")
+	return target, changes, nil
+}
+
+// Plan returns the workload and changes Apply would make without changing anything
+func (r *MemoryResizer) Plan(ctx context.Context, issue *models.Issue) (corev1.ObjectReference, []MemoryChange, error) {
+	workload, changes, err := r.resize(ctx, issue)
+	if err != nil {
+		return corev1.ObjectReference{}, nil, err
+	}
+	return workloadRef(workload.kind, issue.Namespace, workload.object.GetName()), changes, nil
+}
+
+// resize loads the issue's workload and computes its new memory
+func (r *MemoryResizer) resize(ctx context.Context, issue *models.Issue) (*memoryWorkload, []MemoryChange, error) {
+	workload, err := r.workload(ctx, issue)
+	if err != nil {
+		return nil, nil, err
+	}
+	changes, err := r.plan(ctx, issue.Namespace, workload)
+	if err != nil {
+		return nil, nil, err
+	}
+	return workload, changes, nil
+}
+
+// workload loads the controller of the issue's resource. Pods are resolved to their
+// controller, since a pod's resources cannot be changed in place.
+func (r *MemoryResizer) workload(ctx context.Context, issue *models.Issue) (*memoryWorkload, error) {
+	kind, name := NormalizeKind(issue.ResourceType), issue.ResourceName
+	var pod *corev1.Pod
+	if kind == "Pod" {
+		var err error
+		pod, err = r.clientset.CoreV1().Pods(issue.Namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get pod: %w", err)
+		}
+		controller, err := podController(ctx, r.clientset, pod)
+		if err != nil {
+			return nil, err
+		}
+		if controller.Kind == "Pod" {
+			return nil, fmt.Errorf("pod %s/%s has no controller whose memory can be raised", issue.Namespace, name)
+		}
+		kind, name = controller.Kind, controller.Name
+	}
+
+	workload := &memoryWorkload{kind: kind, pod: pod}
+	deployments := r.clientset.AppsV1().Deployments(issue.Namespace)
+	statefulSets := r.clientset.AppsV1().StatefulSets(issue.Namespace)
+	daemonSets := r.clientset.AppsV1().DaemonSets(issue.Namespace)
+	switch kind {
+	case "Deployment":
+		deployment, err := deployments.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get deployment: %w", err)
+		}
+		workload.object, workload.template, workload.selector = deployment, &deployment.Spec.Template, deployment.Spec.Selector
+		workload.replicas = replicaCount(deployment.Spec.Replicas)
+		workload.update = func(ctx context.Context) (types.UID, error) {
+			updated, err := deployments.Update(ctx, deployment, metav1.UpdateOptions{})
+			if err != nil {
+				return "", err
+			}
+			return updated.UID, nil
+		}
+	case "StatefulSet":
+		sts, err := statefulSets.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get statefulset: %w", err)
+		}
+		workload.object, workload.template, workload.selector = sts, &sts.Spec.Template, sts.Spec.Selector
+		workload.replicas = replicaCount(sts.Spec.Replicas)
+		workload.update = func(ctx context.Context) (types.UID, error) {
+			updated, err := statefulSets.Update(ctx, sts, metav1.UpdateOptions{})
+			if err != nil {
+				return "", err
+			}
+			return updated.UID, nil
+		}
+	case "DaemonSet":
+		ds, err := daemonSets.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get daemonset: %w", err)
+		}
+		workload.object, workload.template, workload.selector = ds, &ds.Spec.Template, ds.Spec.Selector
+		workload.replicas = int64(ds.Status.DesiredNumberScheduled)
+		workload.update = func(ctx context.Context) (types.UID, error) {
+			updated, err := daemonSets.Update(ctx, ds, metav1.UpdateOptions{})
+			if err != nil {
+				return "", err
+			}
+			return updated.UID, nil
+		}
+	default:
+		return nil, fmt.Errorf("cannot raise the memory of %s %s/%s", kind, issue.Namespace, name)
+	}
+	if workload.replicas < 1 {
+		workload.replicas = 1
+	}
+	return workload, nil
+}
+
+// replicaCount returns the desired replicas, which default to one
+func replicaCount(replicas *int32) int64 {
+	if replicas == nil {
+		return 1
+	}
+	return int64(*replicas)
+}
+
+// plan computes the new memory of the workload's OOM-killed containers, or of all
+// its containers when no pod reports an OOM kill
+func (r *MemoryResizer) plan(ctx context.Context, namespace string, workload *memoryWorkload) ([]MemoryChange, error) {
+	limits, err := r.limits(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+	oomKilled := r.oomKilledContainers(ctx, namespace, workload)
+
+	type resize struct {
+		container                                  string
+		oldLimit, newLimit, oldRequest, newRequest int64
+		hasLimit, hasRequest                       bool
+	}
+	var resizes []resize
+	var limitDelta, requestDelta int64
+	for _, container := range workload.template.Spec.Containers {
+		if len(oomKilled) > 0 && !oomKilled[container.Name] {
+			continue
+		}
+		oldLimit := container.Resources.Limits.Memory().Value()
+		hasLimit := oldLimit > 0
+		if !hasLimit {
+			oldLimit = limits.defaultLimit
+		}
+		if oldLimit == 0 {
+			// Without a limit the container is not killed for exceeding it
+			continue
+		}
+
+		newLimit := roundUpMebibytes(oldLimit + oldLimit*int64(r.StepPercent)/100)
+		if limits.maxLimit > 0 && newLimit > limits.maxLimit {
+			newLimit = limits.maxLimit
+		}
+		if newLimit <= oldLimit {
+			continue
+		}
+
+		// A missing request defaults to the limit
+		oldRequest := container.Resources.Requests.Memory().Value()
+		hasRequest := oldRequest > 0
+		if !hasRequest {
+			oldRequest = oldLimit
+		}
+		newRequest := newLimit
+		if hasRequest {
+			newRequest = roundUpMebibytes(int64(float64(oldRequest) * float64(newLimit) / float64(oldLimit)))
+			if limits.maxRatio > 0 && float64(newLimit)/float64(newRequest) > limits.maxRatio {
+				newRequest = roundUpMebibytes(int64(float64(newLimit) / limits.maxRatio))
+			}
+			if newRequest > newLimit {
+				newRequest = newLimit
+			}
+		}
+
+		resizes = append(resizes, resize{container.Name, oldLimit, newLimit, oldRequest, newRequest, hasLimit, hasRequest})
+		limitDelta += (newLimit - oldLimit) * workload.replicas
+		requestDelta += (newRequest - oldRequest) * workload.replicas
+	}
+	if len(resizes) == 0 {
+		return nil, fmt.Errorf("memory of %s %s/%s cannot be raised: containers have no memory limit or are at the ceiling of %s",
+			strings.ToLower(workload.kind), namespace, workload.object.GetName(), resource.NewQuantity(limits.maxLimit, resource.BinarySI))
+	}
+
+	// Shrink the increase to what the quota has left
+	fraction := 1.0
+	if limits.limitHeadroom >= 0 && limitDelta > limits.limitHeadroom {
+		fraction = float64(limits.limitHeadroom) / float64(limitDelta)
+	}
+	if limits.requestHeadroom >= 0 && requestDelta > limits.requestHeadroom {
+		fraction = min(fraction, float64(limits.requestHeadroom)/float64(requestDelta))
+	}
+
+	changes := make([]MemoryChange, 0, len(resizes))
+	for _, rs := range resizes {
+		newLimit, newRequest := rs.newLimit, rs.newRequest
+		if fraction < 1 {
+			// Round down so the quota is not exceeded
+			newLimit = rs.oldLimit + int64(float64(newLimit-rs.oldLimit)*fraction)/mebibyte*mebibyte
+			newRequest = rs.oldRequest + int64(float64(newRequest-rs.oldRequest)*fraction)/mebibyte*mebibyte
+		}
+		if newLimit <= rs.oldLimit {
+			continue
+		}
+
+		change := MemoryChange{
+			Container: rs.container,
+			NewLimit:  resource.NewQuantity(newLimit, resource.BinarySI).String(),
+		}
+		if rs.hasLimit {
+			change.OldLimit = resource.NewQuantity(rs.oldLimit, resource.BinarySI).String()
+		}
+		if rs.hasRequest {
+			change.OldRequest = resource.NewQuantity(rs.oldRequest, resource.BinarySI).String()
+			change.NewRequest = resource.NewQuantity(newRequest, resource.BinarySI).String()
+		}
+		changes = append(changes, change)
+	}
+	if len(changes) == 0 {
+		return nil, fmt.Errorf("memory of %s %s/%s cannot be raised: the namespace ResourceQuota is exhausted",
+			strings.ToLower(workload.kind), namespace, workload.object.GetName())
+	}
+	return changes, nil
+}
+
+// limits reads the namespace's LimitRanges and ResourceQuotas. LimitRanges or
+// ResourceQuotas the engine may not list constrain nothing beyond the ceiling.
+func (r *MemoryResizer) limits(ctx context.Context, namespace string) (*memoryLimits, error) {
+	limits := &memoryLimits{
+		maxLimit:        r.Ceiling.Value(),
+		limitHeadroom:   -1,
+		requestHeadroom: -1,
+	}
+
+	ranges, err := r.clientset.CoreV1().LimitRanges(namespace).List(ctx, metav1.ListOptions{})
+	if apierrors.IsForbidden(err) {
+		r.log.WithError(err).WithField("namespace", namespace).Warn("Not allowed to list limit ranges, applying only the memory ceiling")
+		ranges = &corev1.LimitRangeList{}
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to list limit ranges: %w", err)
+	}
+	for _, lr := range ranges.Items {
+		for _, item := range lr.Spec.Limits {
+			if item.Type != corev1.LimitTypeContainer {
+				continue
+			}
+			if max, ok := item.Max[corev1.ResourceMemory]; ok && (limits.maxLimit == 0 || max.Value() < limits.maxLimit) {
+				limits.maxLimit = max.Value()
+			}
+			if def, ok := item.Default[corev1.ResourceMemory]; ok && limits.defaultLimit == 0 {
+				limits.defaultLimit = def.Value()
+			}
+			if ratio, ok := item.MaxLimitRequestRatio[corev1.ResourceMemory]; ok && ratio.AsApproximateFloat64() > 0 &&
+				(limits.maxRatio == 0 || ratio.AsApproximateFloat64() < limits.maxRatio) {
+				limits.maxRatio = ratio.AsApproximateFloat64()
+			}
+		}
+	}
+
+	quotas, err := r.clientset.CoreV1().ResourceQuotas(namespace).List(ctx, metav1.ListOptions{})
+	if apierrors.IsForbidden(err) {
+		r.log.WithError(err).WithField("namespace", namespace).Warn("Not allowed to list resource quotas, ignoring quota headroom")
+		quotas = &corev1.ResourceQuotaList{}
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to list resource quotas: %w", err)
+	}
+	for _, quota := range quotas.Items {
+		limits.limitHeadroom = minHeadroom(limits.limitHeadroom, quota, corev1.ResourceLimitsMemory)
+		limits.requestHeadroom = minHeadroom(limits.requestHeadroom, quota, corev1.ResourceRequestsMemory)
+		limits.requestHeadroom = minHeadroom(limits.requestHeadroom, quota, corev1.ResourceMemory)
+	}
+	return limits, nil
+}
+
+// minHeadroom returns the smaller of headroom and what the quota has left of name
+func minHeadroom(headroom int64, quota corev1.ResourceQuota, name corev1.ResourceName) int64 {
+	hard, ok := quota.Status.Hard[name]
+	if !ok {
+		hard, ok = quota.Spec.Hard[name]
+	}
+	if !ok {
+		return headroom
+	}
+	used := quota.Status.Used[name]
+	left := hard.Value() - used.Value()
+	if left < 0 {
+		left = 0
+	}
+	if headroom < 0 || left < headroom {
+		return left
+	}
+	return headroom
+}
+
+// oomKilledContainers returns the containers of the workload's pods that were last
+// terminated for running out of memory
+func (r *MemoryResizer) oomKilledContainers(ctx context.Context, namespace string, workload *memoryWorkload) map[string]bool {
+	pods := []corev1.Pod{}
+	if workload.pod != nil {
+		pods = append(pods, *workload.pod)
+	} else if selector, err := metav1.LabelSelectorAsSelector(workload.selector); err == nil && workload.selector != nil {
+		list, err := r.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+		if err != nil {
+			r.log.WithError(err).Debug("Failed to list pods for OOM-killed containers")
+		} else {
+			pods = list.Items
+		}
+	}
+
+	oomKilled := make(map[string]bool)
+	for _, pod := range pods {
+		for _, status := range pod.Status.ContainerStatuses {
+			for _, terminated := range []*corev1.ContainerStateTerminated{status.State.Terminated, status.LastTerminationState.Terminated} {
+				if terminated != nil && terminated.Reason == "OOMKilled" {
+					oomKilled[status.Name] = true
+				}
+			}
+		}
+	}
+	return oomKilled
+}
+
+// roundUpMebibytes rounds bytes up to a whole mebibyte
+func roundUpMebibytes(bytes int64) int64 {
+	return (bytes + mebibyte - 1) / mebibyte * mebibyte
+}
+
+// Recommend describes the memory change for a workload deployed from source, such
+// as Helm values or ArgoCD manifests, whose live object would be reverted by the
+// next release or sync. helmValues names chart values paths instead of manifest
+// fields.
+func (r *MemoryResizer) Recommend(ctx context.Context, issue *models.Issue, source string, helmValues bool) []string {
+	recommendations := []string{fmt.Sprintf("%s is deployed from %s; raise its memory there instead of patching the live object",
+		issueResource(issue), source)}
+
+	var workload *memoryWorkload
+	var changes []MemoryChange
+	err := fmt.Errorf("no memory resizer configured")
+	if r != nil {
+		workload, changes, err = r.resize(ctx, issue)
+	}
+	if err != nil {
+		return append(recommendations,
+			fmt.Sprintf("Raise the container memory limits in %s (automatic sizing failed: %v)", source, err))
+	}
+
+	valuesPath := func(container, field string) string {
+		switch {
+		case !helmValues:
+			return fmt.Sprintf("spec.template.spec.containers[name=%s].resources.%s.memory", container, field)
+		case len(workload.template.Spec.Containers) == 1:
+			return fmt.Sprintf("resources.%s.memory", field)
+		default:
+			return fmt.Sprintf("%s.resources.%s.memory", container, field)
+		}
+	}
+	for _, change := range changes {
+		recommendations = append(recommendations, fmt.Sprintf("Set %s to %s (currently %s) in %s",
+			valuesPath(change.Container, "limits"), change.NewLimit, valueOrNone(change.OldLimit), source))
+		if change.NewRequest != "" {
+			recommendations = append(recommendations, fmt.Sprintf("Set %s to %s (currently %s) in %s",
+				valuesPath(change.Container, "requests"), change.NewRequest, change.OldRequest, source))
+		}
+	}
+	return recommendations
+}
+
+// isOOMIssue returns true for issues caused by containers running out of memory
+func isOOMIssue(issue *models.Issue) bool {
+	return strings.EqualFold(issue.Type, "OOMKilled")
+}
@@ -0,0 +1,194 @@
+package remediation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/detector"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// newOOMDeployment returns a deployment whose app container was OOM-killed, with a
+// sidecar that was not
+func newOOMDeployment(replicas int32, limit, request string, annotations map[string]string) []runtime.Object {
+	labels := map[string]string{"app": "payment"}
+	isController := true
+	memory := func(value string) corev1.ResourceList {
+		return corev1.ResourceList{corev1.ResourceMemory: resource.MustParse(value)}
+	}
+	return []runtime.Object{
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "payment", Namespace: "default", Annotations: annotations},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: &replicas,
+				Selector: &metav1.LabelSelector{MatchLabels: labels},
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{Labels: labels},
+					Spec: corev1.PodSpec{Containers: []corev1.Container{
+						{Name: "app", Resources: corev1.ResourceRequirements{Limits: memory(limit), Requests: memory(request)}},
+						{Name: "proxy", Resources: corev1.ResourceRequirements{Limits: memory("128Mi")}},
+					}},
+				},
+			},
+		},
+		&appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{
+			Name: "payment-7d9f", Namespace: "default",
+			OwnerReferences: []metav1.OwnerReference{{Kind: "Deployment", Name: "payment", Controller: &isController}},
+		}},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "payment-7d9f-abc12", Namespace: "default", Labels: labels,
+				OwnerReferences: []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "payment-7d9f", Controller: &isController}},
+			},
+			Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{
+				{Name: "app", LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "OOMKilled"}}},
+				{Name: "proxy"},
+			}},
+		},
+	}
+}
+
+func TestMemoryResizer_Plan(t *testing.T) {
+	limitRange := func(limit corev1.LimitRangeItem) *corev1.LimitRange {
+		limit.Type = corev1.LimitTypeContainer
+		return &corev1.LimitRange{
+			ObjectMeta: metav1.ObjectMeta{Name: "limits", Namespace: "default"},
+			Spec:       corev1.LimitRangeSpec{Limits: []corev1.LimitRangeItem{limit}},
+		}
+	}
+	quota := &corev1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: "quota", Namespace: "default"},
+		Status: corev1.ResourceQuotaStatus{
+			Hard: corev1.ResourceList{corev1.ResourceLimitsMemory: resource.MustParse("1Gi")},
+			Used: corev1.ResourceList{corev1.ResourceLimitsMemory: resource.MustParse("800Mi")},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		limit       string
+		request     string
+		objects     []runtime.Object
+		forbidden   []string // resources the engine may not list
+		wantLimit   string
+		wantRequest string
+		wantErr     string
+	}{
+		{name: "step", limit: "512Mi", request: "256Mi", wantLimit: "768Mi", wantRequest: "384Mi"},
+		{name: "ceiling", limit: "3Gi", request: "3Gi", wantLimit: "4Gi", wantRequest: "4Gi"},
+		{name: "at ceiling", limit: "4Gi", request: "1Gi", wantErr: "at the ceiling"},
+		{name: "limit range max", limit: "512Mi", request: "256Mi", wantLimit: "700Mi", wantRequest: "350Mi",
+			objects: []runtime.Object{limitRange(corev1.LimitRangeItem{Max: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("700Mi")}})}},
+		{name: "limit range ratio", limit: "512Mi", request: "128Mi", wantLimit: "768Mi", wantRequest: "384Mi",
+			objects: []runtime.Object{limitRange(corev1.LimitRangeItem{MaxLimitRequestRatio: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("2")}})}},
+		{name: "quota headroom", limit: "512Mi", request: "256Mi", wantLimit: "624Mi", wantRequest: "312Mi",
+			objects: []runtime.Object{quota}},
+		{name: "limit ranges and quotas forbidden", limit: "3Gi", request: "3Gi", wantLimit: "4Gi", wantRequest: "4Gi",
+			objects: []runtime.Object{quota}, forbidden: []string{"limitranges", "resourcequotas"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objects := append(newOOMDeployment(2, tt.limit, tt.request, nil), tt.objects...)
+			log := logrus.New()
+			log.SetLevel(logrus.ErrorLevel)
+			clientset := fake.NewSimpleClientset(objects...)
+			for _, forbidden := range tt.forbidden {
+				clientset.PrependReactor("list", forbidden, func(action k8stesting.Action) (bool, runtime.Object, error) {
+					return true, nil, apierrors.NewForbidden(action.GetResource().GroupResource(), "", errors.New("no RBAC rule"))
+				})
+			}
+			resizer := NewMemoryResizer(clientset, 0, resource.Quantity{}, log)
+
+			issue := &models.Issue{Type: "OOMKilled", Namespace: "default", ResourceType: "pod", ResourceName: "payment-7d9f-abc12"}
+			target, changes, err := resizer.Plan(context.Background(), issue)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "Deployment", target.Kind)
+			assert.Equal(t, "payment", target.Name)
+			// Only the OOM-killed container is resized
+			require.Len(t, changes, 1)
+			assert.Equal(t, MemoryChange{Container: "app", OldLimit: tt.limit, NewLimit: tt.wantLimit,
+				OldRequest: tt.request, NewRequest: tt.wantRequest}, changes[0])
+		})
+	}
+}
+
+func TestManualRemediator_IncreaseMemory(t *testing.T) {
+	clientset := fake.NewSimpleClientset(newOOMDeployment(1, "512Mi", "256Mi", nil)...)
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	remediator := NewManualRemediator(clientset, log)
+
+	issue := &models.Issue{ID: "issue-1", Type: "OOMKilled", Namespace: "default", ResourceType: "Deployment", ResourceName: "payment"}
+	info := models.NewDeploymentInfo("default", "payment", "Deployment", models.DeploymentMethodManual, 0.6)
+	result, err := remediator.Remediate(context.Background(), info, issue)
+	require.NoError(t, err)
+	assert.Equal(t, []models.PerformedAction{{Action: "increase_memory", Target: "Deployment default/payment",
+		Before: "app: limit 512Mi, request 256Mi", After: "app: limit 768Mi, request 384Mi"}}, result.Actions)
+
+	updated, err := clientset.AppsV1().Deployments("default").Get(context.Background(), "payment", metav1.GetOptions{})
+	require.NoError(t, err)
+	app := updated.Spec.Template.Spec.Containers[0]
+	assert.Equal(t, "768Mi", app.Resources.Limits.Memory().String())
+	assert.Equal(t, "384Mi", app.Resources.Requests.Memory().String())
+	assert.Equal(t, "128Mi", updated.Spec.Template.Spec.Containers[1].Resources.Limits.Memory().String())
+
+	// The previous values are recorded for reverting
+	var previous []MemoryChange
+	require.NoError(t, json.Unmarshal([]byte(updated.Annotations[AnnotationPreviousMemory]), &previous))
+	require.Len(t, previous, 1)
+	assert.Equal(t, "512Mi", previous[0].OldLimit)
+	assert.Equal(t, "256Mi", previous[0].OldRequest)
+}
+
+func TestOrchestrator_HelmOOMRecommendsValues(t *testing.T) {
+	objects := newOOMDeployment(1, "512Mi", "256Mi", map[string]string{
+		detector.HelmReleaseNameAnnotation: "payment",
+	})
+	clientset := fake.NewSimpleClientset(objects...)
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	helm := NewHelmRemediator(clientset, nil, log)
+	helm.SetMemoryResizer(NewMemoryResizer(clientset, 0, resource.Quantity{}, log))
+	selector := NewStrategySelector(log)
+	selector.SetFallbackRemediator(NewManualRemediator(clientset, log))
+	selector.RegisterRemediator(helm)
+	o := NewOrchestrator(detector.NewDetector(clientset, log), selector, log)
+
+	issue := newTestIssue("inc-1")
+	issue.Type = "OOMKilled"
+	wf, _, err := o.TriggerRemediation(context.Background(), "inc-1", issue)
+	require.NoError(t, err)
+
+	recommended := waitForStatus(t, o, wf.ID, models.WorkflowStatusRecommended)
+	assert.Contains(t, recommended.Recommendations, "Set app.resources.limits.memory to 768Mi (currently 512Mi) in the values of Helm release default/payment")
+	assert.Equal(t, "recommended", recommended.Steps[len(recommended.Steps)-1].Status)
+
+	// The live object is left alone
+	live, err := clientset.AppsV1().Deployments("default").Get(context.Background(), "payment", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "512Mi", live.Spec.Template.Spec.Containers[0].Resources.Limits.Memory().String())
+
+	result, err := helm.Remediate(context.Background(), models.NewDeploymentInfo("default", "payment", "Deployment", models.DeploymentMethodHelm, 0.9), issue)
+	assert.Error(t, err) // no release_name detail
+	assert.Empty(t, result.Recommendations)
+	assert.Equal(t, models.ErrorClassPermanent, ClassifyError(err))
+}
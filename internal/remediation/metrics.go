@@ -52,6 +52,42 @@ var (
 		[]string{"strategy", "deployment_method", "selected"},
 	)
 
+	// StrategyFailoversTotal counts fall-throughs to another remediator after a retryable failure
+	StrategyFailoversTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "coordination_engine_strategy_failovers_total",
+			Help: "Total number of remediator failovers after retryable failures by result (allowed, denied)",
+		},
+		[]string{"from", "to", "result"},
+	)
+
+	// RemediationRetriesTotal counts retries of remediations that failed with a retryable error
+	RemediationRetriesTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "coordination_engine_remediation_retries_total",
+			Help: "Total number of remediation retries by remediator, failed action and error reason",
+		},
+		[]string{"remediator", "action", "reason"},
+	)
+
+	// RemediationRetriesExhaustedTotal counts remediations that still failed after all attempts
+	RemediationRetriesExhaustedTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "coordination_engine_remediation_retries_exhausted_total",
+			Help: "Total number of remediations that failed after using every attempt of their retry policy",
+		},
+		[]string{"remediator", "reason"},
+	)
+
+	// StrategyRecommendOnlyTotal counts remediations downgraded to recommendations by low detection confidence
+	StrategyRecommendOnlyTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "coordination_engine_strategy_recommend_only_total",
+			Help: "Total number of remediations downgraded to recommendations because detection confidence was below the threshold",
+		},
+		[]string{"deployment_method"},
+	)
+
 	// RemediationSuccessRate tracks current success rate as a gauge
 	RemediationSuccessRate = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
@@ -88,6 +124,160 @@ var (
 		[]string{"step_type", "status"},
 	)
 
+	// DeduplicatedTriggersTotal counts remediation triggers folded into an active workflow
+	DeduplicatedTriggersTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "coordination_engine_remediation_deduplicated_total",
+			Help: "Total number of remediation triggers deduplicated to an active workflow",
+		},
+		[]string{"reason"},
+	)
+
+	// ResourceLockWaitDuration tracks how long remediations waited for a resource lock
+	ResourceLockWaitDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "coordination_engine_resource_lock_wait_seconds",
+			Help:    "Time spent waiting for a per-resource remediation lock",
+			Buckets: []float64{0.1, 1, 5, 10, 30, 60, 120, 300},
+		},
+		[]string{"kind"},
+	)
+
+	// ResourceLockContentionTotal counts lock acquisitions that had to wait
+	ResourceLockContentionTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "coordination_engine_resource_lock_contention_total",
+			Help: "Total number of resource lock acquisitions that waited for another holder",
+		},
+		[]string{"kind"},
+	)
+
+	// WorkQueueDepth tracks workflows waiting for a worker
+	WorkQueueDepth = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "coordination_engine_workflow_queue_depth",
+			Help: "Number of workflows waiting in the execution queue",
+		},
+	)
+
+	// WorkQueueWaitDuration tracks how long workflows waited in the queue before running
+	WorkQueueWaitDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "coordination_engine_workflow_queue_wait_seconds",
+			Help:    "Time workflows spent queued before execution started",
+			Buckets: []float64{0.1, 1, 5, 10, 30, 60, 120, 300, 600},
+		},
+		[]string{"source"},
+	)
+
+	// ApprovalsTotal counts approval gate requests and decisions
+	ApprovalsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "coordination_engine_approvals_total",
+			Help: "Total number of approval gate requests and decisions",
+		},
+		[]string{"source", "outcome"},
+	)
+
+	// VerificationsTotal counts post-remediation verifications by result
+	VerificationsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "coordination_engine_remediation_verifications_total",
+			Help: "Total number of post-remediation verifications",
+		},
+		[]string{"result"},
+	)
+
+	// VerificationDuration tracks how long remediated resources took to verify
+	VerificationDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "coordination_engine_remediation_verification_duration_seconds",
+			Help:    "Time spent verifying that a remediated resource recovered",
+			Buckets: []float64{5, 10, 30, 60, 120, 300, 600},
+		},
+		[]string{"result"},
+	)
+
+	// CircuitBreakerOpen is 1 for each resource and issue type whose circuit breaker is open
+	CircuitBreakerOpen = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "coordination_engine_circuit_breaker_open",
+			Help: "Remediation circuit breakers currently open (1) by resource and issue type",
+		},
+		[]string{"namespace", "kind", "name", "issue_type"},
+	)
+
+	// CircuitBreakerTripsTotal counts circuit breakers opening
+	CircuitBreakerTripsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "coordination_engine_circuit_breaker_trips_total",
+			Help: "Total number of remediation circuit breakers opened",
+		},
+		[]string{"namespace", "issue_type"},
+	)
+
+	// CircuitBreakerRejectionsTotal counts triggers refused by an open circuit breaker
+	CircuitBreakerRejectionsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "coordination_engine_circuit_breaker_rejections_total",
+			Help: "Total number of remediation triggers refused by an open circuit breaker",
+		},
+		[]string{"namespace", "issue_type"},
+	)
+
+	// MaintenanceDecisionsTotal counts remediations blocked by a maintenance window or freeze
+	MaintenanceDecisionsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "coordination_engine_maintenance_decisions_total",
+			Help: "Total number of remediations rejected, deferred or downgraded to recommendations by maintenance windows",
+		},
+		[]string{"source", "action", "window"},
+	)
+
+	// MaintenanceFreezeActive reports whether the global remediation freeze is on
+	MaintenanceFreezeActive = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "coordination_engine_maintenance_freeze_active",
+			Help: "Global remediation freeze state (1 = frozen)",
+		},
+	)
+
+	// GuardrailRefusalsTotal counts remediations refused by namespace lists or annotations
+	GuardrailRefusalsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "coordination_engine_guardrail_refusals_total",
+			Help: "Total number of remediations refused by namespace allow/deny lists or opt-out annotations",
+		},
+		[]string{"rule"},
+	)
+
+	// PolicyReloadsTotal counts remediation policy loads by result
+	PolicyReloadsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "coordination_engine_policy_reloads_total",
+			Help: "Total number of remediation policy loads by result (success, failed)",
+		},
+		[]string{"result"},
+	)
+
+	// PolicyRuleMatchesTotal counts issues resolved by each remediation policy rule
+	PolicyRuleMatchesTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "coordination_engine_policy_rule_matches_total",
+			Help: "Total number of issues resolved by each remediation policy rule",
+		},
+		[]string{"rule"},
+	)
+
+	// PluginRequestsTotal counts requests to remediation plugins by endpoint and result
+	PluginRequestsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "coordination_engine_plugin_requests_total",
+			Help: "Total number of requests to remediation plugins by endpoint and result (success, failed)",
+		},
+		[]string{"plugin", "endpoint", "result"},
+	)
+
 	// RemediatorHealthScore tracks health/availability of each remediator
 	RemediatorHealthScore = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
@@ -126,6 +316,26 @@ func RecordStrategySelection(strategy, deploymentMethod string, selected bool) {
 	StrategySelectionTotal.WithLabelValues(strategy, deploymentMethod, selectedStr).Inc()
 }
 
+// RecordStrategyFailover records a failover between remediators (allowed, denied)
+func RecordStrategyFailover(from, to, result string) {
+	StrategyFailoversTotal.WithLabelValues(from, to, result).Inc()
+}
+
+// RecordRemediationRetry records a retry of a failed remediation
+func RecordRemediationRetry(remediator, action, reason string) {
+	RemediationRetriesTotal.WithLabelValues(remediator, action, reason).Inc()
+}
+
+// RecordRemediationRetriesExhausted records a remediation that failed on its last attempt
+func RecordRemediationRetriesExhausted(remediator, reason string) {
+	RemediationRetriesExhaustedTotal.WithLabelValues(remediator, reason).Inc()
+}
+
+// RecordStrategyRecommendOnly records a remediation downgraded to recommendations
+func RecordStrategyRecommendOnly(deploymentMethod string) {
+	StrategyRecommendOnlyTotal.WithLabelValues(deploymentMethod).Inc()
+}
+
 // UpdateSuccessRate updates the success rate gauge
 func UpdateSuccessRate(remediator, deploymentMethod string, rate float64) {
 	RemediationSuccessRate.WithLabelValues(remediator, deploymentMethod).Set(rate)
@@ -147,7 +357,95 @@ func RecordWorkflowStep(stepType, status string, duration float64) {
 	WorkflowStepDuration.WithLabelValues(stepType, status).Observe(duration)
 }
 
+// RecordVerification records a post-remediation verification result and duration
+func RecordVerification(result string, durationSeconds float64) {
+	VerificationsTotal.WithLabelValues(result).Inc()
+	VerificationDuration.WithLabelValues(result).Observe(durationSeconds)
+}
+
+// UpdateBreakerState exports whether the circuit breaker of key is open. Closed
+// breakers are removed so the gauge only lists open ones.
+func UpdateBreakerState(key BreakerKey, open bool) {
+	if open {
+		CircuitBreakerOpen.WithLabelValues(key.Namespace, key.Kind, key.Name, key.IssueType).Set(1)
+		return
+	}
+	CircuitBreakerOpen.DeleteLabelValues(key.Namespace, key.Kind, key.Name, key.IssueType)
+}
+
+// RecordBreakerTrip records a circuit breaker opening
+func RecordBreakerTrip(namespace, issueType string) {
+	CircuitBreakerTripsTotal.WithLabelValues(namespace, issueType).Inc()
+}
+
+// RecordBreakerRejection records a trigger refused by an open circuit breaker
+func RecordBreakerRejection(namespace, issueType string) {
+	CircuitBreakerRejectionsTotal.WithLabelValues(namespace, issueType).Inc()
+}
+
 // UpdateRemediatorHealth updates the health score for a remediator
 func UpdateRemediatorHealth(remediator string, healthScore float64) {
 	RemediatorHealthScore.WithLabelValues(remediator).Set(healthScore)
 }
+
+// RecordDeduplicatedTrigger records a trigger that reused an active workflow
+func RecordDeduplicatedTrigger(reason string) {
+	DeduplicatedTriggersTotal.WithLabelValues(reason).Inc()
+}
+
+// RecordLockAcquired records a resource lock acquisition and its wait time
+func RecordLockAcquired(kind string, contended bool, waitSeconds float64) {
+	ResourceLockWaitDuration.WithLabelValues(kind).Observe(waitSeconds)
+	if contended {
+		ResourceLockContentionTotal.WithLabelValues(kind).Inc()
+	}
+}
+
+// UpdateQueueDepth sets the number of queued workflows
+func UpdateQueueDepth(depth int) {
+	WorkQueueDepth.Set(float64(depth))
+}
+
+// RecordQueueWait records how long a workflow waited in the execution queue
+func RecordQueueWait(source string, waitSeconds float64) {
+	WorkQueueWaitDuration.WithLabelValues(source).Observe(waitSeconds)
+}
+
+// RecordApproval records an approval request or decision (approved, rejected, expired)
+func RecordApproval(source, outcome string) {
+	ApprovalsTotal.WithLabelValues(source, outcome).Inc()
+}
+
+// RecordMaintenanceDecision records a remediation blocked by a maintenance window or freeze
+func RecordMaintenanceDecision(source, action, window string) {
+	MaintenanceDecisionsTotal.WithLabelValues(source, action, window).Inc()
+}
+
+// UpdateFreezeState records whether the global remediation freeze is on
+func UpdateFreezeState(active bool) {
+	if active {
+		MaintenanceFreezeActive.Set(1)
+		return
+	}
+	MaintenanceFreezeActive.Set(0)
+}
+
+// RecordGuardrailRefusal records a remediation refused by a guardrail rule
+func RecordGuardrailRefusal(rule string) {
+	GuardrailRefusalsTotal.WithLabelValues(rule).Inc()
+}
+
+// RecordPolicyReload records a remediation policy load (success, failed)
+func RecordPolicyReload(result string) {
+	PolicyReloadsTotal.WithLabelValues(result).Inc()
+}
+
+// RecordPolicyMatch records an issue resolved by a remediation policy rule
+func RecordPolicyMatch(rule string) {
+	PolicyRuleMatchesTotal.WithLabelValues(rule).Inc()
+}
+
+// RecordPluginRequest records a request to a remediation plugin endpoint (success, failed)
+func RecordPluginRequest(plugin, endpoint, result string) {
+	PluginRequestsTotal.WithLabelValues(plugin, endpoint, result).Inc()
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
@@ -21,6 +22,7 @@ import (
 type OperatorRemediator struct {
 	clientset     kubernetes.Interface
 	dynamicClient dynamic.Interface
+	recorder      EventRecorder
 	log           *logrus.Logger
 }
 
@@ -43,8 +45,15 @@ func NewOperatorRemediator(clientset kubernetes.Interface, dynamicClient dynamic
 	}
 }
 
+// SetEventRecorder records the actions taken as Kubernetes Events
+func (or *OperatorRemediator) SetEventRecorder(recorder EventRecorder) {
+	or.recorder = recorder
+}
+
 // Remediate triggers operator reconciliation by updating CR annotation
-func (or *OperatorRemediator) Remediate(ctx context.Context, deploymentInfo *models.DeploymentInfo, issue *models.Issue) error {
+func (or *OperatorRemediator) Remediate(ctx context.Context, deploymentInfo *models.DeploymentInfo, issue *models.Issue) (*models.RemediationResult, error) {
+	result := models.NewRemediationResult(or.Name())
+
 	operatorName := deploymentInfo.GetDetail("operator")
 	if operatorName == "" {
 		operatorName = deploymentInfo.GetDetail("managed_by")
@@ -61,13 +70,15 @@ func (or *OperatorRemediator) Remediate(ctx context.Context, deploymentInfo *mod
 	// Find the Custom Resource (CR) that owns this resource
 	cr, err := or.findOwningCR(ctx, issue.Namespace, issue.ResourceName, issue.ResourceType)
 	if err != nil {
-		return fmt.Errorf("failed to find owning CR: %w", err)
+		return result, fmt.Errorf("failed to find owning CR: %w", err)
 	}
 
 	if cr == nil {
 		or.log.Warn("No owning CR found, cannot trigger operator reconciliation")
-		return fmt.Errorf("no owning CR found for %s/%s", issue.Namespace, issue.ResourceName)
+		return result, Permanent(fmt.Errorf("no owning CR found for %s/%s", issue.Namespace, issue.ResourceName))
 	}
+	result.AddEvidence("operator", operatorName)
+	result.AddEvidence("custom_resource", fmt.Sprintf("%s %s/%s", cr.Kind, issue.Namespace, cr.Name))
 
 	or.log.WithFields(logrus.Fields{
 		"cr_kind":       cr.Kind,
@@ -76,12 +87,45 @@ func (or *OperatorRemediator) Remediate(ctx context.Context, deploymentInfo *mod
 	}).Info("Found owning Custom Resource")
 
 	// Trigger reconciliation by updating CR annotation
-	if err := or.triggerReconciliation(ctx, cr, issue.Namespace); err != nil {
-		return fmt.Errorf("failed to trigger reconciliation: %w", err)
+	crUID, previous, triggeredAt, err := or.triggerReconciliation(ctx, cr, issue.Namespace)
+	if err != nil {
+		return result, fmt.Errorf("failed to trigger reconciliation: %w", err)
 	}
+	result.AddAction("annotate_custom_resource", fmt.Sprintf("%s %s/%s", cr.Kind, issue.Namespace, cr.Name), previous, triggeredAt)
+
+	crRef := corev1.ObjectReference{
+		APIVersion: cr.APIVersion,
+		Kind:       cr.Kind,
+		Namespace:  issue.Namespace,
+		Name:       cr.Name,
+		UID:        crUID,
+	}
+	recordOnTargets(ctx, or.recorder, RemediationEvent{
+		Reason:     EventReasonReconcileTriggered,
+		Action:     "annotate_custom_resource",
+		Remediator: or.Name(),
+		Message:    fmt.Sprintf("Triggered %s %s reconciliation to remediate %s", cr.Kind, cr.Name, issue.Type),
+	}, crRef, workloadRef(issue.ResourceType, issue.Namespace, issue.ResourceName))
 
 	or.log.WithField("cr_name", cr.Name).Info("Operator reconciliation triggered successfully")
-	return nil
+	return result, nil
+}
+
+// PlanActions finds the owning Custom Resource and describes the reconciliation trigger
+func (or *OperatorRemediator) PlanActions(ctx context.Context, _ *models.DeploymentInfo, issue *models.Issue) ([]PlannedAction, error) {
+	cr, err := or.findOwningCR(ctx, issue.Namespace, issue.ResourceName, issue.ResourceType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find owning CR: %w", err)
+	}
+	if cr == nil {
+		return nil, fmt.Errorf("no owning CR found for %s/%s", issue.Namespace, issue.ResourceName)
+	}
+
+	return []PlannedAction{{
+		Action:      "annotate_custom_resource",
+		Target:      fmt.Sprintf("%s %s/%s", cr.Kind, issue.Namespace, cr.Name),
+		Description: fmt.Sprintf("set remediation.aiops/trigger annotation on %s %s to trigger operator reconciliation", cr.Kind, cr.Name),
+	}}, nil
 }
 
 // CanRemediate returns true if deployment is operator-managed
@@ -202,9 +246,10 @@ func (or *OperatorRemediator) isBuiltInKubernetesResource(kind string) bool {
 	return false
 }
 
-// triggerReconciliation triggers operator reconciliation by updating CR annotation
-// Uses dynamic client to patch the Custom Resource
-func (or *OperatorRemediator) triggerReconciliation(ctx context.Context, cr *CustomResourceInfo, namespace string) error {
+// triggerReconciliation triggers operator reconciliation by updating CR annotation.
+// It returns the CR's UID and the trigger annotation before and after the update.
+// Uses dynamic client to patch the Custom Resource and returns its UID
+func (or *OperatorRemediator) triggerReconciliation(ctx context.Context, cr *CustomResourceInfo, namespace string) (uid types.UID, previous, timestamp string, err error) {
 	// Create GVR (GroupVersionResource) for dynamic client
 	gvr := schema.GroupVersionResource{
 		Group:    cr.Group,
@@ -219,13 +264,14 @@ func (or *OperatorRemediator) triggerReconciliation(ctx context.Context, cr *Cus
 	}).Info("Updating CR to trigger reconciliation")
 
 	// Verify the CR exists before patching
-	_, err := or.dynamicClient.Resource(gvr).Namespace(namespace).Get(ctx, cr.Name, metav1.GetOptions{})
+	current, err := or.dynamicClient.Resource(gvr).Namespace(namespace).Get(ctx, cr.Name, metav1.GetOptions{})
 	if err != nil {
-		return fmt.Errorf("failed to get CR: %w", err)
+		return "", "", "", fmt.Errorf("failed to get CR: %w", err)
 	}
+	previous = current.GetAnnotations()["remediation.aiops/trigger"]
 
 	// Create patch to add/update remediation trigger annotation
-	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	timestamp = strconv.FormatInt(time.Now().Unix(), 10)
 
 	// Create merge patch as JSON string
 	patchData := fmt.Sprintf(`{
@@ -246,7 +292,7 @@ func (or *OperatorRemediator) triggerReconciliation(ctx context.Context, cr *Cus
 		"patch":     patchData,
 	}).Debug("Applying patch to CR")
 
-	_, err = or.dynamicClient.Resource(gvr).Namespace(namespace).Patch(
+	patched, err := or.dynamicClient.Resource(gvr).Namespace(namespace).Patch(
 		ctx,
 		cr.Name,
 		types.MergePatchType,
@@ -255,7 +301,7 @@ func (or *OperatorRemediator) triggerReconciliation(ctx context.Context, cr *Cus
 	)
 
 	if err != nil {
-		return fmt.Errorf("failed to patch CR: %w", err)
+		return "", "", "", fmt.Errorf("failed to patch CR: %w", err)
 	}
 
 	or.log.WithFields(logrus.Fields{
@@ -263,7 +309,7 @@ func (or *OperatorRemediator) triggerReconciliation(ctx context.Context, cr *Cus
 		"reconciliation_time": timestamp,
 	}).Info("CR annotation updated, operator should reconcile")
 
-	return nil
+	return patched.GetUID(), previous, timestamp, nil
 }
 
 // parseAPIVersion parses apiVersion into group and version
@@ -2,26 +2,79 @@ package remediation
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
 
 	"github.com/tosin2013/openshift-coordination-engine/internal/detector"
+	"github.com/tosin2013/openshift-coordination-engine/internal/events"
+	"github.com/tosin2013/openshift-coordination-engine/internal/incidents"
 	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
 )
 
 // Orchestrator manages remediation workflow execution
 type Orchestrator struct {
-	detector   *detector.Detector
-	remediator Remediator
-	workflows  map[string]*models.Workflow
-	mu         sync.RWMutex
-	log        *logrus.Logger
+	detector    *detector.Detector
+	remediator  Remediator
+	store       WorkflowStore
+	active      map[string]*activeWorkflow
+	dedupWindow time.Duration
+	locks       *LockManager
+	queue       *WorkQueue
+	approvals   *ApprovalPolicy
+	incidents   *incidents.Tracker
+	events      *events.Bus
+	recorder    EventRecorder
+	verifier    *Verifier
+	breaker     *CircuitBreaker
+	maintenance *MaintenancePolicy
+	owner       string
+	ownerAlive  OwnerCheck
+	mu          sync.RWMutex
+	log         *logrus.Logger
 }
 
+// OwnerCheck reports whether the engine replica that owns a workflow is still running
+type OwnerCheck func(ctx context.Context, owner string) (bool, error)
+
+// activeWorkflow tracks a workflow owned by this orchestrator until it finishes
+type activeWorkflow struct {
+	incidentID  string
+	resourceKey string
+	createdAt   time.Time
+	cancel      context.CancelFunc
+
+	// Other incidents whose triggers were deduplicated into this workflow
+	linkedIncidents []string
+
+	// Progress already published to the event bus
+	publishedStatus models.WorkflowStatus
+	publishedSteps  int
+
+	// Set while the workflow is held for approval
+	held   *models.Workflow
+	start  func() int
+	expiry *time.Timer
+
+	// Set while the workflow is deferred by a maintenance window or freeze
+	target   MaintenanceTarget
+	deferred *models.Workflow
+	resume   *time.Timer
+}
+
+// DefaultDedupWindow is how long a trigger for the same resource reuses an active workflow
+const DefaultDedupWindow = 10 * time.Minute
+
+// ErrWorkflowNotActive is returned when cancelling a workflow that has already finished
+var ErrWorkflowNotActive = errors.New("workflow is not active")
+
 // NewOrchestrator creates a new remediation orchestrator
 func NewOrchestrator(
 	det *detector.Detector,
@@ -29,15 +82,151 @@ func NewOrchestrator(
 	log *logrus.Logger,
 ) *Orchestrator {
 	return &Orchestrator{
-		detector:   det,
-		remediator: remediator,
-		workflows:  make(map[string]*models.Workflow),
-		log:        log,
+		detector:    det,
+		remediator:  remediator,
+		store:       NewMemoryWorkflowStore(),
+		active:      make(map[string]*activeWorkflow),
+		dedupWindow: DefaultDedupWindow,
+		log:         log,
 	}
 }
 
-// TriggerRemediation initiates a remediation workflow
-func (o *Orchestrator) TriggerRemediation(ctx context.Context, incidentID string, issue *models.Issue) (*models.Workflow, error) {
+// SetDedupWindow sets how long triggers for the same namespace/kind/name are
+// deduplicated against an active workflow. Zero disables resource deduplication;
+// repeat triggers with the same incident ID are always deduplicated.
+func (o *Orchestrator) SetDedupWindow(window time.Duration) {
+	o.dedupWindow = window
+	o.log.WithField("window", window).Debug("Remediation dedup window updated")
+}
+
+// SetWorkflowStore replaces the default in-memory workflow store
+func (o *Orchestrator) SetWorkflowStore(store WorkflowStore) {
+	o.store = store
+}
+
+// SetLockManager enables per-resource locking shared with other orchestrators
+func (o *Orchestrator) SetLockManager(locks *LockManager) {
+	o.locks = locks
+}
+
+// SetWorkQueue runs workflows on a bounded, severity-ordered work queue instead of
+// starting a goroutine per trigger
+func (o *Orchestrator) SetWorkQueue(queue *WorkQueue) {
+	o.queue = queue
+}
+
+// SetApprovalPolicy holds matching workflows in pending_approval until approved
+func (o *Orchestrator) SetApprovalPolicy(policy *ApprovalPolicy) {
+	o.approvals = policy
+}
+
+// SetIncidentTracker links workflows to incidents and updates their lifecycle
+func (o *Orchestrator) SetIncidentTracker(tracker *incidents.Tracker) {
+	o.incidents = tracker
+}
+
+// SetVerifier enables checking that the resource recovered before a workflow is
+// marked completed
+func (o *Orchestrator) SetVerifier(verifier *Verifier) {
+	o.verifier = verifier
+}
+
+// SetCircuitBreaker refuses remediation of resources whose issue keeps recurring
+func (o *Orchestrator) SetCircuitBreaker(breaker *CircuitBreaker) {
+	o.breaker = breaker
+}
+
+// CircuitBreakers returns the state of the tracked circuit breakers
+func (o *Orchestrator) CircuitBreakers() []BreakerStatus {
+	return o.breaker.Status()
+}
+
+// SetMaintenancePolicy rejects, defers or downgrades remediations blocked by
+// maintenance windows or the global freeze
+func (o *Orchestrator) SetMaintenancePolicy(policy *MaintenancePolicy) {
+	o.maintenance = policy
+	policy.OnResume(o.resumeDeferred)
+}
+
+// SetEventRecorder records workflow starts and outcomes as Kubernetes Events on
+// the remediated resource
+func (o *Orchestrator) SetEventRecorder(recorder EventRecorder) {
+	o.recorder = recorder
+}
+
+// SetEventBus publishes workflow progress events to bus
+func (o *Orchestrator) SetEventBus(bus *events.Bus) {
+	o.events = bus
+}
+
+// SetOwner records owner as the engine replica executing the workflows this
+// orchestrator creates. Workflows of other replicas are only reconciled as
+// interrupted once alive reports their owner gone.
+func (o *Orchestrator) SetOwner(owner string, alive OwnerCheck) {
+	o.owner = owner
+	o.ownerAlive = alive
+}
+
+// ReconcileInterrupted marks workflows left active by a previous engine instance as interrupted.
+// It should be called once on startup, before new workflows are triggered. Workflows
+// owned by another replica that is still running are left alone.
+func (o *Orchestrator) ReconcileInterrupted(ctx context.Context) (int, error) {
+	workflows, err := o.store.List(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list workflows: %w", err)
+	}
+
+	reconciled := 0
+	for _, workflow := range workflows {
+		if !workflow.IsActive() || o.ownedByLiveReplica(ctx, workflow) {
+			continue
+		}
+
+		now := time.Now()
+		workflow.Status = models.WorkflowStatusInterrupted
+		workflow.ErrorMessage = "workflow interrupted by coordination engine restart"
+		workflow.CompletedAt = &now
+		for i := range workflow.Steps {
+			if workflow.Steps[i].Status == "pending" || workflow.Steps[i].Status == "running" {
+				workflow.Steps[i].Status = "interrupted"
+			}
+		}
+
+		// Publish the interruption and finish the incident like any other outcome
+		if err := o.persistWorkflow(ctx, workflow); err != nil {
+			return reconciled, fmt.Errorf("failed to save interrupted workflow %s: %w", workflow.ID, err)
+		}
+		reconciled++
+
+		o.log.WithFields(logrus.Fields{
+			"workflow_id": workflow.ID,
+			"incident_id": workflow.IncidentID,
+		}).Warn("Marked interrupted workflow from previous run")
+	}
+
+	return reconciled, nil
+}
+
+// ownedByLiveReplica reports whether another engine replica that is still running
+// owns the workflow. An owner that cannot be checked is assumed to be running.
+func (o *Orchestrator) ownedByLiveReplica(ctx context.Context, workflow *models.Workflow) bool {
+	if workflow.Owner == "" || workflow.Owner == o.owner || o.ownerAlive == nil {
+		return false
+	}
+	alive, err := o.ownerAlive(ctx, workflow.Owner)
+	if err != nil {
+		o.log.WithError(err).WithFields(logrus.Fields{
+			"workflow_id": workflow.ID,
+			"owner":       workflow.Owner,
+		}).Warn("Failed to check workflow owner, leaving workflow active")
+		return true
+	}
+	return alive
+}
+
+// TriggerRemediation initiates a remediation workflow. If an active workflow already
+// handles the same incident or resource, that workflow is returned and created is false.
+func (o *Orchestrator) TriggerRemediation(ctx context.Context, incidentID string, issue *models.Issue) (workflow *models.Workflow, created bool, err error) {
 	o.log.WithFields(logrus.Fields{
 		"incident_id": incidentID,
 		"issue_type":  issue.Type,
@@ -46,11 +235,618 @@ func (o *Orchestrator) TriggerRemediation(ctx context.Context, incidentID string
 	}).Info("Triggering remediation workflow")
 
 	// Validate issue
+	if err := issue.Validate(); err != nil {
+		return nil, false, fmt.Errorf("invalid issue: %w", err)
+	}
+
+	// Detect deployment method, and remediate the controller that owns the resource
+	deploymentInfo := o.resolveDeploymentInfo(ctx, issue)
+	issue = controllerIssue(issue, deploymentInfo)
+
+	// Decide up front whether the approval policy holds this workflow
+	approvalRequired, approvalReason := o.approvals.RequiresApproval(issue.Severity, issue.Namespace, o.plannedRemediatorName(ctx, deploymentInfo, issue))
+
+	// and whether a maintenance window or freeze blocks it
+	target := maintenanceTargetForIssue(issue)
+	decision := o.maintenance.Evaluate(ctx, target)
+
+	// Create and register the workflow unless an active one already covers this trigger
+	resourceKey := resourceKeyForIssue(issue)
+
+	o.mu.Lock()
+	if existingID, reason := o.findDuplicate(incidentID, resourceKey); existingID != "" {
+		if aw := o.active[existingID]; aw.incidentID != incidentID {
+			aw.linkedIncidents = appendIfMissing(aw.linkedIncidents, incidentID)
+		}
+		o.mu.Unlock()
+
+		existing, err := o.store.Get(ctx, existingID)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to load deduplicated workflow: %w", err)
+		}
+
+		o.log.WithFields(logrus.Fields{
+			"incident_id": incidentID,
+			"workflow_id": existingID,
+			"reason":      reason,
+		}).Info("Remediation trigger deduplicated to active workflow")
+		RecordDeduplicatedTrigger(reason)
+		if o.incidents != nil {
+			o.incidents.RemediationStarted(ctx, incidentID, issue.Source, issue, existingID)
+		}
+
+		return existing, false, nil
+	}
+
+	if allowed, breakerStatus := o.breaker.Allow(breakerKeyForIssue(issue)); !allowed {
+		o.mu.Unlock()
+		return o.refuseWorkflow(ctx, incidentID, issue, deploymentInfo, breakerStatus), true, nil
+	}
+
+	if decision != nil {
+		switch decision.Action {
+		case MaintenanceActionReject:
+			o.mu.Unlock()
+			RecordMaintenanceDecision("remediation", decision.Action, decision.Window)
+			o.log.WithFields(logrus.Fields{
+				"incident_id": incidentID,
+				"window":      decision.Window,
+				"reason":      decision.Reason,
+			}).Warn("Remediation rejected by maintenance policy")
+			return nil, false, fmt.Errorf("%w: %s", ErrMaintenanceBlocked, decision.Reason)
+		case MaintenanceActionRecommend:
+			o.mu.Unlock()
+			return o.recommendWorkflow(ctx, incidentID, issue, deploymentInfo, decision), true, nil
+		}
+	}
+	// An approval hold comes first; the windows are checked again once approved
+	deferred := decision != nil && !approvalRequired
+
+	workflow = o.createWorkflow(incidentID, issue, deploymentInfo)
+	switch {
+	case approvalRequired:
+		workflow.Status = models.WorkflowStatusPendingApproval
+		workflow.Approval = models.NewApproval(approvalReason, o.approvals.ExpiryOrDefault())
+	case deferred:
+		workflow.Status = models.WorkflowStatusDeferred
+		workflow.Deferral = decision.Deferral(time.Now())
+	case o.queue != nil:
+		workflow.Status = models.WorkflowStatusQueued
+	}
+	if err := o.store.Save(ctx, workflow); err != nil {
+		o.mu.Unlock()
+		return nil, false, fmt.Errorf("failed to store workflow: %w", err)
+	}
+
+	// Execute remediation in background with a per-workflow cancellable context.
+	// Capture a local copy: the named result is overwritten on return.
+	execCtx, cancel := context.WithCancel(WithWorkflowID(context.Background(), workflow.ID))
+	pending := workflow
+	aw := &activeWorkflow{
+		incidentID:  incidentID,
+		resourceKey: resourceKey,
+		createdAt:   workflow.CreatedAt,
+		cancel:      cancel,
+		start: func() int {
+			return o.startWorkflow(execCtx, pending, deploymentInfo, issue)
+		},
+		target: target,
+	}
+	if approvalRequired {
+		aw.held = pending
+		aw.expiry = time.AfterFunc(o.approvals.ExpiryOrDefault(), func() {
+			o.expireApproval(pending.ID)
+		})
+	}
+	if deferred {
+		aw.deferred = pending
+		aw.resume = o.scheduleResume(pending.ID, decision)
+	}
+	o.active[workflow.ID] = aw
+	progress := o.progressEvents(workflow, aw)
+	snapshot := workflow.Clone()
+	o.mu.Unlock()
+
+	o.publish(progress)
+
+	if o.incidents != nil {
+		o.incidents.RemediationStarted(ctx, incidentID, issue.Source, issue, workflow.ID)
+	}
+
+	if approvalRequired {
+		o.log.WithFields(logrus.Fields{
+			"workflow_id": workflow.ID,
+			"reason":      approvalReason,
+			"expires_at":  workflow.Approval.ExpiresAt,
+		}).Info("Remediation workflow waiting for approval")
+		RecordApproval("remediation", "requested")
+		return snapshot, true, nil
+	}
+
+	if deferred {
+		o.log.WithFields(logrus.Fields{
+			"workflow_id": workflow.ID,
+			"window":      decision.Window,
+			"reason":      decision.Reason,
+			"until":       decision.Until,
+		}).Info("Remediation workflow deferred by maintenance policy")
+		RecordMaintenanceDecision("remediation", decision.Action, decision.Window)
+		return snapshot, true, nil
+	}
+
+	snapshot.QueuePosition = aw.start()
+	return snapshot, true, nil
+}
+
+// refuseWorkflow records a workflow refused by an open circuit breaker
+func (o *Orchestrator) refuseWorkflow(ctx context.Context, incidentID string, issue *models.Issue, deploymentInfo *models.DeploymentInfo, breaker *BreakerStatus) *models.Workflow {
+	message := fmt.Sprintf("circuit breaker open: %d failed or ineffective remediations of %s within %s; automated remediation resumes at %s",
+		breaker.Failures, issue.Type, o.breaker.Window, breaker.ResetsAt.Format(time.RFC3339))
+	lead := fmt.Sprintf("Investigate why %s keeps recurring on %s; repeated automated remediation did not resolve it", issue.Type, issueResource(issue))
+	workflow := o.escalateWorkflow(ctx, incidentID, issue, deploymentInfo, models.WorkflowStatusCircuitOpen, message,
+		o.manualRecommendations(ctx, deploymentInfo, issue, lead))
+
+	o.log.WithFields(logrus.Fields{
+		"workflow_id": workflow.ID,
+		"incident_id": incidentID,
+		"breaker":     breaker.String(),
+		"resets_at":   breaker.ResetsAt,
+	}).Warn("Remediation refused by open circuit breaker")
+	return workflow
+}
+
+// recommendWorkflow records a workflow a maintenance window or freeze downgraded to
+// recommendations only
+func (o *Orchestrator) recommendWorkflow(ctx context.Context, incidentID string, issue *models.Issue, deploymentInfo *models.DeploymentInfo, decision *MaintenanceDecision) *models.Workflow {
+	message := "automated remediation downgraded to recommendations: " + decision.Reason
+	lead := fmt.Sprintf("Automated remediation of %s is paused by %s; apply the planned actions manually if the issue cannot wait", issueResource(issue), decision.Window)
+	workflow := o.escalateWorkflow(ctx, incidentID, issue, deploymentInfo, models.WorkflowStatusRecommended, message,
+		o.manualRecommendations(ctx, deploymentInfo, issue, lead))
+
+	RecordMaintenanceDecision("remediation", decision.Action, decision.Window)
+	o.log.WithFields(logrus.Fields{
+		"workflow_id": workflow.ID,
+		"incident_id": incidentID,
+		"window":      decision.Window,
+		"reason":      decision.Reason,
+	}).Warn("Remediation downgraded to recommendations by maintenance policy")
+	return workflow
+}
+
+// escalateWorkflow records a final workflow that automation did not run. Its incident
+// is escalated with recommendations for handling the issue manually.
+func (o *Orchestrator) escalateWorkflow(ctx context.Context, incidentID string, issue *models.Issue, deploymentInfo *models.DeploymentInfo, status models.WorkflowStatus, message string, recommendations []string) *models.Workflow {
+	workflow := o.createWorkflow(incidentID, issue, deploymentInfo)
+	now := time.Now()
+	workflow.Status = status
+	workflow.CompletedAt = &now
+	workflow.ErrorMessage = message
+	workflow.Recommendations = recommendations
+	workflow.Steps[0].Status = "completed"
+	step := workflow.AddStep("Escalate for manual remediation")
+	step.Status = "escalated"
+	step.CompletedAt = &now
+	step.ErrorMessage = message
+
+	if o.incidents != nil {
+		o.incidents.RemediationStarted(ctx, incidentID, issue.Source, issue, workflow.ID)
+	}
+	// Publishes the final status and escalates the incident
+	o.saveWorkflow(workflow)
+	return workflow.Clone()
+}
+
+// manualRecommendations suggests how a human can handle an issue automation did not
+// remediate, starting with lead
+func (o *Orchestrator) manualRecommendations(ctx context.Context, deploymentInfo *models.DeploymentInfo, issue *models.Issue, lead string) []string {
+	recommendations := []string{lead}
+
+	switch strings.ToLower(issue.Type) {
+	case "crashloopbackoff", "pod_crash_loop":
+		recommendations = append(recommendations, "Inspect the logs of the previous container instance (oc logs --previous) for the crash cause")
+	case "imagepullbackoff", "errimagepull":
+		recommendations = append(recommendations, "Verify the image reference exists and the pull secret grants access to the registry")
+	case "oomkilled":
+		recommendations = append(recommendations, "Raise the container memory limit or profile the application for a memory leak")
+	}
+
+	if actions, err := o.remediator.PlanActions(ctx, deploymentInfo, issue); err == nil {
+		for _, action := range actions {
+			recommendations = append(recommendations, fmt.Sprintf("Planned automated %s: %s", action.Action, action.Description))
+		}
+	}
+	return recommendations
+}
+
+// issueResource returns kind namespace/name of the resource an issue is about
+func issueResource(issue *models.Issue) string {
+	return fmt.Sprintf("%s %s/%s", issue.ResourceType, issue.Namespace, issue.ResourceName)
+}
+
+// maintenanceTargetForIssue returns the resource maintenance windows are checked against
+func maintenanceTargetForIssue(issue *models.Issue) MaintenanceTarget {
+	return MaintenanceTarget{
+		Namespace: issue.Namespace,
+		Kind:      NormalizeKind(issue.ResourceType),
+		Name:      issue.ResourceName,
+		Layer:     models.LayerApplication,
+	}
+}
+
+// startWorkflow runs a workflow on the work queue, or directly when no queue is set.
+// It returns the queue position, or 0 when the workflow started immediately.
+func (o *Orchestrator) startWorkflow(ctx context.Context, workflow *models.Workflow, deploymentInfo *models.DeploymentInfo, issue *models.Issue) int {
+	if o.queue == nil {
+		go o.executeWorkflow(ctx, workflow, deploymentInfo, issue)
+		return 0
+	}
+
+	return o.queue.Submit(&WorkItem{
+		ID:        workflow.ID,
+		Namespace: issue.Namespace,
+		Severity:  issue.Severity,
+		Source:    "remediation",
+		Run: func() {
+			o.executeWorkflow(ctx, workflow, deploymentInfo, issue)
+		},
+	})
+}
+
+// plannedRemediatorName returns the remediator that would handle the issue, for
+// approval rules that match on remediator
+func (o *Orchestrator) plannedRemediatorName(ctx context.Context, deploymentInfo *models.DeploymentInfo, issue *models.Issue) string {
+	if !o.approvals.Enabled() || len(o.approvals.Remediators) == 0 {
+		return ""
+	}
+	if selector, ok := o.remediator.(*StrategySelector); ok {
+		if remediator := selector.SelectRemediatorForIssue(ctx, deploymentInfo, issue); remediator != nil {
+			return remediator.Name()
+		}
+		return ""
+	}
+	return o.remediator.Name()
+}
+
+// ApproveWorkflow records the approval of a held workflow and starts it
+func (o *Orchestrator) ApproveWorkflow(workflowID, approver, comment string) (*models.Workflow, error) {
+	o.mu.Lock()
+	aw, err := o.heldWorkflow(workflowID)
+	if err != nil {
+		o.mu.Unlock()
+		return nil, err
+	}
+	workflow := aw.held
+	aw.expiry.Stop()
+	aw.held = nil
+	workflow.Approval.Decide(models.ApprovalDecisionApproved, approver, comment)
+	workflow.Status = models.WorkflowStatusPending
+	if o.queue != nil {
+		workflow.Status = models.WorkflowStatusQueued
+	}
+	snapshot := workflow.Clone()
+	o.mu.Unlock()
+
+	o.saveWorkflow(snapshot)
+	RecordApproval("remediation", models.ApprovalDecisionApproved)
+	o.log.WithFields(logrus.Fields{
+		"workflow_id": workflowID,
+		"approved_by": approver,
+	}).Info("Remediation workflow approved")
+
+	// Windows may have closed or a freeze started while waiting for approval
+	if decision := o.maintenance.Evaluate(context.Background(), aw.target); decision != nil {
+		return o.deferWorkflow(workflow, aw, decision), nil
+	}
+
+	snapshot.QueuePosition = aw.start()
+	return snapshot, nil
+}
+
+// deferWorkflow holds a workflow until no maintenance window or freeze blocks it,
+// or updates the deferral of a workflow that is still blocked
+func (o *Orchestrator) deferWorkflow(workflow *models.Workflow, aw *activeWorkflow, decision *MaintenanceDecision) *models.Workflow {
+	o.mu.Lock()
+	if !workflow.IsActive() {
+		// Cancelled while the windows were evaluated
+		snapshot := workflow.Clone()
+		o.mu.Unlock()
+		return snapshot
+	}
+	redeferred := aw.deferred != nil
+	if aw.resume != nil {
+		aw.resume.Stop()
+	}
+	aw.deferred = workflow
+	aw.resume = o.scheduleResume(workflow.ID, decision)
+	deferral := decision.Deferral(time.Now())
+	if redeferred && workflow.Deferral != nil {
+		deferral.DeferredAt = workflow.Deferral.DeferredAt
+	}
+	workflow.Status = models.WorkflowStatusDeferred
+	workflow.Deferral = deferral
+	snapshot := workflow.Clone()
+	o.mu.Unlock()
+
+	o.saveWorkflow(snapshot)
+	if !redeferred {
+		RecordMaintenanceDecision("remediation", MaintenanceActionQueue, decision.Window)
+		o.log.WithFields(logrus.Fields{
+			"workflow_id": workflow.ID,
+			"window":      decision.Window,
+			"reason":      decision.Reason,
+			"until":       decision.Until,
+		}).Info("Remediation workflow deferred by maintenance policy")
+	}
+	return snapshot
+}
+
+// scheduleResume re-evaluates a deferred workflow once its decision may have changed
+func (o *Orchestrator) scheduleResume(workflowID string, decision *MaintenanceDecision) *time.Timer {
+	return time.AfterFunc(decision.RetryAfter(time.Now()), func() {
+		o.resumeWorkflow(workflowID)
+	})
+}
+
+// resumeWorkflow starts a deferred workflow unless it is still blocked
+func (o *Orchestrator) resumeWorkflow(workflowID string) {
+	o.mu.Lock()
+	aw, ok := o.active[workflowID]
+	if !ok || aw.deferred == nil {
+		o.mu.Unlock()
+		return
+	}
+	workflow := aw.deferred
+	o.mu.Unlock()
+
+	if decision := o.maintenance.Evaluate(context.Background(), aw.target); decision != nil {
+		o.deferWorkflow(workflow, aw, decision)
+		return
+	}
+
+	o.mu.Lock()
+	if aw.deferred != workflow {
+		// Cancelled or already resumed
+		o.mu.Unlock()
+		return
+	}
+	aw.resume.Stop()
+	aw.deferred = nil
+	workflow.Status = models.WorkflowStatusPending
+	if o.queue != nil {
+		workflow.Status = models.WorkflowStatusQueued
+	}
+	snapshot := workflow.Clone()
+	o.mu.Unlock()
+
+	o.saveWorkflow(snapshot)
+	o.log.WithField("workflow_id", workflowID).Info("Resuming deferred remediation workflow")
+	aw.start()
+}
+
+// resumeDeferred re-evaluates every deferred workflow, e.g. after the freeze lifts
+func (o *Orchestrator) resumeDeferred() {
+	o.mu.RLock()
+	var ids []string
+	for id, aw := range o.active {
+		if aw.deferred != nil {
+			ids = append(ids, id)
+		}
+	}
+	o.mu.RUnlock()
+
+	for _, id := range ids {
+		o.resumeWorkflow(id)
+	}
+}
+
+// closeDeferredWorkflow cancels a deferred workflow that never started
+func (o *Orchestrator) closeDeferredWorkflow(workflowID string) error {
+	o.mu.Lock()
+	aw, ok := o.active[workflowID]
+	if !ok || aw.deferred == nil {
+		o.mu.Unlock()
+		return fmt.Errorf("%w: %s is not deferred", ErrWorkflowNotActive, workflowID)
+	}
+	workflow := aw.deferred
+	aw.resume.Stop()
+	aw.deferred = nil
+
+	now := time.Now()
+	workflow.Status = models.WorkflowStatusCancelled
+	workflow.ErrorMessage = "workflow cancelled while deferred"
+	workflow.CompletedAt = &now
+	snapshot := workflow.Clone()
+	o.mu.Unlock()
+
+	o.saveWorkflow(snapshot)
+	o.releaseActive(workflowID)
+	return nil
+}
+
+// RejectWorkflow records the rejection of a held workflow; it never runs
+func (o *Orchestrator) RejectWorkflow(workflowID, approver, comment string) (*models.Workflow, error) {
+	workflow, err := o.closeHeldWorkflow(workflowID, models.WorkflowStatusRejected, "workflow rejected by "+approver, func(approval *models.Approval) {
+		approval.Decide(models.ApprovalDecisionRejected, approver, comment)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	RecordApproval("remediation", models.ApprovalDecisionRejected)
+	o.log.WithFields(logrus.Fields{
+		"workflow_id": workflowID,
+		"rejected_by": approver,
+	}).Info("Remediation workflow rejected")
+	return workflow, nil
+}
+
+// expireApproval cancels a held workflow whose approval window has passed
+func (o *Orchestrator) expireApproval(workflowID string) {
+	message := fmt.Sprintf("workflow cancelled: approval not given within %s", o.approvals.ExpiryOrDefault())
+	_, err := o.closeHeldWorkflow(workflowID, models.WorkflowStatusCancelled, message, func(approval *models.Approval) {
+		approval.Decide(models.ApprovalDecisionExpired, "system", "")
+	})
+	if err != nil {
+		// Approved, rejected or cancelled just before the timer fired
+		return
+	}
+
+	RecordApproval("remediation", models.ApprovalDecisionExpired)
+	o.log.WithField("workflow_id", workflowID).Warn("Remediation workflow approval expired")
+}
+
+// closeHeldWorkflow moves a held workflow to a final status without running it
+func (o *Orchestrator) closeHeldWorkflow(workflowID string, status models.WorkflowStatus, message string, decide func(*models.Approval)) (*models.Workflow, error) {
+	o.mu.Lock()
+	aw, err := o.heldWorkflow(workflowID)
+	if err != nil {
+		o.mu.Unlock()
+		return nil, err
+	}
+	workflow := aw.held
+	aw.expiry.Stop()
+	aw.held = nil
+
+	now := time.Now()
+	if decide != nil {
+		decide(workflow.Approval)
+	}
+	workflow.Status = status
+	workflow.ErrorMessage = message
+	workflow.CompletedAt = &now
+	snapshot := workflow.Clone()
+	o.mu.Unlock()
+
+	o.saveWorkflow(snapshot)
+	o.releaseActive(workflowID)
+	return snapshot, nil
+}
+
+// heldWorkflow returns the active entry of a workflow waiting for approval.
+// Callers must hold o.mu.
+func (o *Orchestrator) heldWorkflow(workflowID string) (*activeWorkflow, error) {
+	if aw, ok := o.active[workflowID]; ok && aw.held != nil {
+		return aw, nil
+	}
+
+	workflow, err := o.store.Get(context.Background(), workflowID)
+	if err != nil {
+		return nil, err
+	}
+	return nil, fmt.Errorf("%w: %s is %s", ErrWorkflowNotPendingApproval, workflowID, workflow.Status)
+}
+
+// DryRunResult describes what a remediation would do without executing it
+type DryRunResult struct {
+	DeploymentMethod string          `json:"deployment_method"`
+	Confidence       float64         `json:"confidence"`
+	Remediator       string          `json:"remediator"`
+	PlannedActions   []PlannedAction `json:"planned_actions"`
+
+	// Selection explains the choice of remediator; a recommend_only decision means
+	// the planned actions would only be recommended
+	Selection []models.SelectionDecision `json:"selection,omitempty"`
+
+	// Gates a trigger passes before remediating; a blocked gate means the planned
+	// actions would not run
+	Gates []DryRunGate `json:"gates"`
+}
+
+// Gates evaluated by a dry run
+const (
+	DryRunGateCircuitBreaker = "circuit_breaker"
+	DryRunGateMaintenance    = "maintenance"
+	DryRunGateGuardrails     = "guardrails"
+)
+
+// DryRunGate is the outcome a trigger would get from one gate
+type DryRunGate struct {
+	Gate    string `json:"gate"`
+	Allowed bool   `json:"allowed"`
+	Action  string `json:"action,omitempty"` // maintenance action taken instead: reject, queue or recommend
+	Reason  string `json:"reason,omitempty"`
+}
+
+// DryRun runs deployment detection and remediator selection for an issue and returns
+// the actions the selected remediator would take, and whether the circuit breaker,
+// maintenance policy and guardrails would let them run. Nothing is mutated and no
+// workflow is created.
+func (o *Orchestrator) DryRun(ctx context.Context, issue *models.Issue) (*DryRunResult, error) {
 	if err := issue.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid issue: %w", err)
 	}
 
-	// Detect deployment method
+	deploymentInfo := o.resolveDeploymentInfo(ctx, issue)
+	issue = controllerIssue(issue, deploymentInfo)
+
+	remediator := o.remediator
+	var selection []models.SelectionDecision
+	if selector, ok := remediator.(*StrategySelector); ok {
+		var candidates []Remediator
+		candidates, selection = selector.candidates(ctx, deploymentInfo, issue)
+		if len(candidates) == 0 {
+			return nil, fmt.Errorf("no remediator available for deployment method: %s", deploymentInfo.Method)
+		}
+		remediator = candidates[0]
+		if reason, low := selector.lowConfidence(deploymentInfo); low {
+			selection = append(selection, selector.decision(remediator, models.SelectionRecommendOnly, reason))
+		}
+	}
+
+	actions, err := remediator.PlanActions(ctx, deploymentInfo, issue)
+	if err != nil {
+		return nil, fmt.Errorf("%s failed to plan actions: %w", remediator.Name(), err)
+	}
+
+	gates := o.dryRunGates(ctx, issue, actions)
+
+	o.log.WithFields(logrus.Fields{
+		"issue_id":   issue.ID,
+		"remediator": remediator.Name(),
+		"actions":    len(actions),
+	}).Info("Remediation dry run completed")
+
+	return &DryRunResult{
+		DeploymentMethod: string(deploymentInfo.Method),
+		Confidence:       deploymentInfo.Confidence,
+		Remediator:       remediator.Name(),
+		PlannedActions:   actions,
+		Selection:        selection,
+		Gates:            gates,
+	}, nil
+}
+
+// dryRunGates evaluates the gates a trigger of issue would pass, in the order a
+// trigger checks them, without counting the trigger against the circuit breaker
+func (o *Orchestrator) dryRunGates(ctx context.Context, issue *models.Issue, actions []PlannedAction) []DryRunGate {
+	breakerGate := DryRunGate{Gate: DryRunGateCircuitBreaker, Allowed: true}
+	if allowed, breaker := o.breaker.Peek(breakerKeyForIssue(issue)); !allowed {
+		breakerGate.Allowed = false
+		breakerGate.Reason = fmt.Sprintf("circuit breaker open: %d failed or ineffective remediations of %s within %s",
+			breaker.Failures, issue.Type, o.breaker.Window)
+	}
+
+	maintenanceGate := DryRunGate{Gate: DryRunGateMaintenance, Allowed: true}
+	if decision := o.maintenance.Evaluate(ctx, maintenanceTargetForIssue(issue)); decision != nil {
+		maintenanceGate.Allowed = false
+		maintenanceGate.Action = decision.Action
+		maintenanceGate.Reason = decision.Reason
+	}
+
+	guardrailsGate := DryRunGate{Gate: DryRunGateGuardrails, Allowed: true}
+	if selector, ok := o.remediator.(*StrategySelector); ok {
+		err := selector.guardrails.Check(ctx, issue.Namespace, issue.ResourceType, issue.ResourceName, actionNames(actions))
+		if err != nil {
+			guardrailsGate.Allowed = false
+			guardrailsGate.Reason = err.Error()
+		}
+	}
+	return []DryRunGate{breakerGate, maintenanceGate, guardrailsGate}
+}
+
+// resolveDeploymentInfo detects how the issue's resource was deployed, falling back
+// to an unknown method handled by manual remediation
+func (o *Orchestrator) resolveDeploymentInfo(ctx context.Context, issue *models.Issue) *models.DeploymentInfo {
 	deploymentInfo, err := o.detectDeploymentMethod(ctx, issue)
 	if err != nil {
 		o.log.WithError(err).Warn("Failed to detect deployment method, using manual remediation")
@@ -63,47 +859,117 @@ func (o *Orchestrator) TriggerRemediation(ctx context.Context, incidentID string
 			0.5,
 		)
 	}
+	return deploymentInfo
+}
 
-	// Create workflow
-	workflow := o.createWorkflow(incidentID, issue, deploymentInfo)
+// findDuplicate returns the ID of an active workflow for the same incident, or for
+// the same resource within the dedup window. Callers must hold o.mu.
+func (o *Orchestrator) findDuplicate(incidentID, resourceKey string) (workflowID, reason string) {
+	for id, aw := range o.active {
+		if aw.incidentID == incidentID {
+			return id, "incident"
+		}
+	}
+
+	if o.dedupWindow <= 0 {
+		return "", ""
+	}
+	cutoff := time.Now().Add(-o.dedupWindow)
+	for id, aw := range o.active {
+		if aw.resourceKey == resourceKey && aw.createdAt.After(cutoff) {
+			return id, "resource"
+		}
+	}
+	return "", ""
+}
+
+// resourceKeyForIssue builds the namespace/kind/name key used for deduplication
+func resourceKeyForIssue(issue *models.Issue) string {
+	return fmt.Sprintf("%s/%s/%s", issue.Namespace, strings.ToLower(issue.ResourceType), issue.ResourceName)
+}
+
+// CancelWorkflow stops a running workflow. The remediator observes the cancelled
+// context and the workflow is marked cancelled once it returns.
+func (o *Orchestrator) CancelWorkflow(workflowID string) error {
+	workflow, err := o.store.Get(context.Background(), workflowID)
+	if err != nil {
+		return err
+	}
+	if !workflow.IsActive() {
+		return fmt.Errorf("%w: %s is %s", ErrWorkflowNotActive, workflowID, workflow.Status)
+	}
 
-	// Store workflow
 	o.mu.Lock()
-	o.workflows[workflow.ID] = workflow
+	aw, running := o.active[workflowID]
+	held := running && aw.held != nil
+	deferred := running && aw.deferred != nil
 	o.mu.Unlock()
 
-	// Execute remediation in background
-	go o.executeWorkflow(context.Background(), workflow, deploymentInfo, issue)
+	// A workflow held for approval never started, so close it without a decision
+	if held {
+		if _, err := o.closeHeldWorkflow(workflowID, models.WorkflowStatusCancelled, "workflow cancelled", nil); err == nil {
+			o.log.WithField("workflow_id", workflowID).Info("Cancelled remediation workflow waiting for approval")
+			return nil
+		}
+	}
+	if deferred {
+		if err := o.closeDeferredWorkflow(workflowID); err == nil {
+			o.log.WithField("workflow_id", workflowID).Info("Cancelled deferred remediation workflow")
+			return nil
+		}
+	}
 
-	return workflow, nil
+	// A queued workflow never started, so drop it from the queue and record the cancellation
+	dequeued := o.queue != nil && o.queue.Remove(workflowID)
+
+	if !running || dequeued {
+		// No execution owns this workflow, record the cancellation directly
+		now := time.Now()
+		workflow.Status = models.WorkflowStatusCancelled
+		workflow.ErrorMessage = "workflow cancelled"
+		workflow.CompletedAt = &now
+		o.saveWorkflow(workflow)
+		if dequeued {
+			o.releaseActive(workflowID)
+		}
+		return nil
+	}
+
+	o.log.WithField("workflow_id", workflowID).Info("Cancelling remediation workflow")
+	aw.cancel()
+	return nil
 }
 
 // GetWorkflow retrieves a workflow by ID
 func (o *Orchestrator) GetWorkflow(workflowID string) (*models.Workflow, error) {
-	o.mu.RLock()
-	defer o.mu.RUnlock()
-
-	workflow, exists := o.workflows[workflowID]
-	if !exists {
-		return nil, fmt.Errorf("workflow not found: %s", workflowID)
+	workflow, err := o.store.Get(context.Background(), workflowID)
+	if err != nil {
+		return nil, err
 	}
-
+	o.setQueuePosition(workflow)
 	return workflow, nil
 }
 
 // ListWorkflows returns all workflows
 func (o *Orchestrator) ListWorkflows() []*models.Workflow {
-	o.mu.RLock()
-	defer o.mu.RUnlock()
-
-	workflows := make([]*models.Workflow, 0, len(o.workflows))
-	for _, wf := range o.workflows {
-		workflows = append(workflows, wf)
+	workflows, err := o.store.List(context.Background())
+	if err != nil {
+		o.log.WithError(err).Error("Failed to list workflows")
+		return []*models.Workflow{}
+	}
+	for _, workflow := range workflows {
+		o.setQueuePosition(workflow)
 	}
-
 	return workflows
 }
 
+// setQueuePosition fills in the live queue position of a queued workflow
+func (o *Orchestrator) setQueuePosition(workflow *models.Workflow) {
+	if o.queue != nil && workflow.Status == models.WorkflowStatusQueued {
+		workflow.QueuePosition = o.queue.Position(workflow.ID)
+	}
+}
+
 // createWorkflow creates a new workflow instance
 func (o *Orchestrator) createWorkflow(incidentID string, issue *models.Issue, deploymentInfo *models.DeploymentInfo) *models.Workflow {
 	workflow := &models.Workflow{
@@ -115,11 +981,17 @@ func (o *Orchestrator) createWorkflow(incidentID string, issue *models.Issue, de
 		ResourceName:     issue.ResourceName,
 		ResourceKind:     issue.ResourceType,
 		IssueType:        issue.Type,
+		Severity:         issue.Severity,
 		CreatedAt:        time.Now(),
+		Owner:            o.owner,
 	}
 
 	// Add initial step
-	workflow.AddStep(fmt.Sprintf("Detect deployment method for %s/%s", issue.Namespace, issue.ResourceName))
+	description := fmt.Sprintf("Detect deployment method for %s/%s", issue.Namespace, issue.ResourceName)
+	if chain := deploymentInfo.GetDetail(detector.DetailOwnerChain); chain != "" {
+		description += fmt.Sprintf(" (owner chain: %s)", chain)
+	}
+	workflow.AddStep(description)
 
 	return workflow
 }
@@ -127,6 +999,7 @@ func (o *Orchestrator) createWorkflow(incidentID string, issue *models.Issue, de
 // executeWorkflow executes the remediation workflow
 func (o *Orchestrator) executeWorkflow(ctx context.Context, workflow *models.Workflow, deploymentInfo *models.DeploymentInfo, issue *models.Issue) {
 	o.log.WithField("workflow_id", workflow.ID).Info("Starting workflow execution")
+	defer o.releaseActive(workflow.ID)
 
 	// Record workflow start metrics
 	RecordWorkflowStart()
@@ -136,6 +1009,12 @@ func (o *Orchestrator) executeWorkflow(ctx context.Context, workflow *models.Wor
 	startTime := time.Now()
 	workflow.StartedAt = &startTime
 
+	// Serialize mutations of the target resource with other workflows and plans
+	lease, err := o.acquireResourceLock(ctx, workflow, issue)
+	if lease != nil {
+		defer lease.Release()
+	}
+
 	// Add remediation step
 	step := workflow.AddStep(fmt.Sprintf("Execute %s remediation for %s", o.remediator.Name(), issue.Type))
 	workflow.Remediator = o.remediator.Name()
@@ -143,14 +1022,119 @@ func (o *Orchestrator) executeWorkflow(ctx context.Context, workflow *models.Wor
 	// Save workflow state
 	o.saveWorkflow(workflow)
 
-	// Execute remediation
-	err := o.remediator.Remediate(ctx, deploymentInfo, issue)
+	// Execute remediation unless the workflow was cancelled before it started
+	if err == nil {
+		err = ctx.Err()
+	}
+	// Resolve what to verify before remediation may replace the pod
+	var verifyTarget *VerificationTarget
+	var resolveErr error
+	if err == nil && o.verifier != nil {
+		verifyTarget, resolveErr = o.verifier.Resolve(ctx, issue)
+	}
+	if err == nil {
+		o.recordWorkflowEvent(ctx, issue, RemediationEvent{
+			Reason:  EventReasonRemediationStarted,
+			Message: fmt.Sprintf("Started %s remediation of %s", o.remediator.Name(), issue.Type),
+		})
+		var result *models.RemediationResult
+		remediateCtx := withRollbackRecorder(ctx, func(rollback models.Rollback) {
+			workflow.Rollback = &rollback
+		})
+		remediateCtx = withSelectionRecorder(remediateCtx, func(decision models.SelectionDecision) {
+			workflow.Selection = append(workflow.Selection, decision)
+		})
+		remediateCtx = withAttemptRecorder(remediateCtx, func(attempt RemediationAttempt) {
+			// Each retried attempt keeps its failed step; the retry gets a new one
+			now := time.Now()
+			step.Status = "failed"
+			step.ErrorMessage = attempt.Err.Error()
+			step.Result = attempt.Result
+			step.CompletedAt = &now
+			step = workflow.AddStep(fmt.Sprintf("Retry %s remediation for %s after %s error (attempt %d of %d)",
+				attempt.Remediator, issue.Type, attempt.Reason, attempt.Attempt+1, attempt.MaxAttempts))
+			o.saveWorkflow(workflow)
+		})
+		result, err = o.remediator.Remediate(remediateCtx, deploymentInfo, issue)
+		if result == nil {
+			result = models.NewRemediationResult(o.remediator.Name())
+		}
+		if err != nil && result.Status != models.RemediationStatusFailed {
+			result.Fail(err, ClassifyError(err))
+		}
+		step.Result = result
+	}
+	// Nothing was changed when the remediator only recommended changes
+	recommended := err == nil && step.Result != nil && step.Result.Status == models.RemediationStatusRecommended
+	unverifiable := errors.Is(resolveErr, ErrVerificationUnsupported)
+	if err == nil && !recommended && unverifiable {
+		// Leave the remediation unverified rather than failing a change that succeeded
+		o.log.WithError(resolveErr).WithField("workflow_id", workflow.ID).Info("Skipping verification of remediated resource")
+		RecordVerification("unsupported", 0)
+	}
+	if err == nil && !recommended && o.verifier != nil && !unverifiable {
+		// The verification step becomes the step finalized below
+		now := time.Now()
+		step.Status = "completed"
+		step.CompletedAt = &now
+		step, err = o.verifyWorkflow(ctx, workflow, verifyTarget, resolveErr, issue)
+	}
 
 	completedTime := time.Now()
 	workflow.CompletedAt = &completedTime
 	duration := completedTime.Sub(startTime).Seconds()
 
-	if err != nil {
+	switch {
+	case err != nil && ctx.Err() != nil:
+		o.log.WithField("workflow_id", workflow.ID).Warn("Remediation cancelled")
+		workflow.Status = models.WorkflowStatusCancelled
+		workflow.ErrorMessage = "workflow cancelled: " + err.Error()
+		step.Status = "cancelled"
+		step.CompletedAt = &completedTime
+
+		RecordRemediation(o.remediator.Name(), string(deploymentInfo.Method), issue.Type, duration, false)
+		RecordWorkflowEnd("cancelled")
+	case errors.Is(err, ErrVerificationFailed):
+		o.log.WithError(err).Warn("Remediation applied but the resource did not recover")
+		workflow.Status = models.WorkflowStatusFailedVerification
+		workflow.ErrorMessage = err.Error()
+		step.Status = "failed"
+		step.ErrorMessage = err.Error()
+		step.CompletedAt = &completedTime
+
+		RecordRemediation(o.remediator.Name(), string(deploymentInfo.Method), issue.Type, duration, false)
+		RecordRemediationFailure(o.remediator.Name(), string(deploymentInfo.Method), issue.Type, "verification_failed")
+		RecordWorkflowEnd(string(models.WorkflowStatusFailedVerification))
+	case recommended:
+		o.log.WithField("workflow_id", workflow.ID).Warn("Remediation produced recommendations instead of changes")
+		workflow.Status = models.WorkflowStatusRecommended
+		workflow.ErrorMessage = fmt.Sprintf("%s remediation recommended manual changes instead of making them", o.remediator.Name())
+		workflow.Recommendations = step.Result.Recommendations
+		step.Status = "recommended"
+		step.CompletedAt = &completedTime
+
+		RecordWorkflowEnd(string(models.WorkflowStatusRecommended))
+	case errors.Is(err, ErrRecommendOnly):
+		o.log.WithError(err).Warn("Resource is recommend-only, escalating with recommendations")
+		workflow.Status = models.WorkflowStatusRecommended
+		workflow.ErrorMessage = err.Error()
+		workflow.Recommendations = o.manualRecommendations(ctx, deploymentInfo, issue,
+			fmt.Sprintf("%s is annotated %s=%s; apply the fix manually", issueResource(issue), AnnotationMode, ModeRecommendOnly))
+		step.Status = "refused"
+		step.ErrorMessage = err.Error()
+		step.CompletedAt = &completedTime
+
+		RecordWorkflowEnd(string(models.WorkflowStatusRecommended))
+	case errors.Is(err, ErrRemediationRefused):
+		o.log.WithError(err).Warn("Remediation refused by guardrails")
+		workflow.Status = models.WorkflowStatusRefused
+		workflow.ErrorMessage = err.Error()
+		step.Status = "refused"
+		step.ErrorMessage = err.Error()
+		step.CompletedAt = &completedTime
+
+		RecordWorkflowEnd(string(models.WorkflowStatusRefused))
+	case err != nil:
 		o.log.WithError(err).Error("Remediation failed")
 		workflow.Status = models.WorkflowStatusFailed
 		workflow.ErrorMessage = err.Error()
@@ -161,7 +1145,7 @@ func (o *Orchestrator) executeWorkflow(ctx context.Context, workflow *models.Wor
 		RecordRemediation(o.remediator.Name(), string(deploymentInfo.Method), issue.Type, duration, false)
 		RecordRemediationFailure(o.remediator.Name(), string(deploymentInfo.Method), issue.Type, "remediation_error")
 		RecordWorkflowEnd("failed")
-	} else {
+	default:
 		o.log.Info("Remediation completed successfully")
 		workflow.Status = models.WorkflowStatusCompleted
 		step.Status = "completed"
@@ -172,6 +1156,9 @@ func (o *Orchestrator) executeWorkflow(ctx context.Context, workflow *models.Wor
 		RecordWorkflowEnd("completed")
 	}
 
+	o.breaker.RecordOutcome(breakerKeyForIssue(issue), workflow.Status)
+	o.recordOutcomeEvent(ctx, workflow, issue)
+
 	// Save final workflow state
 	o.saveWorkflow(workflow)
 
@@ -182,21 +1169,124 @@ func (o *Orchestrator) executeWorkflow(ctx context.Context, workflow *models.Wor
 	}).Info("Workflow execution completed")
 }
 
-// detectDeploymentMethod detects how the resource was deployed
-func (o *Orchestrator) detectDeploymentMethod(ctx context.Context, issue *models.Issue) (*models.DeploymentInfo, error) {
-	// Map issue resource type to Kubernetes kind
-	var kind string
-	switch issue.ResourceType {
-	case "deployment", "Deployment":
-		kind = "Deployment"
-	case "statefulset", "StatefulSet":
-		kind = "StatefulSet"
-	case "daemonset", "DaemonSet":
-		kind = "DaemonSet"
-	case "pod", "Pod":
-		// For pods, we need to find the owner
-		kind = "Deployment" // Default assumption
+// verifyWorkflow adds a verification step and waits for the remediated resource to
+// become healthy. It returns the step and an error wrapping ErrVerificationFailed
+// if the resource did not recover.
+func (o *Orchestrator) verifyWorkflow(ctx context.Context, workflow *models.Workflow, target *VerificationTarget, resolveErr error, issue *models.Issue) (*models.WorkflowStep, error) {
+	description := fmt.Sprintf("Verify %s/%s recovered from %s", issue.Namespace, issue.ResourceName, issue.Type)
+	if target != nil {
+		description = fmt.Sprintf("Verify %s recovered from %s", target, issue.Type)
+	}
+	step := workflow.AddStep(description)
+	index := step.Order
+	startedAt := time.Now()
+	step.Status = "running"
+	step.StartedAt = &startedAt
+	o.saveWorkflow(workflow)
+
+	err := resolveErr
+	if err != nil {
+		err = fmt.Errorf("%w: %w", ErrVerificationFailed, err)
+	} else {
+		err = o.verifier.Verify(ctx, target, issue.Type)
+	}
+
+	result := "verified"
+	switch {
+	case errors.Is(err, ErrVerificationFailed):
+		result = "failed"
+	case err != nil:
+		result = "cancelled"
+	}
+	RecordVerification(result, time.Since(startedAt).Seconds())
+
+	return &workflow.Steps[index], err
+}
+
+// recordWorkflowEvent records a workflow event on the issue's resource
+func (o *Orchestrator) recordWorkflowEvent(ctx context.Context, issue *models.Issue, event RemediationEvent) {
+	event.Target = workloadRef(issue.ResourceType, issue.Namespace, issue.ResourceName)
+	event.Remediator = o.remediator.Name()
+	event.Action = "remediate"
+	recordEvent(ctx, o.recorder, event)
+}
+
+// recordOutcomeEvent records how a finished workflow ended on the issue's resource
+func (o *Orchestrator) recordOutcomeEvent(ctx context.Context, workflow *models.Workflow, issue *models.Issue) {
+	// The workflow context may be cancelled; the event must still be recorded
+	ctx = context.WithoutCancel(ctx)
+
+	switch workflow.Status {
+	case models.WorkflowStatusCompleted:
+		o.recordWorkflowEvent(ctx, issue, RemediationEvent{
+			Reason:  EventReasonRemediationSucceeded,
+			Message: fmt.Sprintf("Remediated %s", issue.Type),
+		})
+	case models.WorkflowStatusFailedVerification:
+		o.recordWorkflowEvent(ctx, issue, RemediationEvent{
+			Type:    corev1.EventTypeWarning,
+			Reason:  EventReasonVerificationFailed,
+			Message: fmt.Sprintf("Remediated %s but the resource did not recover: %s", issue.Type, workflow.ErrorMessage),
+		})
+	case models.WorkflowStatusRefused, models.WorkflowStatusRecommended:
+		o.recordWorkflowEvent(ctx, issue, RemediationEvent{
+			Type:    corev1.EventTypeWarning,
+			Reason:  EventReasonRemediationRefused,
+			Message: fmt.Sprintf("Remediation of %s refused: %s", issue.Type, workflow.ErrorMessage),
+		})
+	case models.WorkflowStatusCancelled:
+		o.recordWorkflowEvent(ctx, issue, RemediationEvent{
+			Type:    corev1.EventTypeWarning,
+			Reason:  EventReasonRemediationFailed,
+			Message: fmt.Sprintf("Remediation of %s cancelled", issue.Type),
+		})
 	default:
+		o.recordWorkflowEvent(ctx, issue, RemediationEvent{
+			Type:    corev1.EventTypeWarning,
+			Reason:  EventReasonRemediationFailed,
+			Message: fmt.Sprintf("Remediation of %s failed: %s", issue.Type, workflow.ErrorMessage),
+		})
+	}
+}
+
+// acquireResourceLock takes the lock for the issue's resource. If another workflow or
+// plan holds it, a workflow step records the wait until the lock is released.
+func (o *Orchestrator) acquireResourceLock(ctx context.Context, workflow *models.Workflow, issue *models.Issue) (*ResourceLease, error) {
+	if o.locks == nil {
+		return nil, nil
+	}
+
+	ref := ResourceRef{Namespace: issue.Namespace, Kind: issue.ResourceType, Name: issue.ResourceName}
+	lease, holder := o.locks.TryAcquire(ctx, ref, workflow.ID)
+	if lease != nil {
+		return lease, nil
+	}
+
+	waitStep := workflow.AddStep(fmt.Sprintf("Wait for lock on %s held by %s", ref, holder))
+	waitIndex := waitStep.Order
+	startedAt := time.Now()
+	waitStep.Status = "running"
+	waitStep.StartedAt = &startedAt
+	o.saveWorkflow(workflow)
+
+	lease, err := o.locks.Acquire(ctx, ref, workflow.ID)
+
+	completedAt := time.Now()
+	workflow.Steps[waitIndex].CompletedAt = &completedAt
+	if err != nil {
+		workflow.Steps[waitIndex].Status = "failed"
+		workflow.Steps[waitIndex].ErrorMessage = err.Error()
+		return nil, err
+	}
+	workflow.Steps[waitIndex].Status = "completed"
+	return lease, nil
+}
+
+// detectDeploymentMethod detects how the resource was deployed. Pods and other
+// owned resources are resolved to their top-level controller.
+func (o *Orchestrator) detectDeploymentMethod(ctx context.Context, issue *models.Issue) (*models.DeploymentInfo, error) {
+	kind := NormalizeKind(issue.ResourceType)
+	if kind == "" {
 		kind = "Deployment"
 	}
 
@@ -207,6 +1297,45 @@ func (o *Orchestrator) detectDeploymentMethod(ctx context.Context, issue *models
 	return deploymentInfo, nil
 }
 
+// remediableControllers are the top-level controller kinds that pod-level issues
+// are retargeted to
+var remediableControllers = map[string]bool{
+	"Deployment":       true,
+	"StatefulSet":      true,
+	"DaemonSet":        true,
+	"DeploymentConfig": true,
+	"Rollout":          true,
+}
+
+// controllerIssue returns a copy of the issue targeting the top-level controller
+// resolved during detection, or the issue itself if it already targets it or the
+// controller cannot be remediated
+func controllerIssue(issue *models.Issue, info *models.DeploymentInfo) *models.Issue {
+	kind := info.GetDetail(detector.DetailControllerKind)
+	name := info.GetDetail(detector.DetailControllerName)
+	if !remediableControllers[kind] || name == "" {
+		return issue
+	}
+	if NormalizeKind(issue.ResourceType) == kind && issue.ResourceName == name {
+		return issue
+	}
+
+	retargeted := *issue
+	retargeted.ResourceType = kind
+	retargeted.ResourceName = name
+	return &retargeted
+}
+
+// releaseActive cancels and forgets the execution context of a finished workflow
+func (o *Orchestrator) releaseActive(workflowID string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if aw, ok := o.active[workflowID]; ok {
+		aw.cancel()
+		delete(o.active, workflowID)
+	}
+}
+
 // updateWorkflowStatus updates the workflow status
 func (o *Orchestrator) updateWorkflowStatus(workflow *models.Workflow, status models.WorkflowStatus) {
 	o.mu.Lock()
@@ -214,11 +1343,103 @@ func (o *Orchestrator) updateWorkflowStatus(workflow *models.Workflow, status mo
 	workflow.Status = status
 }
 
-// saveWorkflow persists workflow state
+// saveWorkflow persists workflow state. Once the workflow reaches a final status, the
+// incidents it remediates are updated.
 func (o *Orchestrator) saveWorkflow(workflow *models.Workflow) {
+	if err := o.persistWorkflow(context.Background(), workflow); err != nil {
+		o.log.WithError(err).WithField("workflow_id", workflow.ID).Error("Failed to persist workflow state")
+	}
+}
+
+// persistWorkflow saves the workflow, publishes its progress and reports finished
+// workflows to their incidents. The save error is returned.
+func (o *Orchestrator) persistWorkflow(ctx context.Context, workflow *models.Workflow) error {
 	o.mu.Lock()
-	defer o.mu.Unlock()
-	o.workflows[workflow.ID] = workflow
+	err := o.store.Save(ctx, workflow)
+	aw := o.active[workflow.ID]
+	progress := o.progressEvents(workflow, aw)
+	var incidentIDs []string
+	if !workflow.IsActive() {
+		incidentIDs = append(incidentIDs, workflow.IncidentID)
+		if aw != nil {
+			incidentIDs = append(incidentIDs, aw.linkedIncidents...)
+		}
+	}
+	o.mu.Unlock()
+
+	o.publish(progress)
+	if o.incidents != nil {
+		for _, incidentID := range incidentIDs {
+			o.incidents.WorkflowFinished(ctx, incidentID, workflow.ID, string(workflow.Status))
+		}
+	}
+	return err
+}
+
+// progressEvents returns the step additions and status change of a workflow not yet
+// published. Callers must hold o.mu; aw may be nil for workflows no longer tracked.
+func (o *Orchestrator) progressEvents(workflow *models.Workflow, aw *activeWorkflow) []events.Event {
+	if o.events == nil {
+		return nil
+	}
+
+	var progress []events.Event
+	publishedSteps := 0
+	if aw != nil {
+		publishedSteps = aw.publishedSteps
+		aw.publishedSteps = len(workflow.Steps)
+	}
+	for _, step := range workflow.Steps[min(publishedSteps, len(workflow.Steps)):] {
+		progress = append(progress, events.Event{
+			Type:       events.TypeStepAdded,
+			Source:     events.SourceRemediation,
+			WorkflowID: workflow.ID,
+			Message:    step.Description,
+			Details:    map[string]string{"order": strconv.Itoa(step.Order)},
+		})
+	}
+
+	if aw == nil || aw.publishedStatus != workflow.Status {
+		if aw != nil {
+			aw.publishedStatus = workflow.Status
+		}
+		progress = append(progress, events.Event{
+			Type:       events.TypeStatusChanged,
+			Source:     events.SourceRemediation,
+			WorkflowID: workflow.ID,
+			IncidentID: workflow.IncidentID,
+			Namespace:  workflow.Namespace,
+			Severity:   workflow.Severity,
+			Status:     string(workflow.Status),
+			Message:    workflow.ErrorMessage,
+			Details: map[string]string{
+				"remediator":        workflow.Remediator,
+				"deployment_method": workflow.DeploymentMethod,
+				"resource_kind":     workflow.ResourceKind,
+				"resource_name":     workflow.ResourceName,
+				"issue_type":        workflow.IssueType,
+			},
+			Final: !workflow.IsActive(),
+		})
+	}
+	return progress
+}
+
+// publish sends events to the event bus in order
+func (o *Orchestrator) publish(progress []events.Event) {
+	for _, event := range progress {
+		o.events.Publish(event)
+	}
+}
+
+// appendIfMissing appends value unless values already contains it
+func appendIfMissing(values []string, value string) []string {
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+	return append(values, value)
 }
 
 // generateWorkflowID generates a unique workflow ID
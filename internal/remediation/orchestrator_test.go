@@ -0,0 +1,313 @@
+package remediation
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/detector"
+	"github.com/tosin2013/openshift-coordination-engine/internal/events"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// blockingRemediator blocks until its context is cancelled or release is closed
+type blockingRemediator struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func newBlockingRemediator() *blockingRemediator {
+	return &blockingRemediator{
+		started: make(chan struct{}, 1),
+		release: make(chan struct{}),
+	}
+}
+
+func (b *blockingRemediator) Remediate(ctx context.Context, _ *models.DeploymentInfo, _ *models.Issue) (*models.RemediationResult, error) {
+	b.started <- struct{}{}
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-b.release:
+		return nil, nil
+	}
+}
+
+func (b *blockingRemediator) PlanActions(_ context.Context, _ *models.DeploymentInfo, _ *models.Issue) ([]PlannedAction, error) {
+	return []PlannedAction{{Action: "block", Target: "test"}}, nil
+}
+
+func (b *blockingRemediator) CanRemediate(_ *models.DeploymentInfo) bool { return true }
+
+func (b *blockingRemediator) Name() string { return "blocking" }
+
+func newTestOrchestrator(remediator Remediator) *Orchestrator {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	det := detector.NewDetector(fake.NewSimpleClientset(), log)
+	return NewOrchestrator(det, remediator, log)
+}
+
+func newTestIssue(id string) *models.Issue {
+	return &models.Issue{
+		ID:           id,
+		Type:         "CrashLoopBackOff",
+		Severity:     "high",
+		Namespace:    "default",
+		ResourceType: "Deployment",
+		ResourceName: "payment",
+		DetectedAt:   time.Now(),
+	}
+}
+
+// waitForStatus polls the orchestrator until the workflow reaches the given status
+func waitForStatus(t *testing.T, o *Orchestrator, workflowID string, status models.WorkflowStatus) *models.Workflow {
+	t.Helper()
+	var wf *models.Workflow
+	require.Eventually(t, func() bool {
+		var err error
+		wf, err = o.GetWorkflow(workflowID)
+		return err == nil && wf.Status == status
+	}, 2*time.Second, 10*time.Millisecond)
+	return wf
+}
+
+func TestOrchestrator_CancelWorkflow(t *testing.T) {
+	remediator := newBlockingRemediator()
+	o := newTestOrchestrator(remediator)
+
+	wf, _, err := o.TriggerRemediation(context.Background(), "inc-1", newTestIssue("inc-1"))
+	require.NoError(t, err)
+	<-remediator.started
+
+	require.NoError(t, o.CancelWorkflow(wf.ID))
+
+	cancelled := waitForStatus(t, o, wf.ID, models.WorkflowStatusCancelled)
+	assert.Contains(t, cancelled.ErrorMessage, "cancelled")
+	assert.Equal(t, "cancelled", cancelled.Steps[len(cancelled.Steps)-1].Status)
+
+	// Cancelling a finished workflow is rejected
+	err = o.CancelWorkflow(wf.ID)
+	assert.True(t, errors.Is(err, ErrWorkflowNotActive))
+}
+
+func TestOrchestrator_CancelWorkflow_NotFound(t *testing.T) {
+	o := newTestOrchestrator(newBlockingRemediator())
+
+	err := o.CancelWorkflow("wf-missing")
+	assert.True(t, errors.Is(err, ErrWorkflowNotFound))
+}
+
+func TestOrchestrator_TriggerRemediation_Deduplicates(t *testing.T) {
+	remediator := newBlockingRemediator()
+	defer close(remediator.release)
+	o := newTestOrchestrator(remediator)
+	ctx := context.Background()
+
+	first, created, err := o.TriggerRemediation(ctx, "inc-1", newTestIssue("inc-1"))
+	require.NoError(t, err)
+	assert.True(t, created)
+	<-remediator.started
+
+	// Same incident ID returns the active workflow
+	again, created, err := o.TriggerRemediation(ctx, "inc-1", newTestIssue("inc-1"))
+	require.NoError(t, err)
+	assert.False(t, created)
+	assert.Equal(t, first.ID, again.ID)
+
+	// Different incident for the same resource inside the window is also deduplicated
+	sameResource, created, err := o.TriggerRemediation(ctx, "inc-2", newTestIssue("inc-2"))
+	require.NoError(t, err)
+	assert.False(t, created)
+	assert.Equal(t, first.ID, sameResource.ID)
+
+	// A different resource starts a new workflow
+	other := newTestIssue("inc-3")
+	other.ResourceName = "checkout"
+	otherWf, created, err := o.TriggerRemediation(ctx, "inc-3", other)
+	require.NoError(t, err)
+	assert.True(t, created)
+	assert.NotEqual(t, first.ID, otherWf.ID)
+	<-remediator.started
+}
+
+func TestOrchestrator_TriggerRemediation_DedupWindowDisabled(t *testing.T) {
+	remediator := newBlockingRemediator()
+	defer close(remediator.release)
+	o := newTestOrchestrator(remediator)
+	o.SetDedupWindow(0)
+	ctx := context.Background()
+
+	first, _, err := o.TriggerRemediation(ctx, "inc-1", newTestIssue("inc-1"))
+	require.NoError(t, err)
+	<-remediator.started
+
+	second, created, err := o.TriggerRemediation(ctx, "inc-2", newTestIssue("inc-2"))
+	require.NoError(t, err)
+	assert.True(t, created)
+	assert.NotEqual(t, first.ID, second.ID)
+	<-remediator.started
+}
+
+func TestOrchestrator_DryRun(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	clientset := fake.NewSimpleClientset(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "payment-abc", Namespace: "default"},
+	})
+
+	selector := NewStrategySelector(log)
+	selector.SetFallbackRemediator(NewManualRemediator(clientset, log))
+	o := NewOrchestrator(detector.NewDetector(clientset, log), selector, log)
+
+	issue := newTestIssue("inc-1")
+	issue.ResourceType = "pod"
+	issue.ResourceName = "payment-abc"
+
+	result, err := o.DryRun(context.Background(), issue)
+	require.NoError(t, err)
+	assert.Equal(t, "manual", result.Remediator)
+	require.Len(t, result.PlannedActions, 1)
+	assert.Equal(t, "delete_pod", result.PlannedActions[0].Action)
+	assert.Equal(t, []DryRunGate{
+		{Gate: DryRunGateCircuitBreaker, Allowed: true},
+		{Gate: DryRunGateMaintenance, Allowed: true},
+		{Gate: DryRunGateGuardrails, Allowed: true},
+	}, result.Gates)
+
+	// No workflow is created and the pod is left alone
+	assert.Empty(t, o.ListWorkflows())
+	_, err = clientset.CoreV1().Pods("default").Get(context.Background(), "payment-abc", metav1.GetOptions{})
+	assert.NoError(t, err)
+}
+
+func TestOrchestrator_DryRunGates(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	clientset := fake.NewSimpleClientset(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "payment-abc", Namespace: "default"},
+	})
+
+	selector := NewStrategySelector(log)
+	selector.SetFallbackRemediator(NewManualRemediator(clientset, log))
+	selector.SetGuardrails(NewGuardrails(nil, []string{"default"}, clientset, log))
+	o := NewOrchestrator(detector.NewDetector(clientset, log), selector, log)
+	maintenance := NewMaintenancePolicy(nil, clientset, log)
+	_, err := maintenance.SetFreeze(MaintenanceActionRecommend, "release day", "ops", nil)
+	require.NoError(t, err)
+	o.SetMaintenancePolicy(maintenance)
+	breaker := NewCircuitBreaker(2, time.Hour, time.Hour, log)
+	o.SetCircuitBreaker(breaker)
+
+	// A trigger would count the completed remediation as ineffective and open the breaker
+	issue := newTestIssue("inc-1")
+	issue.ResourceType = "pod"
+	issue.ResourceName = "payment-abc"
+	key := breakerKeyForIssue(issue)
+	breaker.RecordOutcome(key, models.WorkflowStatusFailed)
+	breaker.RecordOutcome(key, models.WorkflowStatusCompleted)
+
+	result, err := o.DryRun(context.Background(), issue)
+	require.NoError(t, err)
+	require.Len(t, result.Gates, 3)
+
+	assert.Equal(t, DryRunGateCircuitBreaker, result.Gates[0].Gate)
+	assert.False(t, result.Gates[0].Allowed)
+	assert.Contains(t, result.Gates[0].Reason, "2 failed or ineffective remediations")
+
+	assert.Equal(t, DryRunGate{Gate: DryRunGateMaintenance, Action: MaintenanceActionRecommend,
+		Reason: "global remediation freeze is on: release day"}, result.Gates[1])
+
+	assert.Equal(t, DryRunGateGuardrails, result.Gates[2].Gate)
+	assert.False(t, result.Gates[2].Allowed)
+	assert.Contains(t, result.Gates[2].Reason, "default")
+
+	// The dry run neither opened the breaker nor created a workflow
+	assert.Equal(t, BreakerStateClosed, breaker.Status()[0].State)
+	assert.Empty(t, o.ListWorkflows())
+}
+
+func TestOrchestrator_RetargetsPodIssueToController(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	isController := true
+	clientset := fake.NewSimpleClientset(
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "payment", Namespace: "default"}},
+		&appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{
+			Name: "payment-7d9f", Namespace: "default",
+			OwnerReferences: []metav1.OwnerReference{{Kind: "Deployment", Name: "payment", Controller: &isController}},
+		}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name: "payment-7d9f-abc12", Namespace: "default",
+			OwnerReferences: []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "payment-7d9f", Controller: &isController}},
+		}},
+	)
+
+	selector := NewStrategySelector(log)
+	selector.SetFallbackRemediator(NewManualRemediator(clientset, log))
+	o := NewOrchestrator(detector.NewDetector(clientset, log), selector, log)
+
+	issue := newTestIssue("inc-1")
+	issue.ResourceType = "pod"
+	issue.ResourceName = "payment-7d9f-abc12"
+
+	result, err := o.DryRun(context.Background(), issue)
+	require.NoError(t, err)
+	assert.Equal(t, "manual", result.DeploymentMethod)
+	require.Len(t, result.PlannedActions, 1)
+	assert.Equal(t, "restart_deployment", result.PlannedActions[0].Action)
+	assert.Contains(t, result.PlannedActions[0].Target, "payment")
+	assert.NotContains(t, result.PlannedActions[0].Target, "abc12")
+
+	// The caller's issue is left unchanged
+	assert.Equal(t, "payment-7d9f-abc12", issue.ResourceName)
+}
+
+func TestOrchestrator_PublishesProgressEvents(t *testing.T) {
+	remediator := newBlockingRemediator()
+	o := newTestOrchestrator(remediator)
+	bus := events.NewBus(o.log)
+	o.SetEventBus(bus)
+	sub := bus.Subscribe(nil)
+	defer sub.Close()
+
+	wf, _, err := o.TriggerRemediation(context.Background(), "inc-1", newTestIssue("inc-1"))
+	require.NoError(t, err)
+	<-remediator.started
+	close(remediator.release)
+
+	var received []events.Event
+	for event := range sub.C {
+		assert.Equal(t, wf.ID, event.WorkflowID)
+		received = append(received, event)
+		if event.Final {
+			break
+		}
+	}
+
+	var statuses []models.WorkflowStatus
+	steps := 0
+	for _, event := range received {
+		switch event.Type {
+		case events.TypeStatusChanged:
+			statuses = append(statuses, models.WorkflowStatus(event.Status))
+		case events.TypeStepAdded:
+			steps++
+		}
+	}
+	assert.Equal(t, []models.WorkflowStatus{
+		models.WorkflowStatusPending,
+		models.WorkflowStatusRunning,
+		models.WorkflowStatusCompleted,
+	}, statuses)
+	assert.Equal(t, 2, steps)
+}
@@ -0,0 +1,645 @@
+package remediation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/yaml"
+
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// Plugin endpoints, relative to the plugin URL
+const (
+	PluginPathCanRemediate = "/can-remediate" // POST PluginRequest, returns PluginCanRemediateResponse
+	PluginPathRemediate    = "/remediate"     // POST PluginRequest, returns a models.RemediationResult
+	PluginPathHealth       = "/healthz"       // GET, any 2xx status is healthy
+)
+
+// PluginAction is the action planned for every plugin remediation, in the "plugin"
+// category of the allowed-actions annotation
+const PluginAction = "plugin_remediate"
+
+// Plugin defaults
+const (
+	DefaultPluginTimeout        = 10 * time.Second
+	DefaultPluginHealthInterval = 30 * time.Second
+	maxPluginResponseBytes      = 1 << 20
+)
+
+// Plugin health states
+const (
+	PluginHealthUnknown   = "unknown" // not checked yet; the plugin is still offered issues
+	PluginHealthHealthy   = "healthy"
+	PluginHealthUnhealthy = "unhealthy"
+)
+
+// PluginConfig declares an external remediator called over HTTP
+type PluginConfig struct {
+	Name    string      `json:"name"`
+	URL     string      `json:"url"`
+	Match   PluginMatch `json:"match,omitempty"`
+	Timeout string      `json:"timeout,omitempty"` // per request, e.g. 30s (default 10s)
+	Auth    PluginAuth  `json:"auth,omitempty"`
+
+	timeout time.Duration
+}
+
+// PluginMatch holds the issues a plugin is offered: the conditions of a policy rule,
+// namespaces and a selector on the remediated resource's labels. Empty conditions
+// match anything.
+type PluginMatch struct {
+	PolicyMatch
+	Namespaces    []string `json:"namespaces,omitempty"`     // exact names or path.Match globs
+	LabelSelector string   `json:"label_selector,omitempty"` // matched against the resource's labels
+
+	labelSelector labels.Selector
+}
+
+// PluginAuth authenticates requests to a plugin. Tokens come from the environment
+// or a file, such as a mounted Secret, so they stay out of the plugin list; the
+// file is read on every request so rotated tokens apply.
+type PluginAuth struct {
+	BearerTokenEnv  string            `json:"bearer_token_env,omitempty"`
+	BearerTokenFile string            `json:"bearer_token_file,omitempty"`
+	Headers         map[string]string `json:"headers,omitempty"`
+
+	token string
+}
+
+// PluginRequest is the body sent to the can-remediate and remediate endpoints
+type PluginRequest struct {
+	Version        string                 `json:"version"` // models.RemediationResultVersion
+	Plugin         string                 `json:"plugin"`
+	DeploymentInfo *models.DeploymentInfo `json:"deployment_info"`
+	Issue          *models.Issue          `json:"issue"`
+}
+
+// PluginCanRemediateResponse is the answer of the can-remediate endpoint
+type PluginCanRemediateResponse struct {
+	CanRemediate bool   `json:"can_remediate"`
+	Reason       string `json:"reason,omitempty"`
+}
+
+// pluginFile is the document read by LoadPlugins
+type pluginFile struct {
+	Plugins []PluginConfig `json:"plugins"`
+}
+
+// LoadPlugins reads plugin definitions from a YAML or JSON file
+func LoadPlugins(filename string) ([]PluginConfig, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plugin config: %w", err)
+	}
+
+	var file pluginFile
+	if err := yaml.UnmarshalStrict(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse plugin config %s: %w", filename, err)
+	}
+
+	names := make(map[string]bool, len(file.Plugins))
+	for i := range file.Plugins {
+		plugin := &file.Plugins[i]
+		if err := plugin.Validate(); err != nil {
+			return nil, fmt.Errorf("plugin %d: %w", i, err)
+		}
+		if names[plugin.Name] {
+			return nil, fmt.Errorf("duplicate plugin %s", plugin.Name)
+		}
+		names[plugin.Name] = true
+	}
+	return file.Plugins, nil
+}
+
+// Validate checks the plugin definition and prepares it for use
+func (p *PluginConfig) Validate() error {
+	if p.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	parsed, err := url.Parse(p.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("%s: url must be an absolute http or https URL", p.Name)
+	}
+
+	p.timeout = DefaultPluginTimeout
+	if p.Timeout != "" {
+		timeout, err := time.ParseDuration(p.Timeout)
+		if err != nil || timeout <= 0 {
+			return fmt.Errorf("%s: timeout must be a positive duration", p.Name)
+		}
+		p.timeout = timeout
+	}
+
+	if err := p.Match.parse(); err != nil {
+		return fmt.Errorf("%s: %w", p.Name, err)
+	}
+	for _, pattern := range p.Match.Namespaces {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("%s: invalid namespace pattern %q", p.Name, pattern)
+		}
+	}
+	if p.Match.LabelSelector != "" {
+		selector, err := labels.Parse(p.Match.LabelSelector)
+		if err != nil {
+			return fmt.Errorf("%s: invalid label_selector: %w", p.Name, err)
+		}
+		p.Match.labelSelector = selector
+	}
+
+	if p.Auth.BearerTokenEnv != "" && p.Auth.BearerTokenFile != "" {
+		return fmt.Errorf("%s: bearer_token_env and bearer_token_file are mutually exclusive", p.Name)
+	}
+	if p.Auth.BearerTokenEnv != "" {
+		p.Auth.token = os.Getenv(p.Auth.BearerTokenEnv)
+		if p.Auth.token == "" {
+			return fmt.Errorf("%s: environment variable %s is not set", p.Name, p.Auth.BearerTokenEnv)
+		}
+	}
+	return nil
+}
+
+// apply sets the authentication headers of a plugin request
+func (a *PluginAuth) apply(req *http.Request) error {
+	for key, value := range a.Headers {
+		req.Header.Set(key, value)
+	}
+	token := a.token
+	if a.BearerTokenFile != "" {
+		data, err := os.ReadFile(a.BearerTokenFile)
+		if err != nil {
+			return fmt.Errorf("failed to read bearer token: %w", err)
+		}
+		token = strings.TrimSpace(string(data))
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return nil
+}
+
+// WebhookRemediator delegates remediation to an external HTTP endpoint, which
+// decides which issues it takes and returns a structured result
+type WebhookRemediator struct {
+	config    PluginConfig
+	client    *http.Client
+	clientset kubernetes.Interface // reads labels for selectors; nil never matches them
+	log       *logrus.Logger
+}
+
+// NewWebhookRemediator creates a remediator for a validated plugin definition
+func NewWebhookRemediator(config PluginConfig, clientset kubernetes.Interface, log *logrus.Logger) *WebhookRemediator {
+	if config.timeout <= 0 {
+		config.timeout = DefaultPluginTimeout
+	}
+	return &WebhookRemediator{
+		config:    config,
+		client:    &http.Client{},
+		clientset: clientset,
+		log:       log,
+	}
+}
+
+// Config returns the plugin definition
+func (wr *WebhookRemediator) Config() PluginConfig {
+	return wr.config
+}
+
+// Accepts reports whether the plugin takes the issue: it must match the plugin's
+// conditions, and the plugin's can-remediate endpoint must accept it
+func (wr *WebhookRemediator) Accepts(ctx context.Context, deploymentInfo *models.DeploymentInfo, issue *models.Issue) (bool, error) {
+	if !wr.matches(ctx, deploymentInfo, issue) {
+		return false, nil
+	}
+
+	var response PluginCanRemediateResponse
+	if err := wr.call(ctx, PluginPathCanRemediate, wr.request(deploymentInfo, issue), &response); err != nil {
+		return false, err
+	}
+	wr.log.WithFields(logrus.Fields{
+		"plugin":        wr.config.Name,
+		"issue_id":      issue.ID,
+		"can_remediate": response.CanRemediate,
+		"reason":        response.Reason,
+	}).Debug("Remediation plugin answered")
+	return response.CanRemediate, nil
+}
+
+// matches reports whether the issue meets the plugin's conditions
+func (wr *WebhookRemediator) matches(ctx context.Context, deploymentInfo *models.DeploymentInfo, issue *models.Issue) bool {
+	match := &wr.config.Match
+	if len(match.Namespaces) > 0 && !matchesNamespace(match.Namespaces, issue.Namespace) {
+		return false
+	}
+	namespaceLabels := func() labels.Set {
+		return wr.labels(ctx, "Namespace", "", issue.Namespace)
+	}
+	if !match.matches(deploymentInfo, issue, namespaceLabels) {
+		return false
+	}
+	if match.labelSelector != nil &&
+		!match.labelSelector.Matches(wr.labels(ctx, issue.ResourceType, issue.Namespace, issue.ResourceName)) {
+		return false
+	}
+	return true
+}
+
+// labels returns the labels of a namespace or workload, or none if they cannot be read
+func (wr *WebhookRemediator) labels(ctx context.Context, kind, namespace, name string) labels.Set {
+	if wr.clientset == nil {
+		return labels.Set{}
+	}
+
+	var meta metav1.Object
+	var err error
+	switch NormalizeKind(kind) {
+	case "Namespace":
+		meta, err = wr.clientset.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+	case "Deployment":
+		meta, err = wr.clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+	case "StatefulSet":
+		meta, err = wr.clientset.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+	case "DaemonSet":
+		meta, err = wr.clientset.AppsV1().DaemonSets(namespace).Get(ctx, name, metav1.GetOptions{})
+	case "Pod":
+		meta, err = wr.clientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+	default:
+		return labels.Set{}
+	}
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			wr.log.WithError(err).WithFields(logrus.Fields{
+				"plugin":    wr.config.Name,
+				"namespace": namespace,
+				"name":      name,
+			}).Warn("Failed to read labels for remediation plugin match")
+		}
+		return labels.Set{}
+	}
+	return labels.Set(meta.GetLabels())
+}
+
+// Remediate sends the issue to the plugin's remediate endpoint and returns its result
+func (wr *WebhookRemediator) Remediate(ctx context.Context, deploymentInfo *models.DeploymentInfo, issue *models.Issue) (*models.RemediationResult, error) {
+	wr.log.WithFields(logrus.Fields{
+		"plugin":     wr.config.Name,
+		"issue_id":   issue.ID,
+		"issue_type": issue.Type,
+		"namespace":  issue.Namespace,
+		"resource":   issue.ResourceName,
+	}).Info("Calling remediation plugin")
+
+	var response models.RemediationResult
+	if err := wr.call(ctx, PluginPathRemediate, wr.request(deploymentInfo, issue), &response); err != nil {
+		return models.NewRemediationResult(wr.Name()), err
+	}
+	if response.Version != models.RemediationResultVersion {
+		return models.NewRemediationResult(wr.Name()), Permanent(fmt.Errorf("plugin %s returned result version %q, expected %s",
+			wr.config.Name, response.Version, models.RemediationResultVersion))
+	}
+	switch response.Status {
+	case models.RemediationStatusChanged, models.RemediationStatusNoOp, models.RemediationStatusRecommended, models.RemediationStatusFailed:
+	default:
+		return models.NewRemediationResult(wr.Name()), Permanent(fmt.Errorf("plugin %s returned invalid status %q", wr.config.Name, response.Status))
+	}
+	response.Method = wr.Name()
+	response.Duration = ""
+
+	if response.Status == models.RemediationStatusFailed {
+		err := fmt.Errorf("plugin %s failed: %s", wr.config.Name, response.Error)
+		if response.ErrorClass == models.ErrorClassPermanent {
+			err = Permanent(err)
+		}
+		return &response, err
+	}
+	return &response, nil
+}
+
+// PlanActions describes the plugin call; what the plugin changes is up to the plugin
+func (wr *WebhookRemediator) PlanActions(_ context.Context, _ *models.DeploymentInfo, issue *models.Issue) ([]PlannedAction, error) {
+	return []PlannedAction{{
+		Action:      PluginAction,
+		Target:      issueResource(issue),
+		Description: fmt.Sprintf("Call remediation plugin %s for %s", wr.config.Name, issue.Type),
+	}}, nil
+}
+
+// CanRemediate returns true if the deployment method meets the plugin's conditions;
+// the issue-level conditions and the plugin's own answer are checked by Accepts
+func (wr *WebhookRemediator) CanRemediate(deploymentInfo *models.DeploymentInfo) bool {
+	methods := wr.config.Match.DeploymentMethods
+	return len(methods) == 0 || (deploymentInfo != nil && containsFold(methods, string(deploymentInfo.Method)))
+}
+
+// Name returns the remediator name
+func (wr *WebhookRemediator) Name() string {
+	return "plugin:" + wr.config.Name
+}
+
+// CheckHealth calls the plugin's health endpoint
+func (wr *WebhookRemediator) CheckHealth(ctx context.Context) error {
+	return wr.call(ctx, PluginPathHealth, nil, nil)
+}
+
+// request builds the body of a can-remediate or remediate request
+func (wr *WebhookRemediator) request(deploymentInfo *models.DeploymentInfo, issue *models.Issue) *PluginRequest {
+	return &PluginRequest{
+		Version:        models.RemediationResultVersion,
+		Plugin:         wr.config.Name,
+		DeploymentInfo: deploymentInfo,
+		Issue:          issue,
+	}
+}
+
+// call sends a request to a plugin endpoint within the plugin timeout and decodes
+// the response into out. Client errors other than timeouts and rate limiting are
+// permanent; anything else may succeed on retry.
+func (wr *WebhookRemediator) call(ctx context.Context, endpoint string, body, out interface{}) (err error) {
+	defer func() {
+		result := "success"
+		if err != nil {
+			result = "failed"
+		}
+		RecordPluginRequest(wr.config.Name, endpoint, result)
+	}()
+
+	ctx, cancel := context.WithTimeout(ctx, wr.config.timeout)
+	defer cancel()
+
+	method := http.MethodGet
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return Permanent(fmt.Errorf("failed to encode plugin request: %w", err))
+		}
+		method = http.MethodPost
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(wr.config.URL, "/")+endpoint, reader)
+	if err != nil {
+		return Permanent(fmt.Errorf("failed to create plugin request: %w", err))
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if err := wr.config.Auth.apply(req); err != nil {
+		return fmt.Errorf("plugin %s: %w", wr.config.Name, err)
+	}
+
+	resp, err := wr.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("plugin %s %s request failed: %w", wr.config.Name, endpoint, err)
+	}
+	defer resp.Body.Close()
+
+	payload, err := io.ReadAll(io.LimitReader(resp.Body, maxPluginResponseBytes))
+	if err != nil {
+		return fmt.Errorf("failed to read plugin %s response: %w", wr.config.Name, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		err := &pluginStatusError{
+			code:    resp.StatusCode,
+			message: fmt.Sprintf("plugin %s %s returned %s: %s", wr.config.Name, endpoint, resp.Status, strings.TrimSpace(string(payload))),
+		}
+		if resp.StatusCode >= 400 && resp.StatusCode < 500 &&
+			resp.StatusCode != http.StatusRequestTimeout && resp.StatusCode != http.StatusTooManyRequests {
+			return Permanent(err)
+		}
+		return err
+	}
+	if out != nil {
+		if err := json.Unmarshal(payload, out); err != nil {
+			return Permanent(fmt.Errorf("plugin %s %s returned an invalid response: %w", wr.config.Name, endpoint, err))
+		}
+	}
+	return nil
+}
+
+// pluginStatusError is a plugin response with a non-2xx status
+type pluginStatusError struct {
+	code    int
+	message string
+}
+
+// Error returns the plugin, endpoint, status and response body
+func (e *pluginStatusError) Error() string {
+	return e.message
+}
+
+// HTTPStatusCode returns the status the plugin answered with
+func (e *pluginStatusError) HTTPStatusCode() int {
+	return e.code
+}
+
+// PluginStatus reports a plugin's definition and its last health check
+type PluginStatus struct {
+	Name        string      `json:"name"`
+	URL         string      `json:"url"`
+	Match       PluginMatch `json:"match"`
+	Timeout     string      `json:"timeout"`
+	Health      string      `json:"health"` // unknown, healthy or unhealthy
+	LastChecked *time.Time  `json:"last_checked,omitempty"`
+	LastError   string      `json:"last_error,omitempty"`
+}
+
+// registeredPlugin is a plugin and its health
+type registeredPlugin struct {
+	remediator  *WebhookRemediator
+	health      string
+	lastChecked *time.Time
+	lastError   string
+}
+
+// PluginRegistry holds the remediation plugins, in the order they are offered
+// issues, and tracks their health. Unhealthy plugins are skipped until a health
+// check succeeds again.
+type PluginRegistry struct {
+	mu      sync.RWMutex
+	plugins []*registeredPlugin
+	log     *logrus.Logger
+}
+
+// NewPluginRegistry creates an empty plugin registry
+func NewPluginRegistry(log *logrus.Logger) *PluginRegistry {
+	return &PluginRegistry{log: log}
+}
+
+// Register adds a plugin after the ones already registered
+func (r *PluginRegistry) Register(remediator *WebhookRemediator) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, p := range r.plugins {
+		if p.remediator.config.Name == remediator.config.Name {
+			return fmt.Errorf("plugin %s is already registered", remediator.config.Name)
+		}
+	}
+	r.plugins = append(r.plugins, &registeredPlugin{remediator: remediator, health: PluginHealthUnknown})
+	r.log.WithFields(logrus.Fields{
+		"plugin": remediator.config.Name,
+		"url":    remediator.config.URL,
+	}).Info("Remediation plugin registered")
+	return nil
+}
+
+// Plugins returns the status of every registered plugin
+func (r *PluginRegistry) Plugins() []PluginStatus {
+	if r == nil {
+		return []PluginStatus{}
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	statuses := make([]PluginStatus, 0, len(r.plugins))
+	for _, p := range r.plugins {
+		config := p.remediator.config
+		statuses = append(statuses, PluginStatus{
+			Name:        config.Name,
+			URL:         config.URL,
+			Match:       config.Match,
+			Timeout:     config.timeout.String(),
+			Health:      p.health,
+			LastChecked: p.lastChecked,
+			LastError:   p.lastError,
+		})
+	}
+	return statuses
+}
+
+// Names returns the remediator names of the registered plugins
+func (r *PluginRegistry) Names() []string {
+	if r == nil {
+		return nil
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.plugins))
+	for _, p := range r.plugins {
+		names = append(names, p.remediator.Name())
+	}
+	return names
+}
+
+// Select returns the first plugin that is not unhealthy and accepts the issue, or
+// nil. A plugin that cannot be asked is skipped, and marked unhealthy if it is
+// failing rather than refusing the request.
+func (r *PluginRegistry) Select(ctx context.Context, deploymentInfo *models.DeploymentInfo, issue *models.Issue) Remediator {
+	if r == nil {
+		return nil
+	}
+	r.mu.RLock()
+	plugins := make([]*registeredPlugin, 0, len(r.plugins))
+	for _, p := range r.plugins {
+		if p.health != PluginHealthUnhealthy {
+			plugins = append(plugins, p)
+		}
+	}
+	r.mu.RUnlock()
+
+	for _, p := range plugins {
+		accepted, err := p.remediator.Accepts(ctx, deploymentInfo, issue)
+		if err != nil {
+			entry := r.log.WithError(err).WithFields(logrus.Fields{
+				"plugin":   p.remediator.config.Name,
+				"issue_id": issue.ID,
+			})
+			if !pluginFailing(ctx, err) {
+				entry.Info("Remediation plugin refused the request, skipping it")
+				continue
+			}
+			entry.Warn("Remediation plugin could not be asked, skipping it")
+			r.setHealth(p, err)
+			continue
+		}
+		if accepted {
+			return p.remediator
+		}
+	}
+	return nil
+}
+
+// pluginFailing reports whether an error asking a plugin means the plugin is
+// failing: it could not be reached, timed out or answered with a server error.
+// Client errors and the caller giving up say nothing about its health.
+func pluginFailing(ctx context.Context, err error) bool {
+	var statusErr httpStatusError
+	switch {
+	case ctx.Err() != nil:
+		return false
+	case errors.As(err, &statusErr):
+		code := statusErr.HTTPStatusCode()
+		return code >= 500 || code == http.StatusRequestTimeout
+	default:
+		return ClassifyError(err) == models.ErrorClassRetryable
+	}
+}
+
+// CheckHealth calls the health endpoint of every plugin and records the outcome
+func (r *PluginRegistry) CheckHealth(ctx context.Context) {
+	if r == nil {
+		return
+	}
+	r.mu.RLock()
+	plugins := append([]*registeredPlugin(nil), r.plugins...)
+	r.mu.RUnlock()
+
+	for _, p := range plugins {
+		err := p.remediator.CheckHealth(ctx)
+		if err != nil {
+			r.log.WithError(err).WithField("plugin", p.remediator.config.Name).Warn("Remediation plugin health check failed")
+		}
+		r.setHealth(p, err)
+	}
+}
+
+// Watch checks plugin health now and then every interval until ctx is done
+func (r *PluginRegistry) Watch(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultPluginHealthInterval
+	}
+	r.CheckHealth(ctx)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.CheckHealth(ctx)
+		}
+	}
+}
+
+// setHealth records the outcome of a call to a plugin
+func (r *PluginRegistry) setHealth(p *registeredPlugin, err error) {
+	now := time.Now()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p.lastChecked = &now
+	if err != nil {
+		p.health = PluginHealthUnhealthy
+		p.lastError = err.Error()
+		UpdateRemediatorHealth(p.remediator.Name(), 0)
+		return
+	}
+	p.health = PluginHealthHealthy
+	p.lastError = ""
+	UpdateRemediatorHealth(p.remediator.Name(), 1)
+}
@@ -0,0 +1,249 @@
+package remediation
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+func TestLoadPlugins(t *testing.T) {
+	t.Setenv("DB_PLUGIN_TOKEN", "secret")
+	tests := []struct {
+		name    string
+		config  string
+		wantErr string
+	}{
+		{name: "valid", config: `
+plugins:
+  - name: db-failover
+    url: https://db-failover.tools.svc:8443
+    timeout: 30s
+    match:
+      issue_types: [DatabaseDown]
+      deployment_methods: [operator]
+      namespaces: ["db-*"]
+      label_selector: app.kubernetes.io/part-of=postgres
+    auth:
+      bearer_token_env: DB_PLUGIN_TOKEN
+`},
+		{name: "missing name", config: "plugins:\n  - url: http://plugin\n", wantErr: "name is required"},
+		{name: "relative url", config: "plugins:\n  - name: p\n    url: /remediate\n", wantErr: "absolute http or https URL"},
+		{name: "invalid timeout", config: "plugins:\n  - name: p\n    url: http://plugin\n    timeout: soon\n", wantErr: "positive duration"},
+		{name: "invalid label selector", config: "plugins:\n  - name: p\n    url: http://plugin\n    match:\n      label_selector: 'a in ('\n",
+			wantErr: "invalid label_selector"},
+		{name: "unset token", config: "plugins:\n  - name: p\n    url: http://plugin\n    auth:\n      bearer_token_env: MISSING_PLUGIN_TOKEN\n",
+			wantErr: "MISSING_PLUGIN_TOKEN is not set"},
+		{name: "duplicate", config: "plugins:\n  - name: p\n    url: http://a\n  - name: p\n    url: http://b\n", wantErr: "duplicate plugin p"},
+		{name: "unknown field", config: "plugins:\n  - name: p\n    url: http://plugin\n    retries: 3\n", wantErr: "unknown field"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), "plugins.yaml")
+			require.NoError(t, os.WriteFile(file, []byte(tt.config), 0o600))
+
+			plugins, err := LoadPlugins(file)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, plugins, 1)
+			assert.Equal(t, "db-failover", plugins[0].Name)
+			assert.Equal(t, "secret", plugins[0].Auth.token)
+			assert.Equal(t, "30s", plugins[0].timeout.String())
+		})
+	}
+}
+
+// newPluginServer serves a plugin that takes DatabaseDown issues and remediates
+// them with the given result, counting remediate calls
+func newPluginServer(t *testing.T, result models.RemediationResult, remediations *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case PluginPathHealth:
+			w.WriteHeader(http.StatusOK)
+		case PluginPathCanRemediate:
+			var req PluginRequest
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			assert.Equal(t, "db-failover", req.Plugin)
+			_ = json.NewEncoder(w).Encode(PluginCanRemediateResponse{CanRemediate: req.Issue.Type == "DatabaseDown"})
+		case PluginPathRemediate:
+			atomic.AddInt32(remediations, 1)
+			_ = json.NewEncoder(w).Encode(result)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func TestStrategySelector_RemediatesWithPlugin(t *testing.T) {
+	clientset := fake.NewSimpleClientset(&appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{
+		Name: "postgres", Namespace: "db-prod", Labels: map[string]string{"app": "postgres"},
+	}})
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	var remediations int32
+	server := newPluginServer(t, models.RemediationResult{
+		Version: models.RemediationResultVersion,
+		Status:  models.RemediationStatusChanged,
+		Actions: []models.PerformedAction{{Action: "promote_replica", Target: "StatefulSet db-prod/postgres", Before: "postgres-0", After: "postgres-1"}},
+	}, &remediations)
+	defer server.Close()
+
+	config := PluginConfig{Name: "db-failover", URL: server.URL, Match: PluginMatch{Namespaces: []string{"db-*"}, LabelSelector: "app=postgres"}}
+	require.NoError(t, config.Validate())
+	config.Auth.token = "secret"
+
+	registry := NewPluginRegistry(log)
+	require.NoError(t, registry.Register(NewWebhookRemediator(config, clientset, log)))
+	selector := NewStrategySelector(log)
+	selector.SetFallbackRemediator(NewManualRemediator(clientset, log))
+	selector.SetPluginRegistry(registry)
+
+	info := models.NewDeploymentInfo("db-prod", "postgres", "StatefulSet", models.DeploymentMethodOperator, 0.9)
+	issue := &models.Issue{ID: "issue-1", Type: "DatabaseDown", Namespace: "db-prod", ResourceType: "StatefulSet", ResourceName: "postgres"}
+
+	result, err := selector.Remediate(context.Background(), info, issue)
+	require.NoError(t, err)
+	assert.Equal(t, "plugin:db-failover", result.Method)
+	assert.Equal(t, models.RemediationStatusChanged, result.Status)
+	assert.Equal(t, "promote_replica", result.Actions[0].Action)
+	assert.NotEmpty(t, result.Duration)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&remediations))
+
+	// Issues the plugin declines, or outside its match, go to the built-in remediators
+	declined := *issue
+	declined.Type = "CrashLoopBackOff"
+	assert.Equal(t, "manual", selector.SelectRemediatorForIssue(context.Background(), info, &declined).Name())
+	elsewhere := *issue
+	elsewhere.Namespace = "payments"
+	assert.Equal(t, "manual", selector.SelectRemediatorForIssue(context.Background(), info, &elsewhere).Name())
+
+	assert.Equal(t, []string{"plugin:db-failover", "manual (fallback)"}, selector.GetRegisteredRemediators())
+}
+
+func TestWebhookRemediator_FailedResult(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	var remediations int32
+	server := newPluginServer(t, models.RemediationResult{
+		Version:    models.RemediationResultVersion,
+		Status:     models.RemediationStatusFailed,
+		Error:      "no replica in sync",
+		ErrorClass: models.ErrorClassPermanent,
+	}, &remediations)
+	defer server.Close()
+
+	config := PluginConfig{Name: "db-failover", URL: server.URL}
+	require.NoError(t, config.Validate())
+	config.Auth.token = "secret"
+	remediator := NewWebhookRemediator(config, nil, log)
+
+	issue := &models.Issue{ID: "issue-1", Type: "DatabaseDown", Namespace: "db-prod", ResourceType: "StatefulSet", ResourceName: "postgres"}
+	result, err := remediator.Remediate(context.Background(), nil, issue)
+	assert.ErrorContains(t, err, "no replica in sync")
+	assert.Equal(t, models.ErrorClassPermanent, ClassifyError(err))
+	assert.Equal(t, models.RemediationStatusFailed, result.Status)
+
+	// Rejected credentials are permanent
+	config.Auth.token = "wrong"
+	_, err = NewWebhookRemediator(config, nil, log).Remediate(context.Background(), nil, issue)
+	assert.ErrorContains(t, err, "401")
+	assert.Equal(t, models.ErrorClassPermanent, ClassifyError(err))
+}
+
+func TestPluginRegistry_Health(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	var healthy atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !healthy.Load() {
+			http.Error(w, "starting", http.StatusServiceUnavailable)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(PluginCanRemediateResponse{CanRemediate: true})
+	}))
+	defer server.Close()
+
+	config := PluginConfig{Name: "db-failover", URL: server.URL}
+	require.NoError(t, config.Validate())
+	registry := NewPluginRegistry(log)
+	require.NoError(t, registry.Register(NewWebhookRemediator(config, nil, log)))
+	assert.ErrorContains(t, registry.Register(NewWebhookRemediator(config, nil, log)), "already registered")
+	assert.Equal(t, PluginHealthUnknown, registry.Plugins()[0].Health)
+
+	issue := newTestIssue("issue-1")
+	info := models.NewDeploymentInfo("default", "payment", "Deployment", models.DeploymentMethodManual, 0.6)
+
+	// A plugin that cannot answer is skipped and marked unhealthy
+	assert.Nil(t, registry.Select(context.Background(), info, issue))
+	status := registry.Plugins()[0]
+	assert.Equal(t, PluginHealthUnhealthy, status.Health)
+	assert.Contains(t, status.LastError, "503")
+
+	// It stays skipped until a health check succeeds
+	healthy.Store(true)
+	assert.Nil(t, registry.Select(context.Background(), info, issue))
+	registry.CheckHealth(context.Background())
+	status = registry.Plugins()[0]
+	assert.Equal(t, PluginHealthHealthy, status.Health)
+	assert.Empty(t, status.LastError)
+	assert.NotNil(t, status.LastChecked)
+	assert.NotNil(t, registry.Select(context.Background(), info, issue))
+}
+
+func TestPluginRegistry_SelectKeepsHealthOnRefusals(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	var status atomic.Int32
+	status.Store(http.StatusUnprocessableEntity)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "cannot take this issue", int(status.Load()))
+	}))
+	defer server.Close()
+
+	config := PluginConfig{Name: "db-failover", URL: server.URL}
+	require.NoError(t, config.Validate())
+	registry := NewPluginRegistry(log)
+	require.NoError(t, registry.Register(NewWebhookRemediator(config, nil, log)))
+
+	issue := newTestIssue("issue-1")
+	info := models.NewDeploymentInfo("default", "payment", "Deployment", models.DeploymentMethodManual, 0.6)
+
+	// A client error only skips the plugin for this issue
+	assert.Nil(t, registry.Select(context.Background(), info, issue))
+	assert.Equal(t, PluginHealthUnknown, registry.Plugins()[0].Health)
+
+	// So does the caller giving up
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Nil(t, registry.Select(ctx, info, issue))
+	assert.Equal(t, PluginHealthUnknown, registry.Plugins()[0].Health)
+
+	// A server error marks it unhealthy
+	status.Store(http.StatusInternalServerError)
+	assert.Nil(t, registry.Select(context.Background(), info, issue))
+	assert.Equal(t, PluginHealthUnhealthy, registry.Plugins()[0].Health)
+}
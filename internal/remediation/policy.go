@@ -0,0 +1,636 @@
+package remediation
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/yaml"
+
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// DefaultPolicyReloadInterval is how often a policy source is checked for changes
+const DefaultPolicyReloadInterval = 30 * time.Second
+
+// ErrNoPolicyRule is returned when no policy rule covers an issue
+var ErrNoPolicyRule = errors.New("no remediation policy rule matches")
+
+// ErrPolicyLimit is returned when an action reached its max_per_hour limit. It is
+// permanent, so the limited action is neither retried nor failed over.
+var ErrPolicyLimit = errors.New("policy limit reached")
+
+// Policy maps issues to the ordered actions that remediate them. Rules are
+// evaluated in order and the first match wins.
+type Policy struct {
+	Rules []PolicyRule `json:"rules"`
+
+	// StepIssueTypes maps the action of a coordination plan step to the issue type
+	// its remediation is resolved for
+	StepIssueTypes map[string]string `json:"step_issue_types,omitempty"`
+
+	// Failover lists where the strategy selector may fall through to another
+	// remediator after a retryable failure. Without a matching rule it never does.
+	Failover []FailoverRule `json:"failover,omitempty"`
+
+	// Retry overrides the configured retry policy of remediations
+	Retry RetryPolicies `json:"retry,omitempty"`
+}
+
+// RetryPolicies overrides retry policies. A policy of the failed action overrides
+// that of the remediator, which overrides the default.
+type RetryPolicies struct {
+	Default     models.RetryPolicy            `json:"default,omitempty"`
+	Remediators map[string]models.RetryPolicy `json:"remediators,omitempty"` // by remediator name
+	Actions     map[string]models.RetryPolicy `json:"actions,omitempty"`     // by action, e.g. helm_upgrade
+}
+
+// FailoverRule allows issues matching its conditions to fail over between remediators
+type FailoverRule struct {
+	Name  string      `json:"name"`
+	Match PolicyMatch `json:"match,omitempty"`
+	From  []string    `json:"from,omitempty"` // remediators that may fail over; empty means any
+	To    []string    `json:"to"`             // remediators they may fail over to, e.g. manual
+}
+
+// PolicyRule is a set of conditions and the actions taken when all of them match
+type PolicyRule struct {
+	Name    string         `json:"name"`
+	Match   PolicyMatch    `json:"match,omitempty"`
+	Actions []PolicyAction `json:"actions"`
+}
+
+// PolicyMatch holds the conditions of a rule. Empty conditions match anything.
+type PolicyMatch struct {
+	IssueTypes        []string `json:"issue_types,omitempty"`        // case-insensitive
+	DeploymentMethods []string `json:"deployment_methods,omitempty"` // argocd, helm, operator, manual or unknown
+	Severities        []string `json:"severities,omitempty"`
+	ResourceKinds     []string `json:"resource_kinds,omitempty"`
+	NamespaceSelector string   `json:"namespace_selector,omitempty"` // matched against the issue namespace's labels
+
+	selector labels.Selector
+}
+
+// PolicyAction is one action of a rule, with its parameters and limits
+type PolicyAction struct {
+	Action     string            `json:"action"`
+	Params     map[string]string `json:"params,omitempty"`
+	Timeout    string            `json:"timeout,omitempty"`      // deadline of the action, e.g. 5m
+	MaxPerHour int               `json:"max_per_hour,omitempty"` // per resource; zero is unlimited
+
+	timeout time.Duration
+}
+
+// Param returns a parameter of the action, or def if it is not set
+func (a *PolicyAction) Param(key, def string) string {
+	if value, ok := a.Params[key]; ok {
+		return value
+	}
+	return def
+}
+
+// PolicyDecision is the rule that matched an issue and the actions to take
+type PolicyDecision struct {
+	Rule    string
+	Actions []PolicyAction
+}
+
+// policyActionSet is the set of actions an executor can carry out
+type policyActionSet map[string]bool
+
+// supports reports whether every action is in the set
+func (s policyActionSet) supports(actions []PolicyAction) bool {
+	for _, action := range actions {
+		if !s[action.Action] {
+			return false
+		}
+	}
+	return true
+}
+
+// knownPolicyAction returns true if some remediator executes action
+func knownPolicyAction(action string) bool {
+	return manualPolicyActions[action] || argocdPolicyActions[action]
+}
+
+// defaultPolicyYAML is the built-in policy. Loaded rules are evaluated before it,
+// so a policy file only needs the rules it adds or overrides.
+const defaultPolicyYAML = `
+rules:
+  - name: argocd-oom
+    match:
+      deployment_methods: [argocd]
+      issue_types: [OOMKilled]
+    actions:
+      - action: recommend_memory
+  - name: argocd-sync
+    match:
+      deployment_methods: [argocd]
+    actions:
+      - action: argocd_sync
+        params:
+          prune: "false"
+      - action: argocd_wait_for_sync
+  - name: rollback-deployment
+    match:
+      issue_types: [RolloutFailed, Rollback]
+      resource_kinds: [Deployment]
+    actions:
+      - action: rollback_deployment
+  - name: rollback-statefulset
+    match:
+      issue_types: [RolloutFailed, Rollback]
+      resource_kinds: [StatefulSet]
+    actions:
+      - action: rollback_statefulset
+  - name: rollback-daemonset
+    match:
+      issue_types: [RolloutFailed, Rollback]
+      resource_kinds: [DaemonSet]
+    actions:
+      - action: rollback_daemonset
+  - name: crash-loop-deployment
+    match:
+      issue_types: [CrashLoopBackOff, pod_crash_loop]
+      resource_kinds: [Deployment]
+    actions:
+      - action: restart_deployment
+  - name: crash-loop-pod
+    match:
+      issue_types: [CrashLoopBackOff, pod_crash_loop]
+      resource_kinds: [Pod]
+    actions:
+      - action: delete_pod
+  - name: image-pull
+    match:
+      issue_types: [ImagePullBackOff]
+    actions:
+      - action: manual_intervention
+        params:
+          message: verify the image exists and pull secrets are configured
+  - name: oom-killed
+    match:
+      issue_types: [OOMKilled]
+    actions:
+      - action: increase_memory
+  - name: restart-deployment
+    match:
+      resource_kinds: [Deployment]
+    actions:
+      - action: restart_deployment
+  - name: restart-statefulset
+    match:
+      resource_kinds: [StatefulSet]
+    actions:
+      - action: restart_statefulset
+  - name: restart-daemonset
+    match:
+      resource_kinds: [DaemonSet]
+    actions:
+      - action: restart_daemonset
+  - name: restart-deploymentconfig
+    match:
+      resource_kinds: [DeploymentConfig]
+    actions:
+      - action: restart_deploymentconfig
+  - name: restart-rollout
+    match:
+      resource_kinds: [Rollout]
+    actions:
+      - action: restart_rollout
+  - name: delete-pod
+    actions:
+      - action: delete_pod
+step_issue_types:
+  restart_pod: pod_crash_loop
+  restart_deployment: deployment_not_ready
+  restart_statefulset: statefulset_not_ready
+`
+
+// defaultPolicy is the parsed built-in policy
+var defaultPolicy = func() *Policy {
+	policy, err := ParsePolicy([]byte(defaultPolicyYAML))
+	if err != nil {
+		panic(fmt.Sprintf("invalid default remediation policy: %v", err))
+	}
+	return policy
+}()
+
+// ParsePolicy parses and validates a YAML policy document
+func ParsePolicy(data []byte) (*Policy, error) {
+	var policy Policy
+	if err := yaml.UnmarshalStrict(data, &policy); err != nil {
+		return nil, fmt.Errorf("failed to parse remediation policy: %w", err)
+	}
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// Validate checks the rules and parses their selectors and timeouts
+func (p *Policy) Validate() error {
+	names := make(map[string]bool, len(p.Rules))
+	for i := range p.Rules {
+		rule := &p.Rules[i]
+		if rule.Name == "" {
+			return fmt.Errorf("policy rule %d has no name", i)
+		}
+		if names[rule.Name] {
+			return fmt.Errorf("duplicate policy rule %s", rule.Name)
+		}
+		names[rule.Name] = true
+
+		if err := rule.Match.parse(); err != nil {
+			return fmt.Errorf("policy rule %s: %w", rule.Name, err)
+		}
+
+		if len(rule.Actions) == 0 {
+			return fmt.Errorf("policy rule %s has no actions", rule.Name)
+		}
+		for j := range rule.Actions {
+			action := &rule.Actions[j]
+			if !knownPolicyAction(action.Action) {
+				return fmt.Errorf("policy rule %s: unknown action %q", rule.Name, action.Action)
+			}
+			if action.MaxPerHour < 0 {
+				return fmt.Errorf("policy rule %s: max_per_hour of %s cannot be negative", rule.Name, action.Action)
+			}
+			if action.Timeout != "" {
+				timeout, err := time.ParseDuration(action.Timeout)
+				if err != nil || timeout <= 0 {
+					return fmt.Errorf("policy rule %s: timeout of %s must be a positive duration", rule.Name, action.Action)
+				}
+				action.timeout = timeout
+			}
+		}
+	}
+
+	names = make(map[string]bool, len(p.Failover))
+	for i := range p.Failover {
+		rule := &p.Failover[i]
+		if rule.Name == "" {
+			return fmt.Errorf("failover rule %d has no name", i)
+		}
+		if names[rule.Name] {
+			return fmt.Errorf("duplicate failover rule %s", rule.Name)
+		}
+		names[rule.Name] = true
+		if len(rule.To) == 0 {
+			return fmt.Errorf("failover rule %s has no remediators to fail over to", rule.Name)
+		}
+		if err := rule.Match.parse(); err != nil {
+			return fmt.Errorf("failover rule %s: %w", rule.Name, err)
+		}
+	}
+
+	if err := p.Retry.Default.Validate(); err != nil {
+		return fmt.Errorf("default retry policy: %w", err)
+	}
+	for name, retry := range p.Retry.Remediators {
+		if err := retry.Validate(); err != nil {
+			return fmt.Errorf("retry policy of remediator %s: %w", name, err)
+		}
+	}
+	for action, retry := range p.Retry.Actions {
+		if err := retry.Validate(); err != nil {
+			return fmt.Errorf("retry policy of action %s: %w", action, err)
+		}
+	}
+	return nil
+}
+
+// parse parses the namespace selector
+func (m *PolicyMatch) parse() error {
+	if m.NamespaceSelector == "" {
+		return nil
+	}
+	selector, err := labels.Parse(m.NamespaceSelector)
+	if err != nil {
+		return fmt.Errorf("invalid namespace_selector: %w", err)
+	}
+	m.selector = selector
+	return nil
+}
+
+// matches reports whether the rule's conditions hold for the issue. namespaceLabels
+// is only called for rules with a namespace selector.
+func (m *PolicyMatch) matches(deploymentInfo *models.DeploymentInfo, issue *models.Issue, namespaceLabels func() labels.Set) bool {
+	if len(m.IssueTypes) > 0 && !containsFold(m.IssueTypes, issue.Type) {
+		return false
+	}
+	if len(m.DeploymentMethods) > 0 {
+		if deploymentInfo == nil || !containsFold(m.DeploymentMethods, string(deploymentInfo.Method)) {
+			return false
+		}
+	}
+	if len(m.Severities) > 0 && !containsFold(m.Severities, issue.Severity) {
+		return false
+	}
+	if len(m.ResourceKinds) > 0 {
+		kind := NormalizeKind(issue.ResourceType)
+		found := false
+		for _, k := range m.ResourceKinds {
+			if NormalizeKind(k) == kind {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if m.selector != nil && !m.selector.Matches(namespaceLabels()) {
+		return false
+	}
+	return true
+}
+
+// PolicyLoader reads a policy document from its source
+type PolicyLoader func(ctx context.Context) ([]byte, error)
+
+// FilePolicyLoader reads the policy from a file, such as a mounted ConfigMap key
+func FilePolicyLoader(file string) PolicyLoader {
+	return func(_ context.Context) ([]byte, error) {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read remediation policy %s: %w", file, err)
+		}
+		return data, nil
+	}
+}
+
+// ConfigMapPolicyLoader reads the policy from a ConfigMap key
+func ConfigMapPolicyLoader(clientset kubernetes.Interface, namespace, name, key string) PolicyLoader {
+	return func(ctx context.Context) ([]byte, error) {
+		cm, err := clientset.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get remediation policy ConfigMap %s/%s: %w", namespace, name, err)
+		}
+		data, ok := cm.Data[key]
+		if !ok {
+			return nil, fmt.Errorf("remediation policy ConfigMap %s/%s has no key %s", namespace, name, key)
+		}
+		return []byte(data), nil
+	}
+}
+
+// PolicyEngine resolves issues to remediation actions from the loaded policy,
+// falling back to the built-in rules. It is safe for concurrent use, and a nil
+// engine uses only the built-in rules.
+type PolicyEngine struct {
+	clientset kubernetes.Interface // reads namespace labels; nil never matches namespace selectors
+	log       *logrus.Logger
+
+	mu     sync.RWMutex
+	policy *Policy // loaded policy, nil until one is loaded
+	data   []byte  // source of the loaded policy, to skip unchanged reloads
+
+	limitsMu   sync.Mutex
+	executions map[string][]time.Time // recent executions by resource and action
+
+	now func() time.Time
+}
+
+// NewPolicyEngine creates a policy engine with only the built-in rules
+func NewPolicyEngine(clientset kubernetes.Interface, log *logrus.Logger) *PolicyEngine {
+	return &PolicyEngine{
+		clientset:  clientset,
+		log:        log,
+		executions: make(map[string][]time.Time),
+		now:        time.Now,
+	}
+}
+
+// SetPolicy replaces the loaded policy
+func (e *PolicyEngine) SetPolicy(policy *Policy) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.policy = policy
+}
+
+// Policy returns the loaded policy, or nil if only the built-in rules apply
+func (e *PolicyEngine) Policy() *Policy {
+	if e == nil {
+		return nil
+	}
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.policy
+}
+
+// Load reads and applies the policy from loader. It returns false without error if
+// the policy did not change. An invalid policy leaves the current one in place.
+func (e *PolicyEngine) Load(ctx context.Context, loader PolicyLoader) (bool, error) {
+	data, err := loader(ctx)
+	if err != nil {
+		RecordPolicyReload("failed")
+		return false, err
+	}
+
+	e.mu.RLock()
+	unchanged := e.data != nil && bytes.Equal(e.data, data)
+	e.mu.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+
+	policy, err := ParsePolicy(data)
+	if err != nil {
+		RecordPolicyReload("failed")
+		return false, err
+	}
+
+	e.mu.Lock()
+	e.policy = policy
+	e.data = data
+	e.mu.Unlock()
+
+	RecordPolicyReload("success")
+	e.log.WithField("rules", len(policy.Rules)).Info("Remediation policy loaded")
+	return true, nil
+}
+
+// Watch reloads the policy from loader every interval until ctx is done, so
+// changes to the file or ConfigMap apply without a restart
+func (e *PolicyEngine) Watch(ctx context.Context, loader PolicyLoader, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultPolicyReloadInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := e.Load(ctx, loader); err != nil {
+				e.log.WithError(err).Error("Failed to reload remediation policy, keeping the current one")
+			}
+		}
+	}
+}
+
+// rules returns the loaded rules followed by the built-in ones
+func (e *PolicyEngine) rules() []PolicyRule {
+	loaded := e.Policy()
+	if loaded == nil {
+		return defaultPolicy.Rules
+	}
+	rules := make([]PolicyRule, 0, len(loaded.Rules)+len(defaultPolicy.Rules))
+	return append(append(rules, loaded.Rules...), defaultPolicy.Rules...)
+}
+
+// Resolve returns the first rule matching the issue whose actions the executor
+// supports. Rules for actions of other remediators are skipped.
+func (e *PolicyEngine) Resolve(ctx context.Context, deploymentInfo *models.DeploymentInfo, issue *models.Issue, executor policyActionSet) (*PolicyDecision, error) {
+	var nsLabels labels.Set
+	namespaceLabels := func() labels.Set {
+		if nsLabels == nil {
+			nsLabels = e.namespaceLabels(ctx, issue.Namespace)
+		}
+		return nsLabels
+	}
+
+	rules := e.rules()
+	for i := range rules {
+		rule := &rules[i]
+		if !executor.supports(rule.Actions) || !rule.Match.matches(deploymentInfo, issue, namespaceLabels) {
+			continue
+		}
+		RecordPolicyMatch(rule.Name)
+		return &PolicyDecision{Rule: rule.Name, Actions: rule.Actions}, nil
+	}
+	return nil, fmt.Errorf("%w %s issue on %s", ErrNoPolicyRule, issue.Type, issueResource(issue))
+}
+
+// AllowsFailover returns the failover rule that lets the issue fall through from
+// one remediator to another, if any
+func (e *PolicyEngine) AllowsFailover(ctx context.Context, deploymentInfo *models.DeploymentInfo, issue *models.Issue, from, to string) (string, bool) {
+	if e == nil {
+		return "", false
+	}
+	loaded := e.Policy()
+	if loaded == nil {
+		return "", false
+	}
+
+	var nsLabels labels.Set
+	namespaceLabels := func() labels.Set {
+		if nsLabels == nil {
+			nsLabels = e.namespaceLabels(ctx, issue.Namespace)
+		}
+		return nsLabels
+	}
+	for i := range loaded.Failover {
+		rule := &loaded.Failover[i]
+		if (len(rule.From) > 0 && !containsFold(rule.From, from)) || !containsFold(rule.To, to) {
+			continue
+		}
+		if rule.Match.matches(deploymentInfo, issue, namespaceLabels) {
+			return rule.Name, true
+		}
+	}
+	return "", false
+}
+
+// RetryPolicy returns base overridden by the loaded policy's default retry policy,
+// then that of the remediator, then that of the failed action, if any
+func (e *PolicyEngine) RetryPolicy(base models.RetryPolicy, remediator, action string) models.RetryPolicy {
+	if e == nil {
+		return base
+	}
+	loaded := e.Policy()
+	if loaded == nil {
+		return base
+	}
+	policy := base.Merge(&loaded.Retry.Default)
+	if retry, ok := loaded.Retry.Remediators[remediator]; ok {
+		policy = policy.Merge(&retry)
+	}
+	if retry, ok := loaded.Retry.Actions[action]; ok && action != "" {
+		policy = policy.Merge(&retry)
+	}
+	return policy
+}
+
+// namespaceLabels returns the labels of namespace, or none if they cannot be read
+func (e *PolicyEngine) namespaceLabels(ctx context.Context, namespace string) labels.Set {
+	if e == nil || e.clientset == nil {
+		return labels.Set{}
+	}
+	ns, err := e.clientset.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{})
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			e.log.WithError(err).WithField("namespace", namespace).Warn("Failed to read namespace labels for remediation policy")
+		}
+		return labels.Set{}
+	}
+	return labels.Set(ns.Labels)
+}
+
+// StepIssueType returns the issue type a coordination plan step with the given
+// action remediates
+func (e *PolicyEngine) StepIssueType(action string) string {
+	if loaded := e.Policy(); loaded != nil {
+		if issueType, ok := loaded.StepIssueTypes[action]; ok {
+			return issueType
+		}
+	}
+	if issueType, ok := defaultPolicy.StepIssueTypes[action]; ok {
+		return issueType
+	}
+	return "generic_issue"
+}
+
+// Execute runs one policy action within its timeout, refusing it once the action
+// reached its hourly limit on the issue's resource. Errors of the action are
+// attributed to it for per-action retry policies.
+func (e *PolicyEngine) Execute(ctx context.Context, issue *models.Issue, action PolicyAction, run func(context.Context) error) error {
+	if err := e.admit(issue, action); err != nil {
+		return err
+	}
+	if action.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, action.timeout)
+		defer cancel()
+	}
+	return withAction(action.Action, run(ctx))
+}
+
+// admit counts an execution of action against its hourly limit
+func (e *PolicyEngine) admit(issue *models.Issue, action PolicyAction) error {
+	if e == nil || action.MaxPerHour == 0 {
+		return nil
+	}
+
+	key := strings.Join([]string{issue.Namespace, NormalizeKind(issue.ResourceType), issue.ResourceName, action.Action}, "/")
+	now := e.now()
+
+	e.limitsMu.Lock()
+	defer e.limitsMu.Unlock()
+
+	recent := e.executions[key][:0]
+	for _, at := range e.executions[key] {
+		if now.Sub(at) < time.Hour {
+			recent = append(recent, at)
+		}
+	}
+	if len(recent) >= action.MaxPerHour {
+		e.executions[key] = recent
+		return fmt.Errorf("%w: %s ran %d times on %s in the last hour", ErrPolicyLimit, action.Action, len(recent), issueResource(issue))
+	}
+	e.executions[key] = append(recent, now)
+	return nil
+}
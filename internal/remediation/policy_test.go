@@ -0,0 +1,221 @@
+package remediation
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+const testPolicyYAML = `
+rules:
+  - name: stale-config
+    match:
+      issue_types: [StaleConfig]
+      severities: [high, critical]
+      namespace_selector: env=prod
+    actions:
+      - action: restart_deployment
+        timeout: 1m
+        max_per_hour: 1
+  - name: argocd-prune
+    match:
+      deployment_methods: [argocd]
+      issue_types: [StaleConfig]
+    actions:
+      - action: argocd_sync
+        params:
+          prune: "true"
+step_issue_types:
+  restart_deployment: StaleConfig
+`
+
+func newTestPolicyEngine(t *testing.T, policyYAML string, objects ...runtime.Object) *PolicyEngine {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	engine := NewPolicyEngine(fake.NewSimpleClientset(objects...), log)
+	if policyYAML != "" {
+		policy, err := ParsePolicy([]byte(policyYAML))
+		require.NoError(t, err)
+		engine.SetPolicy(policy)
+	}
+	return engine
+}
+
+func TestParsePolicy_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"unknown action":     "rules: [{name: a, actions: [{action: format_disk}]}]",
+		"no actions":         "rules: [{name: a}]",
+		"no name":            "rules: [{actions: [{action: delete_pod}]}]",
+		"duplicate name":     "rules: [{name: a, actions: [{action: delete_pod}]}, {name: a, actions: [{action: delete_pod}]}]",
+		"invalid selector":   "rules: [{name: a, match: {namespace_selector: 'env in (prod'}, actions: [{action: delete_pod}]}]",
+		"invalid timeout":    "rules: [{name: a, actions: [{action: delete_pod, timeout: soon}]}]",
+		"negative limit":     "rules: [{name: a, actions: [{action: delete_pod, max_per_hour: -1}]}]",
+		"unknown field":      "rules: [{name: a, when: {}, actions: [{action: delete_pod}]}]",
+		"malformed document": "rules: {",
+		"failover no target": "failover: [{name: f, from: [argocd]}]",
+		"failover no name":   "failover: [{to: [manual]}]",
+		"retry bad reason":   "retry: {actions: {helm_upgrade: {retry_on: [gremlins]}}}",
+		"retry bad backoff":  "retry: {remediators: {argocd: {initial_backoff: soon}}}",
+	}
+	for name, policyYAML := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParsePolicy([]byte(policyYAML))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestPolicyEngine_Resolve(t *testing.T) {
+	prod := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "prod", Labels: map[string]string{"env": "prod"}}}
+	engine := newTestPolicyEngine(t, testPolicyYAML, prod)
+	manual := models.NewDeploymentInfo("prod", "payment", "Deployment", models.DeploymentMethodManual, 0.9)
+	argocd := models.NewDeploymentInfo("prod", "payment", "Deployment", models.DeploymentMethodArgoCD, 0.9)
+
+	issue := func(namespace, issueType, severity, kind string) *models.Issue {
+		return &models.Issue{ID: "issue-1", Type: issueType, Severity: severity, Namespace: namespace, ResourceType: kind, ResourceName: "payment"}
+	}
+
+	tests := []struct {
+		name     string
+		engine   *PolicyEngine
+		info     *models.DeploymentInfo
+		issue    *models.Issue
+		executor policyActionSet
+		wantRule string
+	}{
+		{"loaded rule", engine, manual, issue("prod", "staleconfig", "high", "Deployment"), manualPolicyActions, "stale-config"},
+		{"severity not matched", engine, manual, issue("prod", "StaleConfig", "low", "Deployment"), manualPolicyActions, "restart-deployment"},
+		{"namespace selector not matched", engine, manual, issue("dev", "StaleConfig", "high", "Deployment"), manualPolicyActions, "restart-deployment"},
+		{"rules for other executors are skipped", engine, argocd, issue("prod", "StaleConfig", "high", "Deployment"), argocdPolicyActions, "argocd-prune"},
+		{"built-in rule", engine, manual, issue("prod", "CrashLoopBackOff", "high", "pod"), manualPolicyActions, "crash-loop-pod"},
+		{"nil engine uses built-in rules", nil, nil, issue("prod", "OOMKilled", "high", "Pod"), manualPolicyActions, "oom-killed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision, err := tt.engine.Resolve(context.Background(), tt.info, tt.issue, tt.executor)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantRule, decision.Rule)
+		})
+	}
+
+	// The built-in ArgoCD rule only applies to ArgoCD-managed resources
+	_, err := engine.Resolve(context.Background(), manual, issue("prod", "CrashLoopBackOff", "high", "Deployment"), argocdPolicyActions)
+	assert.ErrorIs(t, err, ErrNoPolicyRule)
+
+	assert.Equal(t, "StaleConfig", engine.StepIssueType("restart_deployment"))
+	assert.Equal(t, "pod_crash_loop", engine.StepIssueType("restart_pod"))
+	assert.Equal(t, "generic_issue", engine.StepIssueType("scale_up"))
+}
+
+func TestPolicyEngine_Execute(t *testing.T) {
+	engine := newTestPolicyEngine(t, "")
+	now := time.Now()
+	engine.now = func() time.Time { return now }
+	issue := &models.Issue{Namespace: "prod", ResourceType: "Deployment", ResourceName: "payment"}
+	action := PolicyAction{Action: "restart_deployment", MaxPerHour: 2, timeout: time.Minute}
+
+	run := func(ctx context.Context) error {
+		deadline, ok := ctx.Deadline()
+		assert.True(t, ok)
+		assert.WithinDuration(t, time.Now().Add(time.Minute), deadline, 5*time.Second)
+		return nil
+	}
+	require.NoError(t, engine.Execute(context.Background(), issue, action, run))
+	require.NoError(t, engine.Execute(context.Background(), issue, action, run))
+	assert.ErrorContains(t, engine.Execute(context.Background(), issue, action, run), "policy limit reached")
+
+	// Other resources have their own limit, and executions expire after an hour
+	other := &models.Issue{Namespace: "prod", ResourceType: "Deployment", ResourceName: "checkout"}
+	assert.NoError(t, engine.Execute(context.Background(), other, action, run))
+	engine.now = func() time.Time { return now.Add(time.Hour) }
+	assert.NoError(t, engine.Execute(context.Background(), issue, action, run))
+}
+
+func TestPolicyEngine_Load(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "policy.yaml")
+	require.NoError(t, os.WriteFile(file, []byte(testPolicyYAML), 0o600))
+	engine := newTestPolicyEngine(t, "")
+	loader := FilePolicyLoader(file)
+
+	changed, err := engine.Load(context.Background(), loader)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	require.NotNil(t, engine.Policy())
+	assert.Len(t, engine.Policy().Rules, 2)
+
+	changed, err = engine.Load(context.Background(), loader)
+	require.NoError(t, err)
+	assert.False(t, changed)
+
+	// An invalid update keeps the current policy
+	require.NoError(t, os.WriteFile(file, []byte("rules: [{name: a, actions: [{action: format_disk}]}]"), 0o600))
+	_, err = engine.Load(context.Background(), loader)
+	assert.Error(t, err)
+	assert.Len(t, engine.Policy().Rules, 2)
+
+	// Watch picks up changes
+	require.NoError(t, os.WriteFile(file, []byte("rules: [{name: only, actions: [{action: delete_pod}]}]"), 0o600))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go engine.Watch(ctx, loader, 10*time.Millisecond)
+	require.Eventually(t, func() bool {
+		return len(engine.Policy().Rules) == 1
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
+func TestConfigMapPolicyLoader(t *testing.T) {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "remediation-policy", Namespace: "engine"},
+		Data:       map[string]string{"policy.yaml": testPolicyYAML},
+	}
+	clientset := fake.NewSimpleClientset(cm)
+
+	data, err := ConfigMapPolicyLoader(clientset, "engine", "remediation-policy", "policy.yaml")(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, testPolicyYAML, string(data))
+
+	_, err = ConfigMapPolicyLoader(clientset, "engine", "remediation-policy", "rules.yaml")(context.Background())
+	assert.Error(t, err)
+}
+
+func TestManualRemediator_PolicyDriven(t *testing.T) {
+	prod := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "prod", Labels: map[string]string{"env": "prod"}}}
+	deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "payment", Namespace: "prod"}}
+	clientset := fake.NewSimpleClientset(prod, deployment)
+
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	remediator := NewManualRemediator(clientset, log)
+	remediator.SetPolicy(newTestPolicyEngine(t, testPolicyYAML, prod))
+
+	info := models.NewDeploymentInfo("prod", "payment", "Deployment", models.DeploymentMethodManual, 0.9)
+	issue := &models.Issue{ID: "issue-1", Type: "StaleConfig", Severity: "high", Namespace: "prod", ResourceType: "Deployment", ResourceName: "payment"}
+
+	actions, err := remediator.PlanActions(context.Background(), info, issue)
+	require.NoError(t, err)
+	require.Len(t, actions, 1)
+	assert.Equal(t, "restart_deployment", actions[0].Action)
+
+	_, err = remediator.Remediate(context.Background(), info, issue)
+	require.NoError(t, err)
+	updated, err := clientset.AppsV1().Deployments("prod").Get(context.Background(), "payment", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.NotEmpty(t, updated.Spec.Template.Annotations["remediation.aiops/restarted-at"])
+
+	// The rule allows one restart per hour
+	_, err = remediator.Remediate(context.Background(), info, issue)
+	assert.ErrorContains(t, err, "policy limit reached")
+}
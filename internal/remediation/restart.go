@@ -0,0 +1,212 @@
+package remediation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/detector"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// AnnotationRestartedAt is the pod template annotation a restart updates to roll
+// out new pods, as kubectl rollout restart does with its own annotation
+const AnnotationRestartedAt = "remediation.aiops/restarted-at"
+
+// restartKinds maps each restart action to the kind of workload it restarts
+var restartKinds = map[string]string{
+	"restart_deployment":       "Deployment",
+	"restart_statefulset":      "StatefulSet",
+	"restart_daemonset":        "DaemonSet",
+	"restart_deploymentconfig": "DeploymentConfig",
+	"restart_rollout":          "Rollout",
+}
+
+// restartPatch returns a merge patch setting the restarted-at template annotation
+func restartPatch(restartedAt string) []byte {
+	patch, _ := json.Marshal(map[string]any{
+		"spec": map[string]any{
+			"template": map[string]any{
+				"metadata": map[string]any{
+					"annotations": map[string]string{AnnotationRestartedAt: restartedAt},
+				},
+			},
+		},
+	})
+	return patch
+}
+
+// restart rolls the pods of the issue's workload by updating its restarted-at
+// template annotation
+func (mr *ManualRemediator) restart(ctx context.Context, action string, issue *models.Issue, result *models.RemediationResult) error {
+	kind := restartKinds[action]
+	mr.log.WithFields(logrus.Fields{
+		"namespace": issue.Namespace,
+		"kind":      kind,
+		"name":      issue.ResourceName,
+	}).Info("Restarting workload")
+
+	restartedAt := time.Now().Format(time.RFC3339)
+	patch := restartPatch(restartedAt)
+	var restarted restartedWorkload
+	var err error
+	switch kind {
+	case "Deployment":
+		restarted, err = mr.restartDeployment(ctx, issue.Namespace, issue.ResourceName, patch)
+	case "StatefulSet":
+		restarted, err = mr.restartStatefulSet(ctx, issue.Namespace, issue.ResourceName, patch)
+	case "DaemonSet":
+		restarted, err = mr.restartDaemonSet(ctx, issue.Namespace, issue.ResourceName, patch)
+	case "DeploymentConfig":
+		restarted, err = mr.restartCustomWorkload(ctx, detector.DeploymentConfigGVR, issue.Namespace, issue.ResourceName, patch)
+	case "Rollout":
+		restarted, err = mr.restartCustomWorkload(ctx, detector.RolloutGVR, issue.Namespace, issue.ResourceName, patch)
+	default:
+		return Permanent(fmt.Errorf("manual remediator cannot execute action %s", action))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to restart %s: %w", strings.ToLower(kind), err)
+	}
+
+	result.AddAction(action, fmt.Sprintf("%s %s/%s", kind, issue.Namespace, issue.ResourceName), restarted.previous, restartedAt)
+	result.AddEvidence("restart_note", restarted.note)
+
+	message := fmt.Sprintf("Restarted %s to remediate %s", strings.ToLower(kind), issue.Type)
+	if restarted.note != "" {
+		message += "; " + restarted.note
+	}
+	target := workloadRef(kind, issue.Namespace, issue.ResourceName)
+	target.UID = restarted.uid
+	recordEvent(ctx, mr.recorder, RemediationEvent{
+		Target:     target,
+		Reason:     EventReasonRestarted,
+		Action:     action,
+		Remediator: mr.Name(),
+		Message:    message,
+	})
+
+	mr.log.WithFields(logrus.Fields{
+		"namespace": issue.Namespace,
+		"kind":      kind,
+		"name":      issue.ResourceName,
+	}).Info("Workload restart triggered")
+	return nil
+}
+
+// restartedWorkload describes a restart: the workload's UID, the restarted-at
+// annotation it replaced and any caveat of the workload's update strategy
+type restartedWorkload struct {
+	uid      types.UID
+	previous string
+	note     string
+}
+
+// restartDeployment restarts a Deployment
+func (mr *ManualRemediator) restartDeployment(ctx context.Context, namespace, name string, patch []byte) (restartedWorkload, error) {
+	deployments := mr.clientset.AppsV1().Deployments(namespace)
+	deployment, err := deployments.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return restartedWorkload{}, err
+	}
+	updated, err := deployments.Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return restartedWorkload{}, err
+	}
+	return restartedWorkload{uid: updated.UID, previous: deployment.Spec.Template.Annotations[AnnotationRestartedAt]}, nil
+}
+
+// restartDaemonSet restarts a DaemonSet
+func (mr *ManualRemediator) restartDaemonSet(ctx context.Context, namespace, name string, patch []byte) (restartedWorkload, error) {
+	daemonSets := mr.clientset.AppsV1().DaemonSets(namespace)
+	ds, err := daemonSets.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return restartedWorkload{}, err
+	}
+	updated, err := daemonSets.Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return restartedWorkload{}, err
+	}
+	return restartedWorkload{uid: updated.UID, previous: ds.Spec.Template.Annotations[AnnotationRestartedAt]}, nil
+}
+
+// restartStatefulSet restarts a StatefulSet without touching its update strategy: a
+// partitioned rolling update only restarts the pods at or above the partition, and
+// the OnDelete strategy leaves restarting the pods to whoever deletes them
+func (mr *ManualRemediator) restartStatefulSet(ctx context.Context, namespace, name string, patch []byte) (restartedWorkload, error) {
+	statefulSets := mr.clientset.AppsV1().StatefulSets(namespace)
+	sts, err := statefulSets.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return restartedWorkload{}, err
+	}
+
+	restarted := restartedWorkload{previous: sts.Spec.Template.Annotations[AnnotationRestartedAt]}
+	replicas := int32(1)
+	if sts.Spec.Replicas != nil {
+		replicas = *sts.Spec.Replicas
+	}
+	strategy := sts.Spec.UpdateStrategy
+	switch {
+	case strategy.Type == appsv1.OnDeleteStatefulSetStrategyType:
+		restarted.note = "its OnDelete update strategy restarts pods only when they are deleted"
+	case strategy.RollingUpdate != nil && strategy.RollingUpdate.Partition != nil && *strategy.RollingUpdate.Partition > 0:
+		partition := *strategy.RollingUpdate.Partition
+		if partition >= replicas {
+			return restartedWorkload{}, Permanent(fmt.Errorf("statefulset %s/%s is partitioned at %d of %d replicas, so no pod would restart",
+				namespace, name, partition, replicas))
+		}
+		restarted.note = fmt.Sprintf("only ordinals %d and above restart under its rolling update partition", partition)
+	}
+
+	updated, err := statefulSets.Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return restartedWorkload{}, err
+	}
+	restarted.uid = updated.UID
+	return restarted, nil
+}
+
+// restartCustomWorkload restarts a DeploymentConfig or Argo Rollout through the
+// dynamic client
+func (mr *ManualRemediator) restartCustomWorkload(ctx context.Context, gvr schema.GroupVersionResource, namespace, name string, patch []byte) (restartedWorkload, error) {
+	if mr.dynamic == nil {
+		return restartedWorkload{}, Permanent(fmt.Errorf("no dynamic client configured for %s", gvr.Resource))
+	}
+
+	resource := mr.dynamic.Resource(gvr).Namespace(namespace)
+	current, err := resource.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return restartedWorkload{}, err
+	}
+	previous, _, _ := unstructured.NestedString(current.Object, "spec", "template", "metadata", "annotations", AnnotationRestartedAt)
+
+	updated, err := resource.Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return restartedWorkload{}, err
+	}
+
+	restarted := restartedWorkload{uid: updated.GetUID(), previous: previous}
+	if gvr == detector.DeploymentConfigGVR && !hasConfigChangeTrigger(updated) {
+		restarted.note = "it has no ConfigChange trigger, so a new deployment must be started with oc rollout latest"
+	}
+	return restarted, nil
+}
+
+// hasConfigChangeTrigger reports whether a DeploymentConfig rolls out template changes
+func hasConfigChangeTrigger(dc *unstructured.Unstructured) bool {
+	triggers, _, _ := unstructured.NestedSlice(dc.Object, "spec", "triggers")
+	for _, trigger := range triggers {
+		if t, ok := trigger.(map[string]any); ok && t["type"] == "ConfigChange" {
+			return true
+		}
+	}
+	return false
+}
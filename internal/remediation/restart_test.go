@@ -0,0 +1,127 @@
+package remediation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/detector"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+func TestManualRemediator_RestartStatefulSet(t *testing.T) {
+	newStatefulSet := func(replicas int32, strategy appsv1.StatefulSetUpdateStrategy) *appsv1.StatefulSet {
+		return &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default"},
+			Spec:       appsv1.StatefulSetSpec{Replicas: &replicas, UpdateStrategy: strategy},
+		}
+	}
+	partitioned := func(partition int32) appsv1.StatefulSetUpdateStrategy {
+		return appsv1.StatefulSetUpdateStrategy{
+			Type:          appsv1.RollingUpdateStatefulSetStrategyType,
+			RollingUpdate: &appsv1.RollingUpdateStatefulSetStrategy{Partition: &partition},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		sts      *appsv1.StatefulSet
+		wantNote string
+		wantErr  string
+	}{
+		{name: "rolling update", sts: newStatefulSet(3, appsv1.StatefulSetUpdateStrategy{})},
+		{name: "partitioned", sts: newStatefulSet(3, partitioned(2)), wantNote: "only ordinals 2 and above restart"},
+		{name: "partitioned above replicas", sts: newStatefulSet(3, partitioned(3)), wantErr: "no pod would restart"},
+		{name: "on delete", sts: newStatefulSet(3, appsv1.StatefulSetUpdateStrategy{Type: appsv1.OnDeleteStatefulSetStrategyType}),
+			wantNote: "only when they are deleted"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientset := fake.NewSimpleClientset(tt.sts)
+			log := logrus.New()
+			log.SetLevel(logrus.ErrorLevel)
+			remediator := NewManualRemediator(clientset, log)
+			recorder := &fakeEventRecorder{}
+			remediator.SetEventRecorder(recorder)
+
+			issue := &models.Issue{ID: "issue-1", Type: "statefulset_not_ready", Namespace: "default", ResourceType: "StatefulSet", ResourceName: "db"}
+			_, err := remediator.Remediate(context.Background(), nil, issue)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			updated, err := clientset.AppsV1().StatefulSets("default").Get(context.Background(), "db", metav1.GetOptions{})
+			require.NoError(t, err)
+			assert.NotEmpty(t, updated.Spec.Template.Annotations[AnnotationRestartedAt])
+			// The update strategy is left alone
+			assert.Equal(t, tt.sts.Spec.UpdateStrategy, updated.Spec.UpdateStrategy)
+
+			events := recorder.recorded()
+			require.Len(t, events, 1)
+			assert.Equal(t, "restart_statefulset", events[0].Action)
+			assert.Contains(t, events[0].Message, tt.wantNote)
+		})
+	}
+}
+
+func TestManualRemediator_RestartWorkloads(t *testing.T) {
+	newCustomWorkload := func(apiVersion, kind string, spec map[string]any) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": apiVersion,
+			"kind":       kind,
+			"metadata":   map[string]any{"name": "web", "namespace": "default"},
+			"spec":       spec,
+		}}
+	}
+	clientset := fake.NewSimpleClientset(&appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"}})
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(),
+		newCustomWorkload("apps.openshift.io/v1", "DeploymentConfig", map[string]any{
+			"triggers": []any{map[string]any{"type": "ConfigChange"}},
+		}),
+		newCustomWorkload("argoproj.io/v1alpha1", "Rollout", map[string]any{}),
+	)
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	remediator := NewManualRemediator(clientset, log)
+	remediator.SetDynamicClient(dynamicClient)
+
+	remediate := func(kind string) {
+		issue := &models.Issue{ID: "issue-1", Type: "HighLatency", Namespace: "default", ResourceType: kind, ResourceName: "web"}
+		_, err := remediator.Remediate(context.Background(), nil, issue)
+		require.NoError(t, err, kind)
+	}
+
+	remediate("DaemonSet")
+	ds, err := clientset.AppsV1().DaemonSets("default").Get(context.Background(), "web", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.NotEmpty(t, ds.Spec.Template.Annotations[AnnotationRestartedAt])
+
+	for kind, gvr := range map[string]schema.GroupVersionResource{
+		"DeploymentConfig": detector.DeploymentConfigGVR,
+		"Rollout":          detector.RolloutGVR,
+	} {
+		remediate(kind)
+		obj, err := dynamicClient.Resource(gvr).Namespace("default").Get(context.Background(), "web", metav1.GetOptions{})
+		require.NoError(t, err)
+		restartedAt, _, _ := unstructured.NestedString(obj.Object, "spec", "template", "metadata", "annotations", AnnotationRestartedAt)
+		assert.NotEmpty(t, restartedAt, kind)
+	}
+
+	// Without a dynamic client only the core workloads can be restarted
+	remediator.SetDynamicClient(nil)
+	issue := &models.Issue{ID: "issue-2", Type: "HighLatency", Namespace: "default", ResourceType: "DeploymentConfig", ResourceName: "web"}
+	_, err = remediator.Remediate(context.Background(), nil, issue)
+	assert.ErrorContains(t, err, "no dynamic client")
+}
@@ -0,0 +1,101 @@
+package remediation
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// ErrPermanent is matched by errors that retrying the remediation cannot fix
+var ErrPermanent = errors.New("permanent remediation error")
+
+// permanentError marks an error permanent without changing its message
+type permanentError struct {
+	err error
+}
+
+// Error returns the message of the wrapped error
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the wrapped error
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// Is matches ErrPermanent
+func (e *permanentError) Is(target error) bool {
+	return target == ErrPermanent
+}
+
+// Permanent marks err as an error that retrying the remediation cannot fix
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// httpStatusError is implemented by errors of HTTP APIs, such as the ArgoCD API,
+// that carry the response status
+type httpStatusError interface {
+	HTTPStatusCode() int
+}
+
+// ClassifyError returns whether retrying a remediation that failed with err may
+// succeed. Refusals, policy limits, missing or invalid resources, client errors of
+// HTTP APIs and errors marked Permanent are permanent; anything else, such as
+// conflicts and timeouts, is retryable.
+func ClassifyError(err error) models.ErrorClass {
+	var statusErr httpStatusError
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, ErrPermanent), errors.Is(err, ErrRemediationRefused), errors.Is(err, ErrNoPolicyRule),
+		errors.Is(err, ErrPolicyLimit), errors.Is(err, ErrNoRollbackRevision), errors.Is(err, ErrHelmReleaseNotFound), errors.Is(err, context.Canceled):
+		return models.ErrorClassPermanent
+	case apierrors.IsNotFound(err), apierrors.IsForbidden(err), apierrors.IsUnauthorized(err),
+		apierrors.IsInvalid(err), apierrors.IsBadRequest(err), apierrors.IsMethodNotSupported(err):
+		return models.ErrorClassPermanent
+	case errors.As(err, &statusErr) && statusErr.HTTPStatusCode() >= 400 && statusErr.HTTPStatusCode() < 500 &&
+		statusErr.HTTPStatusCode() != http.StatusRequestTimeout && statusErr.HTTPStatusCode() != http.StatusTooManyRequests:
+		return models.ErrorClassPermanent
+	default:
+		return models.ErrorClassRetryable
+	}
+}
+
+// ErrorReason returns why a remediation failed, as matched by the retry_on list of
+// a retry policy
+func ErrorReason(err error) string {
+	var statusErr httpStatusError
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, ErrHelmOperationInProgress):
+		return models.ErrorReasonLockContention
+	case apierrors.IsConflict(err):
+		return models.ErrorReasonConflict
+	case apierrors.IsTooManyRequests(err):
+		return models.ErrorReasonRateLimited
+	case errors.Is(err, context.DeadlineExceeded), apierrors.IsTimeout(err), apierrors.IsServerTimeout(err):
+		return models.ErrorReasonTimeout
+	case apierrors.IsInternalError(err), apierrors.IsServiceUnavailable(err), apierrors.IsUnexpectedServerError(err):
+		return models.ErrorReasonServerError
+	case errors.As(err, &statusErr):
+		switch code := statusErr.HTTPStatusCode(); {
+		case code == http.StatusTooManyRequests:
+			return models.ErrorReasonRateLimited
+		case code == http.StatusRequestTimeout, code == http.StatusGatewayTimeout:
+			return models.ErrorReasonTimeout
+		case code >= 500:
+			return models.ErrorReasonServerError
+		}
+	}
+	return models.ErrorReasonOther
+}
@@ -0,0 +1,44 @@
+package remediation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+func TestClassifyError(t *testing.T) {
+	deployments := schema.GroupResource{Group: "apps", Resource: "deployments"}
+	tests := []struct {
+		name string
+		err  error
+		want models.ErrorClass
+	}{
+		{name: "nil", err: nil, want: ""},
+		{name: "marked permanent", err: fmt.Errorf("wrapped: %w", Permanent(errors.New("bad input"))), want: models.ErrorClassPermanent},
+		{name: "guardrail refusal", err: &GuardrailError{Rule: GuardrailDisabled, Reason: "disabled"}, want: models.ErrorClassPermanent},
+		{name: "no policy rule", err: ErrNoPolicyRule, want: models.ErrorClassPermanent},
+		{name: "cancelled", err: context.Canceled, want: models.ErrorClassPermanent},
+		{name: "not found", err: apierrors.NewNotFound(deployments, "payment"), want: models.ErrorClassPermanent},
+		{name: "forbidden", err: apierrors.NewForbidden(deployments, "payment", errors.New("rbac")), want: models.ErrorClassPermanent},
+		{name: "conflict", err: apierrors.NewConflict(deployments, "payment", errors.New("stale")), want: models.ErrorClassRetryable},
+		{name: "deadline", err: context.DeadlineExceeded, want: models.ErrorClassRetryable},
+		{name: "unknown", err: errors.New("exit status 1"), want: models.ErrorClassRetryable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ClassifyError(tt.err))
+		})
+	}
+
+	// Marking an error permanent keeps its message and chain
+	err := Permanent(ErrNoRollbackRevision)
+	assert.Equal(t, ErrNoRollbackRevision.Error(), err.Error())
+	assert.ErrorIs(t, err, ErrNoRollbackRevision)
+}
@@ -0,0 +1,141 @@
+package remediation
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// Defaults of the retry policy fields left unset
+const (
+	DefaultRetryInitialBackoff = 2 * time.Second
+	DefaultRetryMaxBackoff     = 30 * time.Second
+	DefaultRetryMultiplier     = 2.0
+)
+
+// actionError attributes an error to the remediation action that failed, so that
+// per-action retry policies apply
+type actionError struct {
+	action string
+	err    error
+}
+
+// Error returns the message of the wrapped error
+func (e *actionError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the wrapped error
+func (e *actionError) Unwrap() error {
+	return e.err
+}
+
+// withAction attributes err to action without changing its message
+func withAction(action string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &actionError{action: action, err: err}
+}
+
+// failedAction returns the action err is attributed to, or "" if none
+func failedAction(err error) string {
+	var actionErr *actionError
+	if errors.As(err, &actionErr) {
+		return actionErr.action
+	}
+	return ""
+}
+
+// retrySchedule is a parsed retry policy
+type retrySchedule struct {
+	maxAttempts    int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	multiplier     float64
+	jitter         float64
+	retryOn        []string
+}
+
+// newRetrySchedule parses a validated retry policy, defaulting unset fields. An
+// unset max_attempts disables retries.
+func newRetrySchedule(policy models.RetryPolicy) retrySchedule {
+	schedule := retrySchedule{
+		maxAttempts:    max(policy.MaxAttempts, 1),
+		initialBackoff: DefaultRetryInitialBackoff,
+		maxBackoff:     DefaultRetryMaxBackoff,
+		multiplier:     DefaultRetryMultiplier,
+		jitter:         policy.Jitter,
+		retryOn:        policy.RetryOn,
+	}
+	if d, err := time.ParseDuration(policy.InitialBackoff); err == nil {
+		schedule.initialBackoff = d
+	}
+	if d, err := time.ParseDuration(policy.MaxBackoff); err == nil {
+		schedule.maxBackoff = d
+	}
+	if policy.Multiplier >= 1 {
+		schedule.multiplier = policy.Multiplier
+	}
+	return schedule
+}
+
+// retries reports whether a retryable error with the given reason is retried
+func (s retrySchedule) retries(reason string) bool {
+	if len(s.retryOn) == 0 {
+		return true
+	}
+	for _, r := range s.retryOn {
+		if r == reason {
+			return true
+		}
+	}
+	return false
+}
+
+// backoff returns how long to wait before the given retry, counted from 1: the
+// initial backoff grown exponentially and capped, with up to the jitter fraction
+// added or removed at random
+func (s retrySchedule) backoff(retry int) time.Duration {
+	backoff := float64(s.initialBackoff) * math.Pow(s.multiplier, float64(retry-1))
+	if s.maxBackoff > 0 {
+		backoff = math.Min(backoff, float64(s.maxBackoff))
+	}
+	if s.jitter > 0 {
+		// #nosec G404 -- jitter spreads retries, it needs no cryptographic randomness
+		backoff *= 1 + s.jitter*(2*rand.Float64()-1)
+	}
+	return time.Duration(backoff)
+}
+
+// RemediationAttempt describes a failed remediation attempt that is retried
+type RemediationAttempt struct {
+	Remediator  string
+	Action      string // action that failed, if known
+	Attempt     int    // counted from 1
+	MaxAttempts int
+	Reason      string // error reason, e.g. conflict
+	Backoff     time.Duration
+	Err         error
+	Result      *models.RemediationResult
+}
+
+// attemptRecorderKey is the context key of the function retried attempts are reported to
+type attemptRecorderKey struct{}
+
+// withAttemptRecorder returns a context through which the strategy selector
+// reports each failed attempt before retrying it
+func withAttemptRecorder(ctx context.Context, record func(RemediationAttempt)) context.Context {
+	return context.WithValue(ctx, attemptRecorderKey{}, record)
+}
+
+// reportAttempt reports an attempt to the recorder set by withAttemptRecorder
+func reportAttempt(ctx context.Context, attempt RemediationAttempt) {
+	if record, ok := ctx.Value(attemptRecorderKey{}).(func(RemediationAttempt)); ok {
+		record(attempt)
+	}
+}
@@ -0,0 +1,167 @@
+package remediation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/integrations"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// newConflictError returns the error of an update racing another writer
+func newConflictError() error {
+	return apierrors.NewConflict(schema.GroupResource{Group: "apps", Resource: "deployments"}, "payment",
+		errors.New("the object has been modified"))
+}
+
+func TestErrorReason(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantReason string
+		wantClass  models.ErrorClass
+	}{
+		{name: "conflict", err: fmt.Errorf("patch failed: %w", newConflictError()),
+			wantReason: models.ErrorReasonConflict, wantClass: models.ErrorClassRetryable},
+		{name: "helm lock", err: fmt.Errorf("revision 3 of helm release default/payment already exists: %w", ErrHelmOperationInProgress),
+			wantReason: models.ErrorReasonLockContention, wantClass: models.ErrorClassRetryable},
+		{name: "argocd server error", err: &integrations.APIError{Message: "ArgoCD sync failed", StatusCode: 503},
+			wantReason: models.ErrorReasonServerError, wantClass: models.ErrorClassRetryable},
+		{name: "argocd rate limit", err: &integrations.APIError{Message: "ArgoCD API error", StatusCode: 429},
+			wantReason: models.ErrorReasonRateLimited, wantClass: models.ErrorClassRetryable},
+		{name: "argocd client error", err: &integrations.APIError{Message: "ArgoCD API error", StatusCode: 404},
+			wantReason: models.ErrorReasonOther, wantClass: models.ErrorClassPermanent},
+		{name: "deadline", err: fmt.Errorf("rollout wait: %w", context.DeadlineExceeded),
+			wantReason: models.ErrorReasonTimeout, wantClass: models.ErrorClassRetryable},
+		{name: "other", err: errors.New("connection reset by peer"),
+			wantReason: models.ErrorReasonOther, wantClass: models.ErrorClassRetryable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantReason, ErrorReason(tt.err))
+			assert.Equal(t, tt.wantClass, ClassifyError(tt.err))
+		})
+	}
+}
+
+func TestRetrySchedule_Backoff(t *testing.T) {
+	schedule := newRetrySchedule(models.RetryPolicy{MaxAttempts: 5, InitialBackoff: "1s", MaxBackoff: "5s"})
+	assert.Equal(t, 5, schedule.maxAttempts)
+	assert.Equal(t, time.Second, schedule.backoff(1))
+	assert.Equal(t, 2*time.Second, schedule.backoff(2))
+	assert.Equal(t, 4*time.Second, schedule.backoff(3))
+	assert.Equal(t, 5*time.Second, schedule.backoff(4))
+
+	schedule = newRetrySchedule(models.RetryPolicy{InitialBackoff: "10s", Jitter: 0.5})
+	assert.Equal(t, 1, schedule.maxAttempts, "retries are disabled unless max_attempts is set")
+	for i := 0; i < 20; i++ {
+		backoff := schedule.backoff(1)
+		assert.GreaterOrEqual(t, backoff, 5*time.Second)
+		assert.LessOrEqual(t, backoff, 15*time.Second)
+	}
+}
+
+func TestStrategySelector_Retry(t *testing.T) {
+	actionPolicy := `
+retry:
+  actions:
+    restart_deployment:
+      max_attempts: 4
+`
+	tests := []struct {
+		name         string
+		policy       string
+		retryOn      []string
+		override     *models.RetryPolicy
+		err          error
+		failures     int
+		wantErr      bool
+		wantCalls    int
+		wantAttempts int
+	}{
+		{name: "retries until success", err: newConflictError(), failures: 2, wantCalls: 3, wantAttempts: 2},
+		{name: "gives up after max attempts", err: newConflictError(), wantErr: true, wantCalls: 3, wantAttempts: 2},
+		{name: "reason not retried", retryOn: []string{models.ErrorReasonLockContention}, err: newConflictError(),
+			wantErr: true, wantCalls: 1},
+		{name: "permanent error", err: Permanent(errors.New("bad image")), wantErr: true, wantCalls: 1},
+		{name: "request override", override: &models.RetryPolicy{MaxAttempts: 1}, err: newConflictError(),
+			wantErr: true, wantCalls: 1},
+		{name: "action policy", policy: actionPolicy, err: withAction("restart_deployment", newConflictError()),
+			wantErr: true, wantCalls: 4, wantAttempts: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := logrus.New()
+			log.SetLevel(logrus.ErrorLevel)
+			manual := &stubRemediator{name: "manual", err: tt.err, failures: tt.failures}
+
+			engine := NewPolicyEngine(nil, log)
+			if tt.policy != "" {
+				policy, err := ParsePolicy([]byte(tt.policy))
+				require.NoError(t, err)
+				engine.SetPolicy(policy)
+			}
+			selector := NewStrategySelector(log)
+			selector.SetFallbackRemediator(manual)
+			selector.SetPolicy(engine)
+			selector.SetRetryPolicy(models.RetryPolicy{MaxAttempts: 3, InitialBackoff: "1ms", RetryOn: tt.retryOn})
+
+			var attempts []RemediationAttempt
+			ctx := withAttemptRecorder(context.Background(), func(a RemediationAttempt) { attempts = append(attempts, a) })
+			issue := newTestIssue("issue-1")
+			issue.Retry = tt.override
+			info := models.NewDeploymentInfo("default", "payment", "Deployment", models.DeploymentMethodManual, 0.9)
+
+			result, err := selector.Remediate(ctx, info, issue)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Equal(t, models.RemediationStatusFailed, result.Status)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, models.RemediationStatusChanged, result.Status)
+			}
+			assert.Equal(t, tt.wantCalls, manual.calls)
+			require.Len(t, attempts, tt.wantAttempts)
+			for i, attempt := range attempts {
+				assert.Equal(t, i+1, attempt.Attempt)
+				assert.Equal(t, models.ErrorReasonConflict, attempt.Reason)
+				assert.Equal(t, models.RemediationStatusFailed, attempt.Result.Status)
+			}
+		})
+	}
+}
+
+func TestOrchestrator_RecordsRetriedAttempts(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	selector := NewStrategySelector(log)
+	selector.SetFallbackRemediator(&stubRemediator{name: "manual", err: newConflictError(), failures: 1})
+	selector.SetRetryPolicy(models.RetryPolicy{MaxAttempts: 2, InitialBackoff: "1ms"})
+	o := newTestOrchestrator(selector)
+
+	wf, _, err := o.TriggerRemediation(context.Background(), "inc-1", newTestIssue("inc-1"))
+	require.NoError(t, err)
+	completed := waitForStatus(t, o, wf.ID, models.WorkflowStatusCompleted)
+
+	// Detection, the failed attempt and its retry
+	require.Len(t, completed.Steps, 3)
+	first, retry := completed.Steps[1], completed.Steps[2]
+	assert.Equal(t, "failed", first.Status)
+	assert.Contains(t, first.ErrorMessage, "the object has been modified")
+	require.NotNil(t, first.Result)
+	assert.Equal(t, models.ErrorClassRetryable, first.Result.ErrorClass)
+
+	assert.Equal(t, "Retry manual remediation for CrashLoopBackOff after conflict error (attempt 2 of 2)", retry.Description)
+	assert.Equal(t, "completed", retry.Status)
+	require.NotNil(t, retry.Result)
+	assert.Equal(t, models.RemediationStatusChanged, retry.Result.Status)
+}
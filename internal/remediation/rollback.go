@@ -0,0 +1,277 @@
+package remediation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// deploymentRevisionAnnotation is set by the Deployment controller on a Deployment
+// and its ReplicaSets
+const deploymentRevisionAnnotation = "deployment.kubernetes.io/revision"
+
+// ErrNoRollbackRevision is returned when a workload has no revision to roll back to
+var ErrNoRollbackRevision = errors.New("no revision to roll back to")
+
+// rollbackRecorderKey is the context key of the function rollbacks are reported to
+type rollbackRecorderKey struct{}
+
+// withRollbackRecorder returns a context through which remediators report the
+// rollbacks they perform
+func withRollbackRecorder(ctx context.Context, record func(models.Rollback)) context.Context {
+	return context.WithValue(ctx, rollbackRecorderKey{}, record)
+}
+
+// reportRollback reports a rollback to the recorder set by withRollbackRecorder
+func reportRollback(ctx context.Context, rollback models.Rollback) {
+	if record, ok := ctx.Value(rollbackRecorderKey{}).(func(models.Rollback)); ok {
+		record(rollback)
+	}
+}
+
+// rollbackRevision returns the revision a rollback targets: the one requested with
+// the issue, then the one set by the policy action, or zero for the last available
+func rollbackRevision(action *PolicyAction, issue *models.Issue) (int64, error) {
+	if issue.RollbackRevision > 0 {
+		return issue.RollbackRevision, nil
+	}
+	value := action.Param("revision", "0")
+	revision, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || revision < 0 {
+		return 0, Permanent(fmt.Errorf("invalid rollback revision %q", value))
+	}
+	return revision, nil
+}
+
+// rollback rolls the issue's workload back and reports the revisions it moved between
+func (mr *ManualRemediator) rollback(ctx context.Context, action *PolicyAction, issue *models.Issue, result *models.RemediationResult) error {
+	toRevision, err := rollbackRevision(action, issue)
+	if err != nil {
+		return err
+	}
+
+	var rollback *models.Rollback
+	var uid types.UID
+	switch action.Action {
+	case "rollback_statefulset":
+		rollback, uid, err = mr.rollbackStatefulSet(ctx, issue.Namespace, issue.ResourceName, toRevision)
+	case "rollback_daemonset":
+		rollback, uid, err = mr.rollbackDaemonSet(ctx, issue.Namespace, issue.ResourceName, toRevision)
+	default:
+		rollback, uid, err = mr.rollbackDeployment(ctx, issue.Namespace, issue.ResourceName, toRevision)
+	}
+	if err != nil {
+		return err
+	}
+	reportRollback(ctx, *rollback)
+	result.AddAction(action.Action, fmt.Sprintf("%s %s/%s", rollback.Kind, issue.Namespace, rollback.Name),
+		fmt.Sprintf("revision %d", rollback.FromRevision), fmt.Sprintf("revision %d", rollback.ToRevision))
+
+	target := workloadRef(rollback.Kind, issue.Namespace, rollback.Name)
+	target.UID = uid
+	recordEvent(ctx, mr.recorder, RemediationEvent{
+		Target:     target,
+		Reason:     EventReasonRolledBack,
+		Action:     action.Action,
+		Remediator: mr.Name(),
+		Message: fmt.Sprintf("Rolled back %s from revision %d to revision %d to remediate %s",
+			strings.ToLower(rollback.Kind), rollback.FromRevision, rollback.ToRevision, issue.Type),
+	})
+	return nil
+}
+
+// rollbackDeployment restores the pod template of a previous ReplicaSet revision, as
+// kubectl rollout undo does. Without a revision it picks the newest earlier revision
+// that is running all its replicas available.
+func (mr *ManualRemediator) rollbackDeployment(ctx context.Context, namespace, name string, toRevision int64) (*models.Rollback, types.UID, error) {
+	deployments := mr.clientset.AppsV1().Deployments(namespace)
+	deployment, err := deployments.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get deployment: %w", err)
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid deployment selector: %w", err)
+	}
+	list, err := mr.clientset.AppsV1().ReplicaSets(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to list replicasets: %w", err)
+	}
+
+	current := parseRevision(deployment.Annotations)
+	replicaSets := make(map[int64]*appsv1.ReplicaSet)
+	for i := range list.Items {
+		rs := &list.Items[i]
+		if !metav1.IsControlledBy(rs, deployment) {
+			continue
+		}
+		revision := parseRevision(rs.Annotations)
+		replicaSets[revision] = rs
+		if deployment.Annotations[deploymentRevisionAnnotation] == "" && revision > current {
+			current = revision
+		}
+	}
+
+	var target *appsv1.ReplicaSet
+	var targetRevision int64
+	switch {
+	case toRevision > 0 && toRevision == current:
+		return nil, "", Permanent(fmt.Errorf("deployment %s/%s is already at revision %d", namespace, name, current))
+	case toRevision > 0:
+		if target = replicaSets[toRevision]; target == nil {
+			return nil, "", Permanent(fmt.Errorf("deployment %s/%s has no revision %d", namespace, name, toRevision))
+		}
+		targetRevision = toRevision
+	default:
+		for revision, rs := range replicaSets {
+			if revision < current && revision > targetRevision && replicaSetAvailable(rs) {
+				target, targetRevision = rs, revision
+			}
+		}
+		if target == nil {
+			return nil, "", fmt.Errorf("deployment %s/%s has no earlier revision with available replicas: %w", namespace, name, ErrNoRollbackRevision)
+		}
+	}
+
+	mr.log.WithFields(logrus.Fields{
+		"namespace":     namespace,
+		"deployment":    name,
+		"from_revision": current,
+		"to_revision":   targetRevision,
+		"replicaset":    target.Name,
+	}).Info("Rolling back deployment")
+
+	template := target.Spec.Template.DeepCopy()
+	delete(template.Labels, appsv1.DefaultDeploymentUniqueLabelKey)
+	deployment.Spec.Template = *template
+	updated, err := deployments.Update(ctx, deployment, metav1.UpdateOptions{})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to update deployment: %w", err)
+	}
+
+	return &models.Rollback{Kind: "Deployment", Name: name, FromRevision: current, ToRevision: targetRevision}, updated.UID, nil
+}
+
+// replicaSetAvailable returns true for a ReplicaSet running all its replicas
+// available, such as the old ReplicaSet of a stuck rollout. A ReplicaSet scaled to
+// zero is not known to be healthy.
+func replicaSetAvailable(rs *appsv1.ReplicaSet) bool {
+	replicas := int32(1)
+	if rs.Spec.Replicas != nil {
+		replicas = *rs.Spec.Replicas
+	}
+	return replicas > 0 && rs.Status.AvailableReplicas >= replicas
+}
+
+// parseRevision returns the deployment revision annotation, or zero
+func parseRevision(annotations map[string]string) int64 {
+	revision, _ := strconv.ParseInt(annotations[deploymentRevisionAnnotation], 10, 64)
+	return revision
+}
+
+// rollbackStatefulSet restores a previous ControllerRevision of a StatefulSet
+func (mr *ManualRemediator) rollbackStatefulSet(ctx context.Context, namespace, name string, toRevision int64) (*models.Rollback, types.UID, error) {
+	statefulSets := mr.clientset.AppsV1().StatefulSets(namespace)
+	sts, err := statefulSets.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get statefulset: %w", err)
+	}
+	return mr.rollbackControllerRevision(ctx, "StatefulSet", sts, sts.Spec.Selector, sts.Status.UpdateRevision, toRevision,
+		func(ctx context.Context, patch []byte) (types.UID, error) {
+			updated, err := statefulSets.Patch(ctx, name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+			if err != nil {
+				return "", err
+			}
+			return updated.UID, nil
+		})
+}
+
+// rollbackDaemonSet restores a previous ControllerRevision of a DaemonSet
+func (mr *ManualRemediator) rollbackDaemonSet(ctx context.Context, namespace, name string, toRevision int64) (*models.Rollback, types.UID, error) {
+	daemonSets := mr.clientset.AppsV1().DaemonSets(namespace)
+	ds, err := daemonSets.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get daemonset: %w", err)
+	}
+	// The DaemonSet controller keeps its current revision the newest
+	return mr.rollbackControllerRevision(ctx, "DaemonSet", ds, ds.Spec.Selector, "", toRevision,
+		func(ctx context.Context, patch []byte) (types.UID, error) {
+			updated, err := daemonSets.Patch(ctx, name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+			if err != nil {
+				return "", err
+			}
+			return updated.UID, nil
+		})
+}
+
+// rollbackControllerRevision applies the stored template patch of a previous
+// ControllerRevision of owner, as kubectl rollout undo does. currentName names the
+// current revision; empty means the newest.
+func (mr *ManualRemediator) rollbackControllerRevision(ctx context.Context, kind string, owner metav1.Object, labelSelector *metav1.LabelSelector,
+	currentName string, toRevision int64, patch func(context.Context, []byte) (types.UID, error)) (*models.Rollback, types.UID, error) {
+	namespace, name := owner.GetNamespace(), owner.GetName()
+	selector, err := metav1.LabelSelectorAsSelector(labelSelector)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid %s selector: %w", kind, err)
+	}
+	list, err := mr.clientset.AppsV1().ControllerRevisions(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to list controller revisions: %w", err)
+	}
+
+	var current int64
+	revisions := make(map[int64]*appsv1.ControllerRevision)
+	for i := range list.Items {
+		cr := &list.Items[i]
+		if !metav1.IsControlledBy(cr, owner) {
+			continue
+		}
+		revisions[cr.Revision] = cr
+		if cr.Name == currentName || (currentName == "" && cr.Revision > current) {
+			current = cr.Revision
+		}
+	}
+
+	var target *appsv1.ControllerRevision
+	switch {
+	case toRevision > 0 && toRevision == current:
+		return nil, "", Permanent(fmt.Errorf("%s %s/%s is already at revision %d", kind, namespace, name, current))
+	case toRevision > 0:
+		if target = revisions[toRevision]; target == nil {
+			return nil, "", Permanent(fmt.Errorf("%s %s/%s has no revision %d", kind, namespace, name, toRevision))
+		}
+	default:
+		for revision, cr := range revisions {
+			if revision < current && (target == nil || revision > target.Revision) {
+				target = cr
+			}
+		}
+		if target == nil {
+			return nil, "", fmt.Errorf("%s %s/%s: %w", kind, namespace, name, ErrNoRollbackRevision)
+		}
+	}
+
+	mr.log.WithFields(logrus.Fields{
+		"namespace":     namespace,
+		"kind":          kind,
+		"name":          name,
+		"from_revision": current,
+		"to_revision":   target.Revision,
+	}).Info("Rolling back to controller revision")
+
+	uid, err := patch(ctx, target.Data.Raw)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to patch %s: %w", kind, err)
+	}
+	return &models.Rollback{Kind: kind, Name: name, FromRevision: current, ToRevision: target.Revision}, uid, nil
+}
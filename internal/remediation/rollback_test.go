@@ -0,0 +1,188 @@
+package remediation
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/detector"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// newRolloutDeployment returns a deployment at revision 3 whose newest ReplicaSet
+// never became available, with revision 1 scaled down and revision 2 still running
+// the given available replicas
+func newRolloutDeployment(previousAvailable int32) []runtime.Object {
+	labels := map[string]string{"app": "payment"}
+	isController := true
+	template := func(image, hash string) corev1.PodTemplateSpec {
+		return corev1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "payment", appsv1.DefaultDeploymentUniqueLabelKey: hash}},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: image}}},
+		}
+	}
+	replicaSet := func(revision int, replicas, available int32) *appsv1.ReplicaSet {
+		hash := fmt.Sprintf("hash%d", revision)
+		return &appsv1.ReplicaSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "payment-" + hash, Namespace: "default", Labels: template("", hash).Labels,
+				Annotations:     map[string]string{deploymentRevisionAnnotation: fmt.Sprint(revision)},
+				OwnerReferences: []metav1.OwnerReference{{Kind: "Deployment", Name: "payment", UID: "payment-uid", Controller: &isController}},
+			},
+			Spec:   appsv1.ReplicaSetSpec{Replicas: &replicas, Template: template(fmt.Sprintf("payment:v%d", revision), hash)},
+			Status: appsv1.ReplicaSetStatus{AvailableReplicas: available},
+		}
+	}
+	replicas := int32(2)
+	return []runtime.Object{
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "payment", Namespace: "default", UID: "payment-uid",
+				Annotations: map[string]string{deploymentRevisionAnnotation: "3"},
+			},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: &replicas,
+				Selector: &metav1.LabelSelector{MatchLabels: labels},
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{Labels: labels},
+					Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: "payment:v3"}}},
+				},
+			},
+		},
+		replicaSet(1, 0, 0),
+		replicaSet(2, previousAvailable, previousAvailable),
+		replicaSet(3, 2, 0),
+	}
+}
+
+func TestManualRemediator_RollbackDeployment(t *testing.T) {
+	tests := []struct {
+		name      string
+		revision  int64
+		available int32
+		wantImage string
+		want      models.Rollback
+		wantErr   string
+	}{
+		{name: "last available", available: 1, wantImage: "payment:v2",
+			want: models.Rollback{Kind: "Deployment", Name: "payment", FromRevision: 3, ToRevision: 2}},
+		{name: "earlier revisions scaled down", wantErr: "no earlier revision with available replicas"},
+		{name: "explicit revision", revision: 1, wantImage: "payment:v1",
+			want: models.Rollback{Kind: "Deployment", Name: "payment", FromRevision: 3, ToRevision: 1}},
+		{name: "current revision", revision: 3, wantErr: "already at revision 3"},
+		{name: "missing revision", revision: 7, wantErr: "has no revision 7"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientset := fake.NewSimpleClientset(newRolloutDeployment(tt.available)...)
+			log := logrus.New()
+			log.SetLevel(logrus.ErrorLevel)
+			remediator := NewManualRemediator(clientset, log)
+
+			var recorded *models.Rollback
+			ctx := withRollbackRecorder(context.Background(), func(rollback models.Rollback) { recorded = &rollback })
+			issue := &models.Issue{ID: "issue-1", Type: "RolloutFailed", Namespace: "default", ResourceType: "Deployment",
+				ResourceName: "payment", RollbackRevision: tt.revision}
+			info := models.NewDeploymentInfo("default", "payment", "Deployment", models.DeploymentMethodManual, 0.6)
+			_, err := remediator.Remediate(ctx, info, issue)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				assert.Equal(t, models.ErrorClassPermanent, ClassifyError(err))
+				return
+			}
+			require.NoError(t, err)
+			require.NotNil(t, recorded)
+			assert.Equal(t, tt.want, *recorded)
+
+			updated, err := clientset.AppsV1().Deployments("default").Get(context.Background(), "payment", metav1.GetOptions{})
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantImage, updated.Spec.Template.Spec.Containers[0].Image)
+			// The ReplicaSet hash label is left for the Deployment controller to set
+			assert.NotContains(t, updated.Spec.Template.Labels, appsv1.DefaultDeploymentUniqueLabelKey)
+		})
+	}
+}
+
+func TestManualRemediator_RollbackStatefulSet(t *testing.T) {
+	isController := true
+	revision := func(number int64, image string) *appsv1.ControllerRevision {
+		return &appsv1.ControllerRevision{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: fmt.Sprintf("db-%d", number), Namespace: "default", Labels: map[string]string{"app": "db"},
+				OwnerReferences: []metav1.OwnerReference{{Kind: "StatefulSet", Name: "db", UID: "db-uid", Controller: &isController}},
+			},
+			Revision: number,
+			Data: runtime.RawExtension{Raw: []byte(fmt.Sprintf(
+				`{"spec":{"template":{"metadata":{"labels":{"app":"db"}},"spec":{"containers":[{"name":"db","image":%q}]},"$patch":"replace"}}}`, image))},
+		}
+	}
+	clientset := fake.NewSimpleClientset(
+		&appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default", UID: "db-uid"},
+			Spec: appsv1.StatefulSetSpec{
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "db"}},
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "db"}},
+					Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "db", Image: "db:v2"}}},
+				},
+			},
+			Status: appsv1.StatefulSetStatus{CurrentRevision: "db-1", UpdateRevision: "db-2"},
+		},
+		revision(1, "db:v1"),
+		revision(2, "db:v2"),
+	)
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	remediator := NewManualRemediator(clientset, log)
+
+	rollback, uid, err := remediator.rollbackStatefulSet(context.Background(), "default", "db", 0)
+	require.NoError(t, err)
+	assert.Equal(t, models.Rollback{Kind: "StatefulSet", Name: "db", FromRevision: 2, ToRevision: 1}, *rollback)
+	assert.Equal(t, types.UID("db-uid"), uid)
+
+	updated, err := clientset.AppsV1().StatefulSets("default").Get(context.Background(), "db", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "db:v1", updated.Spec.Template.Spec.Containers[0].Image)
+
+	_, _, err = remediator.rollbackStatefulSet(context.Background(), "default", "db", 2)
+	assert.ErrorContains(t, err, "already at revision 2")
+}
+
+func TestOrchestrator_RecordsRollbackRevisions(t *testing.T) {
+	clientset := fake.NewSimpleClientset(newRolloutDeployment(1)...)
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	selector := NewStrategySelector(log)
+	selector.SetFallbackRemediator(NewManualRemediator(clientset, log))
+	o := NewOrchestrator(detector.NewDetector(clientset, log), selector, log)
+
+	issue := newTestIssue("inc-1")
+	issue.Type = "RolloutFailed"
+	wf, _, err := o.TriggerRemediation(context.Background(), "inc-1", issue)
+	require.NoError(t, err)
+
+	completed := waitForStatus(t, o, wf.ID, models.WorkflowStatusCompleted)
+	require.NotNil(t, completed.Rollback)
+	assert.Equal(t, models.Rollback{Kind: "Deployment", Name: "payment", FromRevision: 3, ToRevision: 2}, *completed.Rollback)
+	require.Len(t, completed.Selection, 1)
+	assert.Equal(t, "manual", completed.Selection[0].Remediator)
+	assert.Equal(t, models.SelectionSelected, completed.Selection[0].Decision)
+
+	result := completed.Steps[1].Result
+	require.NotNil(t, result)
+	assert.Equal(t, models.RemediationResultVersion, result.Version)
+	assert.Equal(t, models.RemediationStatusChanged, result.Status)
+	assert.Equal(t, []models.PerformedAction{{Action: "rollback_deployment", Target: "Deployment default/payment",
+		Before: "revision 3", After: "revision 2"}}, result.Actions)
+}
@@ -3,6 +3,8 @@ package remediation
 import (
 	"context"
 	"fmt"
+	"sort"
+	"time"
 
 	"github.com/sirupsen/logrus"
 
@@ -13,13 +15,22 @@ import (
 type StrategySelector struct {
 	remediators        []Remediator
 	fallbackRemediator Remediator
+	guardrails         *Guardrails     // optional; refuses remediation of opted-out resources
+	plugins            *PluginRegistry // optional; external remediators offered each issue first
+	policy             *PolicyEngine   // optional; allows failover after retryable failures
+	priorities         map[string]int  // by remediator name; higher is tried first, default 0
+	minConfidence      float64         // detection confidence below which only recommendations are made
 	log                *logrus.Logger
+
+	// retry is the default retry policy, overridden by the policy and the issue
+	retry models.RetryPolicy
 }
 
 // NewStrategySelector creates a new strategy selector
 func NewStrategySelector(log *logrus.Logger) *StrategySelector {
 	return &StrategySelector{
 		remediators: make([]Remediator, 0),
+		priorities:  make(map[string]int),
 		log:         log,
 	}
 }
@@ -36,8 +47,78 @@ func (ss *StrategySelector) SetFallbackRemediator(remediator Remediator) {
 	ss.log.WithField("remediator", remediator.Name()).Info("Fallback remediator set")
 }
 
-// SelectRemediator chooses the appropriate remediator based on deployment info
+// SetGuardrails sets the guardrails checked before any remediator mutates a resource
+func (ss *StrategySelector) SetGuardrails(guardrails *Guardrails) {
+	ss.guardrails = guardrails
+}
+
+// SetPluginRegistry sets the remediation plugins offered each issue before the
+// registered remediators
+func (ss *StrategySelector) SetPluginRegistry(plugins *PluginRegistry) {
+	ss.plugins = plugins
+}
+
+// SetPolicy sets the remediation policy whose failover rules decide when a
+// retryable failure falls through to the next capable remediator
+func (ss *StrategySelector) SetPolicy(policy *PolicyEngine) {
+	ss.policy = policy
+}
+
+// SetPriority sets the priority of a registered remediator. Remediators are tried
+// from the highest priority down, in registration order for equal priorities.
+func (ss *StrategySelector) SetPriority(remediator string, priority int) {
+	ss.priorities[remediator] = priority
+}
+
+// SetMinConfidence sets the detection confidence below which remediation is
+// downgraded to recommendations (zero disables the threshold)
+func (ss *StrategySelector) SetMinConfidence(confidence float64) {
+	ss.minConfidence = confidence
+}
+
+// SetRetryPolicy sets the default retry policy of remediators. The remediation
+// policy may override it per remediator and action, and each issue may override
+// both.
+func (ss *StrategySelector) SetRetryPolicy(policy models.RetryPolicy) {
+	ss.retry = policy
+}
+
+// SetEventRecorder passes recorder to every registered remediator that records events
+func (ss *StrategySelector) SetEventRecorder(recorder EventRecorder) {
+	remediators := ss.remediators
+	if ss.fallbackRemediator != nil {
+		remediators = append(remediators[:len(remediators):len(remediators)], ss.fallbackRemediator)
+	}
+	for _, remediator := range remediators {
+		if r, ok := remediator.(interface{ SetEventRecorder(EventRecorder) }); ok {
+			r.SetEventRecorder(recorder)
+		}
+	}
+}
+
+// SelectRemediator chooses the appropriate remediator based on deployment info.
+// Plugins are not considered, as they match on the issue.
 func (ss *StrategySelector) SelectRemediator(deploymentInfo *models.DeploymentInfo) Remediator {
+	return ss.SelectRemediatorForIssue(context.Background(), deploymentInfo, nil)
+}
+
+// SelectRemediatorForIssue chooses the remediator for an issue: the first plugin
+// that accepts it, else the highest priority registered remediator that can handle
+// the deployment, else the fallback
+func (ss *StrategySelector) SelectRemediatorForIssue(ctx context.Context, deploymentInfo *models.DeploymentInfo, issue *models.Issue) Remediator {
+	candidates, _ := ss.candidates(ctx, deploymentInfo, issue)
+	if len(candidates) == 0 {
+		// This shouldn't happen if fallback is set properly
+		ss.log.Error("No remediator found and no fallback set")
+		return nil
+	}
+	return candidates[0]
+}
+
+// candidates returns the remediators able to handle the issue, primary first: a
+// plugin that accepts it, then the capable registered remediators by priority, then
+// the fallback. The decisions explain the choice of the primary.
+func (ss *StrategySelector) candidates(ctx context.Context, deploymentInfo *models.DeploymentInfo, issue *models.Issue) ([]Remediator, []models.SelectionDecision) {
 	ss.log.WithFields(logrus.Fields{
 		"method":     deploymentInfo.Method,
 		"confidence": deploymentInfo.Confidence,
@@ -45,49 +126,167 @@ func (ss *StrategySelector) SelectRemediator(deploymentInfo *models.DeploymentIn
 		"resource":   deploymentInfo.ResourceName,
 	}).Debug("Selecting remediation strategy")
 
-	// Try each registered remediator in order
-	for _, remediator := range ss.remediators {
-		if remediator.CanRemediate(deploymentInfo) {
+	var candidates []Remediator
+	var decisions []models.SelectionDecision
+	decide := func(remediator Remediator, decision, reason string) {
+		decisions = append(decisions, ss.decision(remediator, decision, reason))
+	}
+
+	if issue != nil {
+		if plugin := ss.plugins.Select(ctx, deploymentInfo, issue); plugin != nil {
+			ss.log.WithFields(logrus.Fields{
+				"remediator": plugin.Name(),
+				"method":     deploymentInfo.Method,
+				"issue_id":   issue.ID,
+			}).Info("Remediation plugin selected")
+			RecordStrategySelection(plugin.Name(), string(deploymentInfo.Method), true)
+			decide(plugin, models.SelectionSelected, fmt.Sprintf("plugin accepted the %s issue", issue.Type))
+			candidates = append(candidates, plugin)
+		}
+	}
+
+	// Try each registered remediator by priority
+	for _, remediator := range ss.ordered() {
+		if !remediator.CanRemediate(deploymentInfo) {
+			// Record non-selection
+			RecordStrategySelection(remediator.Name(), string(deploymentInfo.Method), false)
+			decide(remediator, models.SelectionSkipped, fmt.Sprintf("cannot handle %s deployments", deploymentInfo.Method))
+			continue
+		}
+		if len(candidates) == 0 {
 			ss.log.WithFields(logrus.Fields{
 				"remediator": remediator.Name(),
 				"method":     deploymentInfo.Method,
+				"priority":   ss.priorities[remediator.Name()],
 			}).Info("Remediator selected")
 
 			// Record strategy selection metrics
 			RecordStrategySelection(remediator.Name(), string(deploymentInfo.Method), true)
-
-			return remediator
-		} else {
-			// Record non-selection
-			RecordStrategySelection(remediator.Name(), string(deploymentInfo.Method), false)
+			decide(remediator, models.SelectionSelected, fmt.Sprintf("highest priority remediator that can handle %s deployments", deploymentInfo.Method))
 		}
+		candidates = append(candidates, remediator)
 	}
 
 	// Fall back to default remediator
-	if ss.fallbackRemediator != nil {
-		ss.log.WithFields(logrus.Fields{
-			"remediator": ss.fallbackRemediator.Name(),
-			"method":     deploymentInfo.Method,
-		}).Warn("No specific remediator matched, using fallback")
+	if ss.fallbackRemediator != nil && !containsRemediator(candidates, ss.fallbackRemediator) {
+		if len(candidates) == 0 {
+			ss.log.WithFields(logrus.Fields{
+				"remediator": ss.fallbackRemediator.Name(),
+				"method":     deploymentInfo.Method,
+			}).Warn("No specific remediator matched, using fallback")
 
-		// Record fallback strategy selection
-		RecordStrategySelection(ss.fallbackRemediator.Name(), string(deploymentInfo.Method), true)
+			// Record fallback strategy selection
+			RecordStrategySelection(ss.fallbackRemediator.Name(), string(deploymentInfo.Method), true)
+			decide(ss.fallbackRemediator, models.SelectionSelected, "no specific remediator matched, using the fallback")
+		}
+		candidates = append(candidates, ss.fallbackRemediator)
+	}
+	return candidates, decisions
+}
 
-		return ss.fallbackRemediator
+// containsRemediator returns true if remediators includes remediator
+func containsRemediator(remediators []Remediator, remediator Remediator) bool {
+	for _, r := range remediators {
+		if r == remediator {
+			return true
+		}
 	}
+	return false
+}
 
-	// This shouldn't happen if fallback is set properly
-	ss.log.Error("No remediator found and no fallback set")
-	return nil
+// ordered returns the registered remediators from the highest priority down
+func (ss *StrategySelector) ordered() []Remediator {
+	ordered := append([]Remediator(nil), ss.remediators...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ss.priorities[ordered[i].Name()] > ss.priorities[ordered[j].Name()]
+	})
+	return ordered
 }
 
-// Remediate executes remediation using the selected strategy
-func (ss *StrategySelector) Remediate(ctx context.Context, deploymentInfo *models.DeploymentInfo, issue *models.Issue) error {
-	remediator := ss.SelectRemediator(deploymentInfo)
-	if remediator == nil {
-		return fmt.Errorf("no remediator available for deployment method: %s", deploymentInfo.Method)
+// decision builds a selection decision about a remediator
+func (ss *StrategySelector) decision(remediator Remediator, decision, reason string) models.SelectionDecision {
+	return models.SelectionDecision{
+		Remediator: remediator.Name(),
+		Decision:   decision,
+		Reason:     reason,
+		Priority:   ss.priorities[remediator.Name()],
+		Timestamp:  time.Now(),
+	}
+}
+
+// Remediate executes remediation using the selected strategy. Below the confidence
+// threshold it only recommends what the selected remediator would do. A remediator
+// failing with a retryable error is retried as its retry policy allows; once it
+// still fails, the next capable remediator is tried if a policy failover rule
+// allows it. Every decision is reported to the selection
+// recorder of ctx. The result is returned even on failure, classified as retryable
+// or permanent.
+func (ss *StrategySelector) Remediate(ctx context.Context, deploymentInfo *models.DeploymentInfo, issue *models.Issue) (*models.RemediationResult, error) {
+	startTime := time.Now()
+	if err := ctx.Err(); err != nil {
+		return ss.failed(models.NewRemediationResult(ss.Name()), startTime, fmt.Errorf("remediation cancelled before start: %w", err))
+	}
+
+	candidates, decisions := ss.candidates(ctx, deploymentInfo, issue)
+	for _, decision := range decisions {
+		reportSelection(ctx, decision)
+	}
+	if len(candidates) == 0 {
+		ss.log.Error("No remediator found and no fallback set")
+		return ss.failed(models.NewRemediationResult(ss.Name()), startTime,
+			Permanent(fmt.Errorf("no remediator available for deployment method: %s", deploymentInfo.Method)))
 	}
 
+	if reason, low := ss.lowConfidence(deploymentInfo); low {
+		return ss.recommendOnly(ctx, candidates[0], deploymentInfo, issue, reason, startTime)
+	}
+
+	var failed Remediator
+	var result *models.RemediationResult
+	var err error
+	for _, remediator := range candidates {
+		if failed != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			rule, allowed := ss.policy.AllowsFailover(ctx, deploymentInfo, issue, failed.Name(), remediator.Name())
+			if !allowed {
+				RecordStrategyFailover(failed.Name(), remediator.Name(), "denied")
+				reportSelection(ctx, ss.decision(remediator, models.SelectionFailoverDenied,
+					fmt.Sprintf("no failover rule allows falling through from %s", failed.Name())))
+				continue
+			}
+			RecordStrategyFailover(failed.Name(), remediator.Name(), "allowed")
+			reportSelection(ctx, ss.decision(remediator, models.SelectionFailover,
+				fmt.Sprintf("failover rule %s allows falling through from %s after a retryable failure", rule, failed.Name())))
+			ss.log.WithFields(logrus.Fields{
+				"from":     failed.Name(),
+				"to":       remediator.Name(),
+				"rule":     rule,
+				"issue_id": issue.ID,
+			}).Warn("Failing over to the next capable remediator")
+		}
+
+		result, err = ss.remediateWith(ctx, remediator, deploymentInfo, issue)
+		if err == nil {
+			result.Finish(startTime)
+			return result, nil
+		}
+
+		class := ClassifyError(err)
+		reportSelection(ctx, ss.decision(remediator, models.SelectionFailed, fmt.Sprintf("%s error: %v", class, err)))
+		if class != models.ErrorClassRetryable {
+			break
+		}
+		failed = remediator
+	}
+	return ss.failed(result, startTime, err)
+}
+
+// remediateWith runs one remediator after checking guardrails, retrying it while it
+// fails with retryable errors its retry policy retries on. Each failed attempt
+// that is retried is reported to the attempt recorder of ctx.
+func (ss *StrategySelector) remediateWith(ctx context.Context, remediator Remediator, deploymentInfo *models.DeploymentInfo, issue *models.Issue) (*models.RemediationResult, error) {
 	ss.log.WithFields(logrus.Fields{
 		"issue_id":   issue.ID,
 		"issue_type": issue.Type,
@@ -96,23 +295,178 @@ func (ss *StrategySelector) Remediate(ctx context.Context, deploymentInfo *model
 		"resource":   issue.ResourceName,
 	}).Info("Starting remediation with selected strategy")
 
-	err := remediator.Remediate(ctx, deploymentInfo, issue)
+	if err := ss.checkGuardrails(ctx, remediator, deploymentInfo, issue); err != nil {
+		return models.NewRemediationResult(remediator.Name()), err
+	}
+
+	for attempt := 1; ; attempt++ {
+		attemptStart := time.Now()
+		result, err := ss.runRemediator(ctx, remediator, deploymentInfo, issue)
+		if err == nil || ClassifyError(err) != models.ErrorClassRetryable {
+			return result, err
+		}
+
+		action, reason := failedAction(err), ErrorReason(err)
+		schedule := newRetrySchedule(ss.retryPolicy(remediator.Name(), action, issue))
+		if !schedule.retries(reason) {
+			return result, err
+		}
+		if attempt >= schedule.maxAttempts {
+			if attempt > 1 {
+				RecordRemediationRetriesExhausted(remediator.Name(), reason)
+			}
+			return result, err
+		}
+
+		backoff := schedule.backoff(attempt)
+		RecordRemediationRetry(remediator.Name(), action, reason)
+		result.Fail(err, models.ErrorClassRetryable)
+		result.Finish(attemptStart)
+		reportAttempt(ctx, RemediationAttempt{
+			Remediator:  remediator.Name(),
+			Action:      action,
+			Attempt:     attempt,
+			MaxAttempts: schedule.maxAttempts,
+			Reason:      reason,
+			Backoff:     backoff,
+			Err:         err,
+			Result:      result,
+		})
+		ss.log.WithError(err).WithFields(logrus.Fields{
+			"remediator":   remediator.Name(),
+			"issue_id":     issue.ID,
+			"action":       action,
+			"reason":       reason,
+			"attempt":      attempt,
+			"max_attempts": schedule.maxAttempts,
+			"backoff":      backoff.String(),
+		}).Warn("Remediation attempt failed, retrying after backoff")
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return result, err
+		case <-timer.C:
+		}
+	}
+}
+
+// runRemediator makes one remediation attempt with the remediator
+func (ss *StrategySelector) runRemediator(ctx context.Context, remediator Remediator, deploymentInfo *models.DeploymentInfo, issue *models.Issue) (*models.RemediationResult, error) {
+	result, err := remediator.Remediate(ctx, deploymentInfo, issue)
+	if result == nil {
+		result = models.NewRemediationResult(remediator.Name())
+	}
 	if err != nil {
 		ss.log.WithError(err).WithFields(logrus.Fields{
 			"remediator": remediator.Name(),
 			"issue_id":   issue.ID,
 		}).Error("Remediation failed")
-		return fmt.Errorf("%s remediation failed: %w", remediator.Name(), err)
+		return result, fmt.Errorf("%s remediation failed: %w", remediator.Name(), err)
 	}
 
 	ss.log.WithFields(logrus.Fields{
 		"remediator": remediator.Name(),
 		"issue_id":   issue.ID,
+		"status":     result.Status,
+		"actions":    len(result.Actions),
 	}).Info("Remediation completed successfully")
+	return result, nil
+}
+
+// retryPolicy returns the retry policy of a remediator after a failed action: the
+// default, overridden by the remediation policy, then by the issue
+func (ss *StrategySelector) retryPolicy(remediator, action string, issue *models.Issue) models.RetryPolicy {
+	return ss.policy.RetryPolicy(ss.retry, remediator, action).Merge(issue.Retry)
+}
+
+// lowConfidence reports whether the deployment method was detected with too little
+// confidence to remediate, and why
+func (ss *StrategySelector) lowConfidence(deploymentInfo *models.DeploymentInfo) (string, bool) {
+	if ss.minConfidence <= 0 || deploymentInfo.Confidence >= ss.minConfidence {
+		return "", false
+	}
+	return fmt.Sprintf("%s deployment detected with confidence %.2f, below the %.2f threshold",
+		deploymentInfo.Method, deploymentInfo.Confidence, ss.minConfidence), true
+}
+
+// recommendOnly recommends the actions the remediator would take instead of taking
+// them, because the deployment method was detected with too little confidence
+func (ss *StrategySelector) recommendOnly(ctx context.Context, remediator Remediator, deploymentInfo *models.DeploymentInfo, issue *models.Issue, reason string, startTime time.Time) (*models.RemediationResult, error) {
+	reportSelection(ctx, ss.decision(remediator, models.SelectionRecommendOnly, reason))
+	RecordStrategyRecommendOnly(string(deploymentInfo.Method))
+	ss.log.WithFields(logrus.Fields{
+		"remediator": remediator.Name(),
+		"issue_id":   issue.ID,
+		"method":     deploymentInfo.Method,
+		"confidence": deploymentInfo.Confidence,
+	}).Warn("Detection confidence below threshold, recommending instead of remediating")
 
+	result := models.NewRemediationResult(remediator.Name())
+	result.AddEvidence("deployment_method", string(deploymentInfo.Method))
+	result.AddEvidence("confidence", fmt.Sprintf("%.2f", deploymentInfo.Confidence))
+	result.Recommend(fmt.Sprintf("Confirm how %s is deployed: %s", issueResource(issue), reason))
+	actions, err := remediator.PlanActions(ctx, deploymentInfo, issue)
+	if err != nil {
+		return ss.failed(result, startTime, fmt.Errorf("%s failed to plan actions: %w", remediator.Name(), err))
+	}
+	for _, action := range actions {
+		result.Recommend(fmt.Sprintf("Planned automated %s: %s", action.Action, action.Description))
+	}
+	result.Finish(startTime)
+	return result, nil
+}
+
+// failed marks the result failed with the classified error and returns both
+func (ss *StrategySelector) failed(result *models.RemediationResult, startTime time.Time, err error) (*models.RemediationResult, error) {
+	result.Fail(err, ClassifyError(err))
+	result.Finish(startTime)
+	return result, err
+}
+
+// checkGuardrails refuses remediation when guardrails do not allow every action
+// the remediator plans for the issue
+func (ss *StrategySelector) checkGuardrails(ctx context.Context, remediator Remediator, deploymentInfo *models.DeploymentInfo, issue *models.Issue) error {
+	if ss.guardrails == nil {
+		return nil
+	}
+
+	planned, err := remediator.PlanActions(ctx, deploymentInfo, issue)
+	if err != nil {
+		return fmt.Errorf("%s failed to plan actions for guardrail check: %w", remediator.Name(), err)
+	}
+
+	if err := ss.guardrails.Check(ctx, issue.Namespace, issue.ResourceType, issue.ResourceName, actionNames(planned)); err != nil {
+		ss.log.WithError(err).WithFields(logrus.Fields{
+			"remediator": remediator.Name(),
+			"issue_id":   issue.ID,
+			"namespace":  issue.Namespace,
+			"resource":   issue.ResourceName,
+		}).Warn("Remediation refused by guardrails")
+		return err
+	}
 	return nil
 }
 
+// actionNames returns the action of each planned action
+func actionNames(planned []PlannedAction) []string {
+	actions := make([]string, 0, len(planned))
+	for _, action := range planned {
+		actions = append(actions, action.Action)
+	}
+	return actions
+}
+
+// PlanActions describes the actions of the remediator that would be selected
+func (ss *StrategySelector) PlanActions(ctx context.Context, deploymentInfo *models.DeploymentInfo, issue *models.Issue) ([]PlannedAction, error) {
+	remediator := ss.SelectRemediatorForIssue(ctx, deploymentInfo, issue)
+	if remediator == nil {
+		return nil, fmt.Errorf("no remediator available for deployment method: %s", deploymentInfo.Method)
+	}
+	return remediator.PlanActions(ctx, deploymentInfo, issue)
+}
+
 // CanRemediate returns true if any remediator can handle the deployment
 func (ss *StrategySelector) CanRemediate(deploymentInfo *models.DeploymentInfo) bool {
 	return ss.SelectRemediator(deploymentInfo) != nil
@@ -123,10 +477,10 @@ func (ss *StrategySelector) Name() string {
 	return "strategy-selector"
 }
 
-// GetRegisteredRemediators returns all registered remediators
+// GetRegisteredRemediators returns all registered remediators in the order they are tried
 func (ss *StrategySelector) GetRegisteredRemediators() []string {
-	names := make([]string, 0, len(ss.remediators))
-	for _, r := range ss.remediators {
+	names := ss.plugins.Names()
+	for _, r := range ss.ordered() {
 		names = append(names, r.Name())
 	}
 	if ss.fallbackRemediator != nil {
@@ -134,3 +488,19 @@ func (ss *StrategySelector) GetRegisteredRemediators() []string {
 	}
 	return names
 }
+
+// selectionRecorderKey is the context key of the function selection decisions are reported to
+type selectionRecorderKey struct{}
+
+// withSelectionRecorder returns a context through which the strategy selector
+// reports how it chose the remediator
+func withSelectionRecorder(ctx context.Context, record func(models.SelectionDecision)) context.Context {
+	return context.WithValue(ctx, selectionRecorderKey{}, record)
+}
+
+// reportSelection reports a decision to the recorder set by withSelectionRecorder
+func reportSelection(ctx context.Context, decision models.SelectionDecision) {
+	if record, ok := ctx.Value(selectionRecorderKey{}).(func(models.SelectionDecision)); ok {
+		record(decision)
+	}
+}
@@ -0,0 +1,245 @@
+package remediation
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// stubRemediator handles the given deployment methods (any when empty) and fails
+// with err, on every call or only on the first failures calls
+type stubRemediator struct {
+	name     string
+	methods  []models.DeploymentMethod
+	err      error
+	failures int
+	calls    int
+}
+
+func (s *stubRemediator) Remediate(_ context.Context, _ *models.DeploymentInfo, issue *models.Issue) (*models.RemediationResult, error) {
+	s.calls++
+	result := models.NewRemediationResult(s.name)
+	if s.err != nil && (s.failures == 0 || s.calls <= s.failures) {
+		return result, s.err
+	}
+	result.AddAction("restart_deployment", issueResource(issue), "", "now")
+	return result, nil
+}
+
+func (s *stubRemediator) PlanActions(_ context.Context, _ *models.DeploymentInfo, issue *models.Issue) ([]PlannedAction, error) {
+	return []PlannedAction{{Action: "restart_deployment", Target: issueResource(issue), Description: "Restart the deployment"}}, nil
+}
+
+func (s *stubRemediator) CanRemediate(deploymentInfo *models.DeploymentInfo) bool {
+	if len(s.methods) == 0 {
+		return true
+	}
+	for _, method := range s.methods {
+		if method == deploymentInfo.Method {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *stubRemediator) Name() string { return s.name }
+
+// decisions returns the remediator and decision of each recorded selection decision
+func decisions(recorded []models.SelectionDecision) []string {
+	out := make([]string, 0, len(recorded))
+	for _, d := range recorded {
+		out = append(out, d.Remediator+":"+d.Decision)
+	}
+	return out
+}
+
+func TestStrategySelector_Priority(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	helm := &stubRemediator{name: "helm", methods: []models.DeploymentMethod{models.DeploymentMethodHelm}}
+	first := &stubRemediator{name: "first"}
+	second := &stubRemediator{name: "second"}
+
+	selector := NewStrategySelector(log)
+	selector.RegisterRemediator(helm)
+	selector.RegisterRemediator(first)
+	selector.RegisterRemediator(second)
+	selector.SetFallbackRemediator(&stubRemediator{name: "manual"})
+
+	info := models.NewDeploymentInfo("default", "payment", "Deployment", models.DeploymentMethodManual, 0.9)
+	assert.Equal(t, "first", selector.SelectRemediator(info).Name())
+
+	selector.SetPriority("second", 10)
+	assert.Equal(t, "second", selector.SelectRemediator(info).Name())
+	assert.Equal(t, []string{"second", "helm", "first", "manual (fallback)"}, selector.GetRegisteredRemediators())
+
+	var recorded []models.SelectionDecision
+	ctx := withSelectionRecorder(context.Background(), func(d models.SelectionDecision) { recorded = append(recorded, d) })
+	result, err := selector.Remediate(ctx, info, newTestIssue("issue-1"))
+	require.NoError(t, err)
+	assert.Equal(t, "second", result.Method)
+	assert.Equal(t, []string{"second:selected", "helm:skipped"}, decisions(recorded))
+	assert.Equal(t, 10, recorded[0].Priority)
+}
+
+func TestStrategySelector_LowConfidence(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	manual := &stubRemediator{name: "manual"}
+	selector := NewStrategySelector(log)
+	selector.SetFallbackRemediator(manual)
+	selector.SetMinConfidence(0.7)
+
+	var recorded []models.SelectionDecision
+	ctx := withSelectionRecorder(context.Background(), func(d models.SelectionDecision) { recorded = append(recorded, d) })
+	info := models.NewDeploymentInfo("default", "payment", "Deployment", models.DeploymentMethodUnknown, 0.5)
+	result, err := selector.Remediate(ctx, info, newTestIssue("issue-1"))
+	require.NoError(t, err)
+	assert.Equal(t, 0, manual.calls)
+	assert.Equal(t, models.RemediationStatusRecommended, result.Status)
+	assert.Equal(t, "0.50", result.Evidence["confidence"])
+	assert.Contains(t, result.Recommendations[0], "below the 0.70 threshold")
+	assert.Equal(t, "Planned automated restart_deployment: Restart the deployment", result.Recommendations[1])
+	assert.Equal(t, []string{"manual:selected", "manual:recommend_only"}, decisions(recorded))
+
+	// At or above the threshold the remediator runs
+	info.Confidence = 0.7
+	result, err = selector.Remediate(context.Background(), info, newTestIssue("issue-2"))
+	require.NoError(t, err)
+	assert.Equal(t, models.RemediationStatusChanged, result.Status)
+	assert.Equal(t, 1, manual.calls)
+}
+
+func TestStrategySelector_Failover(t *testing.T) {
+	failoverPolicy := `
+failover:
+  - name: argocd-unreachable
+    match:
+      deployment_methods: [argocd]
+    from: [argocd]
+    to: [manual]
+`
+	tests := []struct {
+		name          string
+		policy        string
+		argocdErr     error
+		wantErr       string
+		wantManual    int
+		wantDecisions []string
+	}{
+		{
+			name:          "retryable error with failover rule",
+			policy:        failoverPolicy,
+			argocdErr:     errors.New("argocd API unreachable"),
+			wantManual:    1,
+			wantDecisions: []string{"argocd:selected", "argocd:failed", "manual:failover"},
+		},
+		{
+			name:          "retryable error without failover rule",
+			argocdErr:     errors.New("argocd API unreachable"),
+			wantErr:       "argocd API unreachable",
+			wantDecisions: []string{"argocd:selected", "argocd:failed", "manual:failover_denied"},
+		},
+		{
+			name:          "permanent error",
+			policy:        failoverPolicy,
+			argocdErr:     Permanent(errors.New("application not found")),
+			wantErr:       "application not found",
+			wantDecisions: []string{"argocd:selected", "argocd:failed"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := logrus.New()
+			log.SetLevel(logrus.ErrorLevel)
+			argocd := &stubRemediator{name: "argocd", methods: []models.DeploymentMethod{models.DeploymentMethodArgoCD}, err: tt.argocdErr}
+			manual := &stubRemediator{name: "manual"}
+
+			engine := NewPolicyEngine(nil, log)
+			if tt.policy != "" {
+				policy, err := ParsePolicy([]byte(tt.policy))
+				require.NoError(t, err)
+				engine.SetPolicy(policy)
+			}
+			selector := NewStrategySelector(log)
+			selector.RegisterRemediator(argocd)
+			selector.SetFallbackRemediator(manual)
+			selector.SetPolicy(engine)
+
+			var recorded []models.SelectionDecision
+			ctx := withSelectionRecorder(context.Background(), func(d models.SelectionDecision) { recorded = append(recorded, d) })
+			info := models.NewDeploymentInfo("default", "payment", "Deployment", models.DeploymentMethodArgoCD, 0.9)
+			result, err := selector.Remediate(ctx, info, newTestIssue("issue-1"))
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				assert.Equal(t, models.RemediationStatusFailed, result.Status)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, "manual", result.Method)
+			}
+			assert.Equal(t, tt.wantManual, manual.calls)
+			assert.Equal(t, tt.wantDecisions, decisions(recorded))
+		})
+	}
+}
+
+func TestStrategySelector_PolicyLimitIsFinal(t *testing.T) {
+	limitPolicy := `
+rules:
+  - name: restart
+    match:
+      issue_types: [CrashLoopBackOff]
+    actions:
+      - action: restart_deployment
+        max_per_hour: 1
+failover:
+  - name: manual-to-backup
+    from: [manual]
+    to: [backup]
+`
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	replicas := int32(1)
+	clientset := fake.NewSimpleClientset(&appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "payment", Namespace: "default"},
+		Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+	})
+	engine := NewPolicyEngine(clientset, log)
+	policy, err := ParsePolicy([]byte(limitPolicy))
+	require.NoError(t, err)
+	engine.SetPolicy(policy)
+
+	manual := NewManualRemediator(clientset, log)
+	manual.SetPolicy(engine)
+	backup := &stubRemediator{name: "backup"}
+	selector := NewStrategySelector(log)
+	selector.RegisterRemediator(manual)
+	selector.RegisterRemediator(backup)
+	selector.SetPolicy(engine)
+	selector.SetRetryPolicy(models.RetryPolicy{MaxAttempts: 3, InitialBackoff: "1ms"})
+
+	info := models.NewDeploymentInfo("default", "payment", "Deployment", models.DeploymentMethodManual, 0.9)
+	_, err = selector.Remediate(context.Background(), info, newTestIssue("issue-1"))
+	require.NoError(t, err)
+
+	// The second restart within the hour is limited, and neither retried nor failed over
+	var attempts []RemediationAttempt
+	var recorded []models.SelectionDecision
+	ctx := withAttemptRecorder(context.Background(), func(a RemediationAttempt) { attempts = append(attempts, a) })
+	ctx = withSelectionRecorder(ctx, func(d models.SelectionDecision) { recorded = append(recorded, d) })
+	result, err := selector.Remediate(ctx, info, newTestIssue("issue-2"))
+	assert.ErrorIs(t, err, ErrPolicyLimit)
+	assert.Equal(t, models.ErrorClassPermanent, result.ErrorClass)
+	assert.Empty(t, attempts)
+	assert.Equal(t, 0, backup.calls)
+	assert.Equal(t, []string{"manual:selected", "manual:failed"}, decisions(recorded))
+}
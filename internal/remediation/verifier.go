@@ -0,0 +1,498 @@
+package remediation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/detector"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// Verification defaults
+const (
+	DefaultVerificationTimeout      = 5 * time.Minute
+	DefaultVerificationPollInterval = 5 * time.Second
+)
+
+// ErrVerificationFailed is returned when a remediated resource did not become healthy in time
+var ErrVerificationFailed = errors.New("remediation verification failed")
+
+// ErrVerificationUnsupported is returned by Resolve for resources whose health the
+// verifier cannot check; their remediation is left unverified
+var ErrVerificationUnsupported = errors.New("resource kind cannot be verified")
+
+// Verifier checks that a remediated resource actually recovered: the rollout
+// finished, its pods are ready, container restart counts stopped rising and the
+// original issue is no longer reported by any container.
+type Verifier struct {
+	clientset    kubernetes.Interface
+	dynamic      dynamic.Interface
+	timeout      time.Duration
+	pollInterval time.Duration
+	log          *logrus.Logger
+}
+
+// NewVerifier creates a verifier with the default timeout and poll interval
+func NewVerifier(clientset kubernetes.Interface, log *logrus.Logger) *Verifier {
+	return &Verifier{
+		clientset:    clientset,
+		timeout:      DefaultVerificationTimeout,
+		pollInterval: DefaultVerificationPollInterval,
+		log:          log,
+	}
+}
+
+// SetDynamicClient enables verification of DeploymentConfigs and Argo Rollouts
+func (v *Verifier) SetDynamicClient(client dynamic.Interface) {
+	v.dynamic = client
+}
+
+// SetTimeout sets how long Verify waits for the resource to become healthy
+func (v *Verifier) SetTimeout(timeout time.Duration) {
+	v.timeout = timeout
+}
+
+// SetPollInterval sets how often Verify re-checks the resource
+func (v *Verifier) SetPollInterval(interval time.Duration) {
+	v.pollInterval = interval
+}
+
+// Timeout returns the verification timeout
+func (v *Verifier) Timeout() time.Duration {
+	return v.timeout
+}
+
+// VerificationTarget is the workload whose health is verified
+type VerificationTarget struct {
+	Kind      string // Deployment, StatefulSet, DaemonSet, DeploymentConfig, Rollout, ReplicationController or Pod
+	Namespace string
+	Name      string
+}
+
+// String returns kind namespace/name
+func (t *VerificationTarget) String() string {
+	return fmt.Sprintf("%s %s/%s", t.Kind, t.Namespace, t.Name)
+}
+
+// Resolve maps the issue's resource to the workload that is verified. Pods owned
+// by a controller are verified through that controller, since remediation may
+// replace the pod; call Resolve before remediating. Kinds the verifier cannot
+// check return an error wrapping ErrVerificationUnsupported.
+func (v *Verifier) Resolve(ctx context.Context, issue *models.Issue) (*VerificationTarget, error) {
+	kind := NormalizeKind(issue.ResourceType)
+	switch kind {
+	case "Pod":
+	case "Deployment", "StatefulSet", "DaemonSet", "ReplicationController", "DeploymentConfig", "Rollout":
+		if !v.supports(kind) {
+			return nil, fmt.Errorf("%w: %s needs a dynamic client", ErrVerificationUnsupported, kind)
+		}
+		return &VerificationTarget{Kind: kind, Namespace: issue.Namespace, Name: issue.ResourceName}, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrVerificationUnsupported, issue.ResourceType)
+	}
+
+	chain, err := detector.ResolveOwnerChain(ctx, v.clientset, issue.Namespace, "Pod", issue.ResourceName)
+	if err != nil {
+		return nil, err
+	}
+	return v.chainTarget(issue.Namespace, chain)
+}
+
+// supports returns true if the verifier can check the rollout of a workload kind
+func (v *Verifier) supports(kind string) bool {
+	switch kind {
+	case "DeploymentConfig", "Rollout":
+		return v.dynamic != nil
+	default:
+		return true
+	}
+}
+
+// Verify polls target until it is healthy. It returns an error wrapping
+// ErrVerificationFailed with the last unhealthy reason on timeout, or the context
+// error if ctx is cancelled first.
+func (v *Verifier) Verify(ctx context.Context, target *VerificationTarget, issueType string) error {
+	v.log.WithFields(logrus.Fields{
+		"target":  target.String(),
+		"timeout": v.timeout,
+	}).Info("Verifying remediated resource")
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, v.timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(v.pollInterval)
+	defer ticker.Stop()
+
+	// Restart counts must not rise between two consecutive checks
+	var previousRestarts map[string]int32
+	reason := "no health check completed"
+	for {
+		restarts, unhealthy, err := v.check(timeoutCtx, target, issueType)
+		switch {
+		case err != nil:
+			reason = err.Error()
+		case unhealthy != "":
+			reason = unhealthy
+		case previousRestarts == nil:
+			reason = "waiting to confirm container restart counts are stable"
+		default:
+			if rising := risingRestarts(previousRestarts, restarts); rising != "" {
+				reason = rising
+			} else {
+				return nil
+			}
+		}
+		if err == nil {
+			previousRestarts = restarts
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timeoutCtx.Done():
+			return fmt.Errorf("%w after %s: %s", ErrVerificationFailed, v.timeout, reason)
+		case <-ticker.C:
+		}
+	}
+}
+
+// chainTarget returns the top-level controller in the pod's owner chain whose
+// rollout the verifier can check, or the pod itself when it has no controller
+func (v *Verifier) chainTarget(namespace string, chain detector.OwnerChain) (*VerificationTarget, error) {
+	for i := len(chain) - 1; i > 0; i-- {
+		switch kind := chain[i].Kind; kind {
+		case "Deployment", "StatefulSet", "DaemonSet", "ReplicationController", "DeploymentConfig", "Rollout":
+			if !v.supports(kind) {
+				// Remediation replaces the pods of the intermediate controller too
+				return nil, fmt.Errorf("%w: %s needs a dynamic client", ErrVerificationUnsupported, kind)
+			}
+			return &VerificationTarget{Kind: kind, Namespace: namespace, Name: chain[i].Name}, nil
+		}
+	}
+	return &VerificationTarget{Kind: "Pod", Namespace: namespace, Name: chain[0].Name}, nil
+}
+
+// podController returns the Deployment, StatefulSet or DaemonSet controlling pod,
+// or the pod itself when it has no such controller
+func podController(ctx context.Context, clientset kubernetes.Interface, pod *corev1.Pod) (*VerificationTarget, error) {
+	target := &VerificationTarget{Kind: "Pod", Namespace: pod.Namespace, Name: pod.Name}
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil {
+		return target, nil
+	}
+
+	switch owner.Kind {
+	case "StatefulSet", "DaemonSet":
+		return &VerificationTarget{Kind: owner.Kind, Namespace: pod.Namespace, Name: owner.Name}, nil
+	case "ReplicaSet":
+		rs, err := clientset.AppsV1().ReplicaSets(pod.Namespace).Get(ctx, owner.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get replicaset: %w", err)
+		}
+		if rsOwner := metav1.GetControllerOf(rs); rsOwner != nil && rsOwner.Kind == "Deployment" {
+			return &VerificationTarget{Kind: "Deployment", Namespace: pod.Namespace, Name: rsOwner.Name}, nil
+		}
+	}
+	return target, nil
+}
+
+// check inspects the target once. It returns the restart count of every container
+// and a description of why the target is unhealthy, or "" when it is healthy.
+func (v *Verifier) check(ctx context.Context, target *VerificationTarget, issueType string) (map[string]int32, string, error) {
+	var pods []corev1.Pod
+	switch target.Kind {
+	case "Pod":
+		pod, err := v.clientset.CoreV1().Pods(target.Namespace).Get(ctx, target.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to get pod: %w", err)
+		}
+		pods = []corev1.Pod{*pod}
+	default:
+		selector, unhealthy, err := v.rolloutStatus(ctx, target)
+		if err != nil || unhealthy != "" {
+			return nil, unhealthy, err
+		}
+		list, err := v.clientset.CoreV1().Pods(target.Namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to list pods: %w", err)
+		}
+		pods = list.Items
+	}
+
+	restarts := make(map[string]int32)
+	for i := range pods {
+		pod := &pods[i]
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+		for _, status := range pod.Status.ContainerStatuses {
+			if reason := issueReason(status, issueType); reason != "" {
+				return nil, fmt.Sprintf("container %s in pod %s still reports %s", status.Name, pod.Name, reason), nil
+			}
+			restarts[pod.Name+"/"+status.Name] = status.RestartCount
+		}
+		if !isPodReady(pod) {
+			return nil, fmt.Sprintf("pod %s is not ready", pod.Name), nil
+		}
+	}
+	return restarts, "", nil
+}
+
+// rolloutStatus reports whether a workload's rollout finished and returns its pod selector
+func (v *Verifier) rolloutStatus(ctx context.Context, target *VerificationTarget) (labels.Selector, string, error) {
+	apps := v.clientset.AppsV1()
+	var labelSelector *metav1.LabelSelector
+	var unhealthy string
+
+	switch target.Kind {
+	case "DeploymentConfig", "Rollout":
+		return v.customRolloutStatus(ctx, target)
+	case "ReplicationController":
+		rc, err := v.clientset.CoreV1().ReplicationControllers(target.Namespace).Get(ctx, target.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to get replicationcontroller: %w", err)
+		}
+		return labels.SelectorFromSet(rc.Spec.Selector), replicationControllerRolloutStatus(rc), nil
+	case "Deployment":
+		d, err := apps.Deployments(target.Namespace).Get(ctx, target.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to get deployment: %w", err)
+		}
+		labelSelector = d.Spec.Selector
+		unhealthy = deploymentRolloutStatus(d)
+	case "StatefulSet":
+		s, err := apps.StatefulSets(target.Namespace).Get(ctx, target.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to get statefulset: %w", err)
+		}
+		labelSelector = s.Spec.Selector
+		unhealthy = statefulSetRolloutStatus(s)
+	case "DaemonSet":
+		d, err := apps.DaemonSets(target.Namespace).Get(ctx, target.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to get daemonset: %w", err)
+		}
+		labelSelector = d.Spec.Selector
+		unhealthy = daemonSetRolloutStatus(d)
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(labelSelector)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid %s selector: %w", strings.ToLower(target.Kind), err)
+	}
+	return selector, unhealthy, nil
+}
+
+// customRolloutStatus reports whether the rollout of a DeploymentConfig or Argo
+// Rollout finished and returns its pod selector
+func (v *Verifier) customRolloutStatus(ctx context.Context, target *VerificationTarget) (labels.Selector, string, error) {
+	gvr := detector.DeploymentConfigGVR
+	if target.Kind == "Rollout" {
+		gvr = detector.RolloutGVR
+	}
+	obj, err := v.dynamic.Resource(gvr).Namespace(target.Namespace).Get(ctx, target.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get %s: %w", strings.ToLower(target.Kind), err)
+	}
+
+	var selector labels.Selector
+	var unhealthy string
+	if target.Kind == "DeploymentConfig" {
+		// A DeploymentConfig selects its pods with a plain label map
+		matchLabels, _, err := unstructured.NestedStringMap(obj.Object, "spec", "selector")
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid deploymentconfig selector: %w", err)
+		}
+		selector = labels.SelectorFromSet(matchLabels)
+		unhealthy = deploymentConfigRolloutStatus(obj)
+	} else {
+		fields, _, err := unstructured.NestedMap(obj.Object, "spec", "selector")
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid rollout selector: %w", err)
+		}
+		var labelSelector metav1.LabelSelector
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(fields, &labelSelector); err != nil {
+			return nil, "", fmt.Errorf("invalid rollout selector: %w", err)
+		}
+		if selector, err = metav1.LabelSelectorAsSelector(&labelSelector); err != nil {
+			return nil, "", fmt.Errorf("invalid rollout selector: %w", err)
+		}
+		unhealthy = argoRolloutStatus(obj)
+	}
+	return selector, unhealthy, nil
+}
+
+// deploymentConfigRolloutStatus mirrors `oc rollout status` for a DeploymentConfig
+func deploymentConfigRolloutStatus(dc *unstructured.Unstructured) string {
+	replicas := nestedInt64(dc, 1, "spec", "replicas")
+	updated := nestedInt64(dc, 0, "status", "updatedReplicas")
+	available := nestedInt64(dc, 0, "status", "availableReplicas")
+	switch {
+	case nestedInt64(dc, 0, "status", "observedGeneration") < dc.GetGeneration():
+		return "deploymentconfig spec update not yet observed"
+	case updated < replicas:
+		return fmt.Sprintf("%d of %d deploymentconfig replicas updated", updated, replicas)
+	case nestedInt64(dc, 0, "status", "replicas") > updated:
+		return fmt.Sprintf("%d old deploymentconfig replicas pending termination", nestedInt64(dc, 0, "status", "replicas")-updated)
+	case available < replicas:
+		return fmt.Sprintf("%d of %d deploymentconfig replicas available", available, replicas)
+	}
+	return ""
+}
+
+// argoRolloutStatus mirrors `kubectl argo rollouts status` for an Argo Rollout
+func argoRolloutStatus(rollout *unstructured.Unstructured) string {
+	replicas := nestedInt64(rollout, 1, "spec", "replicas")
+	updated := nestedInt64(rollout, 0, "status", "updatedReplicas")
+	available := nestedInt64(rollout, 0, "status", "availableReplicas")
+	phase, _, _ := unstructured.NestedString(rollout.Object, "status", "phase")
+	switch {
+	case phase != "" && phase != "Healthy":
+		return fmt.Sprintf("rollout is %s", phase)
+	case updated < replicas:
+		return fmt.Sprintf("%d of %d rollout replicas updated", updated, replicas)
+	case available < replicas:
+		return fmt.Sprintf("%d of %d rollout replicas available", available, replicas)
+	}
+	return ""
+}
+
+// nestedInt64 returns an integer field of obj, or def when it is not set
+func nestedInt64(obj *unstructured.Unstructured, def int64, fields ...string) int64 {
+	value, found, err := unstructured.NestedInt64(obj.Object, fields...)
+	if err != nil || !found {
+		return def
+	}
+	return value
+}
+
+// replicationControllerRolloutStatus reports whether a ReplicationController's
+// replicas are all ready
+func replicationControllerRolloutStatus(rc *corev1.ReplicationController) string {
+	replicas := int32(1)
+	if rc.Spec.Replicas != nil {
+		replicas = *rc.Spec.Replicas
+	}
+	switch {
+	case rc.Status.ObservedGeneration < rc.Generation:
+		return "replicationcontroller spec update not yet observed"
+	case rc.Status.AvailableReplicas < replicas:
+		return fmt.Sprintf("%d of %d replicationcontroller replicas available", rc.Status.AvailableReplicas, replicas)
+	}
+	return ""
+}
+
+// deploymentRolloutStatus mirrors `kubectl rollout status` for a Deployment
+func deploymentRolloutStatus(d *appsv1.Deployment) string {
+	replicas := int32(1)
+	if d.Spec.Replicas != nil {
+		replicas = *d.Spec.Replicas
+	}
+	switch {
+	case d.Status.ObservedGeneration < d.Generation:
+		return "deployment spec update not yet observed"
+	case d.Status.UpdatedReplicas < replicas:
+		return fmt.Sprintf("%d of %d deployment replicas updated", d.Status.UpdatedReplicas, replicas)
+	case d.Status.Replicas > d.Status.UpdatedReplicas:
+		return fmt.Sprintf("%d old deployment replicas pending termination", d.Status.Replicas-d.Status.UpdatedReplicas)
+	case d.Status.AvailableReplicas < replicas:
+		return fmt.Sprintf("%d of %d deployment replicas available", d.Status.AvailableReplicas, replicas)
+	}
+	return ""
+}
+
+// statefulSetRolloutStatus mirrors `kubectl rollout status` for a StatefulSet
+func statefulSetRolloutStatus(s *appsv1.StatefulSet) string {
+	replicas := int32(1)
+	if s.Spec.Replicas != nil {
+		replicas = *s.Spec.Replicas
+	}
+	switch {
+	case s.Status.ObservedGeneration < s.Generation:
+		return "statefulset spec update not yet observed"
+	case s.Status.ReadyReplicas < replicas:
+		return fmt.Sprintf("%d of %d statefulset replicas ready", s.Status.ReadyReplicas, replicas)
+	case s.Spec.UpdateStrategy.Type == appsv1.RollingUpdateStatefulSetStrategyType &&
+		s.Status.UpdateRevision != "" && s.Status.CurrentRevision != s.Status.UpdateRevision:
+		return fmt.Sprintf("statefulset rolling update to revision %s in progress", s.Status.UpdateRevision)
+	}
+	return ""
+}
+
+// daemonSetRolloutStatus mirrors `kubectl rollout status` for a DaemonSet
+func daemonSetRolloutStatus(d *appsv1.DaemonSet) string {
+	desired := d.Status.DesiredNumberScheduled
+	switch {
+	case d.Status.ObservedGeneration < d.Generation:
+		return "daemonset spec update not yet observed"
+	case d.Status.UpdatedNumberScheduled < desired:
+		return fmt.Sprintf("%d of %d daemonset pods updated", d.Status.UpdatedNumberScheduled, desired)
+	case d.Status.NumberAvailable < desired:
+		return fmt.Sprintf("%d of %d daemonset pods available", d.Status.NumberAvailable, desired)
+	}
+	return ""
+}
+
+// isPodReady returns true if the pod's Ready condition is true
+func isPodReady(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// issueReason returns the reason a container still shows the original issue, or ""
+func issueReason(status corev1.ContainerStatus, issueType string) string {
+	var reasons []string
+	switch strings.ToLower(issueType) {
+	case "crashloopbackoff", "pod_crash_loop":
+		reasons = []string{"CrashLoopBackOff"}
+	case "imagepullbackoff", "errimagepull":
+		reasons = []string{"ImagePullBackOff", "ErrImagePull"}
+	case "oomkilled":
+		reasons = []string{"OOMKilled"}
+	default:
+		return ""
+	}
+
+	for _, reason := range reasons {
+		if status.State.Waiting != nil && status.State.Waiting.Reason == reason {
+			return reason
+		}
+		if status.State.Terminated != nil && status.State.Terminated.Reason == reason {
+			return reason
+		}
+	}
+	return ""
+}
+
+// risingRestarts describes containers whose restart count grew between two checks
+func risingRestarts(previous, current map[string]int32) string {
+	var rising []string
+	for container, count := range current {
+		if before, ok := previous[container]; ok && count > before {
+			rising = append(rising, container)
+		}
+	}
+	if len(rising) == 0 {
+		return ""
+	}
+	sort.Strings(rising)
+	return "container restart counts still rising: " + strings.Join(rising, ", ")
+}
@@ -0,0 +1,305 @@
+package remediation
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+func int32Ptr(i int32) *int32 { return &i }
+
+func boolPtr(b bool) *bool { return &b }
+
+// newTestDeployment returns a Deployment whose rollout finished with the given available replicas
+func newTestDeployment(available int32) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "payment", Namespace: "default", UID: "deploy-uid", Generation: 2},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: int32Ptr(1),
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "payment"}},
+		},
+		Status: appsv1.DeploymentStatus{
+			ObservedGeneration: 2,
+			Replicas:           1,
+			UpdatedReplicas:    1,
+			AvailableReplicas:  available,
+		},
+	}
+}
+
+// newTestPod returns a pod of the payment Deployment
+func newTestPod(ready bool, waitingReason string) *corev1.Pod {
+	readyStatus := corev1.ConditionFalse
+	if ready {
+		readyStatus = corev1.ConditionTrue
+	}
+	status := corev1.ContainerStatus{Name: "app", RestartCount: 3}
+	if waitingReason != "" {
+		status.State.Waiting = &corev1.ContainerStateWaiting{Reason: waitingReason}
+	}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "payment-7d9f-abcde",
+			Namespace: "default",
+			Labels:    map[string]string{"app": "payment"},
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "payment-7d9f", Controller: boolPtr(true),
+			}},
+		},
+		Status: corev1.PodStatus{
+			Conditions:        []corev1.PodCondition{{Type: corev1.PodReady, Status: readyStatus}},
+			ContainerStatuses: []corev1.ContainerStatus{status},
+		},
+	}
+}
+
+func newTestVerifier(objects ...runtime.Object) *Verifier {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	verifier := NewVerifier(fake.NewSimpleClientset(objects...), log)
+	verifier.SetTimeout(200 * time.Millisecond)
+	verifier.SetPollInterval(10 * time.Millisecond)
+	return verifier
+}
+
+func TestVerifier_Resolve(t *testing.T) {
+	replicaSet := &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{
+		Name:      "payment-7d9f",
+		Namespace: "default",
+		OwnerReferences: []metav1.OwnerReference{{
+			APIVersion: "apps/v1", Kind: "Deployment", Name: "payment", Controller: boolPtr(true),
+		}},
+	}}
+	verifier := newTestVerifier(newTestPod(false, "CrashLoopBackOff"), replicaSet)
+
+	issue := newTestIssue("inc-1")
+	target, err := verifier.Resolve(context.Background(), issue)
+	require.NoError(t, err)
+	assert.Equal(t, &VerificationTarget{Kind: "Deployment", Namespace: "default", Name: "payment"}, target)
+
+	// A pod resolves to the Deployment owning its ReplicaSet
+	issue.ResourceType = "pod"
+	issue.ResourceName = "payment-7d9f-abcde"
+	target, err = verifier.Resolve(context.Background(), issue)
+	require.NoError(t, err)
+	assert.Equal(t, &VerificationTarget{Kind: "Deployment", Namespace: "default", Name: "payment"}, target)
+
+	issue.ResourceType = "ConfigMap"
+	_, err = verifier.Resolve(context.Background(), issue)
+	assert.ErrorIs(t, err, ErrVerificationUnsupported)
+}
+
+func TestVerifier_ResolveDeploymentConfigPod(t *testing.T) {
+	pod := newTestPod(false, "CrashLoopBackOff")
+	pod.OwnerReferences = []metav1.OwnerReference{{
+		APIVersion: "v1", Kind: "ReplicationController", Name: "legacy-3", Controller: boolPtr(true),
+	}}
+	rc := &corev1.ReplicationController{ObjectMeta: metav1.ObjectMeta{
+		Name:      "legacy-3",
+		Namespace: "default",
+		OwnerReferences: []metav1.OwnerReference{{
+			APIVersion: "apps.openshift.io/v1", Kind: "DeploymentConfig", Name: "legacy", Controller: boolPtr(true),
+		}},
+	}}
+	issue := newTestIssue("inc-1")
+	issue.ResourceType = "Pod"
+	issue.ResourceName = pod.Name
+
+	// Without a dynamic client the DeploymentConfig cannot be checked, and the
+	// RC-owned pod is replaced by the remediation, so it is not verified either
+	verifier := newTestVerifier(pod, rc)
+	_, err := verifier.Resolve(context.Background(), issue)
+	assert.ErrorIs(t, err, ErrVerificationUnsupported)
+
+	verifier.SetDynamicClient(dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()))
+	target, err := verifier.Resolve(context.Background(), issue)
+	require.NoError(t, err)
+	assert.Equal(t, &VerificationTarget{Kind: "DeploymentConfig", Namespace: "default", Name: "legacy"}, target)
+}
+
+// newTestCustomWorkload returns a DeploymentConfig or Rollout selecting the payment pods
+func newTestCustomWorkload(apiVersion, kind string, selector interface{}, status map[string]interface{}) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": apiVersion,
+		"kind":       kind,
+		"metadata":   map[string]interface{}{"name": "payment", "namespace": "default", "generation": int64(2)},
+		"spec":       map[string]interface{}{"replicas": int64(1), "selector": selector},
+		"status":     status,
+	}}
+}
+
+func TestVerifier_VerifyCustomWorkloads(t *testing.T) {
+	dcSelector := map[string]interface{}{"app": "payment"}
+	rolloutSelector := map[string]interface{}{"matchLabels": map[string]interface{}{"app": "payment"}}
+
+	tests := []struct {
+		name       string
+		workload   *unstructured.Unstructured
+		wantReason string
+	}{
+		{
+			name: "deploymentconfig healthy",
+			workload: newTestCustomWorkload("apps.openshift.io/v1", "DeploymentConfig", dcSelector, map[string]interface{}{
+				"observedGeneration": int64(2), "replicas": int64(1), "updatedReplicas": int64(1), "availableReplicas": int64(1),
+			}),
+		},
+		{
+			name: "deploymentconfig rollout incomplete",
+			workload: newTestCustomWorkload("apps.openshift.io/v1", "DeploymentConfig", dcSelector, map[string]interface{}{
+				"observedGeneration": int64(2), "replicas": int64(2), "updatedReplicas": int64(1), "availableReplicas": int64(1),
+			}),
+			wantReason: "1 old deploymentconfig replicas pending termination",
+		},
+		{
+			name: "rollout healthy",
+			workload: newTestCustomWorkload("argoproj.io/v1alpha1", "Rollout", rolloutSelector, map[string]interface{}{
+				"phase": "Healthy", "updatedReplicas": int64(1), "availableReplicas": int64(1),
+			}),
+		},
+		{
+			name: "rollout progressing",
+			workload: newTestCustomWorkload("argoproj.io/v1alpha1", "Rollout", rolloutSelector, map[string]interface{}{
+				"phase": "Progressing", "updatedReplicas": int64(1), "availableReplicas": int64(0),
+			}),
+			wantReason: "rollout is Progressing",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verifier := newTestVerifier(newTestPod(true, ""))
+			verifier.SetDynamicClient(dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), tt.workload))
+
+			target := &VerificationTarget{Kind: tt.workload.GetKind(), Namespace: "default", Name: "payment"}
+			err := verifier.Verify(context.Background(), target, "CrashLoopBackOff")
+			if tt.wantReason == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, ErrVerificationFailed)
+			assert.Contains(t, err.Error(), tt.wantReason)
+		})
+	}
+}
+
+func TestVerifier_Verify(t *testing.T) {
+	target := &VerificationTarget{Kind: "Deployment", Namespace: "default", Name: "payment"}
+
+	tests := []struct {
+		name       string
+		deployment *appsv1.Deployment
+		pod        *corev1.Pod
+		wantReason string
+	}{
+		{
+			name:       "healthy",
+			deployment: newTestDeployment(1),
+			pod:        newTestPod(true, ""),
+		},
+		{
+			name:       "rollout incomplete",
+			deployment: newTestDeployment(0),
+			pod:        newTestPod(true, ""),
+			wantReason: "0 of 1 deployment replicas available",
+		},
+		{
+			name:       "pod not ready",
+			deployment: newTestDeployment(1),
+			pod:        newTestPod(false, ""),
+			wantReason: "pod payment-7d9f-abcde is not ready",
+		},
+		{
+			name:       "original issue still present",
+			deployment: newTestDeployment(1),
+			pod:        newTestPod(true, "CrashLoopBackOff"),
+			wantReason: "still reports CrashLoopBackOff",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verifier := newTestVerifier(tt.deployment, tt.pod)
+			err := verifier.Verify(context.Background(), target, "CrashLoopBackOff")
+			if tt.wantReason == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, ErrVerificationFailed))
+			assert.Contains(t, err.Error(), tt.wantReason)
+		})
+	}
+}
+
+func TestVerifier_RisingRestarts(t *testing.T) {
+	previous := map[string]int32{"pod-a/app": 3, "pod-b/app": 1}
+	assert.Empty(t, risingRestarts(previous, map[string]int32{"pod-a/app": 3, "pod-b/app": 1, "pod-c/app": 0}))
+	assert.Equal(t, "container restart counts still rising: pod-b/app",
+		risingRestarts(previous, map[string]int32{"pod-a/app": 3, "pod-b/app": 2}))
+}
+
+func TestOrchestrator_FailedVerification(t *testing.T) {
+	remediator := newBlockingRemediator()
+	close(remediator.release)
+	o := newTestOrchestrator(remediator)
+	o.SetVerifier(newTestVerifier(newTestDeployment(0), newTestPod(true, "")))
+
+	wf, _, err := o.TriggerRemediation(context.Background(), "inc-1", newTestIssue("inc-1"))
+	require.NoError(t, err)
+
+	failed := waitForStatus(t, o, wf.ID, models.WorkflowStatusFailedVerification)
+	assert.Contains(t, failed.ErrorMessage, "0 of 1 deployment replicas available")
+	assert.False(t, failed.IsActive())
+
+	remediationStep := failed.Steps[len(failed.Steps)-2]
+	assert.Equal(t, "completed", remediationStep.Status)
+	verifyStep := failed.Steps[len(failed.Steps)-1]
+	assert.Equal(t, "failed", verifyStep.Status)
+	assert.Contains(t, verifyStep.Description, "Verify Deployment default/payment")
+	assert.Equal(t, failed.ErrorMessage, verifyStep.ErrorMessage)
+}
+
+func TestOrchestrator_VerifiedWorkflowCompletes(t *testing.T) {
+	remediator := newBlockingRemediator()
+	close(remediator.release)
+	o := newTestOrchestrator(remediator)
+	o.SetVerifier(newTestVerifier(newTestDeployment(1), newTestPod(true, "")))
+
+	wf, _, err := o.TriggerRemediation(context.Background(), "inc-1", newTestIssue("inc-1"))
+	require.NoError(t, err)
+
+	completed := waitForStatus(t, o, wf.ID, models.WorkflowStatusCompleted)
+	assert.Equal(t, "completed", completed.Steps[len(completed.Steps)-1].Status)
+}
+
+func TestOrchestrator_UnsupportedKindSkipsVerification(t *testing.T) {
+	remediator := newBlockingRemediator()
+	close(remediator.release)
+	o := newTestOrchestrator(remediator)
+	o.SetVerifier(newTestVerifier())
+
+	// DeploymentConfigs cannot be verified without a dynamic client
+	issue := newTestIssue("inc-1")
+	issue.ResourceType = "DeploymentConfig"
+	wf, _, err := o.TriggerRemediation(context.Background(), "inc-1", issue)
+	require.NoError(t, err)
+
+	completed := waitForStatus(t, o, wf.ID, models.WorkflowStatusCompleted)
+	for _, step := range completed.Steps {
+		assert.NotContains(t, step.Description, "Verify")
+	}
+}
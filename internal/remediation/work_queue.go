@@ -0,0 +1,227 @@
+package remediation
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Default work queue limits
+const (
+	DefaultQueueWorkers         = 10
+	DefaultQueueMaxPerNamespace = 3
+)
+
+// severityPriority orders work by issue severity; lower runs first
+var severityPriority = map[string]int{
+	"critical": 0,
+	"high":     1,
+	"medium":   2,
+	"low":      3,
+}
+
+// WorkItem is a unit of workflow execution scheduled on the WorkQueue
+type WorkItem struct {
+	ID        string
+	Namespace string
+	Severity  string
+	Source    string // "remediation" or "coordination"
+	Run       func()
+
+	priority   int
+	enqueuedAt time.Time
+}
+
+// WorkQueueConfig configures worker and concurrency limits
+type WorkQueueConfig struct {
+	Workers         int // number of worker goroutines
+	MaxConcurrent   int // global limit on running items, capped at Workers
+	MaxPerNamespace int // limit on running items per namespace, 0 for unlimited
+}
+
+// WorkQueue runs workflow executions on a bounded worker pool. Pending items are
+// ordered by severity, then by arrival, and dispatched only while the global and
+// per-namespace concurrency limits allow.
+type WorkQueue struct {
+	cfg         WorkQueueConfig
+	mu          sync.Mutex
+	cond        *sync.Cond
+	pending     []*WorkItem
+	running     int
+	runningByNS map[string]int
+	stopped     bool
+	wg          sync.WaitGroup
+	log         *logrus.Logger
+}
+
+// NewWorkQueue creates a work queue; call Start to begin processing
+func NewWorkQueue(cfg WorkQueueConfig, log *logrus.Logger) *WorkQueue {
+	if cfg.Workers <= 0 {
+		cfg.Workers = DefaultQueueWorkers
+	}
+	if cfg.MaxConcurrent <= 0 || cfg.MaxConcurrent > cfg.Workers {
+		cfg.MaxConcurrent = cfg.Workers
+	}
+
+	q := &WorkQueue{
+		cfg:         cfg,
+		runningByNS: make(map[string]int),
+		log:         log,
+	}
+	q.cond = sync.NewCond(&q.mu)
+	return q
+}
+
+// Start launches the worker goroutines
+func (q *WorkQueue) Start() {
+	for i := 0; i < q.cfg.Workers; i++ {
+		q.wg.Add(1)
+		go q.worker()
+	}
+	q.log.WithFields(logrus.Fields{
+		"workers":           q.cfg.Workers,
+		"max_concurrent":    q.cfg.MaxConcurrent,
+		"max_per_namespace": q.cfg.MaxPerNamespace,
+	}).Info("Workflow work queue started")
+}
+
+// Stop stops dispatching and waits for running items to finish. Items still
+// pending are dropped; their workflows are reconciled as interrupted on restart.
+func (q *WorkQueue) Stop() {
+	q.mu.Lock()
+	q.stopped = true
+	dropped := len(q.pending)
+	q.pending = nil
+	q.cond.Broadcast()
+	q.mu.Unlock()
+
+	q.wg.Wait()
+	UpdateQueueDepth(0)
+	q.log.WithField("dropped", dropped).Info("Workflow work queue stopped")
+}
+
+// Submit enqueues an item and returns its 1-based position in the queue
+func (q *WorkQueue) Submit(item *WorkItem) int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	item.priority = priorityForSeverity(item.Severity)
+	item.enqueuedAt = time.Now()
+
+	// Insert after all items of equal or higher priority to keep arrival order
+	idx := sort.Search(len(q.pending), func(i int) bool {
+		return q.pending[i].priority > item.priority
+	})
+	q.pending = append(q.pending, nil)
+	copy(q.pending[idx+1:], q.pending[idx:])
+	q.pending[idx] = item
+
+	UpdateQueueDepth(len(q.pending))
+	q.cond.Signal()
+
+	q.log.WithFields(logrus.Fields{
+		"id":        item.ID,
+		"severity":  item.Severity,
+		"namespace": item.Namespace,
+		"position":  idx + 1,
+	}).Debug("Work item queued")
+
+	return idx + 1
+}
+
+// Position returns the 1-based queue position of an item, or 0 if it is not pending
+func (q *WorkQueue) Position(id string) int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for i, item := range q.pending {
+		if item.ID == id {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// Remove drops a pending item; it returns false if the item is not pending
+func (q *WorkQueue) Remove(id string) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for i, item := range q.pending {
+		if item.ID == id {
+			q.pending = append(q.pending[:i], q.pending[i+1:]...)
+			UpdateQueueDepth(len(q.pending))
+			return true
+		}
+	}
+	return false
+}
+
+// Depth returns the number of pending items
+func (q *WorkQueue) Depth() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.pending)
+}
+
+// worker runs dispatched items until the queue stops
+func (q *WorkQueue) worker() {
+	defer q.wg.Done()
+	for {
+		item := q.next()
+		if item == nil {
+			return
+		}
+
+		RecordQueueWait(item.Source, time.Since(item.enqueuedAt).Seconds())
+		item.Run()
+		q.done(item)
+	}
+}
+
+// next blocks until an item may run within the concurrency limits
+func (q *WorkQueue) next() *WorkItem {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for {
+		if q.stopped {
+			return nil
+		}
+		if q.running < q.cfg.MaxConcurrent {
+			for i, item := range q.pending {
+				if q.cfg.MaxPerNamespace > 0 && q.runningByNS[item.Namespace] >= q.cfg.MaxPerNamespace {
+					continue
+				}
+				q.pending = append(q.pending[:i], q.pending[i+1:]...)
+				q.running++
+				q.runningByNS[item.Namespace]++
+				UpdateQueueDepth(len(q.pending))
+				return item
+			}
+		}
+		q.cond.Wait()
+	}
+}
+
+// done releases the concurrency slots held by an item
+func (q *WorkQueue) done(item *WorkItem) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.running--
+	q.runningByNS[item.Namespace]--
+	if q.runningByNS[item.Namespace] <= 0 {
+		delete(q.runningByNS, item.Namespace)
+	}
+	// A namespace slot may unblock any waiting worker, not just one
+	q.cond.Broadcast()
+}
+
+// priorityForSeverity maps an issue severity to a queue priority
+func priorityForSeverity(severity string) int {
+	if p, ok := severityPriority[strings.ToLower(severity)]; ok {
+		return p
+	}
+	return severityPriority["medium"]
+}
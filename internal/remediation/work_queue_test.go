@@ -0,0 +1,133 @@
+package remediation
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+func newTestWorkQueue(cfg WorkQueueConfig) *WorkQueue {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	return NewWorkQueue(cfg, log)
+}
+
+func TestWorkQueue_OrdersBySeverity(t *testing.T) {
+	q := newTestWorkQueue(WorkQueueConfig{Workers: 1})
+
+	var mu sync.Mutex
+	var order []string
+	var wg sync.WaitGroup
+	submit := func(id, severity string) int {
+		wg.Add(1)
+		return q.Submit(&WorkItem{ID: id, Namespace: "default", Severity: severity, Run: func() {
+			mu.Lock()
+			order = append(order, id)
+			mu.Unlock()
+			wg.Done()
+		}})
+	}
+
+	// Queue everything before the single worker starts
+	assert.Equal(t, 1, submit("low-1", "low"))
+	assert.Equal(t, 2, submit("low-2", "low"))
+	assert.Equal(t, 1, submit("critical", "critical"))
+	assert.Equal(t, 2, submit("high", "high"))
+	assert.Equal(t, 3, submit("unknown", ""))
+	assert.Equal(t, 3, q.Position("unknown"))
+	assert.Equal(t, 5, q.Depth())
+
+	q.Start()
+	defer q.Stop()
+	wg.Wait()
+
+	assert.Equal(t, []string{"critical", "high", "unknown", "low-1", "low-2"}, order)
+	assert.Equal(t, 0, q.Depth())
+}
+
+func TestWorkQueue_PerNamespaceLimit(t *testing.T) {
+	q := newTestWorkQueue(WorkQueueConfig{Workers: 4, MaxPerNamespace: 1})
+	q.Start()
+	defer q.Stop()
+
+	release := make(chan struct{})
+	started := make(chan string, 3)
+	run := func(id string) func() {
+		return func() {
+			started <- id
+			<-release
+		}
+	}
+
+	q.Submit(&WorkItem{ID: "a-1", Namespace: "a", Run: run("a-1")})
+	q.Submit(&WorkItem{ID: "a-2", Namespace: "a", Run: run("a-2")})
+	q.Submit(&WorkItem{ID: "b-1", Namespace: "b", Run: run("b-1")})
+
+	got := []string{<-started, <-started}
+	assert.ElementsMatch(t, []string{"a-1", "b-1"}, got)
+
+	// The second item for namespace a waits for the first to finish
+	assert.Eventually(t, func() bool { return q.Position("a-2") == 1 }, time.Second, 10*time.Millisecond)
+	select {
+	case id := <-started:
+		t.Fatalf("%s started while namespace limit was reached", id)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	assert.Equal(t, "a-2", <-started)
+}
+
+func TestWorkQueue_Remove(t *testing.T) {
+	q := newTestWorkQueue(WorkQueueConfig{Workers: 1})
+
+	q.Submit(&WorkItem{ID: "wf-1", Run: func() {}})
+	q.Submit(&WorkItem{ID: "wf-2", Run: func() {}})
+
+	assert.True(t, q.Remove("wf-1"))
+	assert.False(t, q.Remove("wf-1"))
+	assert.Equal(t, 1, q.Position("wf-2"))
+	assert.Equal(t, 0, q.Position("wf-1"))
+}
+
+func TestOrchestrator_QueuedWorkflow(t *testing.T) {
+	remediator := newBlockingRemediator()
+	o := newTestOrchestrator(remediator)
+	o.SetDedupWindow(0)
+
+	q := newTestWorkQueue(WorkQueueConfig{Workers: 1})
+	o.SetWorkQueue(q)
+	q.Start()
+	defer q.Stop()
+	defer close(remediator.release)
+	ctx := context.Background()
+
+	running, _, err := o.TriggerRemediation(ctx, "inc-1", newTestIssue("inc-1"))
+	require.NoError(t, err)
+	<-remediator.started
+	waitForStatus(t, o, running.ID, models.WorkflowStatusRunning)
+
+	// The only worker is busy, so the next workflow waits in the queue
+	queued, created, err := o.TriggerRemediation(ctx, "inc-2", newTestIssue("inc-2"))
+	require.NoError(t, err)
+	assert.True(t, created)
+	assert.Equal(t, models.WorkflowStatusQueued, queued.Status)
+	assert.Equal(t, 1, queued.QueuePosition)
+
+	wf, err := o.GetWorkflow(queued.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, wf.QueuePosition)
+
+	// Cancelling a queued workflow removes it without running it
+	require.NoError(t, o.CancelWorkflow(queued.ID))
+	cancelled := waitForStatus(t, o, queued.ID, models.WorkflowStatusCancelled)
+	assert.Nil(t, cancelled.StartedAt)
+	assert.Equal(t, 0, q.Depth())
+}
@@ -0,0 +1,222 @@
+package remediation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// Workflow store types
+const (
+	WorkflowStoreMemory    = "memory"
+	WorkflowStoreConfigMap = "configmap"
+)
+
+// ConfigMap labels and keys used by ConfigMapWorkflowStore
+const (
+	workflowStoreLabel      = "coordination-engine.aiops/store"
+	workflowStoreLabelValue = "workflow"
+	workflowIDLabel         = "coordination-engine.aiops/workflow-id"
+	workflowConfigMapPrefix = "coordination-wf-"
+	workflowDataKey         = "workflow.json"
+)
+
+// ErrWorkflowNotFound is returned when a workflow does not exist in the store
+var ErrWorkflowNotFound = errors.New("workflow not found")
+
+// WorkflowStore persists remediation workflows
+type WorkflowStore interface {
+	// Save creates or replaces a workflow
+	Save(ctx context.Context, workflow *models.Workflow) error
+
+	// Get returns a workflow by ID, or ErrWorkflowNotFound
+	Get(ctx context.Context, workflowID string) (*models.Workflow, error)
+
+	// List returns all stored workflows ordered by creation time
+	List(ctx context.Context) ([]*models.Workflow, error)
+}
+
+// PodOwnerCheck reports a workflow owner alive while a pod of its name exists in
+// namespace, for engine replicas identified by their pod name
+func PodOwnerCheck(clientset kubernetes.Interface, namespace string) OwnerCheck {
+	return func(ctx context.Context, owner string) (bool, error) {
+		_, err := clientset.CoreV1().Pods(namespace).Get(ctx, owner, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		if err != nil {
+			return false, fmt.Errorf("failed to get owner pod %s/%s: %w", namespace, owner, err)
+		}
+		return true, nil
+	}
+}
+
+// MemoryWorkflowStore keeps workflows in process memory
+type MemoryWorkflowStore struct {
+	workflows map[string]*models.Workflow
+	mu        sync.RWMutex
+}
+
+// NewMemoryWorkflowStore creates a new in-memory workflow store
+func NewMemoryWorkflowStore() *MemoryWorkflowStore {
+	return &MemoryWorkflowStore{
+		workflows: make(map[string]*models.Workflow),
+	}
+}
+
+// Save stores a copy of the workflow
+func (s *MemoryWorkflowStore) Save(_ context.Context, workflow *models.Workflow) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.workflows[workflow.ID] = workflow.Clone()
+	return nil
+}
+
+// Get returns a copy of the stored workflow
+func (s *MemoryWorkflowStore) Get(_ context.Context, workflowID string) (*models.Workflow, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	workflow, exists := s.workflows[workflowID]
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrWorkflowNotFound, workflowID)
+	}
+	return workflow.Clone(), nil
+}
+
+// List returns copies of all stored workflows
+func (s *MemoryWorkflowStore) List(_ context.Context) ([]*models.Workflow, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	workflows := make([]*models.Workflow, 0, len(s.workflows))
+	for _, wf := range s.workflows {
+		workflows = append(workflows, wf.Clone())
+	}
+	sortWorkflows(workflows)
+	return workflows, nil
+}
+
+// ConfigMapWorkflowStore persists each workflow as a ConfigMap so history survives pod restarts
+type ConfigMapWorkflowStore struct {
+	clientset kubernetes.Interface
+	namespace string
+	log       *logrus.Logger
+}
+
+// NewConfigMapWorkflowStore creates a workflow store backed by ConfigMaps in the given namespace
+func NewConfigMapWorkflowStore(clientset kubernetes.Interface, namespace string, log *logrus.Logger) *ConfigMapWorkflowStore {
+	return &ConfigMapWorkflowStore{
+		clientset: clientset,
+		namespace: namespace,
+		log:       log,
+	}
+}
+
+// Save creates or updates the ConfigMap holding the workflow
+func (s *ConfigMapWorkflowStore) Save(ctx context.Context, workflow *models.Workflow) error {
+	data, err := json.Marshal(workflow)
+	if err != nil {
+		return fmt.Errorf("failed to marshal workflow: %w", err)
+	}
+
+	configMaps := s.clientset.CoreV1().ConfigMaps(s.namespace)
+	name := workflowConfigMapPrefix + workflow.ID
+
+	existing, err := configMaps.Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		cm := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: s.namespace,
+				Labels: map[string]string{
+					workflowStoreLabel: workflowStoreLabelValue,
+					workflowIDLabel:    workflow.ID,
+				},
+			},
+			Data: map[string]string{workflowDataKey: string(data)},
+		}
+		if _, err := configMaps.Create(ctx, cm, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("failed to create workflow configmap: %w", err)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get workflow configmap: %w", err)
+	}
+
+	if existing.Data == nil {
+		existing.Data = make(map[string]string)
+	}
+	existing.Data[workflowDataKey] = string(data)
+	if _, err := configMaps.Update(ctx, existing, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to update workflow configmap: %w", err)
+	}
+	return nil
+}
+
+// Get reads a workflow from its ConfigMap
+func (s *ConfigMapWorkflowStore) Get(ctx context.Context, workflowID string) (*models.Workflow, error) {
+	cm, err := s.clientset.CoreV1().ConfigMaps(s.namespace).Get(ctx, workflowConfigMapPrefix+workflowID, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("%w: %s", ErrWorkflowNotFound, workflowID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workflow configmap: %w", err)
+	}
+	return decodeWorkflowConfigMap(cm)
+}
+
+// List reads all workflow ConfigMaps, skipping entries that cannot be decoded
+func (s *ConfigMapWorkflowStore) List(ctx context.Context) ([]*models.Workflow, error) {
+	list, err := s.clientset.CoreV1().ConfigMaps(s.namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", workflowStoreLabel, workflowStoreLabelValue),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workflow configmaps: %w", err)
+	}
+
+	workflows := make([]*models.Workflow, 0, len(list.Items))
+	for i := range list.Items {
+		workflow, err := decodeWorkflowConfigMap(&list.Items[i])
+		if err != nil {
+			s.log.WithError(err).WithField("configmap", list.Items[i].Name).Warn("Skipping unreadable workflow configmap")
+			continue
+		}
+		workflows = append(workflows, workflow)
+	}
+	sortWorkflows(workflows)
+	return workflows, nil
+}
+
+// decodeWorkflowConfigMap unmarshals the workflow stored in a ConfigMap
+func decodeWorkflowConfigMap(cm *corev1.ConfigMap) (*models.Workflow, error) {
+	data, ok := cm.Data[workflowDataKey]
+	if !ok {
+		return nil, fmt.Errorf("configmap %s has no %s key", cm.Name, workflowDataKey)
+	}
+
+	var workflow models.Workflow
+	if err := json.Unmarshal([]byte(data), &workflow); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal workflow from configmap %s: %w", cm.Name, err)
+	}
+	return &workflow, nil
+}
+
+// sortWorkflows orders workflows by creation time, oldest first
+func sortWorkflows(workflows []*models.Workflow) {
+	sort.Slice(workflows, func(i, j int) bool {
+		return workflows[i].CreatedAt.Before(workflows[j].CreatedAt)
+	})
+}
@@ -0,0 +1,234 @@
+package remediation
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/events"
+	"github.com/tosin2013/openshift-coordination-engine/internal/incidents"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+func newTestWorkflow(id string, status models.WorkflowStatus, createdAt time.Time) *models.Workflow {
+	wf := &models.Workflow{
+		ID:           id,
+		IncidentID:   "inc-" + id,
+		Status:       status,
+		Namespace:    "default",
+		ResourceName: "test-app",
+		ResourceKind: "Deployment",
+		IssueType:    "CrashLoopBackOff",
+		CreatedAt:    createdAt,
+	}
+	wf.AddStep("Detect deployment method")
+	return wf
+}
+
+func TestMemoryWorkflowStore_SaveGetList(t *testing.T) {
+	store := NewMemoryWorkflowStore()
+	ctx := context.Background()
+	now := time.Now()
+
+	require.NoError(t, store.Save(ctx, newTestWorkflow("wf-2", models.WorkflowStatusRunning, now)))
+	require.NoError(t, store.Save(ctx, newTestWorkflow("wf-1", models.WorkflowStatusCompleted, now.Add(-time.Minute))))
+
+	wf, err := store.Get(ctx, "wf-2")
+	require.NoError(t, err)
+	assert.Equal(t, models.WorkflowStatusRunning, wf.Status)
+
+	// Mutating the returned copy must not change the stored workflow
+	wf.Status = models.WorkflowStatusFailed
+	wf.Steps[0].Status = "failed"
+	stored, err := store.Get(ctx, "wf-2")
+	require.NoError(t, err)
+	assert.Equal(t, models.WorkflowStatusRunning, stored.Status)
+	assert.Equal(t, "pending", stored.Steps[0].Status)
+
+	workflows, err := store.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, workflows, 2)
+	assert.Equal(t, "wf-1", workflows[0].ID)
+	assert.Equal(t, "wf-2", workflows[1].ID)
+
+	_, err = store.Get(ctx, "missing")
+	assert.True(t, errors.Is(err, ErrWorkflowNotFound))
+}
+
+func TestMemoryWorkflowStore_IsolatesNestedState(t *testing.T) {
+	store := NewMemoryWorkflowStore()
+	ctx := context.Background()
+	started := time.Now()
+
+	wf := newTestWorkflow("wf-1", models.WorkflowStatusRunning, started)
+	wf.StartedAt = &started
+	wf.Recommendations = []string{"raise the memory limit"}
+	wf.Rollback = &models.Rollback{Kind: "Deployment", Name: "test-app", FromRevision: 2, ToRevision: 1}
+	wf.Selection = []models.SelectionDecision{{Remediator: "manual", Decision: models.SelectionSelected}}
+	wf.Deferral = &models.Deferral{Window: "weekend", Until: &started}
+	wf.Steps[0].StartedAt = &started
+	result := models.NewRemediationResult("manual")
+	result.AddAction("restart_deployment", "default/test-app", "", "")
+	result.AddEvidence("pods", "3")
+	result.Recommend("check the readiness probe")
+	wf.Steps[0].Result = result
+	require.NoError(t, store.Save(ctx, wf))
+
+	// Mutate every nested field of a copy read back from the store
+	clone, err := store.Get(ctx, "wf-1")
+	require.NoError(t, err)
+	later := started.Add(time.Hour)
+	*clone.StartedAt = later
+	clone.Recommendations[0] = "changed"
+	clone.Rollback.ToRevision = 5
+	clone.Selection[0].Decision = models.SelectionFailed
+	*clone.Deferral.Until = later
+	*clone.Steps[0].StartedAt = later
+	clone.Steps[0].Result.Status = models.RemediationStatusFailed
+	clone.Steps[0].Result.Actions[0].Action = "changed"
+	clone.Steps[0].Result.Evidence["pods"] = "0"
+	clone.Steps[0].Result.Recommendations[0] = "changed"
+
+	stored, err := store.Get(ctx, "wf-1")
+	require.NoError(t, err)
+	assert.Equal(t, started, *stored.StartedAt)
+	assert.Equal(t, []string{"raise the memory limit"}, stored.Recommendations)
+	assert.Equal(t, int64(1), stored.Rollback.ToRevision)
+	assert.Equal(t, models.SelectionSelected, stored.Selection[0].Decision)
+	assert.Equal(t, started, *stored.Deferral.Until)
+	assert.Equal(t, started, *stored.Steps[0].StartedAt)
+	assert.Equal(t, models.RemediationStatusChanged, stored.Steps[0].Result.Status)
+	assert.Equal(t, "restart_deployment", stored.Steps[0].Result.Actions[0].Action)
+	assert.Equal(t, "3", stored.Steps[0].Result.Evidence["pods"])
+	assert.Equal(t, []string{"check the readiness probe"}, stored.Steps[0].Result.Recommendations)
+}
+
+func TestConfigMapWorkflowStore_SaveGetList(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	store := NewConfigMapWorkflowStore(clientset, "engine", log)
+	ctx := context.Background()
+
+	wf := newTestWorkflow("wf-abc", models.WorkflowStatusRunning, time.Now())
+	require.NoError(t, store.Save(ctx, wf))
+
+	// Update the same workflow
+	wf.Status = models.WorkflowStatusCompleted
+	require.NoError(t, store.Save(ctx, wf))
+
+	got, err := store.Get(ctx, "wf-abc")
+	require.NoError(t, err)
+	assert.Equal(t, models.WorkflowStatusCompleted, got.Status)
+	assert.Equal(t, "inc-wf-abc", got.IncidentID)
+	assert.Len(t, got.Steps, 1)
+
+	workflows, err := store.List(ctx)
+	require.NoError(t, err)
+	assert.Len(t, workflows, 1)
+
+	_, err = store.Get(ctx, "missing")
+	assert.True(t, errors.Is(err, ErrWorkflowNotFound))
+}
+
+func TestOrchestrator_ReconcileInterrupted(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	ctx := context.Background()
+
+	store := NewMemoryWorkflowStore()
+	require.NoError(t, store.Save(ctx, newTestWorkflow("wf-running", models.WorkflowStatusRunning, time.Now())))
+	require.NoError(t, store.Save(ctx, newTestWorkflow("wf-done", models.WorkflowStatusCompleted, time.Now())))
+
+	tracker := incidents.NewTracker(incidents.NewMemoryStore(), log)
+	tracker.RemediationStarted(ctx, "inc-wf-running", "api", newTestIssue("inc-wf-running"), "wf-running")
+	bus := events.NewBus(log)
+	sub := bus.Subscribe(func(event events.Event) bool { return event.Type == events.TypeStatusChanged })
+	defer sub.Close()
+
+	orchestrator := NewOrchestrator(nil, NewManualRemediator(fake.NewSimpleClientset(), log), log)
+	orchestrator.SetWorkflowStore(store)
+	orchestrator.SetIncidentTracker(tracker)
+	orchestrator.SetEventBus(bus)
+
+	count, err := orchestrator.ReconcileInterrupted(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	wf, err := orchestrator.GetWorkflow("wf-running")
+	require.NoError(t, err)
+	assert.Equal(t, models.WorkflowStatusInterrupted, wf.Status)
+	assert.NotNil(t, wf.CompletedAt)
+	assert.Equal(t, "interrupted", wf.Steps[0].Status)
+
+	// The interruption is published as a final status and the incident stops remediating
+	event := <-sub.C
+	assert.Equal(t, "wf-running", event.WorkflowID)
+	assert.Equal(t, string(models.WorkflowStatusInterrupted), event.Status)
+	assert.True(t, event.Final)
+	incident, err := tracker.Get(ctx, "inc-wf-running")
+	require.NoError(t, err)
+	assert.Equal(t, models.IncidentStatusOpen, incident.Status)
+
+	wf, err = orchestrator.GetWorkflow("wf-done")
+	require.NoError(t, err)
+	assert.Equal(t, models.WorkflowStatusCompleted, wf.Status)
+}
+
+func TestOrchestrator_ReconcileInterruptedOwners(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	ctx := context.Background()
+
+	owned := func(id, owner string) *models.Workflow {
+		wf := newTestWorkflow(id, models.WorkflowStatusRunning, time.Now())
+		wf.Owner = owner
+		return wf
+	}
+	store := NewMemoryWorkflowStore()
+	require.NoError(t, store.Save(ctx, owned("wf-own", "engine-a")))
+	require.NoError(t, store.Save(ctx, owned("wf-live", "engine-b")))
+	require.NoError(t, store.Save(ctx, owned("wf-gone", "engine-c")))
+	require.NoError(t, store.Save(ctx, owned("wf-unknown", "engine-d")))
+	require.NoError(t, store.Save(ctx, owned("wf-legacy", "")))
+
+	// engine-b is still running; engine-d cannot be checked
+	clientset := fake.NewSimpleClientset(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "engine-b", Namespace: "engine"}})
+	check := PodOwnerCheck(clientset, "engine")
+	orchestrator := NewOrchestrator(nil, NewManualRemediator(clientset, log), log)
+	orchestrator.SetWorkflowStore(store)
+	orchestrator.SetOwner("engine-a", func(ctx context.Context, owner string) (bool, error) {
+		if owner == "engine-d" {
+			return false, errors.New("forbidden")
+		}
+		return check(ctx, owner)
+	})
+
+	count, err := orchestrator.ReconcileInterrupted(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	for id, want := range map[string]models.WorkflowStatus{
+		"wf-own":     models.WorkflowStatusInterrupted,
+		"wf-live":    models.WorkflowStatusRunning,
+		"wf-gone":    models.WorkflowStatusInterrupted,
+		"wf-unknown": models.WorkflowStatusRunning,
+		"wf-legacy":  models.WorkflowStatusInterrupted,
+	} {
+		wf, err := orchestrator.GetWorkflow(id)
+		require.NoError(t, err)
+		assert.Equal(t, want, wf.Status, id)
+	}
+
+	// New workflows are owned by this replica
+	assert.Equal(t, "engine-a", orchestrator.createWorkflow("inc-1", newTestIssue("inc-1"),
+		models.NewDeploymentInfo("default", "payment", "Deployment", models.DeploymentMethodManual, 0.9)).Owner)
+}
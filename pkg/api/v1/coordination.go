@@ -4,8 +4,10 @@ package v1
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 	"sync"
 	"time"
 
@@ -13,6 +15,9 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/tosin2013/openshift-coordination-engine/internal/coordination"
+	"github.com/tosin2013/openshift-coordination-engine/internal/events"
+	"github.com/tosin2013/openshift-coordination-engine/internal/incidents"
+	"github.com/tosin2013/openshift-coordination-engine/internal/remediation"
 	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
 )
 
@@ -23,6 +28,14 @@ type CoordinationHandler struct {
 	planner               *coordination.MultiLayerPlanner
 	orchestrator          *coordination.MultiLayerOrchestrator
 	coordinationWorkflows map[string]*CoordinationWorkflow
+	cancellations         map[string]*workflowCancellation
+	queue                 *remediation.WorkQueue
+	approvals             *remediation.ApprovalPolicy
+	incidents             *incidents.Tracker
+	events                *events.Bus
+	held                  map[string]*heldCoordinationWorkflow
+	maintenance           *remediation.MaintenancePolicy
+	deferred              map[string]*deferredCoordinationWorkflow
 	mu                    sync.RWMutex
 	log                   *logrus.Logger
 	enableMLDetection     bool // Phase 6: feature flag for ML detection
@@ -32,7 +45,13 @@ type CoordinationHandler struct {
 type CoordinationWorkflow struct {
 	ID              string                        `json:"id"`
 	IncidentID      string                        `json:"incident_id"`
-	Status          string                        `json:"status"` // pending, planning, executing, completed, failed
+	Namespace       string                        `json:"namespace,omitempty"` // namespace of the first affected resource
+	Severity        string                        `json:"severity,omitempty"`
+	Status          string                        `json:"status"` // pending, pending_approval, deferred, queued, executing, completed, failed, cancelled, rejected, rolled_back, recommended
+	QueuePosition   int                           `json:"queue_position,omitempty"`
+	Approval        *models.Approval              `json:"approval,omitempty"`
+	Deferral        *models.Deferral              `json:"deferral,omitempty"`
+	Recommendations []string                      `json:"recommendations,omitempty"` // planned steps when automation was downgraded
 	LayeredIssue    *models.LayeredIssue          `json:"layered_issue,omitempty"`
 	RemediationPlan *models.RemediationPlan       `json:"remediation_plan,omitempty"`
 	ExecutionResult *coordination.ExecutionResult `json:"execution_result,omitempty"`
@@ -42,11 +61,30 @@ type CoordinationWorkflow struct {
 	ErrorMessage    string                        `json:"error_message,omitempty"`
 }
 
+// workflowCancellation tracks how to stop a running coordination workflow
+type workflowCancellation struct {
+	cancel   context.CancelFunc
+	rollback bool
+}
+
+// heldCoordinationWorkflow tracks a workflow waiting for approval
+type heldCoordinationWorkflow struct {
+	start  func() int
+	expiry *time.Timer
+}
+
+// deferredCoordinationWorkflow tracks a workflow waiting for a maintenance window or freeze
+type deferredCoordinationWorkflow struct {
+	start  func() int
+	resume *time.Timer
+}
+
 // TriggerMultiLayerRemediationRequest is the request format for triggering multi-layer remediation
 type TriggerMultiLayerRemediationRequest struct {
 	IncidentID  string            `json:"incident_id"`
 	Description string            `json:"description"`
 	Resources   []models.Resource `json:"resources"`
+	Severity    string            `json:"severity,omitempty"` // orders queued workflows; defaults to medium
 }
 
 // TriggerMultiLayerRemediationResponse is the response format
@@ -56,6 +94,12 @@ type TriggerMultiLayerRemediationResponse struct {
 	AffectedLayers []models.Layer `json:"affected_layers"`
 	RootCauseLayer models.Layer   `json:"root_cause_layer"`
 	EstimatedSteps int            `json:"estimated_steps"`
+	QueuePosition  int            `json:"queue_position,omitempty"`
+
+	// Set when a maintenance window or freeze deferred the workflow or downgraded it
+	// to recommendations
+	Message         string   `json:"message,omitempty"`
+	Recommendations []string `json:"recommendations,omitempty"`
 }
 
 // NewCoordinationHandler creates a new coordination handler
@@ -71,6 +115,9 @@ func NewCoordinationHandler(
 		planner:               planner,
 		orchestrator:          orchestrator,
 		coordinationWorkflows: make(map[string]*CoordinationWorkflow),
+		cancellations:         make(map[string]*workflowCancellation),
+		held:                  make(map[string]*heldCoordinationWorkflow),
+		deferred:              make(map[string]*deferredCoordinationWorkflow),
 		log:                   log,
 		enableMLDetection:     false, // Default to keyword-based detection
 	}
@@ -85,6 +132,33 @@ func (ch *CoordinationHandler) SetMLLayerDetector(mlDetector *coordination.MLLay
 	}
 }
 
+// SetWorkQueue runs coordination workflows on the shared bounded work queue
+func (ch *CoordinationHandler) SetWorkQueue(queue *remediation.WorkQueue) {
+	ch.queue = queue
+}
+
+// SetApprovalPolicy holds matching plans in pending_approval until approved
+func (ch *CoordinationHandler) SetApprovalPolicy(policy *remediation.ApprovalPolicy) {
+	ch.approvals = policy
+}
+
+// SetIncidentTracker links coordination workflows to incidents
+func (ch *CoordinationHandler) SetIncidentTracker(tracker *incidents.Tracker) {
+	ch.incidents = tracker
+}
+
+// SetMaintenancePolicy rejects, defers or downgrades plans blocked by maintenance
+// windows or the global freeze
+func (ch *CoordinationHandler) SetMaintenancePolicy(policy *remediation.MaintenancePolicy) {
+	ch.maintenance = policy
+	policy.OnResume(ch.resumeDeferred)
+}
+
+// SetEventBus publishes coordination workflow status changes and enables event streams
+func (ch *CoordinationHandler) SetEventBus(bus *events.Bus) {
+	ch.events = bus
+}
+
 // TriggerMultiLayerRemediation handles POST /api/v1/coordination/trigger
 func (ch *CoordinationHandler) TriggerMultiLayerRemediation(w http.ResponseWriter, r *http.Request) {
 	var req TriggerMultiLayerRemediationRequest
@@ -137,19 +211,93 @@ func (ch *CoordinationHandler) TriggerMultiLayerRemediation(w http.ResponseWrite
 	workflow := &CoordinationWorkflow{
 		ID:              generateCoordinationWorkflowID(),
 		IncidentID:      req.IncidentID,
+		Namespace:       req.Resources[0].Namespace,
+		Severity:        req.Severity,
 		Status:          "pending",
 		LayeredIssue:    layeredIssue,
 		RemediationPlan: plan,
 		CreatedAt:       time.Now(),
 	}
 
-	// Store workflow
+	namespaces := make([]string, 0, len(req.Resources))
+	for _, resource := range req.Resources {
+		namespaces = append(namespaces, resource.Namespace)
+	}
+	approvalRequired, approvalReason := ch.approvals.RequiresPlanApproval(req.Severity, namespaces, layeredIssue.AffectedLayers)
+
+	decision := ch.maintenance.Evaluate(ctx, workflow.maintenanceTargets()...)
+	if decision != nil {
+		switch decision.Action {
+		case remediation.MaintenanceActionReject:
+			remediation.RecordMaintenanceDecision("coordination", decision.Action, decision.Window)
+			ch.log.WithFields(logrus.Fields{
+				"incident_id": req.IncidentID,
+				"window":      decision.Window,
+				"reason":      decision.Reason,
+			}).Warn("Multi-layer remediation rejected by maintenance policy")
+			http.Error(w, fmt.Sprintf("%v: %s", remediation.ErrMaintenanceBlocked, decision.Reason), http.StatusConflict)
+			return
+		case remediation.MaintenanceActionRecommend:
+			ch.recommendWorkflow(w, workflow, decision, req)
+			return
+		}
+	}
+	// An approval hold comes first; the windows are checked again once approved
+	deferred := decision != nil && !approvalRequired
+
+	switch {
+	case approvalRequired:
+		workflow.Status = string(models.WorkflowStatusPendingApproval)
+		workflow.Approval = models.NewApproval(approvalReason, ch.approvals.ExpiryOrDefault())
+	case deferred:
+		workflow.Status = string(models.WorkflowStatusDeferred)
+		workflow.Deferral = decision.Deferral(time.Now())
+	case ch.queue != nil:
+		workflow.Status = "queued"
+	}
+
+	// Execute remediation in background, bounded by the work queue when configured
+	execCtx, cancel := context.WithCancel(remediation.WithWorkflowID(context.Background(), workflow.ID))
+	start := func() int {
+		if ch.queue == nil {
+			go ch.executeCoordinationWorkflow(execCtx, workflow)
+			return 0
+		}
+		return ch.queue.Submit(&remediation.WorkItem{
+			ID:        workflow.ID,
+			Namespace: workflow.Namespace,
+			Severity:  workflow.Severity,
+			Source:    "coordination",
+			Run: func() {
+				ch.executeCoordinationWorkflow(execCtx, workflow)
+			},
+		})
+	}
+
+	// Store workflow with a cancellable execution context
 	ch.mu.Lock()
 	ch.coordinationWorkflows[workflow.ID] = workflow
+	ch.cancellations[workflow.ID] = &workflowCancellation{cancel: cancel}
+	if approvalRequired {
+		ch.held[workflow.ID] = &heldCoordinationWorkflow{
+			start: start,
+			expiry: time.AfterFunc(ch.approvals.ExpiryOrDefault(), func() {
+				ch.expireApproval(workflow.ID)
+			}),
+		}
+	}
+	if deferred {
+		ch.deferred[workflow.ID] = &deferredCoordinationWorkflow{
+			start:  start,
+			resume: ch.scheduleResume(workflow.ID, decision),
+		}
+	}
+	ch.publishStatus(workflow)
 	ch.mu.Unlock()
 
-	// Execute remediation in background
-	go ch.executeCoordinationWorkflow(workflow)
+	if ch.incidents != nil {
+		ch.incidents.CoordinationStarted(ctx, req.IncidentID, req.Severity, req.Description, req.Resources, workflow.ID)
+	}
 
 	// Return response
 	response := TriggerMultiLayerRemediationResponse{
@@ -160,6 +308,25 @@ func (ch *CoordinationHandler) TriggerMultiLayerRemediation(w http.ResponseWrite
 		EstimatedSteps: len(plan.Steps),
 	}
 
+	if approvalRequired {
+		ch.log.WithFields(logrus.Fields{
+			"workflow_id": workflow.ID,
+			"reason":      approvalReason,
+		}).Info("Multi-layer remediation waiting for approval")
+		remediation.RecordApproval("coordination", "requested")
+	} else if deferred {
+		ch.log.WithFields(logrus.Fields{
+			"workflow_id": workflow.ID,
+			"window":      decision.Window,
+			"reason":      decision.Reason,
+			"until":       decision.Until,
+		}).Info("Multi-layer remediation deferred by maintenance policy")
+		remediation.RecordMaintenanceDecision("coordination", decision.Action, decision.Window)
+		response.Message = decision.Reason
+	} else {
+		response.QueuePosition = start()
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusAccepted)
 	if err := json.NewEncoder(w).Encode(response); err != nil {
@@ -174,6 +341,10 @@ func (ch *CoordinationHandler) GetCoordinationWorkflow(w http.ResponseWriter, r
 
 	ch.mu.RLock()
 	workflow, exists := ch.coordinationWorkflows[workflowID]
+	var view CoordinationWorkflow
+	if exists {
+		view = ch.withQueuePosition(workflow)
+	}
 	ch.mu.RUnlock()
 
 	if !exists {
@@ -182,7 +353,7 @@ func (ch *CoordinationHandler) GetCoordinationWorkflow(w http.ResponseWriter, r
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(workflow); err != nil {
+	if err := json.NewEncoder(w).Encode(view); err != nil {
 		ch.log.WithError(err).Error("Failed to encode workflow response")
 	}
 }
@@ -190,9 +361,9 @@ func (ch *CoordinationHandler) GetCoordinationWorkflow(w http.ResponseWriter, r
 // ListCoordinationWorkflows handles GET /api/v1/coordination/workflows
 func (ch *CoordinationHandler) ListCoordinationWorkflows(w http.ResponseWriter, r *http.Request) {
 	ch.mu.RLock()
-	workflows := make([]*CoordinationWorkflow, 0, len(ch.coordinationWorkflows))
+	workflows := make([]CoordinationWorkflow, 0, len(ch.coordinationWorkflows))
 	for _, wf := range ch.coordinationWorkflows {
-		workflows = append(workflows, wf)
+		workflows = append(workflows, ch.withQueuePosition(wf))
 	}
 	ch.mu.RUnlock()
 
@@ -207,54 +378,594 @@ func (ch *CoordinationHandler) ListCoordinationWorkflows(w http.ResponseWriter,
 	}
 }
 
+// StreamCoordinationWorkflowEvents handles GET /api/v1/coordination/workflows/{id}/events
+// It streams status, step, health checkpoint and rollback events as Server-Sent Events.
+func (ch *CoordinationHandler) StreamCoordinationWorkflowEvents(w http.ResponseWriter, r *http.Request) {
+	workflowID := mux.Vars(r)["id"]
+	if ch.events == nil {
+		http.Error(w, "event streaming is not enabled", http.StatusServiceUnavailable)
+		return
+	}
+
+	ch.mu.RLock()
+	workflow, exists := ch.coordinationWorkflows[workflowID]
+	var planID string
+	if exists {
+		planID = workflow.planID()
+	}
+	ch.mu.RUnlock()
+	if !exists {
+		http.Error(w, "workflow not found", http.StatusNotFound)
+		return
+	}
+
+	// Subscribe before reading the current status so no transition is missed
+	sub := ch.events.Subscribe(func(event events.Event) bool {
+		return event.WorkflowID == workflowID || (planID != "" && event.PlanID == planID)
+	})
+	defer sub.Close()
+
+	ch.mu.RLock()
+	current := events.Event{
+		Type:       events.TypeStatusChanged,
+		Source:     events.SourceCoordination,
+		WorkflowID: workflow.ID,
+		PlanID:     planID,
+		Status:     workflow.Status,
+		Message:    workflow.ErrorMessage,
+		Final:      isFinalCoordinationStatus(workflow.Status),
+		Timestamp:  time.Now(),
+	}
+	ch.mu.RUnlock()
+
+	streamEvents(w, r, sub, current, ch.log)
+}
+
+// CancelCoordinationWorkflow handles DELETE /api/v1/coordination/workflows/{id}
+// Passing ?rollback=true reverts the steps that already ran once execution stops.
+func (ch *CoordinationHandler) CancelCoordinationWorkflow(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	workflowID := vars["id"]
+	rollback := r.URL.Query().Get("rollback") == "true"
+
+	ch.mu.Lock()
+	workflow, exists := ch.coordinationWorkflows[workflowID]
+	cancellation, running := ch.cancellations[workflowID]
+	if running {
+		cancellation.rollback = rollback
+	}
+	ch.mu.Unlock()
+
+	if !exists {
+		http.Error(w, "workflow not found", http.StatusNotFound)
+		return
+	}
+	if !running {
+		http.Error(w, fmt.Sprintf("workflow %s is not active", workflowID), http.StatusConflict)
+		return
+	}
+
+	ch.log.WithFields(logrus.Fields{
+		"workflow_id": workflowID,
+		"rollback":    rollback,
+	}).Info("Cancelling multi-layer remediation workflow")
+	cancellation.cancel()
+
+	status := "cancellation_requested"
+	if _, err := ch.closeHeldWorkflow(workflowID, "cancelled", "workflow cancelled while pending approval", nil); err == nil {
+		status = "cancelled"
+	} else if ch.closeDeferredWorkflow(workflowID) {
+		status = "cancelled"
+	} else if ch.queue != nil && ch.queue.Remove(workflowID) {
+		// The plan never started, so there is nothing to roll back
+		now := time.Now()
+		ch.mu.Lock()
+		delete(ch.cancellations, workflowID)
+		workflow.Status = "cancelled"
+		workflow.ErrorMessage = "workflow cancelled while queued"
+		workflow.CompletedAt = &now
+		ch.publishStatus(workflow)
+		ch.mu.Unlock()
+		status = "cancelled"
+	}
+	if status == "cancelled" {
+		ch.recordIncidentOutcome(workflow.IncidentID, workflowID, status)
+	}
+
+	response := map[string]interface{}{
+		"workflow_id": workflow.ID,
+		"status":      status,
+		"rollback":    rollback,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		ch.log.WithError(err).Error("Failed to encode cancel response")
+	}
+}
+
 // executeCoordinationWorkflow executes the multi-layer remediation workflow
-func (ch *CoordinationHandler) executeCoordinationWorkflow(workflow *CoordinationWorkflow) {
+func (ch *CoordinationHandler) executeCoordinationWorkflow(ctx context.Context, workflow *CoordinationWorkflow) {
 	ch.log.WithField("workflow_id", workflow.ID).Info("Starting multi-layer remediation workflow")
 
 	// Update status to executing
-	ch.updateWorkflowStatus(workflow, "executing")
 	startTime := time.Now()
+	ch.mu.Lock()
+	workflow.Status = "executing"
 	workflow.StartedAt = &startTime
+	ch.publishStatus(workflow)
+	ch.mu.Unlock()
 
 	// Execute plan
-	ctx := context.Background()
 	result, err := ch.orchestrator.ExecutePlan(ctx, workflow.RemediationPlan)
 
+	ch.mu.Lock()
+	cancellation := ch.cancellations[workflow.ID]
+	delete(ch.cancellations, workflow.ID)
+	ch.mu.Unlock()
+	cancellation.cancel()
+
+	var status, errorMessage string
+	switch {
+	case err != nil && result != nil && result.Status == "cancelled":
+		ch.log.WithField("workflow_id", workflow.ID).Warn("Multi-layer remediation cancelled")
+		status = "cancelled"
+		errorMessage = err.Error()
+
+		if cancellation.rollback {
+			if rbErr := ch.orchestrator.RollbackExecutedSteps(context.Background(), workflow.RemediationPlan, result); rbErr != nil {
+				ch.log.WithError(rbErr).Error("Rollback after cancellation failed")
+				errorMessage = fmt.Sprintf("%s; rollback failed: %v", err.Error(), rbErr)
+			} else {
+				status = "rolled_back"
+				result.Status = "rolled_back"
+			}
+		}
+	case err != nil:
+		ch.log.WithError(err).Error("Multi-layer remediation failed")
+		status = "failed"
+		errorMessage = err.Error()
+	default:
+		ch.log.Info("Multi-layer remediation completed successfully")
+		status = "completed"
+	}
+
+	// Save workflow state
 	completedTime := time.Now()
+	ch.mu.Lock()
 	workflow.CompletedAt = &completedTime
 	workflow.ExecutionResult = result
+	workflow.Status = status
+	workflow.ErrorMessage = errorMessage
+	ch.coordinationWorkflows[workflow.ID] = workflow
+	ch.publishStatus(workflow)
+	ch.mu.Unlock()
+	ch.recordIncidentOutcome(workflow.IncidentID, workflow.ID, status)
+
+	ch.log.WithFields(logrus.Fields{
+		"workflow_id": workflow.ID,
+		"status":      status,
+		"duration":    completedTime.Sub(startTime).String(),
+	}).Info("Multi-layer remediation workflow completed")
+}
 
+// ApproveCoordinationWorkflow handles POST /api/v1/coordination/workflows/{id}/approve
+func (ch *CoordinationHandler) ApproveCoordinationWorkflow(w http.ResponseWriter, r *http.Request) {
+	workflowID := mux.Vars(r)["id"]
+	req, err := decodeApprovalDecision(r)
 	if err != nil {
-		ch.log.WithError(err).Error("Multi-layer remediation failed")
-		workflow.Status = "failed"
-		workflow.ErrorMessage = err.Error()
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ch.mu.Lock()
+	workflow, held, err := ch.heldWorkflow(workflowID)
+	if err != nil {
+		ch.mu.Unlock()
+		ch.writeApprovalError(w, err)
+		return
+	}
+	held.expiry.Stop()
+	delete(ch.held, workflowID)
+	workflow.Approval.Decide(models.ApprovalDecisionApproved, req.DecidedBy, req.Comment)
+	workflow.Status = "pending"
+	if ch.queue != nil {
+		workflow.Status = "queued"
+	}
+	ch.publishStatus(workflow)
+	response := ApprovalDecisionResponse{
+		WorkflowID: workflow.ID,
+		Status:     workflow.Status,
+		Approval:   workflow.Approval.Clone(),
+	}
+	ch.mu.Unlock()
+
+	remediation.RecordApproval("coordination", models.ApprovalDecisionApproved)
+	ch.log.WithFields(logrus.Fields{
+		"workflow_id": workflowID,
+		"approved_by": req.DecidedBy,
+	}).Info("Multi-layer remediation approved")
+
+	// Windows may have closed or a freeze started while waiting for approval
+	if decision := ch.maintenance.Evaluate(context.Background(), workflow.maintenanceTargets()...); decision != nil {
+		ch.deferWorkflow(workflow, held.start, decision)
+		response.Status = string(models.WorkflowStatusDeferred)
 	} else {
-		ch.log.Info("Multi-layer remediation completed successfully")
-		workflow.Status = "completed"
+		response.QueuePosition = held.start()
 	}
+	ch.writeApprovalResponse(w, response)
+}
 
-	// Save workflow state
-	ch.saveWorkflow(workflow)
+// recommendWorkflow records a plan a maintenance window or freeze downgraded to
+// recommendations only, and responds with them
+func (ch *CoordinationHandler) recommendWorkflow(w http.ResponseWriter, workflow *CoordinationWorkflow, decision *remediation.MaintenanceDecision, req TriggerMultiLayerRemediationRequest) {
+	now := time.Now()
+	workflow.Status = string(models.WorkflowStatusRecommended)
+	workflow.ErrorMessage = "automated remediation downgraded to recommendations: " + decision.Reason
+	workflow.CompletedAt = &now
+	workflow.Recommendations = []string{
+		fmt.Sprintf("Automated multi-layer remediation is paused by %s; apply the planned steps manually if the incident cannot wait", decision.Window),
+	}
+	for i := range workflow.RemediationPlan.Steps {
+		step := &workflow.RemediationPlan.Steps[i]
+		workflow.Recommendations = append(workflow.Recommendations,
+			fmt.Sprintf("Planned %s layer %s of %s: %s", step.Layer, step.ActionType, step.Target, step.Description))
+	}
+
+	ch.mu.Lock()
+	ch.coordinationWorkflows[workflow.ID] = workflow
+	ch.publishStatus(workflow)
+	ch.mu.Unlock()
+
+	if ch.incidents != nil {
+		ch.incidents.CoordinationStarted(context.Background(), req.IncidentID, req.Severity, req.Description, req.Resources, workflow.ID)
+	}
+	ch.recordIncidentOutcome(workflow.IncidentID, workflow.ID, workflow.Status)
 
+	remediation.RecordMaintenanceDecision("coordination", decision.Action, decision.Window)
 	ch.log.WithFields(logrus.Fields{
 		"workflow_id": workflow.ID,
-		"status":      workflow.Status,
-		"duration":    completedTime.Sub(startTime).String(),
-	}).Info("Multi-layer remediation workflow completed")
+		"window":      decision.Window,
+		"reason":      decision.Reason,
+	}).Warn("Multi-layer remediation downgraded to recommendations by maintenance policy")
+
+	response := TriggerMultiLayerRemediationResponse{
+		WorkflowID:      workflow.ID,
+		Status:          workflow.Status,
+		AffectedLayers:  workflow.LayeredIssue.AffectedLayers,
+		RootCauseLayer:  workflow.LayeredIssue.RootCauseLayer,
+		EstimatedSteps:  len(workflow.RemediationPlan.Steps),
+		Message:         workflow.ErrorMessage,
+		Recommendations: workflow.Recommendations,
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		ch.log.WithError(err).Error("Failed to encode response")
+	}
 }
 
-// updateWorkflowStatus updates the workflow status
-func (ch *CoordinationHandler) updateWorkflowStatus(workflow *CoordinationWorkflow, status string) {
+// deferWorkflow holds a workflow until no maintenance window or freeze blocks it,
+// or updates the deferral of a workflow that is still blocked
+func (ch *CoordinationHandler) deferWorkflow(workflow *CoordinationWorkflow, start func() int, decision *remediation.MaintenanceDecision) {
 	ch.mu.Lock()
 	defer ch.mu.Unlock()
-	workflow.Status = status
+
+	if _, active := ch.cancellations[workflow.ID]; !active {
+		// Cancelled while the windows were evaluated
+		return
+	}
+
+	now := time.Now()
+	deferral := decision.Deferral(now)
+	deferred, redeferred := ch.deferred[workflow.ID]
+	if redeferred {
+		deferred.resume.Stop()
+		if workflow.Deferral != nil {
+			deferral.DeferredAt = workflow.Deferral.DeferredAt
+		}
+	} else {
+		deferred = &deferredCoordinationWorkflow{start: start}
+		ch.deferred[workflow.ID] = deferred
+		remediation.RecordMaintenanceDecision("coordination", remediation.MaintenanceActionQueue, decision.Window)
+		ch.log.WithFields(logrus.Fields{
+			"workflow_id": workflow.ID,
+			"window":      decision.Window,
+			"reason":      decision.Reason,
+			"until":       decision.Until,
+		}).Info("Multi-layer remediation deferred by maintenance policy")
+	}
+	deferred.resume = ch.scheduleResume(workflow.ID, decision)
+	workflow.Status = string(models.WorkflowStatusDeferred)
+	workflow.Deferral = deferral
+	ch.publishStatus(workflow)
+}
+
+// scheduleResume re-evaluates a deferred workflow once its decision may have changed
+func (ch *CoordinationHandler) scheduleResume(workflowID string, decision *remediation.MaintenanceDecision) *time.Timer {
+	return time.AfterFunc(decision.RetryAfter(time.Now()), func() {
+		ch.resumeWorkflow(workflowID)
+	})
+}
+
+// resumeWorkflow starts a deferred workflow unless it is still blocked
+func (ch *CoordinationHandler) resumeWorkflow(workflowID string) {
+	ch.mu.RLock()
+	workflow := ch.coordinationWorkflows[workflowID]
+	_, deferred := ch.deferred[workflowID]
+	ch.mu.RUnlock()
+	if !deferred {
+		return
+	}
+
+	if decision := ch.maintenance.Evaluate(context.Background(), workflow.maintenanceTargets()...); decision != nil {
+		ch.deferWorkflow(workflow, nil, decision)
+		return
+	}
+
+	ch.mu.Lock()
+	held, ok := ch.deferred[workflowID]
+	if !ok {
+		// Cancelled or already resumed
+		ch.mu.Unlock()
+		return
+	}
+	held.resume.Stop()
+	delete(ch.deferred, workflowID)
+	workflow.Status = "pending"
+	if ch.queue != nil {
+		workflow.Status = "queued"
+	}
+	ch.publishStatus(workflow)
+	ch.mu.Unlock()
+
+	ch.log.WithField("workflow_id", workflowID).Info("Resuming deferred multi-layer remediation")
+	held.start()
 }
 
-// saveWorkflow persists workflow state
-func (ch *CoordinationHandler) saveWorkflow(workflow *CoordinationWorkflow) {
+// resumeDeferred re-evaluates every deferred workflow, e.g. after the freeze lifts
+func (ch *CoordinationHandler) resumeDeferred() {
+	ch.mu.RLock()
+	ids := make([]string, 0, len(ch.deferred))
+	for id := range ch.deferred {
+		ids = append(ids, id)
+	}
+	ch.mu.RUnlock()
+
+	for _, id := range ids {
+		ch.resumeWorkflow(id)
+	}
+}
+
+// closeDeferredWorkflow cancels a deferred workflow that never started
+func (ch *CoordinationHandler) closeDeferredWorkflow(workflowID string) bool {
 	ch.mu.Lock()
 	defer ch.mu.Unlock()
-	ch.coordinationWorkflows[workflow.ID] = workflow
+
+	deferred, ok := ch.deferred[workflowID]
+	if !ok {
+		return false
+	}
+	deferred.resume.Stop()
+	delete(ch.deferred, workflowID)
+	delete(ch.cancellations, workflowID)
+
+	now := time.Now()
+	workflow := ch.coordinationWorkflows[workflowID]
+	workflow.Status = "cancelled"
+	workflow.ErrorMessage = "workflow cancelled while deferred"
+	workflow.CompletedAt = &now
+	ch.publishStatus(workflow)
+	return true
+}
+
+// RejectCoordinationWorkflow handles POST /api/v1/coordination/workflows/{id}/reject
+func (ch *CoordinationHandler) RejectCoordinationWorkflow(w http.ResponseWriter, r *http.Request) {
+	workflowID := mux.Vars(r)["id"]
+	req, err := decodeApprovalDecision(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	response, err := ch.closeHeldWorkflow(workflowID, string(models.WorkflowStatusRejected), "workflow rejected by "+req.DecidedBy, func(approval *models.Approval) {
+		approval.Decide(models.ApprovalDecisionRejected, req.DecidedBy, req.Comment)
+	})
+	if err != nil {
+		ch.writeApprovalError(w, err)
+		return
+	}
+
+	ch.recordIncidentOutcome(ch.incidentIDFor(workflowID), workflowID, response.Status)
+	remediation.RecordApproval("coordination", models.ApprovalDecisionRejected)
+	ch.log.WithFields(logrus.Fields{
+		"workflow_id": workflowID,
+		"rejected_by": req.DecidedBy,
+	}).Info("Multi-layer remediation rejected")
+
+	ch.writeApprovalResponse(w, *response)
+}
+
+// expireApproval cancels a held workflow whose approval window has passed
+func (ch *CoordinationHandler) expireApproval(workflowID string) {
+	message := fmt.Sprintf("workflow cancelled: approval not given within %s", ch.approvals.ExpiryOrDefault())
+	_, err := ch.closeHeldWorkflow(workflowID, "cancelled", message, func(approval *models.Approval) {
+		approval.Decide(models.ApprovalDecisionExpired, "system", "")
+	})
+	if err != nil {
+		// Approved, rejected or cancelled just before the timer fired
+		return
+	}
+
+	ch.recordIncidentOutcome(ch.incidentIDFor(workflowID), workflowID, "cancelled")
+	remediation.RecordApproval("coordination", models.ApprovalDecisionExpired)
+	ch.log.WithField("workflow_id", workflowID).Warn("Multi-layer remediation approval expired")
+}
+
+// closeHeldWorkflow moves a held workflow to a final status without running it
+func (ch *CoordinationHandler) closeHeldWorkflow(workflowID, status, message string, decide func(*models.Approval)) (*ApprovalDecisionResponse, error) {
+	ch.mu.Lock()
+	defer ch.mu.Unlock()
+
+	workflow, held, err := ch.heldWorkflow(workflowID)
+	if err != nil {
+		return nil, err
+	}
+	held.expiry.Stop()
+	delete(ch.held, workflowID)
+
+	if cancellation, ok := ch.cancellations[workflowID]; ok {
+		cancellation.cancel()
+		delete(ch.cancellations, workflowID)
+	}
+
+	now := time.Now()
+	if decide != nil {
+		decide(workflow.Approval)
+	}
+	workflow.Status = status
+	workflow.ErrorMessage = message
+	workflow.CompletedAt = &now
+	ch.publishStatus(workflow)
+
+	return &ApprovalDecisionResponse{
+		WorkflowID: workflow.ID,
+		Status:     workflow.Status,
+		Approval:   workflow.Approval.Clone(),
+	}, nil
+}
+
+// heldWorkflow returns a workflow waiting for approval. Callers must hold ch.mu.
+func (ch *CoordinationHandler) heldWorkflow(workflowID string) (*CoordinationWorkflow, *heldCoordinationWorkflow, error) {
+	workflow, exists := ch.coordinationWorkflows[workflowID]
+	if !exists {
+		return nil, nil, remediation.ErrWorkflowNotFound
+	}
+	held, ok := ch.held[workflowID]
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: %s is %s", remediation.ErrWorkflowNotPendingApproval, workflowID, workflow.Status)
+	}
+	return workflow, held, nil
+}
+
+// writeApprovalError maps approval errors to HTTP status codes
+func (ch *CoordinationHandler) writeApprovalError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, remediation.ErrWorkflowNotFound):
+		http.Error(w, "workflow not found", http.StatusNotFound)
+	case errors.Is(err, remediation.ErrWorkflowNotPendingApproval):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// writeApprovalResponse encodes an approval decision response
+func (ch *CoordinationHandler) writeApprovalResponse(w http.ResponseWriter, response ApprovalDecisionResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		ch.log.WithError(err).Error("Failed to encode approval response")
+	}
+}
+
+// recordIncidentOutcome updates the incident linked to a finished coordination workflow
+func (ch *CoordinationHandler) recordIncidentOutcome(incidentID, workflowID, status string) {
+	if ch.incidents == nil || incidentID == "" {
+		return
+	}
+	ch.incidents.WorkflowFinished(context.Background(), incidentID, workflowID, status)
+}
+
+// incidentIDFor returns the incident of a coordination workflow
+func (ch *CoordinationHandler) incidentIDFor(workflowID string) string {
+	ch.mu.RLock()
+	defer ch.mu.RUnlock()
+	if workflow, ok := ch.coordinationWorkflows[workflowID]; ok {
+		return workflow.IncidentID
+	}
+	return ""
+}
+
+// withQueuePosition returns a copy of a workflow with its live queue position.
+// Callers must hold ch.mu.
+func (ch *CoordinationHandler) withQueuePosition(workflow *CoordinationWorkflow) CoordinationWorkflow {
+	view := *workflow
+	view.Approval = workflow.Approval.Clone()
+	if ch.queue != nil && view.Status == "queued" {
+		view.QueuePosition = ch.queue.Position(view.ID)
+	}
+	return view
+}
+
+// publishStatus publishes the current status of a workflow. Callers must hold ch.mu.
+func (ch *CoordinationHandler) publishStatus(workflow *CoordinationWorkflow) {
+	details := map[string]string{"remediator": "multi_layer"}
+	if workflow.LayeredIssue != nil {
+		details["root_cause_layer"] = string(workflow.LayeredIssue.RootCauseLayer)
+	}
+	if workflow.ExecutionResult != nil {
+		details["executed_steps"] = strconv.Itoa(workflow.ExecutionResult.ExecutedSteps)
+	}
+
+	ch.events.Publish(events.Event{
+		Type:       events.TypeStatusChanged,
+		Source:     events.SourceCoordination,
+		WorkflowID: workflow.ID,
+		PlanID:     workflow.planID(),
+		IncidentID: workflow.IncidentID,
+		Namespace:  workflow.Namespace,
+		Severity:   workflow.Severity,
+		Status:     workflow.Status,
+		Message:    workflow.ErrorMessage,
+		Details:    details,
+		Final:      isFinalCoordinationStatus(workflow.Status),
+	})
+}
+
+// planID returns the ID of the workflow's remediation plan, if any
+func (workflow *CoordinationWorkflow) planID() string {
+	if workflow.RemediationPlan == nil {
+		return ""
+	}
+	return workflow.RemediationPlan.ID
+}
+
+// maintenanceTargets returns the resources of each affected layer, for checking
+// maintenance windows. A layer without known resources is checked on its own.
+func (workflow *CoordinationWorkflow) maintenanceTargets() []remediation.MaintenanceTarget {
+	if workflow.LayeredIssue == nil {
+		return nil
+	}
+
+	var targets []remediation.MaintenanceTarget
+	for _, layer := range workflow.LayeredIssue.AffectedLayers {
+		resources := workflow.LayeredIssue.GetResourcesForLayer(layer)
+		if len(resources) == 0 {
+			targets = append(targets, remediation.MaintenanceTarget{Namespace: workflow.Namespace, Layer: layer})
+			continue
+		}
+		for _, resource := range resources {
+			targets = append(targets, remediation.MaintenanceTarget{
+				Namespace: resource.Namespace,
+				Kind:      resource.Kind,
+				Name:      resource.Name,
+				Layer:     layer,
+			})
+		}
+	}
+	return targets
+}
+
+// isFinalCoordinationStatus returns true for statuses a coordination workflow never leaves
+func isFinalCoordinationStatus(status string) bool {
+	switch status {
+	case "completed", "failed", "cancelled", "rejected", "rolled_back", "recommended":
+		return true
+	default:
+		return false
+	}
 }
 
 // generateCoordinationWorkflowID generates a unique workflow ID
@@ -267,5 +978,9 @@ func (ch *CoordinationHandler) RegisterRoutes(router *mux.Router) {
 	apiV1 := router.PathPrefix("/api/v1").Subrouter()
 	apiV1.HandleFunc("/coordination/trigger", ch.TriggerMultiLayerRemediation).Methods("POST")
 	apiV1.HandleFunc("/coordination/workflows/{id}", ch.GetCoordinationWorkflow).Methods("GET")
+	apiV1.HandleFunc("/coordination/workflows/{id}", ch.CancelCoordinationWorkflow).Methods("DELETE")
+	apiV1.HandleFunc("/coordination/workflows/{id}/events", ch.StreamCoordinationWorkflowEvents).Methods("GET")
+	apiV1.HandleFunc("/coordination/workflows/{id}/approve", ch.ApproveCoordinationWorkflow).Methods("POST")
+	apiV1.HandleFunc("/coordination/workflows/{id}/reject", ch.RejectCoordinationWorkflow).Methods("POST")
 	apiV1.HandleFunc("/coordination/workflows", ch.ListCoordinationWorkflows).Methods("GET")
 }
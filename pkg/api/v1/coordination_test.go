@@ -0,0 +1,247 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/coordination"
+	"github.com/tosin2013/openshift-coordination-engine/internal/detector"
+	"github.com/tosin2013/openshift-coordination-engine/internal/remediation"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// blockingStepRemediator reports each remediation it starts and blocks until cancelled
+type blockingStepRemediator struct {
+	started chan string
+}
+
+func (b *blockingStepRemediator) Remediate(ctx context.Context, _ *models.DeploymentInfo, issue *models.Issue) (*models.RemediationResult, error) {
+	b.started <- issue.ResourceName
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (b *blockingStepRemediator) PlanActions(_ context.Context, _ *models.DeploymentInfo, _ *models.Issue) ([]remediation.PlannedAction, error) {
+	return nil, nil
+}
+
+func (b *blockingStepRemediator) CanRemediate(_ *models.DeploymentInfo) bool { return true }
+
+func (b *blockingStepRemediator) Name() string { return "blocking" }
+
+func newTestCoordinationHandler() (*CoordinationHandler, *mux.Router, *blockingStepRemediator) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	clientset := fake.NewSimpleClientset()
+	remediator := &blockingStepRemediator{started: make(chan string, 10)}
+	orchestrator := coordination.NewMultiLayerOrchestrator(
+		coordination.NewHealthChecker(clientset, nil, log), detector.NewDetector(clientset, log), remediator, clientset, log)
+	handler := NewCoordinationHandler(coordination.NewLayerDetector(log), coordination.NewMultiLayerPlanner(log), orchestrator, log)
+
+	router := mux.NewRouter()
+	handler.RegisterRoutes(router)
+	return handler, router, remediator
+}
+
+// triggerCoordination triggers a plan restarting deployment name
+func triggerCoordination(t *testing.T, router *mux.Router, name, severity string) (int, TriggerMultiLayerRemediationResponse) {
+	t.Helper()
+	body := `{"incident_id":"inc-` + name + `","description":"deployment ` + name + ` pods crashing","severity":"` + severity + `",` +
+		`"resources":[{"kind":"Deployment","name":"` + name + `","namespace":"default"}]}`
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/coordination/trigger", strings.NewReader(body)))
+
+	var response TriggerMultiLayerRemediationResponse
+	if rec.Code < http.StatusBadRequest {
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+	}
+	return rec.Code, response
+}
+
+func serveCoordination(router *mux.Router, method, path, body string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(method, path, strings.NewReader(body)))
+	return rec
+}
+
+// waitForCoordinationStatus waits for a workflow to reach status and returns a copy of it
+func waitForCoordinationStatus(t *testing.T, handler *CoordinationHandler, workflowID, status string) CoordinationWorkflow {
+	t.Helper()
+	var view CoordinationWorkflow
+	require.Eventually(t, func() bool {
+		handler.mu.RLock()
+		defer handler.mu.RUnlock()
+		view = handler.withQueuePosition(handler.coordinationWorkflows[workflowID])
+		return view.Status == status
+	}, 5*time.Second, 5*time.Millisecond, "workflow %s never reached %s (last %s)", workflowID, status, view.Status)
+	return view
+}
+
+func TestCoordinationHandler_CancelDuringStep(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus string
+	}{
+		{name: "without rollback", query: "", wantStatus: "cancelled"},
+		{name: "rollback=false", query: "?rollback=false", wantStatus: "cancelled"},
+		{name: "rollback=true", query: "?rollback=true", wantStatus: "rolled_back"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, router, remediator := newTestCoordinationHandler()
+			code, triggered := triggerCoordination(t, router, "payment", "high")
+			require.Equal(t, http.StatusAccepted, code)
+			assert.Equal(t, "payment", <-remediator.started)
+
+			rec := serveCoordination(router, http.MethodDelete, "/api/v1/coordination/workflows/"+triggered.WorkflowID+tt.query, "")
+			require.Equal(t, http.StatusAccepted, rec.Code)
+			assert.Contains(t, rec.Body.String(), `"status":"cancellation_requested"`)
+
+			workflow := waitForCoordinationStatus(t, handler, triggered.WorkflowID, tt.wantStatus)
+			require.NotNil(t, workflow.ExecutionResult)
+			assert.Equal(t, tt.wantStatus, workflow.ExecutionResult.Status)
+			assert.Contains(t, workflow.ErrorMessage, "cancelled")
+			assert.NotNil(t, workflow.StartedAt)
+			assert.NotNil(t, workflow.CompletedAt)
+
+			// A finished workflow cannot be cancelled again
+			rec = serveCoordination(router, http.MethodDelete, "/api/v1/coordination/workflows/"+triggered.WorkflowID, "")
+			assert.Equal(t, http.StatusConflict, rec.Code)
+		})
+	}
+}
+
+func TestCoordinationHandler_ApprovalHold(t *testing.T) {
+	handler, router, remediator := newTestCoordinationHandler()
+	handler.SetApprovalPolicy(&remediation.ApprovalPolicy{Severities: []string{"critical"}})
+
+	code, triggered := triggerCoordination(t, router, "payment", "critical")
+	require.Equal(t, http.StatusAccepted, code)
+	assert.Equal(t, string(models.WorkflowStatusPendingApproval), triggered.Status)
+	assert.Empty(t, remediator.started, "a held plan does not run")
+
+	rec := serveCoordination(router, http.MethodPost, "/api/v1/coordination/workflows/"+triggered.WorkflowID+"/approve", `{}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = serveCoordination(router, http.MethodPost, "/api/v1/coordination/workflows/"+triggered.WorkflowID+"/approve", `{"decided_by":"alice"}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "payment", <-remediator.started)
+
+	workflow := waitForCoordinationStatus(t, handler, triggered.WorkflowID, "executing")
+	assert.Equal(t, models.ApprovalDecisionApproved, workflow.Approval.Decision)
+
+	// Approving twice is refused
+	rec = serveCoordination(router, http.MethodPost, "/api/v1/coordination/workflows/"+triggered.WorkflowID+"/approve", `{"decided_by":"alice"}`)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	serveCoordination(router, http.MethodDelete, "/api/v1/coordination/workflows/"+triggered.WorkflowID, "")
+	waitForCoordinationStatus(t, handler, triggered.WorkflowID, "cancelled")
+}
+
+func TestCoordinationHandler_ApprovalRejectAndExpiry(t *testing.T) {
+	handler, router, remediator := newTestCoordinationHandler()
+	handler.SetApprovalPolicy(&remediation.ApprovalPolicy{Severities: []string{"critical"}, Expiry: 50 * time.Millisecond})
+
+	_, rejected := triggerCoordination(t, router, "payment", "critical")
+	rec := serveCoordination(router, http.MethodPost, "/api/v1/coordination/workflows/"+rejected.WorkflowID+"/reject", `{"decided_by":"bob"}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	workflow := waitForCoordinationStatus(t, handler, rejected.WorkflowID, string(models.WorkflowStatusRejected))
+	assert.Equal(t, models.ApprovalDecisionRejected, workflow.Approval.Decision)
+
+	_, expired := triggerCoordination(t, router, "cart", "critical")
+	workflow = waitForCoordinationStatus(t, handler, expired.WorkflowID, "cancelled")
+	assert.Equal(t, models.ApprovalDecisionExpired, workflow.Approval.Decision)
+	assert.Contains(t, workflow.ErrorMessage, "approval not given")
+	assert.Empty(t, remediator.started, "neither plan runs")
+}
+
+func TestCoordinationHandler_DeferAndResume(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	handler, router, remediator := newTestCoordinationHandler()
+	policy := remediation.NewMaintenancePolicy(nil, nil, log)
+	handler.SetMaintenancePolicy(policy)
+
+	_, err := policy.SetFreeze(remediation.MaintenanceActionQueue, "release week", "alice", nil)
+	require.NoError(t, err)
+	code, triggered := triggerCoordination(t, router, "payment", "high")
+	require.Equal(t, http.StatusAccepted, code)
+	assert.Equal(t, string(models.WorkflowStatusDeferred), triggered.Status)
+	assert.Contains(t, triggered.Message, "release week")
+
+	// A deferred workflow can be cancelled without ever running
+	_, cancelled := triggerCoordination(t, router, "cart", "high")
+	rec := serveCoordination(router, http.MethodDelete, "/api/v1/coordination/workflows/"+cancelled.WorkflowID, "")
+	assert.Contains(t, rec.Body.String(), `"status":"cancelled"`)
+
+	assert.Empty(t, remediator.started, "deferred plans do not run")
+	require.True(t, policy.Unfreeze())
+	assert.Equal(t, "payment", <-remediator.started)
+	waitForCoordinationStatus(t, handler, triggered.WorkflowID, "executing")
+
+	serveCoordination(router, http.MethodDelete, "/api/v1/coordination/workflows/"+triggered.WorkflowID, "")
+	waitForCoordinationStatus(t, handler, triggered.WorkflowID, "cancelled")
+	assert.Empty(t, remediator.started)
+}
+
+func TestCoordinationHandler_MaintenanceRejectAndRecommend(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	handler, router, remediator := newTestCoordinationHandler()
+	policy := remediation.NewMaintenancePolicy(nil, nil, log)
+	handler.SetMaintenancePolicy(policy)
+
+	_, err := policy.SetFreeze(remediation.MaintenanceActionReject, "incident review", "alice", nil)
+	require.NoError(t, err)
+	code, _ := triggerCoordination(t, router, "payment", "high")
+	assert.Equal(t, http.StatusConflict, code)
+
+	_, err = policy.SetFreeze(remediation.MaintenanceActionRecommend, "incident review", "alice", nil)
+	require.NoError(t, err)
+	code, recommended := triggerCoordination(t, router, "payment", "high")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, string(models.WorkflowStatusRecommended), recommended.Status)
+	require.Len(t, recommended.Recommendations, 2)
+	assert.Contains(t, recommended.Recommendations[1], "restart_deployment of default/payment")
+	assert.Empty(t, remediator.started, "recommended plans do not run")
+}
+
+func TestCoordinationHandler_WorkQueue(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	handler, router, remediator := newTestCoordinationHandler()
+	queue := remediation.NewWorkQueue(remediation.WorkQueueConfig{Workers: 1}, log)
+	queue.Start()
+	defer queue.Stop()
+	handler.SetWorkQueue(queue)
+
+	_, running := triggerCoordination(t, router, "payment", "high")
+	assert.Equal(t, "payment", <-remediator.started)
+
+	_, queued := triggerCoordination(t, router, "cart", "high")
+	assert.Equal(t, "queued", queued.Status)
+	assert.Equal(t, 1, queued.QueuePosition)
+	assert.Equal(t, 1, waitForCoordinationStatus(t, handler, queued.WorkflowID, "queued").QueuePosition)
+
+	// Cancelling a queued workflow removes it before it starts
+	rec := serveCoordination(router, http.MethodDelete, "/api/v1/coordination/workflows/"+queued.WorkflowID, "")
+	assert.Contains(t, rec.Body.String(), `"status":"cancelled"`)
+	workflow := waitForCoordinationStatus(t, handler, queued.WorkflowID, "cancelled")
+	assert.Equal(t, "workflow cancelled while queued", workflow.ErrorMessage)
+	assert.Nil(t, workflow.StartedAt)
+
+	serveCoordination(router, http.MethodDelete, "/api/v1/coordination/workflows/"+running.WorkflowID, "")
+	waitForCoordinationStatus(t, handler, running.WorkflowID, "cancelled")
+	assert.Empty(t, remediator.started, "the cancelled queued plan never runs")
+}
@@ -0,0 +1,77 @@
+package v1
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/events"
+)
+
+// eventStreamHeartbeat is how often a comment is sent to keep idle streams open through proxies
+const eventStreamHeartbeat = 15 * time.Second
+
+// streamEvents writes current and then every event of sub as Server-Sent Events.
+// The stream ends when the workflow reaches a final status or the client disconnects.
+func streamEvents(w http.ResponseWriter, r *http.Request, sub *events.Subscription, current events.Event, log *logrus.Logger) {
+	rc := http.NewResponseController(w)
+
+	// Streams outlive the server write timeout
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		log.WithError(err).Debug("Failed to clear write deadline for event stream")
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	if err := writeEvent(w, rc, current); err != nil || current.Final {
+		return
+	}
+
+	heartbeat := time.NewTicker(eventStreamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event, ok := <-sub.C:
+			if !ok {
+				return
+			}
+			if err := writeEvent(w, rc, event); err != nil {
+				log.WithError(err).Debug("Event stream closed by client")
+				return
+			}
+			if event.Final {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": keepalive\n\n"); err != nil {
+				return
+			}
+			if err := rc.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// writeEvent writes one Server-Sent Event and flushes it to the client
+func writeEvent(w http.ResponseWriter, rc *http.ResponseController, event events.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data); err != nil {
+		return err
+	}
+	return rc.Flush()
+}
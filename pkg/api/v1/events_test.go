@@ -0,0 +1,34 @@
+package v1
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/events"
+)
+
+func TestStreamEvents_EndsOnFinalStatus(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	bus := events.NewBus(log)
+	sub := bus.Subscribe(nil)
+	defer sub.Close()
+
+	bus.Publish(events.Event{Type: events.TypeStepAdded, Source: events.SourceRemediation, WorkflowID: "wf-1", Message: "Execute remediation"})
+	bus.Publish(events.Event{Type: events.TypeStatusChanged, Source: events.SourceRemediation, WorkflowID: "wf-1", Status: "completed", Final: true})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/workflows/wf-1/events", nil)
+	rec := httptest.NewRecorder()
+	streamEvents(rec, req, sub, events.Event{Type: events.TypeStatusChanged, WorkflowID: "wf-1", Status: "in_progress"}, log)
+
+	assert.Equal(t, "text/event-stream", rec.Header().Get("Content-Type"))
+	body := rec.Body.String()
+	assert.Equal(t, 3, strings.Count(body, "\n\n"))
+	assert.Contains(t, body, "event: step_added\n")
+	assert.True(t, strings.Index(body, `"status":"in_progress"`) < strings.Index(body, `"status":"completed"`))
+}
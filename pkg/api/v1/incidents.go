@@ -0,0 +1,133 @@
+package v1
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/incidents"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// IncidentHandler handles incident API requests
+type IncidentHandler struct {
+	tracker *incidents.Tracker
+	log     *logrus.Logger
+}
+
+// NewIncidentHandler creates a new incident handler
+func NewIncidentHandler(tracker *incidents.Tracker, log *logrus.Logger) *IncidentHandler {
+	return &IncidentHandler{
+		tracker: tracker,
+		log:     log,
+	}
+}
+
+// ListIncidentsResponse represents a page of incidents
+type ListIncidentsResponse struct {
+	Incidents  []*models.Incident `json:"incidents"`
+	Total      int                `json:"total"` // incidents in this page
+	Limit      int                `json:"limit"`
+	NextCursor string             `json:"next_cursor,omitempty"`
+}
+
+// validIncidentStatusFilters lists accepted values of the status query parameter
+var validIncidentStatusFilters = map[string]bool{
+	incidents.StatusActive:                   true,
+	string(models.IncidentStatusOpen):        true,
+	string(models.IncidentStatusRemediating): true,
+	string(models.IncidentStatusResolved):    true,
+	string(models.IncidentStatusFailed):      true,
+	string(models.IncidentStatusEscalated):   true,
+}
+
+// ListIncidents handles GET /api/v1/incidents
+// Query parameters: namespace, status (a lifecycle status or "active"), limit and cursor.
+func (h *IncidentHandler) ListIncidents(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	opts := incidents.ListOptions{
+		Namespace: query.Get("namespace"),
+		Status:    query.Get("status"),
+		Limit:     incidents.DefaultListLimit,
+		Cursor:    query.Get("cursor"),
+	}
+
+	if opts.Status != "" && !validIncidentStatusFilters[opts.Status] {
+		http.Error(w, "invalid status: "+opts.Status, http.StatusBadRequest)
+		return
+	}
+	if limitStr := query.Get("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 {
+			http.Error(w, "limit must be a positive integer", http.StatusBadRequest)
+			return
+		}
+		if limit > incidents.MaxListLimit {
+			limit = incidents.MaxListLimit
+		}
+		opts.Limit = limit
+	}
+
+	page, next, err := h.tracker.List(r.Context(), opts)
+	if err != nil {
+		if errors.Is(err, incidents.ErrInvalidCursor) {
+			http.Error(w, "invalid cursor", http.StatusBadRequest)
+			return
+		}
+		h.log.WithError(err).Error("Failed to list incidents")
+		http.Error(w, "Failed to list incidents: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := ListIncidentsResponse{
+		Incidents:  page,
+		Total:      len(page),
+		Limit:      opts.Limit,
+		NextCursor: next,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.log.WithError(err).Error("Failed to encode incidents response")
+	}
+
+	h.log.WithFields(logrus.Fields{
+		"count":     len(page),
+		"namespace": opts.Namespace,
+		"status":    opts.Status,
+	}).Debug("Incidents listed successfully")
+}
+
+// GetIncident handles GET /api/v1/incidents/{id}
+func (h *IncidentHandler) GetIncident(w http.ResponseWriter, r *http.Request) {
+	incidentID := mux.Vars(r)["id"]
+
+	incident, err := h.tracker.Get(r.Context(), incidentID)
+	if err != nil {
+		if errors.Is(err, incidents.ErrIncidentNotFound) {
+			http.Error(w, "Incident not found", http.StatusNotFound)
+			return
+		}
+		h.log.WithError(err).Error("Failed to get incident")
+		http.Error(w, "Failed to get incident: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(incident); err != nil {
+		h.log.WithError(err).Error("Failed to encode incident response")
+	}
+}
+
+// RegisterRoutes registers incident API routes
+func (h *IncidentHandler) RegisterRoutes(router *mux.Router) {
+	apiV1 := router.PathPrefix("/api/v1").Subrouter()
+	apiV1.HandleFunc("/incidents", h.ListIncidents).Methods("GET")
+	apiV1.HandleFunc("/incidents/{id}", h.GetIncident).Methods("GET")
+}
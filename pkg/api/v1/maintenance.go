@@ -0,0 +1,110 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/remediation"
+)
+
+// MaintenanceHandler handles maintenance window and global freeze API requests
+type MaintenanceHandler struct {
+	policy *remediation.MaintenancePolicy
+	log    *logrus.Logger
+}
+
+// NewMaintenanceHandler creates a new maintenance handler
+func NewMaintenanceHandler(policy *remediation.MaintenancePolicy, log *logrus.Logger) *MaintenanceHandler {
+	return &MaintenanceHandler{
+		policy: policy,
+		log:    log,
+	}
+}
+
+// MaintenanceStatusResponse reports the global freeze and the configured windows
+type MaintenanceStatusResponse struct {
+	Freeze  remediation.FreezeStatus   `json:"freeze"`
+	Windows []remediation.WindowStatus `json:"windows"`
+}
+
+// FreezeRequest is the request body for enabling the global freeze
+type FreezeRequest struct {
+	FrozenBy string     `json:"frozen_by"`
+	Reason   string     `json:"reason,omitempty"`
+	Action   string     `json:"action,omitempty"`   // reject (default), queue or recommend
+	Until    *time.Time `json:"until,omitempty"`    // lift the freeze at this time
+	Duration string     `json:"duration,omitempty"` // or after this long, e.g. 4h
+}
+
+// GetMaintenanceStatus handles GET /api/v1/maintenance
+func (h *MaintenanceHandler) GetMaintenanceStatus(w http.ResponseWriter, _ *http.Request) {
+	h.writeJSON(w, http.StatusOK, MaintenanceStatusResponse{
+		Freeze:  h.policy.Freeze(),
+		Windows: h.policy.Windows(),
+	})
+}
+
+// SetFreeze handles PUT /api/v1/maintenance/freeze
+func (h *MaintenanceHandler) SetFreeze(w http.ResponseWriter, r *http.Request) {
+	var req FreezeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.FrozenBy == "" {
+		http.Error(w, "frozen_by is required", http.StatusBadRequest)
+		return
+	}
+
+	until := req.Until
+	if req.Duration != "" {
+		if until != nil {
+			http.Error(w, "until and duration are mutually exclusive", http.StatusBadRequest)
+			return
+		}
+		duration, err := time.ParseDuration(req.Duration)
+		if err != nil || duration <= 0 {
+			http.Error(w, "duration must be a positive duration such as 4h", http.StatusBadRequest)
+			return
+		}
+		lift := time.Now().Add(duration)
+		until = &lift
+	}
+
+	status, err := h.policy.SetFreeze(req.Action, req.Reason, req.FrozenBy, until)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, status)
+}
+
+// LiftFreeze handles DELETE /api/v1/maintenance/freeze
+func (h *MaintenanceHandler) LiftFreeze(w http.ResponseWriter, _ *http.Request) {
+	if !h.policy.Unfreeze() {
+		http.Error(w, "global freeze is not active", http.StatusConflict)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, h.policy.Freeze())
+}
+
+// writeJSON writes a JSON response
+func (h *MaintenanceHandler) writeJSON(w http.ResponseWriter, statusCode int, response interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.log.WithError(err).Error("Failed to encode maintenance response")
+	}
+}
+
+// RegisterRoutes registers maintenance API routes
+func (h *MaintenanceHandler) RegisterRoutes(router *mux.Router) {
+	apiV1 := router.PathPrefix("/api/v1").Subrouter()
+	apiV1.HandleFunc("/maintenance", h.GetMaintenanceStatus).Methods("GET")
+	apiV1.HandleFunc("/maintenance/freeze", h.SetFreeze).Methods("PUT")
+	apiV1.HandleFunc("/maintenance/freeze", h.LiftFreeze).Methods("DELETE")
+}
@@ -0,0 +1,56 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/notifications"
+)
+
+// NotificationHandler handles webhook notification API requests
+type NotificationHandler struct {
+	notifier *notifications.Notifier // nil when no webhooks are configured
+	log      *logrus.Logger
+}
+
+// NewNotificationHandler creates a new notification handler
+func NewNotificationHandler(notifier *notifications.Notifier, log *logrus.Logger) *NotificationHandler {
+	return &NotificationHandler{
+		notifier: notifier,
+		log:      log,
+	}
+}
+
+// ListDeadLettersResponse lists notifications that could not be delivered
+type ListDeadLettersResponse struct {
+	DeadLetters []notifications.DeadLetter `json:"dead_letters"`
+	Total       int                        `json:"total"`
+}
+
+// ListDeadLetters handles GET /api/v1/notifications/dead-letters
+func (h *NotificationHandler) ListDeadLetters(w http.ResponseWriter, _ *http.Request) {
+	deadLetters := []notifications.DeadLetter{}
+	if h.notifier != nil {
+		deadLetters = append(deadLetters, h.notifier.DeadLetters()...)
+	}
+
+	response := ListDeadLettersResponse{
+		DeadLetters: deadLetters,
+		Total:       len(deadLetters),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.log.WithError(err).Error("Failed to encode dead letters response")
+	}
+}
+
+// RegisterRoutes registers notification API routes
+func (h *NotificationHandler) RegisterRoutes(router *mux.Router) {
+	apiV1 := router.PathPrefix("/api/v1").Subrouter()
+	apiV1.HandleFunc("/notifications/dead-letters", h.ListDeadLetters).Methods("GET")
+}
@@ -0,0 +1,68 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/remediation"
+)
+
+// PluginHandler handles remediation plugin registry API requests
+type PluginHandler struct {
+	registry *remediation.PluginRegistry // nil when no plugins are configured
+	log      *logrus.Logger
+}
+
+// NewPluginHandler creates a new plugin handler
+func NewPluginHandler(registry *remediation.PluginRegistry, log *logrus.Logger) *PluginHandler {
+	return &PluginHandler{
+		registry: registry,
+		log:      log,
+	}
+}
+
+// ListPluginsResponse lists the registered remediation plugins and their health
+type ListPluginsResponse struct {
+	Plugins []remediation.PluginStatus `json:"plugins"`
+	Total   int                        `json:"total"`
+}
+
+// ListPlugins handles GET /api/v1/remediation/plugins
+func (h *PluginHandler) ListPlugins(w http.ResponseWriter, _ *http.Request) {
+	plugins := h.registry.Plugins()
+	h.writeJSON(w, http.StatusOK, ListPluginsResponse{
+		Plugins: plugins,
+		Total:   len(plugins),
+	})
+}
+
+// GetPlugin handles GET /api/v1/remediation/plugins/{name}
+func (h *PluginHandler) GetPlugin(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["name"]
+	for _, plugin := range h.registry.Plugins() {
+		if plugin.Name == name {
+			h.writeJSON(w, http.StatusOK, plugin)
+			return
+		}
+	}
+	http.Error(w, "plugin not found", http.StatusNotFound)
+}
+
+// writeJSON writes a JSON response
+func (h *PluginHandler) writeJSON(w http.ResponseWriter, statusCode int, response interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.log.WithError(err).Error("Failed to encode plugin response")
+	}
+}
+
+// RegisterRoutes registers plugin registry API routes
+func (h *PluginHandler) RegisterRoutes(router *mux.Router) {
+	apiV1 := router.PathPrefix("/api/v1").Subrouter()
+	apiV1.HandleFunc("/remediation/plugins", h.ListPlugins).Methods("GET")
+	apiV1.HandleFunc("/remediation/plugins/{name}", h.GetPlugin).Methods("GET")
+}
@@ -2,12 +2,14 @@ package v1
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/sirupsen/logrus"
 
+	"github.com/tosin2013/openshift-coordination-engine/internal/events"
 	"github.com/tosin2013/openshift-coordination-engine/internal/remediation"
 	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
 )
@@ -15,6 +17,7 @@ import (
 // RemediationHandler handles remediation API requests
 type RemediationHandler struct {
 	orchestrator *remediation.Orchestrator
+	events       *events.Bus
 	log          *logrus.Logger
 }
 
@@ -26,9 +29,15 @@ func NewRemediationHandler(orchestrator *remediation.Orchestrator, log *logrus.L
 	}
 }
 
+// SetEventBus enables workflow event streams
+func (h *RemediationHandler) SetEventBus(bus *events.Bus) {
+	h.events = bus
+}
+
 // TriggerRemediationRequest represents the request body for triggering remediation
 type TriggerRemediationRequest struct {
 	IncidentID string `json:"incident_id"`
+	Source     string `json:"source,omitempty"` // defaults to "api"
 	Namespace  string `json:"namespace"`
 	Resource   struct {
 		Kind string `json:"kind"`
@@ -39,6 +48,10 @@ type TriggerRemediationRequest struct {
 		Description string `json:"description"`
 		Severity    string `json:"severity"`
 	} `json:"issue"`
+	DryRun bool `json:"dry_run,omitempty"` // plan only: detect and select a remediator without mutating anything
+
+	RollbackRevision int64               `json:"rollback_revision,omitempty"` // revision a rollback targets; zero picks the last available
+	Retry            *models.RetryPolicy `json:"retry,omitempty"`             // overrides the configured retry policies
 }
 
 // TriggerRemediationResponse represents the response for triggering remediation
@@ -47,6 +60,25 @@ type TriggerRemediationResponse struct {
 	Status            string `json:"status"`
 	DeploymentMethod  string `json:"deployment_method"`
 	EstimatedDuration string `json:"estimated_duration"`
+	Deduplicated      bool   `json:"deduplicated"`
+	QueuePosition     int    `json:"queue_position,omitempty"`
+
+	// Set when the trigger was refused, deferred or downgraded to recommendations
+	Message         string   `json:"message,omitempty"`
+	Recommendations []string `json:"recommendations,omitempty"`
+}
+
+// DryRunResponse represents the planned remediation returned for a dry-run trigger
+type DryRunResponse struct {
+	IncidentID       string                      `json:"incident_id"`
+	DryRun           bool                        `json:"dry_run"`
+	DeploymentMethod string                      `json:"deployment_method"`
+	Confidence       float64                     `json:"confidence"`
+	Remediator       string                      `json:"remediator"`
+	PlannedActions   []remediation.PlannedAction `json:"planned_actions"`
+
+	Selection []models.SelectionDecision `json:"selection,omitempty"` // a recommend_only decision means nothing would run
+	Gates     []remediation.DryRunGate   `json:"gates"`               // a gate that is not allowed means nothing would run
 }
 
 // WorkflowResponse represents the response for getting workflow details
@@ -61,11 +93,18 @@ type WorkflowResponse struct {
 	IssueType        string                `json:"issue_type"`
 	Remediator       string                `json:"remediator,omitempty"`
 	ErrorMessage     string                `json:"error_message,omitempty"`
+	QueuePosition    int                   `json:"queue_position,omitempty"`
+	Approval         *models.Approval      `json:"approval,omitempty"`
+	Recommendations  []string              `json:"recommendations,omitempty"`
+	Deferral         *models.Deferral      `json:"deferral,omitempty"`
+	Rollback         *models.Rollback      `json:"rollback,omitempty"`
 	CreatedAt        string                `json:"created_at"`
 	StartedAt        string                `json:"started_at,omitempty"`
 	CompletedAt      string                `json:"completed_at,omitempty"`
 	Duration         string                `json:"duration,omitempty"`
 	Steps            []models.WorkflowStep `json:"steps,omitempty"`
+
+	Selection []models.SelectionDecision `json:"selection,omitempty"`
 }
 
 // TriggerRemediation handles POST /api/v1/remediation/trigger
@@ -97,6 +136,12 @@ func (h *RemediationHandler) TriggerRemediation(w http.ResponseWriter, r *http.R
 		http.Error(w, "issue.type is required", http.StatusBadRequest)
 		return
 	}
+	if req.Retry != nil {
+		if err := req.Retry.ValidateOverride(); err != nil {
+			http.Error(w, "invalid retry: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
 
 	h.log.WithFields(logrus.Fields{
 		"incident_id": req.IncidentID,
@@ -114,11 +159,27 @@ func (h *RemediationHandler) TriggerRemediation(w http.ResponseWriter, r *http.R
 		ResourceType: req.Resource.Kind,
 		ResourceName: req.Resource.Name,
 		Description:  req.Issue.Description,
+		Source:       req.Source,
 		DetectedAt:   time.Now(),
+
+		RollbackRevision: req.RollbackRevision,
+		Retry:            req.Retry,
+	}
+	if issue.Source == "" {
+		issue.Source = "api"
+	}
+
+	if req.DryRun {
+		h.dryRun(w, r, req.IncidentID, issue)
+		return
 	}
 
 	// Trigger remediation workflow
-	workflow, err := h.orchestrator.TriggerRemediation(r.Context(), req.IncidentID, issue)
+	workflow, created, err := h.orchestrator.TriggerRemediation(r.Context(), req.IncidentID, issue)
+	if errors.Is(err, remediation.ErrMaintenanceBlocked) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
 	if err != nil {
 		h.log.WithError(err).Error("Failed to trigger remediation")
 		http.Error(w, "Failed to trigger remediation: "+err.Error(), http.StatusInternalServerError)
@@ -131,20 +192,68 @@ func (h *RemediationHandler) TriggerRemediation(w http.ResponseWriter, r *http.R
 		Status:            string(workflow.Status),
 		DeploymentMethod:  workflow.DeploymentMethod,
 		EstimatedDuration: "5m", // Default estimate
+		Deduplicated:      !created,
+		QueuePosition:     workflow.QueuePosition,
+	}
+
+	// A deduplicated trigger did not start anything new
+	statusCode := http.StatusAccepted
+	switch {
+	case workflow.Status == models.WorkflowStatusCircuitOpen:
+		statusCode = http.StatusConflict
+		response.Message = workflow.ErrorMessage
+		response.Recommendations = workflow.Recommendations
+	case workflow.Status == models.WorkflowStatusRecommended:
+		// Nothing will run; the caller gets the planned actions to apply manually
+		statusCode = http.StatusOK
+		response.Message = workflow.ErrorMessage
+		response.Recommendations = workflow.Recommendations
+	case workflow.Status == models.WorkflowStatusDeferred && workflow.Deferral != nil:
+		response.Message = workflow.Deferral.Reason
+	case !created:
+		statusCode = http.StatusOK
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusAccepted)
+	w.WriteHeader(statusCode)
 	if err := json.NewEncoder(w).Encode(response); err != nil {
 		h.log.WithError(err).Error("Failed to encode response")
 	}
 
 	h.log.WithFields(logrus.Fields{
-		"workflow_id": workflow.ID,
-		"status":      workflow.Status,
+		"workflow_id":  workflow.ID,
+		"status":       workflow.Status,
+		"deduplicated": !created,
 	}).Info("Remediation workflow triggered successfully")
 }
 
+// dryRun responds with the remediator and actions a trigger would use
+func (h *RemediationHandler) dryRun(w http.ResponseWriter, r *http.Request, incidentID string, issue *models.Issue) {
+	result, err := h.orchestrator.DryRun(r.Context(), issue)
+	if err != nil {
+		h.log.WithError(err).Error("Remediation dry run failed")
+		http.Error(w, "Dry run failed: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := DryRunResponse{
+		IncidentID:       incidentID,
+		DryRun:           true,
+		DeploymentMethod: result.DeploymentMethod,
+		Confidence:       result.Confidence,
+		Remediator:       result.Remediator,
+		PlannedActions:   result.PlannedActions,
+		Selection:        result.Selection,
+		Gates:            result.Gates,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.log.WithError(err).Error("Failed to encode dry run response")
+	}
+}
+
 // GetWorkflow handles GET /api/v1/workflows/{id}
 func (h *RemediationHandler) GetWorkflow(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
@@ -172,8 +281,14 @@ func (h *RemediationHandler) GetWorkflow(w http.ResponseWriter, r *http.Request)
 		IssueType:        workflow.IssueType,
 		Remediator:       workflow.Remediator,
 		ErrorMessage:     workflow.ErrorMessage,
+		QueuePosition:    workflow.QueuePosition,
+		Approval:         workflow.Approval,
+		Recommendations:  workflow.Recommendations,
+		Deferral:         workflow.Deferral,
+		Rollback:         workflow.Rollback,
 		CreatedAt:        workflow.CreatedAt.Format(time.RFC3339),
 		Steps:            workflow.Steps,
+		Selection:        workflow.Selection,
 	}
 
 	if workflow.StartedAt != nil {
@@ -196,51 +311,181 @@ func (h *RemediationHandler) GetWorkflow(w http.ResponseWriter, r *http.Request)
 	}).Info("Workflow details retrieved successfully")
 }
 
-// ListIncidents handles GET /api/v1/incidents
-// For now, this returns workflows (we'll enhance later with proper incident tracking)
-func (h *RemediationHandler) ListIncidents(w http.ResponseWriter, r *http.Request) {
-	h.log.Info("Listing incidents")
-
-	// Get all workflows
-	workflows := h.orchestrator.ListWorkflows()
-
-	// Convert to incident response format
-	incidents := make([]map[string]interface{}, 0, len(workflows))
-	for _, wf := range workflows {
-		incident := map[string]interface{}{
-			"id":          wf.IncidentID,
-			"namespace":   wf.Namespace,
-			"resource":    wf.ResourceKind + "/" + wf.ResourceName,
-			"issue_type":  wf.IssueType,
-			"severity":    "high", // Default for now
-			"created_at":  wf.CreatedAt.Format(time.RFC3339),
-			"status":      string(wf.Status),
-			"workflow_id": wf.ID,
+// CircuitBreakersResponse lists remediation circuit breakers
+type CircuitBreakersResponse struct {
+	CircuitBreakers []remediation.BreakerStatus `json:"circuit_breakers"`
+	Total           int                         `json:"total"`
+}
+
+// ListCircuitBreakers handles GET /api/v1/remediation/circuit-breakers
+// Query parameters: namespace and state (open or closed).
+func (h *RemediationHandler) ListCircuitBreakers(w http.ResponseWriter, r *http.Request) {
+	namespace := r.URL.Query().Get("namespace")
+	state := r.URL.Query().Get("state")
+	if state != "" && state != remediation.BreakerStateOpen && state != remediation.BreakerStateClosed {
+		http.Error(w, "invalid state: "+state, http.StatusBadRequest)
+		return
+	}
+
+	breakers := make([]remediation.BreakerStatus, 0)
+	for _, breaker := range h.orchestrator.CircuitBreakers() {
+		if (namespace == "" || breaker.Namespace == namespace) && (state == "" || breaker.State == state) {
+			breakers = append(breakers, breaker)
 		}
+	}
+
+	response := CircuitBreakersResponse{
+		CircuitBreakers: breakers,
+		Total:           len(breakers),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.log.WithError(err).Error("Failed to encode circuit breakers response")
+	}
+}
 
-		// Add remediation status
-		switch wf.Status {
-		case models.WorkflowStatusCompleted:
-			incident["status"] = "remediated"
-		case models.WorkflowStatusFailed:
-			incident["status"] = "failed"
+// ApprovalDecisionRequest is the request body for approving or rejecting a workflow
+type ApprovalDecisionRequest struct {
+	DecidedBy string `json:"decided_by"`
+	Comment   string `json:"comment,omitempty"`
+}
+
+// ApprovalDecisionResponse represents the response for an approval decision
+type ApprovalDecisionResponse struct {
+	WorkflowID    string           `json:"workflow_id"`
+	Status        string           `json:"status"`
+	Approval      *models.Approval `json:"approval"`
+	QueuePosition int              `json:"queue_position,omitempty"`
+}
+
+// decodeApprovalDecision parses and validates an approval decision body
+func decodeApprovalDecision(r *http.Request) (*ApprovalDecisionRequest, error) {
+	var req ApprovalDecisionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.New("invalid request body")
+	}
+	if req.DecidedBy == "" {
+		return nil, errors.New("decided_by is required")
+	}
+	return &req, nil
+}
+
+// StreamWorkflowEvents handles GET /api/v1/workflows/{id}/events
+// It streams step additions and status changes as Server-Sent Events.
+func (h *RemediationHandler) StreamWorkflowEvents(w http.ResponseWriter, r *http.Request) {
+	workflowID := mux.Vars(r)["id"]
+	if h.events == nil {
+		http.Error(w, "event streaming is not enabled", http.StatusServiceUnavailable)
+		return
+	}
+
+	// Subscribe before reading the current status so no transition is missed
+	sub := h.events.Subscribe(func(event events.Event) bool {
+		return event.Source == events.SourceRemediation && event.WorkflowID == workflowID
+	})
+	defer sub.Close()
+
+	workflow, err := h.orchestrator.GetWorkflow(workflowID)
+	if err != nil {
+		http.Error(w, "Workflow not found", http.StatusNotFound)
+		return
+	}
+
+	streamEvents(w, r, sub, events.Event{
+		Type:       events.TypeStatusChanged,
+		Source:     events.SourceRemediation,
+		WorkflowID: workflow.ID,
+		Status:     string(workflow.Status),
+		Message:    workflow.ErrorMessage,
+		Final:      !workflow.IsActive(),
+		Timestamp:  time.Now(),
+	}, h.log)
+}
+
+// ApproveWorkflow handles POST /api/v1/workflows/{id}/approve
+func (h *RemediationHandler) ApproveWorkflow(w http.ResponseWriter, r *http.Request) {
+	h.decideWorkflow(w, r, h.orchestrator.ApproveWorkflow)
+}
+
+// RejectWorkflow handles POST /api/v1/workflows/{id}/reject
+func (h *RemediationHandler) RejectWorkflow(w http.ResponseWriter, r *http.Request) {
+	h.decideWorkflow(w, r, h.orchestrator.RejectWorkflow)
+}
+
+// decideWorkflow applies an approval decision to a workflow held by the approval policy
+func (h *RemediationHandler) decideWorkflow(w http.ResponseWriter, r *http.Request, decide func(workflowID, decidedBy, comment string) (*models.Workflow, error)) {
+	workflowID := mux.Vars(r)["id"]
+
+	req, err := decodeApprovalDecision(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	workflow, err := decide(workflowID, req.DecidedBy, req.Comment)
+	if err != nil {
+		switch {
+		case errors.Is(err, remediation.ErrWorkflowNotFound):
+			http.Error(w, "Workflow not found", http.StatusNotFound)
+		case errors.Is(err, remediation.ErrWorkflowNotPendingApproval):
+			http.Error(w, err.Error(), http.StatusConflict)
 		default:
-			incident["status"] = "in_progress"
+			h.log.WithError(err).Error("Failed to record approval decision")
+			http.Error(w, "Failed to record approval decision: "+err.Error(), http.StatusInternalServerError)
 		}
-
-		incidents = append(incidents, incident)
+		return
 	}
 
-	response := map[string]interface{}{
-		"incidents": incidents,
-		"total":     len(incidents),
+	response := ApprovalDecisionResponse{
+		WorkflowID:    workflow.ID,
+		Status:        string(workflow.Status),
+		Approval:      workflow.Approval,
+		QueuePosition: workflow.QueuePosition,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	if err := json.NewEncoder(w).Encode(response); err != nil {
-		h.log.WithError(err).Error("Failed to encode incidents response")
+		h.log.WithError(err).Error("Failed to encode approval response")
+	}
+}
+
+// CancelWorkflowResponse represents the response for cancelling a workflow
+type CancelWorkflowResponse struct {
+	WorkflowID string `json:"workflow_id"`
+	Status     string `json:"status"`
+}
+
+// CancelWorkflow handles DELETE /api/v1/workflows/{id}
+func (h *RemediationHandler) CancelWorkflow(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	workflowID := vars["id"]
+
+	h.log.WithField("workflow_id", workflowID).Info("Cancelling workflow")
+
+	if err := h.orchestrator.CancelWorkflow(workflowID); err != nil {
+		switch {
+		case errors.Is(err, remediation.ErrWorkflowNotFound):
+			http.Error(w, "Workflow not found", http.StatusNotFound)
+		case errors.Is(err, remediation.ErrWorkflowNotActive):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			h.log.WithError(err).Error("Failed to cancel workflow")
+			http.Error(w, "Failed to cancel workflow: "+err.Error(), http.StatusInternalServerError)
+		}
+		return
 	}
 
-	h.log.WithField("count", len(incidents)).Info("Incidents listed successfully")
+	response := CancelWorkflowResponse{
+		WorkflowID: workflowID,
+		Status:     "cancellation_requested",
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.log.WithError(err).Error("Failed to encode cancel response")
+	}
 }
@@ -0,0 +1,80 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/detector"
+	"github.com/tosin2013/openshift-coordination-engine/internal/remediation"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+func TestTriggerRemediation_DryRunReportsSelection(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	clientset := fake.NewSimpleClientset(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "payment-abc", Namespace: "default"},
+	})
+
+	selector := remediation.NewStrategySelector(log)
+	selector.SetFallbackRemediator(remediation.NewManualRemediator(clientset, log))
+	selector.SetMinConfidence(0.7)
+	handler := NewRemediationHandler(remediation.NewOrchestrator(detector.NewDetector(clientset, log), selector, log), log)
+
+	body := `{"incident_id":"inc-1","namespace":"default","resource":{"kind":"Pod","name":"payment-abc"},"issue":{"type":"CrashLoopBackOff"},"dry_run":true}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/remediation/trigger", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	handler.TriggerRemediation(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var response DryRunResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+	assert.True(t, response.DryRun)
+	assert.Equal(t, "manual", response.Remediator)
+
+	// Detection confidence is below the threshold, so the plan is only recommended
+	require.NotEmpty(t, response.Selection)
+	last := response.Selection[len(response.Selection)-1]
+	assert.Equal(t, "manual", last.Remediator)
+	assert.Equal(t, models.SelectionRecommendOnly, last.Decision)
+	assert.Contains(t, last.Reason, "0.70")
+
+	// No gate is configured, so all of them allow the remediation
+	require.Len(t, response.Gates, 3)
+	for _, gate := range response.Gates {
+		assert.True(t, gate.Allowed, gate.Gate)
+	}
+}
+
+func TestTriggerRemediation_RejectsUnboundedRetry(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	clientset := fake.NewSimpleClientset()
+	selector := remediation.NewStrategySelector(log)
+	handler := NewRemediationHandler(remediation.NewOrchestrator(detector.NewDetector(clientset, log), selector, log), log)
+
+	for name, retry := range map[string]string{
+		"too many attempts": `{"max_attempts":1000}`,
+		"zero backoff":      `{"max_attempts":5,"initial_backoff":"0s"}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			body := `{"incident_id":"inc-1","namespace":"default","resource":{"kind":"Pod","name":"payment-abc"},"issue":{"type":"CrashLoopBackOff"},"retry":` + retry + `}`
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/remediation/trigger", strings.NewReader(body))
+			rec := httptest.NewRecorder()
+			handler.TriggerRemediation(rec, req)
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			assert.Contains(t, rec.Body.String(), "invalid retry")
+		})
+	}
+}
@@ -4,9 +4,14 @@ package config
 import (
 	"fmt"
 	"os"
+	"path"
 	"strconv"
 	"strings"
 	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
 )
 
 // Config holds all application configuration
@@ -34,6 +39,82 @@ type Config struct {
 	// Performance tuning
 	KubernetesQPS   float32 `json:"kubernetes_qps"`
 	KubernetesBurst int     `json:"kubernetes_burst"`
+
+	// Workflow persistence ("memory" or "configmap")
+	WorkflowStore string `json:"workflow_store"`
+
+	// Window in which repeat triggers for the same resource reuse the active workflow
+	RemediationDedupWindow time.Duration `json:"remediation_dedup_window"`
+
+	// Mirror per-resource remediation locks as annotations on the target object
+	LockLeaseAnnotations bool `json:"lock_lease_annotations"`
+
+	// Workflow execution queue: worker count, global and per-namespace concurrency limits
+	QueueWorkers         int `json:"queue_workers"`
+	QueueMaxConcurrent   int `json:"queue_max_concurrent"`
+	QueueMaxPerNamespace int `json:"queue_max_per_namespace"`
+
+	// Approval gate: workflows matching any rule wait for a human decision
+	ApprovalSeverities  []string      `json:"approval_severities,omitempty"`
+	ApprovalNamespaces  []string      `json:"approval_namespaces,omitempty"`
+	ApprovalRemediators []string      `json:"approval_remediators,omitempty"`
+	ApprovalLayers      []string      `json:"approval_layers,omitempty"`
+	ApprovalExpiry      time.Duration `json:"approval_expiry"`
+
+	// Outbound webhooks: definitions file (empty disables notifications) and delivery settings
+	WebhookConfigFile   string        `json:"webhook_config_file,omitempty"`
+	WebhookMaxRetries   int           `json:"webhook_max_retries"`
+	WebhookRetryBackoff time.Duration `json:"webhook_retry_backoff"`
+	WebhookTimeout      time.Duration `json:"webhook_timeout"`
+
+	// How long a remediated resource may take to become healthy (zero disables verification)
+	VerificationTimeout time.Duration `json:"verification_timeout"`
+
+	// Circuit breaker: failed or ineffective remediations of one resource and issue type
+	// within the window that open it (zero disables), and how long it stays open
+	BreakerThreshold int           `json:"breaker_threshold"`
+	BreakerWindow    time.Duration `json:"breaker_window"`
+	BreakerCooldown  time.Duration `json:"breaker_cooldown"`
+
+	// Maintenance and blackout window definitions (empty means no windows; the
+	// global freeze is always available)
+	MaintenanceConfigFile string `json:"maintenance_config_file,omitempty"`
+
+	// Namespaces remediation may touch: when the allow list is set only matching
+	// namespaces are remediated, and deny list matches never are (names or globs)
+	NamespaceAllowList []string `json:"namespace_allow_list,omitempty"`
+	NamespaceDenyList  []string `json:"namespace_deny_list,omitempty"`
+
+	// Remediation policy mapping issues to actions, from a file or a ConfigMap
+	// ("name" in the engine namespace or "namespace/name", key policy.yaml), reloaded
+	// on change. Neither set uses the built-in policy.
+	PolicyConfigFile     string        `json:"policy_config_file,omitempty"`
+	PolicyConfigMap      string        `json:"policy_configmap,omitempty"`
+	PolicyReloadInterval time.Duration `json:"policy_reload_interval"`
+
+	// OOM remediation: percentage a container's memory is raised by per remediation,
+	// and the limit it is never raised above (LimitRanges and quotas also apply)
+	OOMMemoryStepPercent int    `json:"oom_memory_step_percent"`
+	OOMMemoryCeiling     string `json:"oom_memory_ceiling"`
+
+	// External HTTP remediation plugins: definitions file (empty disables plugins)
+	// and how often their health endpoints are checked
+	PluginConfigFile     string        `json:"plugin_config_file,omitempty"`
+	PluginHealthInterval time.Duration `json:"plugin_health_interval"`
+
+	// Remediator selection: priorities as name=priority (higher is tried first), and
+	// the detection confidence below which remediation only recommends (zero disables)
+	RemediatorPriorities []string `json:"remediator_priorities,omitempty"`
+	MinConfidence        float64  `json:"min_confidence"`
+
+	// Default retry policy of remediations failing with retryable errors: attempts
+	// including the first, exponential backoff with jitter, and the error reasons
+	// retried (empty retries any). The remediation policy and requests override it.
+	RetryMaxAttempts    int           `json:"retry_max_attempts"`
+	RetryInitialBackoff time.Duration `json:"retry_initial_backoff"`
+	RetryMaxBackoff     time.Duration `json:"retry_max_backoff"`
+	RetryJitter         float64       `json:"retry_jitter"`
+	RetryOn             []string      `json:"retry_on,omitempty"`
 }
 
 // Default configuration values
@@ -47,8 +128,41 @@ const (
 	DefaultKubernetesQPS   = 50.0
 	DefaultKubernetesBurst = 100
 	DefaultEnableCORS      = false
+	DefaultWorkflowStore   = "memory"
+	DefaultDedupWindow     = 10 * time.Minute
+	DefaultQueueWorkers    = 10
+	DefaultQueueMaxPerNS   = 3
+	DefaultApprovalExpiry  = time.Hour
+	DefaultWebhookRetries  = 3
+	DefaultWebhookBackoff  = 2 * time.Second
+	DefaultWebhookTimeout  = 10 * time.Second
+	DefaultVerifyTimeout   = 5 * time.Minute
+	DefaultBreakerLimit    = 3
+	DefaultBreakerWindow   = time.Hour
+	DefaultBreakerCooldown = 30 * time.Minute
+	DefaultPolicyReload    = 30 * time.Second
+	DefaultOOMMemoryStep   = 50
+	DefaultOOMMemoryLimit  = "4Gi"
+	DefaultPluginHealth    = 30 * time.Second
+	DefaultRetryAttempts   = 3
+	DefaultRetryBackoff    = 2 * time.Second
+	DefaultRetryMaxBackoff = 30 * time.Second
+	DefaultRetryJitter     = 0.2
 )
 
+// Valid layers for approval rules
+var validApprovalLayers = map[string]bool{
+	"infrastructure": true,
+	"platform":       true,
+	"application":    true,
+}
+
+// Valid workflow store backends
+var validWorkflowStores = map[string]bool{
+	"memory":    true,
+	"configmap": true,
+}
+
 // Valid log levels
 var validLogLevels = map[string]bool{
 	"debug": true,
@@ -74,6 +188,55 @@ func Load() (*Config, error) {
 		CORSAllowOrigin: getEnvAsSlice("CORS_ALLOW_ORIGIN", []string{"*"}),
 		KubernetesQPS:   getEnvAsFloat32("KUBERNETES_QPS", DefaultKubernetesQPS),
 		KubernetesBurst: getEnvAsInt("KUBERNETES_BURST", DefaultKubernetesBurst),
+		WorkflowStore:   getEnv("WORKFLOW_STORE", DefaultWorkflowStore),
+
+		RemediationDedupWindow: getEnvAsDuration("REMEDIATION_DEDUP_WINDOW", DefaultDedupWindow),
+		LockLeaseAnnotations:   getEnvAsBool("LOCK_LEASE_ANNOTATIONS", false),
+
+		QueueWorkers:         getEnvAsInt("QUEUE_WORKERS", DefaultQueueWorkers),
+		QueueMaxConcurrent:   getEnvAsInt("QUEUE_MAX_CONCURRENT", DefaultQueueWorkers),
+		QueueMaxPerNamespace: getEnvAsInt("QUEUE_MAX_PER_NAMESPACE", DefaultQueueMaxPerNS),
+
+		ApprovalSeverities:  getEnvAsSlice("APPROVAL_SEVERITIES", nil),
+		ApprovalNamespaces:  getEnvAsSlice("APPROVAL_NAMESPACES", nil),
+		ApprovalRemediators: getEnvAsSlice("APPROVAL_REMEDIATORS", nil),
+		ApprovalLayers:      getEnvAsSlice("APPROVAL_LAYERS", nil),
+		ApprovalExpiry:      getEnvAsDuration("APPROVAL_EXPIRY", DefaultApprovalExpiry),
+
+		WebhookConfigFile:   getEnv("WEBHOOK_CONFIG_FILE", ""),
+		WebhookMaxRetries:   getEnvAsInt("WEBHOOK_MAX_RETRIES", DefaultWebhookRetries),
+		WebhookRetryBackoff: getEnvAsDuration("WEBHOOK_RETRY_BACKOFF", DefaultWebhookBackoff),
+		WebhookTimeout:      getEnvAsDuration("WEBHOOK_TIMEOUT", DefaultWebhookTimeout),
+
+		VerificationTimeout: getEnvAsDuration("VERIFICATION_TIMEOUT", DefaultVerifyTimeout),
+
+		BreakerThreshold: getEnvAsInt("CIRCUIT_BREAKER_THRESHOLD", DefaultBreakerLimit),
+		BreakerWindow:    getEnvAsDuration("CIRCUIT_BREAKER_WINDOW", DefaultBreakerWindow),
+		BreakerCooldown:  getEnvAsDuration("CIRCUIT_BREAKER_COOLDOWN", DefaultBreakerCooldown),
+
+		MaintenanceConfigFile: getEnv("MAINTENANCE_CONFIG_FILE", ""),
+
+		NamespaceAllowList: getEnvAsSlice("REMEDIATION_NAMESPACE_ALLOWLIST", nil),
+		NamespaceDenyList:  getEnvAsSlice("REMEDIATION_NAMESPACE_DENYLIST", nil),
+
+		PolicyConfigFile:     getEnv("REMEDIATION_POLICY_FILE", ""),
+		PolicyConfigMap:      getEnv("REMEDIATION_POLICY_CONFIGMAP", ""),
+		PolicyReloadInterval: getEnvAsDuration("REMEDIATION_POLICY_RELOAD_INTERVAL", DefaultPolicyReload),
+
+		OOMMemoryStepPercent: getEnvAsInt("REMEDIATION_OOM_MEMORY_STEP_PERCENT", DefaultOOMMemoryStep),
+		OOMMemoryCeiling:     getEnv("REMEDIATION_OOM_MEMORY_CEILING", DefaultOOMMemoryLimit),
+
+		PluginConfigFile:     getEnv("REMEDIATION_PLUGINS_FILE", ""),
+		PluginHealthInterval: getEnvAsDuration("REMEDIATION_PLUGIN_HEALTH_INTERVAL", DefaultPluginHealth),
+
+		RemediatorPriorities: getEnvAsSlice("REMEDIATOR_PRIORITIES", nil),
+		MinConfidence:        getEnvAsFloat64("REMEDIATION_MIN_CONFIDENCE", 0),
+
+		RetryMaxAttempts:    getEnvAsInt("REMEDIATION_RETRY_MAX_ATTEMPTS", DefaultRetryAttempts),
+		RetryInitialBackoff: getEnvAsDuration("REMEDIATION_RETRY_INITIAL_BACKOFF", DefaultRetryBackoff),
+		RetryMaxBackoff:     getEnvAsDuration("REMEDIATION_RETRY_MAX_BACKOFF", DefaultRetryMaxBackoff),
+		RetryJitter:         getEnvAsFloat64("REMEDIATION_RETRY_JITTER", DefaultRetryJitter),
+		RetryOn:             getEnvAsSlice("REMEDIATION_RETRY_ON", nil),
 	}
 
 	// Validate configuration
@@ -142,6 +305,110 @@ func (c *Config) Validate() error {
 		errors = append(errors, fmt.Sprintf("kubernetes_burst must be positive: %d", c.KubernetesBurst))
 	}
 
+	// Validate workflow store backend (empty means in-memory)
+	if c.WorkflowStore != "" && !validWorkflowStores[c.WorkflowStore] {
+		errors = append(errors, fmt.Sprintf("invalid workflow_store: %s (must be memory or configmap)", c.WorkflowStore))
+	}
+
+	// Validate dedup window (zero disables resource deduplication)
+	if c.RemediationDedupWindow < 0 {
+		errors = append(errors, fmt.Sprintf("remediation_dedup_window cannot be negative: %s", c.RemediationDedupWindow))
+	}
+
+	// Validate queue limits (zero uses the queue defaults; per-namespace zero means unlimited)
+	if c.QueueWorkers < 0 {
+		errors = append(errors, fmt.Sprintf("queue_workers cannot be negative: %d", c.QueueWorkers))
+	}
+	if c.QueueMaxConcurrent < 0 {
+		errors = append(errors, fmt.Sprintf("queue_max_concurrent cannot be negative: %d", c.QueueMaxConcurrent))
+	}
+	if c.QueueMaxPerNamespace < 0 {
+		errors = append(errors, fmt.Sprintf("queue_max_per_namespace cannot be negative: %d", c.QueueMaxPerNamespace))
+	}
+
+	// Validate approval gate
+	if c.ApprovalExpiry < 0 {
+		errors = append(errors, fmt.Sprintf("approval_expiry cannot be negative: %s", c.ApprovalExpiry))
+	}
+	for _, layer := range c.ApprovalLayers {
+		if !validApprovalLayers[strings.ToLower(layer)] {
+			errors = append(errors, fmt.Sprintf("invalid approval layer: %s (must be infrastructure, platform, or application)", layer))
+		}
+	}
+
+	// Validate webhook delivery (zero backoff and timeout use the notifier defaults)
+	if c.WebhookMaxRetries < 0 {
+		errors = append(errors, fmt.Sprintf("webhook_max_retries cannot be negative: %d", c.WebhookMaxRetries))
+	}
+	if c.WebhookRetryBackoff < 0 {
+		errors = append(errors, fmt.Sprintf("webhook_retry_backoff cannot be negative: %s", c.WebhookRetryBackoff))
+	}
+	if c.WebhookTimeout < 0 {
+		errors = append(errors, fmt.Sprintf("webhook_timeout cannot be negative: %s", c.WebhookTimeout))
+	}
+
+	// Validate post-remediation verification (zero disables it)
+	if c.VerificationTimeout < 0 {
+		errors = append(errors, fmt.Sprintf("verification_timeout cannot be negative: %s", c.VerificationTimeout))
+	}
+
+	// Validate circuit breaker (zero window and cooldown use the breaker defaults)
+	if c.BreakerThreshold < 0 {
+		errors = append(errors, fmt.Sprintf("breaker_threshold cannot be negative: %d", c.BreakerThreshold))
+	}
+	if c.BreakerWindow < 0 {
+		errors = append(errors, fmt.Sprintf("breaker_window cannot be negative: %s", c.BreakerWindow))
+	}
+	if c.BreakerCooldown < 0 {
+		errors = append(errors, fmt.Sprintf("breaker_cooldown cannot be negative: %s", c.BreakerCooldown))
+	}
+
+	// Validate remediation policy source
+	if c.PolicyConfigFile != "" && c.PolicyConfigMap != "" {
+		errors = append(errors, "policy_config_file and policy_configmap are mutually exclusive")
+	}
+	if c.PolicyReloadInterval < 0 {
+		errors = append(errors, fmt.Sprintf("policy_reload_interval cannot be negative: %s", c.PolicyReloadInterval))
+	}
+
+	// Validate OOM memory sizing (zero values use the defaults)
+	if c.OOMMemoryStepPercent < 0 || c.OOMMemoryStepPercent > 1000 {
+		errors = append(errors, fmt.Sprintf("oom_memory_step_percent must be between 0 and 1000, got %d", c.OOMMemoryStepPercent))
+	}
+	if c.OOMMemoryCeiling != "" {
+		if ceiling, err := resource.ParseQuantity(c.OOMMemoryCeiling); err != nil || ceiling.Sign() <= 0 {
+			errors = append(errors, fmt.Sprintf("invalid oom_memory_ceiling: %s", c.OOMMemoryCeiling))
+		}
+	}
+
+	// Validate plugin health checks (zero uses the default)
+	if c.PluginHealthInterval < 0 {
+		errors = append(errors, fmt.Sprintf("plugin_health_interval cannot be negative: %s", c.PluginHealthInterval))
+	}
+
+	// Validate remediator selection
+	for _, entry := range c.RemediatorPriorities {
+		if _, _, err := parsePriority(entry); err != nil {
+			errors = append(errors, err.Error())
+		}
+	}
+	if c.MinConfidence < 0 || c.MinConfidence > 1 {
+		errors = append(errors, fmt.Sprintf("min_confidence must be between 0 and 1, got %g", c.MinConfidence))
+	}
+
+	// Validate the default retry policy
+	retry := c.RetryPolicy()
+	if err := retry.Validate(); err != nil {
+		errors = append(errors, fmt.Sprintf("invalid retry policy: %v", err))
+	}
+
+	// Validate namespace allow and deny list globs
+	for _, pattern := range append(append([]string{}, c.NamespaceAllowList...), c.NamespaceDenyList...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			errors = append(errors, fmt.Sprintf("invalid namespace pattern: %s", pattern))
+		}
+	}
+
 	if len(errors) > 0 {
 		return fmt.Errorf("configuration validation failed:\n  - %s", strings.Join(errors, "\n  - "))
 	}
@@ -149,6 +416,39 @@ func (c *Config) Validate() error {
 	return nil
 }
 
+// RemediatorPriorityMap returns the configured remediator priorities by name
+func (c *Config) RemediatorPriorityMap() map[string]int {
+	priorities := make(map[string]int, len(c.RemediatorPriorities))
+	for _, entry := range c.RemediatorPriorities {
+		if name, priority, err := parsePriority(entry); err == nil {
+			priorities[name] = priority
+		}
+	}
+	return priorities
+}
+
+// RetryPolicy returns the configured default retry policy of remediations
+func (c *Config) RetryPolicy() models.RetryPolicy {
+	return models.RetryPolicy{
+		MaxAttempts:    c.RetryMaxAttempts,
+		InitialBackoff: c.RetryInitialBackoff.String(),
+		MaxBackoff:     c.RetryMaxBackoff.String(),
+		Jitter:         c.RetryJitter,
+		RetryOn:        c.RetryOn,
+	}
+}
+
+// parsePriority parses a name=priority remediator priority
+func parsePriority(entry string) (string, int, error) {
+	name, value, found := strings.Cut(entry, "=")
+	name = strings.TrimSpace(name)
+	priority, err := strconv.Atoi(strings.TrimSpace(value))
+	if !found || name == "" || err != nil {
+		return "", 0, fmt.Errorf("invalid remediator priority %q (must be name=integer)", entry)
+	}
+	return name, priority, nil
+}
+
 // getEnv gets an environment variable or returns a default value
 func getEnv(key, defaultVal string) string {
 	if value := os.Getenv(key); value != "" {
@@ -183,6 +483,19 @@ func getEnvAsFloat32(key string, defaultVal float32) float32 {
 	return float32(value)
 }
 
+// getEnvAsFloat64 gets an environment variable as a float64 or returns a default value
+func getEnvAsFloat64(key string, defaultVal float64) float64 {
+	valueStr := os.Getenv(key)
+	if valueStr == "" {
+		return defaultVal
+	}
+	value, err := strconv.ParseFloat(valueStr, 64)
+	if err != nil {
+		return defaultVal
+	}
+	return value
+}
+
 // getEnvAsBool gets an environment variable as a boolean or returns a default value
 func getEnvAsBool(key string, defaultVal bool) bool {
 	valueStr := os.Getenv(key)
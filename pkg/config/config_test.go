@@ -28,6 +28,15 @@ func TestLoad_Defaults(t *testing.T) {
 	assert.Equal(t, DefaultKubernetesBurst, cfg.KubernetesBurst)
 	assert.Equal(t, DefaultEnableCORS, cfg.EnableCORS)
 	assert.Equal(t, []string{"*"}, cfg.CORSAllowOrigin)
+	assert.Equal(t, DefaultWorkflowStore, cfg.WorkflowStore)
+	assert.Equal(t, DefaultDedupWindow, cfg.RemediationDedupWindow)
+	assert.Equal(t, DefaultQueueWorkers, cfg.QueueWorkers)
+	assert.Equal(t, DefaultQueueWorkers, cfg.QueueMaxConcurrent)
+	assert.Equal(t, DefaultQueueMaxPerNS, cfg.QueueMaxPerNamespace)
+	assert.Empty(t, cfg.ApprovalSeverities)
+	assert.Equal(t, DefaultApprovalExpiry, cfg.ApprovalExpiry)
+	assert.Empty(t, cfg.WebhookConfigFile)
+	assert.Equal(t, DefaultWebhookRetries, cfg.WebhookMaxRetries)
 }
 
 func TestLoad_FromEnvironment(t *testing.T) {
@@ -292,6 +301,124 @@ func TestValidate_InvalidKubernetesSettings(t *testing.T) {
 	}
 }
 
+func TestValidate_InvalidWorkflowStore(t *testing.T) {
+	cfg := &Config{
+		Port:            8080,
+		MetricsPort:     9090,
+		LogLevel:        "info",
+		Namespace:       "default",
+		MLServiceURL:    "http://ml-service:8080",
+		HTTPTimeout:     30 * time.Second,
+		KubernetesQPS:   50.0,
+		KubernetesBurst: 100,
+		WorkflowStore:   "etcd",
+	}
+
+	err := cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid workflow_store")
+
+	cfg.WorkflowStore = "configmap"
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestValidate_InvalidNamespacePattern(t *testing.T) {
+	cfg := &Config{
+		Port:               8080,
+		MetricsPort:        9090,
+		LogLevel:           "info",
+		Namespace:          "default",
+		MLServiceURL:       "http://ml-service:8080",
+		HTTPTimeout:        30 * time.Second,
+		KubernetesQPS:      50.0,
+		KubernetesBurst:    100,
+		NamespaceAllowList: []string{"team-*"},
+		NamespaceDenyList:  []string{"kube-[system"},
+	}
+
+	err := cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid namespace pattern: kube-[system")
+
+	cfg.NamespaceDenyList = []string{"kube-*", "openshift-*"}
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestValidate_InvalidOOMMemory(t *testing.T) {
+	cfg := &Config{
+		Port:                 8080,
+		MetricsPort:          9090,
+		LogLevel:             "info",
+		Namespace:            "default",
+		MLServiceURL:         "http://ml-service:8080",
+		HTTPTimeout:          30 * time.Second,
+		KubernetesQPS:        50.0,
+		KubernetesBurst:      100,
+		OOMMemoryStepPercent: -10,
+		OOMMemoryCeiling:     "lots",
+	}
+
+	err := cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "oom_memory_step_percent must be between 0 and 1000")
+	assert.Contains(t, err.Error(), "invalid oom_memory_ceiling: lots")
+
+	cfg.OOMMemoryStepPercent = 25
+	cfg.OOMMemoryCeiling = "8Gi"
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestValidate_RemediatorSelection(t *testing.T) {
+	cfg := &Config{
+		Port:                 8080,
+		MetricsPort:          9090,
+		LogLevel:             "info",
+		Namespace:            "default",
+		MLServiceURL:         "http://ml-service:8080",
+		HTTPTimeout:          30 * time.Second,
+		KubernetesQPS:        50.0,
+		KubernetesBurst:      100,
+		RemediatorPriorities: []string{"argocd=100", "helm"},
+		MinConfidence:        1.5,
+	}
+
+	err := cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid remediator priority "helm"`)
+	assert.Contains(t, err.Error(), "min_confidence must be between 0 and 1")
+
+	cfg.RemediatorPriorities = []string{"argocd=100", "helm = -5"}
+	cfg.MinConfidence = 0.6
+	assert.NoError(t, cfg.Validate())
+	assert.Equal(t, map[string]int{"argocd": 100, "helm": -5}, cfg.RemediatorPriorityMap())
+}
+
+func TestValidate_RetryPolicy(t *testing.T) {
+	cfg := &Config{
+		Port:                8080,
+		MetricsPort:         9090,
+		LogLevel:            "info",
+		Namespace:           "default",
+		MLServiceURL:        "http://ml-service:8080",
+		HTTPTimeout:         30 * time.Second,
+		KubernetesQPS:       50.0,
+		KubernetesBurst:     100,
+		RetryMaxAttempts:    3,
+		RetryInitialBackoff: -time.Second,
+		RetryJitter:         0.2,
+		RetryOn:             []string{"conflict", "gremlins"},
+	}
+
+	err := cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid retry policy")
+
+	cfg.RetryInitialBackoff = time.Second
+	cfg.RetryOn = []string{"conflict", "lock_contention", "server_error"}
+	assert.NoError(t, cfg.Validate())
+	assert.Equal(t, "1s", cfg.RetryPolicy().InitialBackoff)
+}
+
 func TestGetEnvAsSlice(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -402,7 +529,22 @@ func clearEnv(t *testing.T) {
 		"PORT", "METRICS_PORT", "LOG_LEVEL", "KUBECONFIG", "NAMESPACE",
 		"ML_SERVICE_URL", "ARGOCD_API_URL", "HTTP_TIMEOUT",
 		"ENABLE_CORS", "CORS_ALLOW_ORIGIN",
-		"KUBERNETES_QPS", "KUBERNETES_BURST",
+		"KUBERNETES_QPS", "KUBERNETES_BURST", "WORKFLOW_STORE",
+		"REMEDIATION_DEDUP_WINDOW", "LOCK_LEASE_ANNOTATIONS",
+		"QUEUE_WORKERS", "QUEUE_MAX_CONCURRENT", "QUEUE_MAX_PER_NAMESPACE",
+		"APPROVAL_SEVERITIES", "APPROVAL_NAMESPACES", "APPROVAL_REMEDIATORS",
+		"APPROVAL_LAYERS", "APPROVAL_EXPIRY",
+		"WEBHOOK_CONFIG_FILE", "WEBHOOK_MAX_RETRIES", "WEBHOOK_RETRY_BACKOFF", "WEBHOOK_TIMEOUT",
+		"VERIFICATION_TIMEOUT",
+		"CIRCUIT_BREAKER_THRESHOLD", "CIRCUIT_BREAKER_WINDOW", "CIRCUIT_BREAKER_COOLDOWN",
+		"MAINTENANCE_CONFIG_FILE",
+		"REMEDIATION_NAMESPACE_ALLOWLIST", "REMEDIATION_NAMESPACE_DENYLIST",
+		"REMEDIATION_POLICY_FILE", "REMEDIATION_POLICY_CONFIGMAP", "REMEDIATION_POLICY_RELOAD_INTERVAL",
+		"REMEDIATION_OOM_MEMORY_STEP_PERCENT", "REMEDIATION_OOM_MEMORY_CEILING",
+		"REMEDIATION_PLUGINS_FILE", "REMEDIATION_PLUGIN_HEALTH_INTERVAL",
+		"REMEDIATOR_PRIORITIES", "REMEDIATION_MIN_CONFIDENCE",
+		"REMEDIATION_RETRY_MAX_ATTEMPTS", "REMEDIATION_RETRY_INITIAL_BACKOFF", "REMEDIATION_RETRY_MAX_BACKOFF",
+		"REMEDIATION_RETRY_JITTER", "REMEDIATION_RETRY_ON",
 	}
 	for _, key := range envVars {
 		os.Unsetenv(key)
@@ -0,0 +1,58 @@
+package models
+
+import "time"
+
+// Approval decisions
+const (
+	ApprovalDecisionApproved = "approved"
+	ApprovalDecisionRejected = "rejected"
+	ApprovalDecisionExpired  = "expired"
+)
+
+// Approval records the human sign-off required before a high-risk remediation runs
+type Approval struct {
+	Reason      string     `json:"reason"` // policy rule that required approval
+	RequestedAt time.Time  `json:"requested_at"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+	Decision    string     `json:"decision,omitempty"` // approved, rejected, expired
+	DecidedBy   string     `json:"decided_by,omitempty"`
+	DecidedAt   *time.Time `json:"decided_at,omitempty"`
+	Comment     string     `json:"comment,omitempty"`
+}
+
+// NewApproval creates a pending approval that expires after ttl
+func NewApproval(reason string, ttl time.Duration) *Approval {
+	now := time.Now()
+	return &Approval{
+		Reason:      reason,
+		RequestedAt: now,
+		ExpiresAt:   now.Add(ttl),
+	}
+}
+
+// IsPending returns true if no decision has been recorded yet
+func (a *Approval) IsPending() bool {
+	return a.Decision == ""
+}
+
+// Decide records the decision, who made it and when
+func (a *Approval) Decide(decision, decidedBy, comment string) {
+	now := time.Now()
+	a.Decision = decision
+	a.DecidedBy = decidedBy
+	a.DecidedAt = &now
+	a.Comment = comment
+}
+
+// Clone returns a deep copy of the approval
+func (a *Approval) Clone() *Approval {
+	if a == nil {
+		return nil
+	}
+	clone := *a
+	if a.DecidedAt != nil {
+		decidedAt := *a.DecidedAt
+		clone.DecidedAt = &decidedAt
+	}
+	return &clone
+}
@@ -0,0 +1,136 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// IncidentStatus represents the lifecycle state of an incident
+type IncidentStatus string
+
+// Incident status constants: open → remediating → resolved/failed/escalated
+const (
+	IncidentStatusOpen        IncidentStatus = "open"
+	IncidentStatusRemediating IncidentStatus = "remediating"
+	IncidentStatusResolved    IncidentStatus = "resolved"
+	IncidentStatusFailed      IncidentStatus = "failed"
+	IncidentStatusEscalated   IncidentStatus = "escalated" // needs a human to act
+)
+
+// incidentTransitions lists the statuses each status may move to. A new remediation
+// attempt may start from any state, including after a resolved incident regresses.
+var incidentTransitions = map[IncidentStatus][]IncidentStatus{
+	IncidentStatusOpen:        {IncidentStatusRemediating, IncidentStatusResolved, IncidentStatusEscalated},
+	IncidentStatusRemediating: {IncidentStatusOpen, IncidentStatusResolved, IncidentStatusFailed, IncidentStatusEscalated},
+	IncidentStatusFailed:      {IncidentStatusRemediating, IncidentStatusResolved, IncidentStatusEscalated},
+	IncidentStatusEscalated:   {IncidentStatusRemediating, IncidentStatusResolved},
+	IncidentStatusResolved:    {IncidentStatusRemediating},
+}
+
+// Incident is a detected problem and the remediation attempts made for it
+type Incident struct {
+	ID                      string         `json:"id"`
+	Source                  string         `json:"source"` // e.g. "api", "coordination", "alertmanager"
+	Severity                string         `json:"severity"`
+	Namespace               string         `json:"namespace"`
+	IssueType               string         `json:"issue_type,omitempty"`
+	Description             string         `json:"description,omitempty"`
+	AffectedResources       []Resource     `json:"affected_resources"`
+	Status                  IncidentStatus `json:"status"`
+	DetectedAt              time.Time      `json:"detected_at"`
+	ResolvedAt              *time.Time     `json:"resolved_at,omitempty"`
+	UpdatedAt               time.Time      `json:"updated_at"`
+	WorkflowIDs             []string       `json:"workflow_ids,omitempty"`
+	CoordinationWorkflowIDs []string       `json:"coordination_workflow_ids,omitempty"`
+}
+
+// CanTransitionTo returns true if the lifecycle allows moving to status
+func (i *Incident) CanTransitionTo(status IncidentStatus) bool {
+	if i.Status == status {
+		return true
+	}
+	for _, allowed := range incidentTransitions[i.Status] {
+		if allowed == status {
+			return true
+		}
+	}
+	return false
+}
+
+// TransitionTo moves the incident to status, recording the resolution time
+func (i *Incident) TransitionTo(status IncidentStatus) error {
+	if !i.CanTransitionTo(status) {
+		return fmt.Errorf("invalid incident transition from %s to %s", i.Status, status)
+	}
+
+	now := time.Now()
+	i.Status = status
+	i.UpdatedAt = now
+	if status == IncidentStatusResolved {
+		i.ResolvedAt = &now
+	} else {
+		i.ResolvedAt = nil
+	}
+	return nil
+}
+
+// IsClosed returns true if no remediation is open or running for the incident
+func (i *Incident) IsClosed() bool {
+	return i.Status == IncidentStatusResolved || i.Status == IncidentStatusFailed || i.Status == IncidentStatusEscalated
+}
+
+// AffectsNamespace returns true if the incident or any affected resource is in namespace
+func (i *Incident) AffectsNamespace(namespace string) bool {
+	if i.Namespace == namespace {
+		return true
+	}
+	for _, r := range i.AffectedResources {
+		if r.Namespace == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// AddResource adds a resource if it is not already listed
+func (i *Incident) AddResource(resource Resource) {
+	for _, r := range i.AffectedResources {
+		if r.Kind == resource.Kind && r.Namespace == resource.Namespace && r.Name == resource.Name {
+			return
+		}
+	}
+	i.AffectedResources = append(i.AffectedResources, resource)
+}
+
+// LinkWorkflow records a remediation workflow for the incident
+func (i *Incident) LinkWorkflow(workflowID string) {
+	i.WorkflowIDs = appendUnique(i.WorkflowIDs, workflowID)
+}
+
+// LinkCoordinationWorkflow records a multi-layer coordination workflow for the incident
+func (i *Incident) LinkCoordinationWorkflow(workflowID string) {
+	i.CoordinationWorkflowIDs = appendUnique(i.CoordinationWorkflowIDs, workflowID)
+}
+
+// Clone returns a deep copy of the incident
+func (i *Incident) Clone() *Incident {
+	clone := *i
+	clone.AffectedResources = append([]Resource(nil), i.AffectedResources...)
+	clone.WorkflowIDs = append([]string(nil), i.WorkflowIDs...)
+	clone.CoordinationWorkflowIDs = append([]string(nil), i.CoordinationWorkflowIDs...)
+	if i.ResolvedAt != nil {
+		resolvedAt := *i.ResolvedAt
+		clone.ResolvedAt = &resolvedAt
+	}
+	return &clone
+}
+
+// appendUnique appends value unless values already contains it
+func appendUnique(values []string, value string) []string {
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
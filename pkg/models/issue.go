@@ -14,7 +14,11 @@ type Issue struct {
 	ResourceType string    `json:"resource_type"` // "pod", "deployment", "statefulset"
 	ResourceName string    `json:"resource_name"`
 	Description  string    `json:"description"`
+	Source       string    `json:"source,omitempty"` // where the issue was reported from, e.g. "api", "alertmanager"
 	DetectedAt   time.Time `json:"detected_at"`
+
+	RollbackRevision int64        `json:"rollback_revision,omitempty"` // revision a rollback targets; zero picks the last available
+	Retry            *RetryPolicy `json:"retry,omitempty"`             // overrides the configured retry policies for this issue
 }
 
 // Validate checks if the issue is valid
@@ -34,6 +38,11 @@ func (i *Issue) Validate() error {
 	if i.ResourceType == "" {
 		return fmt.Errorf("resource type is required")
 	}
+	if i.Retry != nil {
+		if err := i.Retry.Validate(); err != nil {
+			return fmt.Errorf("invalid retry policy: %w", err)
+		}
+	}
 	return nil
 }
 
@@ -47,7 +47,7 @@ type RemediationPlan struct {
 	Checkpoints   []HealthCheckpoint `json:"checkpoints"`
 	RollbackSteps []RemediationStep  `json:"rollback_steps,omitempty"`
 	CreatedAt     time.Time          `json:"created_at"`
-	Status        string             `json:"status"` // pending, executing, completed, failed, rolled_back
+	Status        string             `json:"status"` // pending, executing, completed, failed, cancelled, rolled_back
 	CurrentStep   int                `json:"current_step"`
 }
 
@@ -144,6 +144,11 @@ func (rp *RemediationPlan) MarkFailed() {
 	rp.Status = "failed"
 }
 
+// MarkCancelled marks the plan as cancelled by an operator
+func (rp *RemediationPlan) MarkCancelled() {
+	rp.Status = "cancelled"
+}
+
 // MarkRolledBack marks the plan as rolled back
 func (rp *RemediationPlan) MarkRolledBack() {
 	rp.Status = "rolled_back"
@@ -0,0 +1,117 @@
+package models
+
+import "time"
+
+// RemediationResultVersion is the version of the remediation result contract;
+// it changes when fields are removed or change meaning
+const RemediationResultVersion = "v1"
+
+// RemediationStatus tells apart what a remediation did
+type RemediationStatus string
+
+// Remediation status constants
+const (
+	RemediationStatusChanged     RemediationStatus = "changed"     // at least one resource was changed
+	RemediationStatusNoOp        RemediationStatus = "no_op"       // nothing needed changing
+	RemediationStatusRecommended RemediationStatus = "recommended" // changes were recommended rather than made
+	RemediationStatusFailed      RemediationStatus = "failed"
+)
+
+// ErrorClass tells whether retrying a failed remediation may succeed
+type ErrorClass string
+
+// Error class constants
+const (
+	ErrorClassRetryable ErrorClass = "retryable"
+	ErrorClassPermanent ErrorClass = "permanent"
+)
+
+// RemediationResult is the structured outcome of a remediation
+type RemediationResult struct {
+	Version         string            `json:"version"`
+	Status          RemediationStatus `json:"status"`
+	Method          string            `json:"method"` // remediator that produced the result
+	Message         string            `json:"message,omitempty"`
+	Actions         []PerformedAction `json:"actions,omitempty"`
+	Recommendations []string          `json:"recommendations,omitempty"`
+	Evidence        map[string]string `json:"evidence,omitempty"` // what the remediator observed, e.g. release status
+	Error           string            `json:"error,omitempty"`
+	ErrorClass      ErrorClass        `json:"error_class,omitempty"`
+	Duration        string            `json:"duration,omitempty"`
+}
+
+// PerformedAction is a change a remediator made, with the values it replaced
+type PerformedAction struct {
+	Action string `json:"action"` // e.g. "restart_deployment", "helm_rollback"
+	Target string `json:"target"`
+	Before string `json:"before,omitempty"`
+	After  string `json:"after,omitempty"`
+}
+
+// NewRemediationResult creates an empty result of the current version
+func NewRemediationResult(method string) *RemediationResult {
+	return &RemediationResult{
+		Version: RemediationResultVersion,
+		Status:  RemediationStatusNoOp,
+		Method:  method,
+	}
+}
+
+// AddAction records a change and marks the result changed
+func (r *RemediationResult) AddAction(action, target, before, after string) {
+	r.Actions = append(r.Actions, PerformedAction{Action: action, Target: target, Before: before, After: after})
+	if r.Status != RemediationStatusFailed {
+		r.Status = RemediationStatusChanged
+	}
+}
+
+// Recommend records manual changes; the result is only marked recommended if
+// nothing was changed
+func (r *RemediationResult) Recommend(recommendations ...string) {
+	r.Recommendations = append(r.Recommendations, recommendations...)
+	if r.Status == RemediationStatusNoOp {
+		r.Status = RemediationStatusRecommended
+	}
+}
+
+// AddEvidence records something the remediator observed
+func (r *RemediationResult) AddEvidence(key, value string) {
+	if value == "" {
+		return
+	}
+	if r.Evidence == nil {
+		r.Evidence = make(map[string]string)
+	}
+	r.Evidence[key] = value
+}
+
+// Fail marks the result failed with the error and its class
+func (r *RemediationResult) Fail(err error, class ErrorClass) {
+	r.Status = RemediationStatusFailed
+	r.Error = err.Error()
+	r.ErrorClass = class
+}
+
+// Clone returns a deep copy of the result, or nil
+func (r *RemediationResult) Clone() *RemediationResult {
+	if r == nil {
+		return nil
+	}
+	clone := *r
+	if r.Actions != nil {
+		clone.Actions = append([]PerformedAction{}, r.Actions...)
+	}
+	clone.Recommendations = cloneStrings(r.Recommendations)
+	if r.Evidence != nil {
+		clone.Evidence = make(map[string]string, len(r.Evidence))
+		for key, value := range r.Evidence {
+			clone.Evidence[key] = value
+		}
+	}
+	return &clone
+}
+
+// Finish records how long the remediation took
+func (r *RemediationResult) Finish(started time.Time) {
+	r.Duration = time.Since(started).Round(time.Millisecond).String()
+}
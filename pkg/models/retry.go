@@ -0,0 +1,128 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// Reasons a remediation failed with a retryable error, which retry policies retry on
+const (
+	ErrorReasonConflict       = "conflict"        // the object changed concurrently, e.g. on update
+	ErrorReasonLockContention = "lock_contention" // another operation holds the resource, e.g. a Helm release
+	ErrorReasonServerError    = "server_error"    // an API answered with a 5xx error
+	ErrorReasonRateLimited    = "rate_limited"    // an API answered with 429 Too Many Requests
+	ErrorReasonTimeout        = "timeout"         // a request or operation timed out
+	ErrorReasonOther          = "other"           // any other retryable error
+)
+
+// Limits of retry policies. Every attempt holds a worker and the resource lock and
+// adds a workflow step, so attempts are capped and request overrides cannot retry
+// without waiting.
+const (
+	MaxRetryAttempts        = 10
+	MinOverrideRetryBackoff = time.Second
+)
+
+// errorReasons are the reasons a retry policy may list in retry_on
+var errorReasons = map[string]bool{
+	ErrorReasonConflict:       true,
+	ErrorReasonLockContention: true,
+	ErrorReasonServerError:    true,
+	ErrorReasonRateLimited:    true,
+	ErrorReasonTimeout:        true,
+	ErrorReasonOther:          true,
+}
+
+// RetryPolicy controls how a remediation that failed with a retryable error is
+// retried. Unset fields inherit from the policy it overrides.
+type RetryPolicy struct {
+	MaxAttempts    int      `json:"max_attempts,omitempty"`    // including the first; 1 disables retries
+	InitialBackoff string   `json:"initial_backoff,omitempty"` // wait before the first retry, e.g. 2s
+	MaxBackoff     string   `json:"max_backoff,omitempty"`     // longest wait between attempts
+	Multiplier     float64  `json:"multiplier,omitempty"`      // backoff growth per retry, at least 1
+	Jitter         float64  `json:"jitter,omitempty"`          // fraction of each backoff that is randomized, 0 to 1
+	RetryOn        []string `json:"retry_on,omitempty"`        // error reasons retried; empty retries any retryable error
+}
+
+// Validate checks the limits, durations and error reasons of the policy
+func (p *RetryPolicy) Validate() error {
+	if p.MaxAttempts < 0 || p.MaxAttempts > MaxRetryAttempts {
+		return fmt.Errorf("max_attempts must be between 0 and %d", MaxRetryAttempts)
+	}
+	if err := validBackoff("initial_backoff", p.InitialBackoff); err != nil {
+		return err
+	}
+	if err := validBackoff("max_backoff", p.MaxBackoff); err != nil {
+		return err
+	}
+	if p.Multiplier != 0 && p.Multiplier < 1 {
+		return fmt.Errorf("multiplier must be at least 1")
+	}
+	if p.Jitter < 0 || p.Jitter > 1 {
+		return fmt.Errorf("jitter must be between 0 and 1")
+	}
+	for _, reason := range p.RetryOn {
+		if !errorReasons[reason] {
+			return fmt.Errorf("unknown retry_on reason %q", reason)
+		}
+	}
+	return nil
+}
+
+// ValidateOverride checks a policy overriding the configured ones for a single
+// request: on top of Validate, the backoffs it sets must be at least
+// MinOverrideRetryBackoff
+func (p *RetryPolicy) ValidateOverride() error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	if err := minBackoff("initial_backoff", p.InitialBackoff); err != nil {
+		return err
+	}
+	return minBackoff("max_backoff", p.MaxBackoff)
+}
+
+// minBackoff checks that an optional, valid backoff is at least MinOverrideRetryBackoff
+func minBackoff(field, value string) error {
+	if d, _ := time.ParseDuration(value); value != "" && d < MinOverrideRetryBackoff {
+		return fmt.Errorf("%s must be at least %s", field, MinOverrideRetryBackoff)
+	}
+	return nil
+}
+
+// validBackoff checks that an optional backoff is a non-negative duration
+func validBackoff(field, value string) error {
+	if value == "" {
+		return nil
+	}
+	if d, err := time.ParseDuration(value); err != nil || d < 0 {
+		return fmt.Errorf("%s must be a non-negative duration", field)
+	}
+	return nil
+}
+
+// Merge returns the policy with the fields set in override replacing its own
+func (p RetryPolicy) Merge(override *RetryPolicy) RetryPolicy {
+	if override == nil {
+		return p
+	}
+	if override.MaxAttempts > 0 {
+		p.MaxAttempts = override.MaxAttempts
+	}
+	if override.InitialBackoff != "" {
+		p.InitialBackoff = override.InitialBackoff
+	}
+	if override.MaxBackoff != "" {
+		p.MaxBackoff = override.MaxBackoff
+	}
+	if override.Multiplier > 0 {
+		p.Multiplier = override.Multiplier
+	}
+	if override.Jitter > 0 {
+		p.Jitter = override.Jitter
+	}
+	if len(override.RetryOn) > 0 {
+		p.RetryOn = override.RetryOn
+	}
+	return p
+}
@@ -0,0 +1,67 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryPolicy_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  RetryPolicy
+		wantErr string
+	}{
+		{name: "valid", policy: RetryPolicy{MaxAttempts: 5, InitialBackoff: "1s", MaxBackoff: "1m", Multiplier: 3, Jitter: 0.3,
+			RetryOn: []string{ErrorReasonConflict, ErrorReasonServerError}}},
+		{name: "empty", policy: RetryPolicy{}},
+		{name: "negative attempts", policy: RetryPolicy{MaxAttempts: -1}, wantErr: "max_attempts"},
+		{name: "too many attempts", policy: RetryPolicy{MaxAttempts: MaxRetryAttempts + 1}, wantErr: "max_attempts"},
+		{name: "invalid backoff", policy: RetryPolicy{MaxBackoff: "soon"}, wantErr: "max_backoff"},
+		{name: "shrinking backoff", policy: RetryPolicy{Multiplier: 0.5}, wantErr: "multiplier"},
+		{name: "jitter above 1", policy: RetryPolicy{Jitter: 1.5}, wantErr: "jitter"},
+		{name: "unknown reason", policy: RetryPolicy{RetryOn: []string{"gremlins"}}, wantErr: "gremlins"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRetryPolicy_ValidateOverride(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  RetryPolicy
+		wantErr string
+	}{
+		{name: "valid", policy: RetryPolicy{MaxAttempts: 3, InitialBackoff: "1s", MaxBackoff: "30s"}},
+		{name: "inherited backoff", policy: RetryPolicy{MaxAttempts: 3}},
+		{name: "too many attempts", policy: RetryPolicy{MaxAttempts: 1000}, wantErr: "max_attempts"},
+		{name: "zero backoff", policy: RetryPolicy{InitialBackoff: "0s"}, wantErr: "initial_backoff must be at least 1s"},
+		{name: "short max backoff", policy: RetryPolicy{MaxBackoff: "10ms"}, wantErr: "max_backoff must be at least 1s"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.ValidateOverride()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRetryPolicy_Merge(t *testing.T) {
+	base := RetryPolicy{MaxAttempts: 3, InitialBackoff: "2s", Jitter: 0.2}
+	assert.Equal(t, base, base.Merge(nil))
+
+	merged := base.Merge(&RetryPolicy{MaxAttempts: 5, RetryOn: []string{ErrorReasonLockContention}})
+	assert.Equal(t, RetryPolicy{MaxAttempts: 5, InitialBackoff: "2s", Jitter: 0.2, RetryOn: []string{ErrorReasonLockContention}}, merged)
+}
@@ -7,10 +7,30 @@ type WorkflowStatus string
 
 // Workflow status constants
 const (
-	WorkflowStatusPending   WorkflowStatus = "pending"
-	WorkflowStatusRunning   WorkflowStatus = "in_progress"
-	WorkflowStatusCompleted WorkflowStatus = "completed"
-	WorkflowStatusFailed    WorkflowStatus = "failed"
+	WorkflowStatusPending         WorkflowStatus = "pending"
+	WorkflowStatusQueued          WorkflowStatus = "queued"           // waiting for an execution worker
+	WorkflowStatusPendingApproval WorkflowStatus = "pending_approval" // held by the approval policy
+	WorkflowStatusRunning         WorkflowStatus = "in_progress"
+	WorkflowStatusCompleted       WorkflowStatus = "completed"
+	WorkflowStatusFailed          WorkflowStatus = "failed"
+	WorkflowStatusCancelled       WorkflowStatus = "cancelled"
+	WorkflowStatusRejected        WorkflowStatus = "rejected"
+	WorkflowStatusInterrupted     WorkflowStatus = "interrupted" // in flight when the engine stopped
+
+	// Remediation was applied but the resource did not become healthy in time
+	WorkflowStatusFailedVerification WorkflowStatus = "failed_verification"
+
+	// Refused by the circuit breaker and escalated for manual remediation
+	WorkflowStatusCircuitOpen WorkflowStatus = "circuit_open"
+
+	// Held by a maintenance window or freeze until automation may run
+	WorkflowStatusDeferred WorkflowStatus = "deferred"
+
+	// Downgraded by a maintenance window or freeze to recommendations only
+	WorkflowStatusRecommended WorkflowStatus = "recommended"
+
+	// Refused by namespace lists or opt-out annotations before anything was mutated
+	WorkflowStatusRefused WorkflowStatus = "refused"
 )
 
 // Workflow represents a remediation workflow execution
@@ -23,12 +43,57 @@ type Workflow struct {
 	ResourceName     string         `json:"resource_name"`
 	ResourceKind     string         `json:"resource_kind"`
 	IssueType        string         `json:"issue_type"`
+	Severity         string         `json:"severity,omitempty"`
 	Remediator       string         `json:"remediator,omitempty"`
 	ErrorMessage     string         `json:"error_message,omitempty"`
+	QueuePosition    int            `json:"queue_position,omitempty"`
+	Approval         *Approval      `json:"approval,omitempty"`
+	Recommendations  []string       `json:"recommendations,omitempty"` // manual steps when automation was refused
+	Deferral         *Deferral      `json:"deferral,omitempty"`
+	Rollback         *Rollback      `json:"rollback,omitempty"`
 	CreatedAt        time.Time      `json:"created_at"`
 	StartedAt        *time.Time     `json:"started_at,omitempty"`
 	CompletedAt      *time.Time     `json:"completed_at,omitempty"`
 	Steps            []WorkflowStep `json:"steps,omitempty"`
+
+	Selection []SelectionDecision `json:"selection,omitempty"` // how the remediator was chosen
+
+	Owner string `json:"owner,omitempty"` // engine replica executing the workflow
+}
+
+// Deferral records why a workflow is waiting for a maintenance window or freeze
+type Deferral struct {
+	Window     string     `json:"window"`
+	Reason     string     `json:"reason"`
+	DeferredAt time.Time  `json:"deferred_at"`
+	Until      *time.Time `json:"until,omitempty"` // when the workflow is re-evaluated, if known
+}
+
+// Rollback records the revisions a rollback moved a workload between
+type Rollback struct {
+	Kind         string `json:"kind"`
+	Name         string `json:"name"`
+	FromRevision int64  `json:"from_revision"`
+	ToRevision   int64  `json:"to_revision"`
+}
+
+// Selection decisions
+const (
+	SelectionSelected       = "selected"        // chosen as the primary remediator
+	SelectionSkipped        = "skipped"         // cannot handle the deployment
+	SelectionRecommendOnly  = "recommend_only"  // detection confidence too low to change anything
+	SelectionFailed         = "failed"          // remediation failed
+	SelectionFailover       = "failover"        // tried after a retryable failure, as policy allows
+	SelectionFailoverDenied = "failover_denied" // capable, but no policy rule allows failing over to it
+)
+
+// SelectionDecision records one decision made choosing the remediator of an issue
+type SelectionDecision struct {
+	Remediator string    `json:"remediator"`
+	Decision   string    `json:"decision"`
+	Reason     string    `json:"reason"`
+	Priority   int       `json:"priority"`
+	Timestamp  time.Time `json:"timestamp"`
 }
 
 // WorkflowStep represents a single step in the workflow
@@ -40,6 +105,8 @@ type WorkflowStep struct {
 	StartedAt    *time.Time `json:"started_at,omitempty"`
 	CompletedAt  *time.Time `json:"completed_at,omitempty"`
 	ErrorMessage string     `json:"error_message,omitempty"`
+
+	Result *RemediationResult `json:"result,omitempty"` // outcome of a remediation step
 }
 
 // Duration returns the workflow execution duration
@@ -67,7 +134,62 @@ func (w *Workflow) AddStep(description string) *WorkflowStep {
 	return &w.Steps[len(w.Steps)-1]
 }
 
-// IsActive returns true if workflow is currently running
+// Clone returns a deep copy of the workflow that can be read and changed without
+// sharing state with the original
+func (w *Workflow) Clone() *Workflow {
+	clone := *w
+	clone.Approval = w.Approval.Clone()
+	clone.Recommendations = cloneStrings(w.Recommendations)
+	if w.Deferral != nil {
+		deferral := *w.Deferral
+		deferral.Until = cloneTime(w.Deferral.Until)
+		clone.Deferral = &deferral
+	}
+	if w.Rollback != nil {
+		rollback := *w.Rollback
+		clone.Rollback = &rollback
+	}
+	clone.StartedAt = cloneTime(w.StartedAt)
+	clone.CompletedAt = cloneTime(w.CompletedAt)
+	if w.Steps != nil {
+		clone.Steps = make([]WorkflowStep, len(w.Steps))
+		for i, step := range w.Steps {
+			step.StartedAt = cloneTime(step.StartedAt)
+			step.CompletedAt = cloneTime(step.CompletedAt)
+			step.Result = step.Result.Clone()
+			clone.Steps[i] = step
+		}
+	}
+	if w.Selection != nil {
+		clone.Selection = make([]SelectionDecision, len(w.Selection))
+		copy(clone.Selection, w.Selection)
+	}
+	return &clone
+}
+
+// cloneTime returns a copy of t, or nil
+func cloneTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	clone := *t
+	return &clone
+}
+
+// cloneStrings returns a copy of s, keeping nil as nil
+func cloneStrings(s []string) []string {
+	if s == nil {
+		return nil
+	}
+	return append([]string{}, s...)
+}
+
+// IsActive returns true if workflow has not reached a final state
 func (w *Workflow) IsActive() bool {
-	return w.Status == WorkflowStatusPending || w.Status == WorkflowStatusRunning
+	switch w.Status {
+	case WorkflowStatusPending, WorkflowStatusPendingApproval, WorkflowStatusQueued, WorkflowStatusDeferred, WorkflowStatusRunning:
+		return true
+	default:
+		return false
+	}
 }
@@ -86,9 +86,14 @@ check_permission "services" "get" "core"
 check_permission "services" "list" "core"
 check_permission "configmaps" "get" "core"
 check_permission "secrets" "get" "core"
+check_permission "secrets" "list" "core"
+check_permission "secrets" "create" "core"
+check_permission "secrets" "update" "core"
 check_permission "events" "create" "core"
 check_permission "namespaces" "get" "core"
 check_permission "namespaces" "list" "core"
+check_permission "limitranges" "list" "core"
+check_permission "resourcequotas" "list" "core"
 
 echo ""
 echo "Apps API Resources:"
@@ -103,6 +108,14 @@ check_permission "statefulsets" "get" "apps"
 check_permission "statefulsets" "list" "apps"
 check_permission "daemonsets" "get" "apps"
 check_permission "daemonsets" "list" "apps"
+check_permission "controllerrevisions" "list" "apps"
+
+echo ""
+echo "OpenShift DeploymentConfig Resources:"
+echo "-------------------------------------"
+check_permission "replicationcontrollers" "get" "core"
+check_permission "deploymentconfigs" "get" "apps.openshift.io"
+check_permission "deploymentconfigs" "patch" "apps.openshift.io"
 
 echo ""
 echo "Batch API Resources:"
@@ -118,6 +131,8 @@ echo "-----------------"
 check_permission "applications" "get" "argoproj.io"
 check_permission "applications" "list" "argoproj.io"
 check_permission "applications" "watch" "argoproj.io"
+check_permission "rollouts" "get" "argoproj.io"
+check_permission "rollouts" "patch" "argoproj.io"
 
 echo ""
 echo "Machine Configuration Resources:"